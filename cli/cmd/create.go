@@ -6,8 +6,8 @@ import (
 
 // createCmd implements the create command
 var createCmd = &cobra.Command{
-	Use:   "create [project | service]",
-	Short: `Creates a new project or service`,
+	Use:   "create [project | service | schedule]",
+	Short: `Creates a new project, service or schedule`,
 }
 
 func init() {
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/keptn/keptn/cli/pkg/apiclient"
+	"github.com/keptn/keptn/cli/pkg/credentialmanager"
+	"github.com/keptn/keptn/cli/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+type createScheduleCmdParams struct {
+	Project   *string
+	Stage     *string
+	Service   *string
+	Sequence  *string
+	Cron      *string
+	Labels    *map[string]string
+	Timeframe *string
+}
+
+var createScheduleParams *createScheduleCmdParams
+
+// crScheduleCmd represents the create schedule command
+var crScheduleCmd = &cobra.Command{
+	Use:   "schedule --project=PROJECTNAME --stage=STAGE --service=SERVICENAME --sequence=SEQUENCE --cron=CRONEXPRESSION [--evaluation-timeframe=TIMEFRAME]",
+	Short: "Creates a schedule that periodically triggers a task sequence",
+	Long: `Creates a schedule that periodically triggers a task sequence of a service in a stage.
+
+The schedule is defined by a cron expression consisting of the five fields minute, hour, day of month, month and day of week (e.g., '0 2 * * 1-5'),
+or by one of the shorthands @yearly, @monthly, @weekly, @daily and @hourly. Schedules are evaluated in UTC.
+If the task sequence contains an evaluation, the evaluated timeframe before the trigger time has to be set (e.g., --evaluation-timeframe=15m).
+`,
+	Example:      `keptn create schedule --project=sockshop --stage=hardening --service=carts --sequence=evaluation --cron="0 2 * * *" --evaluation-timeframe=15m --labels=trigger=nightly`,
+	SilenceUsage: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		_, _, err := credentialmanager.NewCredentialManager(false).GetCreds(namespace)
+		if err != nil {
+			return errors.New(authErrorMsg)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		endPoint, apiToken, err := credentialmanager.NewCredentialManager(false).GetCreds(namespace)
+		if err != nil {
+			return errors.New(authErrorMsg)
+		}
+		logging.PrintLog("Starting to create schedule", logging.InfoLevel)
+
+		schedule := apiclient.Schedule{
+			Stage:    *createScheduleParams.Stage,
+			Service:  *createScheduleParams.Service,
+			Sequence: *createScheduleParams.Sequence,
+			Cron:     *createScheduleParams.Cron,
+		}
+		if createScheduleParams.Labels != nil {
+			schedule.Labels = *createScheduleParams.Labels
+		}
+		if *createScheduleParams.Timeframe != "" {
+			schedule.Evaluation = &apiclient.ScheduleEvaluation{Timeframe: *createScheduleParams.Timeframe}
+		}
+
+		if endPointErr := checkEndPointStatus(endPoint.String()); endPointErr != nil {
+			return fmt.Errorf("Error connecting to server: %s"+endPointErrorReasons,
+				endPointErr)
+		}
+
+		scheduleHandler := apiclient.NewAuthenticatedScheduleHandler(endPoint.String(), apiToken, "x-token", nil, endPoint.Scheme)
+		logging.PrintLog(fmt.Sprintf("Connecting to server %s", endPoint.String()), logging.VerboseLevel)
+
+		if !mocking {
+			created, err := scheduleHandler.CreateSchedule(*createScheduleParams.Project, schedule)
+			if err != nil {
+				logging.PrintLog("Create schedule was unsuccessful", logging.InfoLevel)
+				return fmt.Errorf("Create schedule was unsuccessful. %s", err.Error())
+			}
+
+			logging.PrintLog(fmt.Sprintf("Schedule %s created successfully. Next trigger at %s", created.ID, created.NextTriggerAt), logging.InfoLevel)
+
+			return nil
+		}
+
+		fmt.Println("Skipping create schedule due to mocking flag set to true")
+		return nil
+	},
+}
+
+func init() {
+	createCmd.AddCommand(crScheduleCmd)
+	createScheduleParams = &createScheduleCmdParams{}
+	createScheduleParams.Project = crScheduleCmd.Flags().StringP("project", "p", "", "The project containing the service")
+	crScheduleCmd.MarkFlagRequired("project")
+	createScheduleParams.Stage = crScheduleCmd.Flags().StringP("stage", "s", "", "The stage in which the task sequence is triggered")
+	crScheduleCmd.MarkFlagRequired("stage")
+	createScheduleParams.Service = crScheduleCmd.Flags().StringP("service", "", "", "The service for which the task sequence is triggered")
+	crScheduleCmd.MarkFlagRequired("service")
+	createScheduleParams.Sequence = crScheduleCmd.Flags().StringP("sequence", "", "", "The name of the task sequence to trigger")
+	crScheduleCmd.MarkFlagRequired("sequence")
+	createScheduleParams.Cron = crScheduleCmd.Flags().StringP("cron", "", "", "The cron expression that defines when the task sequence is triggered")
+	crScheduleCmd.MarkFlagRequired("cron")
+	createScheduleParams.Timeframe = crScheduleCmd.Flags().StringP("evaluation-timeframe", "", "", "The timeframe before the trigger time that is evaluated, required if the task sequence contains an evaluation (e.g., 15m)")
+	createScheduleParams.Labels = crScheduleCmd.Flags().StringToStringP("labels", "l", nil, "Additional labels to be added to the triggered task sequence")
+}
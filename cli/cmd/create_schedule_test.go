@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/keptn/keptn/cli/pkg/credentialmanager"
+	"github.com/keptn/keptn/cli/pkg/logging"
+)
+
+func init() {
+	logging.InitLoggers(os.Stdout, os.Stdout, os.Stderr)
+}
+
+// TestCreateScheduleCmd tests the default use of the create schedule command
+func TestCreateScheduleCmd(t *testing.T) {
+	credentialmanager.MockAuthCreds = true
+	checkEndPointStatusMock = true
+
+	cmd := `create schedule --project=sockshop --stage=hardening --service=carts --sequence=evaluation --cron="0 2 * * *" --evaluation-timeframe=15m --labels=trigger=nightly --mock`
+	_, err := executeActionCommandC(cmd)
+	if err != nil {
+		t.Errorf(unexpectedErrMsg, err)
+	}
+}
+
@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// evaluateCmd implements the evaluate command
+var evaluateCmd = &cobra.Command{
+	Use:   "evaluate [slo]",
+	Short: `Evaluates Keptn resources locally`,
+	Long:  `Evaluates Keptn resources, such as an SLO file, locally without connecting to a Keptn cluster.`,
+}
+
+func init() {
+	rootCmd.AddCommand(evaluateCmd)
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	keptn "github.com/keptn/go-utils/pkg/lib"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/cli/pkg/file"
+	"github.com/keptn/keptn/lighthouse-service/pkg/scoring"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+type evaluateSLOCmdParams struct {
+	SLOFile          *string
+	SLIResultsFile   *string
+	PreviousEvalFile *string
+	OutputFormat     *string
+}
+
+var evaluateSLOParams *evaluateSLOCmdParams
+
+// evaluateSLOCmd represents the evaluate slo command
+var evaluateSLOCmd = &cobra.Command{
+	Use:   "slo --slo=SLOFILE --sli-results=SLIRESULTSFILE",
+	Short: "Evaluates SLI results against an SLO file locally",
+	Long: `Evaluates SLI results against the objectives of an SLO file, using the same scoring logic as the lighthouse-service, without connecting to a Keptn cluster.
+This allows to tune an SLO file before it is added to a service.
+
+The SLI results file contains either a JSON array of SLI results, e.g.:
+[{"metric": "response_time_p95", "value": 310, "success": true}, {"metric": "error_rate", "value": 0, "success": true}]
+or a sh.keptn.event.get-sli.finished event.
+
+The optional file with previous evaluations contains a JSON array of sh.keptn.event.evaluation.finished events, or of their data, starting with the latest evaluation.
+They are used for the comparison criteria of the SLO file, according to its comparison settings.
+`,
+	Example: `keptn evaluate slo --slo=./slo.yaml --sli-results=./sli-results.json
+
+keptn evaluate slo --slo=./slo.yaml --sli-results=./sli-results.json --previous=./evaluations.json --output=json`,
+	SilenceUsage: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if *evaluateSLOParams.OutputFormat != "" {
+			if *evaluateSLOParams.OutputFormat != "yaml" && *evaluateSLOParams.OutputFormat != "json" {
+				return errors.New("Invalid output format, only yaml or json allowed")
+			}
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sloContent, err := file.ReadFile(*evaluateSLOParams.SLOFile)
+		if err != nil {
+			return err
+		}
+		slo, err := scoring.ParseSLO([]byte(sloContent))
+		if err != nil {
+			return fmt.Errorf("Could not parse SLO file: %s", err.Error())
+		}
+		if err := scoring.ValidateSLOCriteria(slo); err != nil {
+			return fmt.Errorf("Invalid SLO file: %s", err.Error())
+		}
+
+		sliResultsContent, err := file.ReadFile(*evaluateSLOParams.SLIResultsFile)
+		if err != nil {
+			return err
+		}
+		getSLIFinishedData, err := parseSLIResults([]byte(sliResultsContent))
+		if err != nil {
+			return fmt.Errorf("Could not parse SLI results: %s", err.Error())
+		}
+
+		var previousEvaluations []*keptnv2.EvaluationFinishedEventData
+		if *evaluateSLOParams.PreviousEvalFile != "" {
+			previousContent, err := file.ReadFile(*evaluateSLOParams.PreviousEvalFile)
+			if err != nil {
+				return err
+			}
+			previousEvaluations, err = parsePreviousEvaluations([]byte(previousContent))
+			if err != nil {
+				return fmt.Errorf("Could not parse previous evaluations: %s", err.Error())
+			}
+		}
+
+		evaluationResult, err := scoring.Evaluate(getSLIFinishedData, slo, selectComparisonEvaluations(previousEvaluations, slo))
+		if err != nil {
+			return fmt.Errorf("Could not evaluate SLO: %s", err.Error())
+		}
+
+		return printEvaluationResult(evaluationResult, strings.ToLower(*evaluateSLOParams.OutputFormat))
+	},
+}
+
+// parseSLIResults accepts either a list of SLI results or a get-sli.finished event
+func parseSLIResults(content []byte) (*keptnv2.GetSLIFinishedEventData, error) {
+	result := &keptnv2.GetSLIFinishedEventData{}
+	if err := json.Unmarshal(content, &result.GetSLI.IndicatorValues); err == nil {
+		return result, nil
+	}
+
+	event := struct {
+		Data *keptnv2.GetSLIFinishedEventData `json:"data"`
+	}{}
+	if err := json.Unmarshal(content, &event); err != nil {
+		return nil, err
+	}
+	if event.Data == nil {
+		if err := json.Unmarshal(content, result); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	return event.Data, nil
+}
+
+// parsePreviousEvaluations accepts a list of evaluation.finished events or a list of their data
+func parsePreviousEvaluations(content []byte) ([]*keptnv2.EvaluationFinishedEventData, error) {
+	entries := []json.RawMessage{}
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, err
+	}
+
+	evaluations := []*keptnv2.EvaluationFinishedEventData{}
+	for _, entry := range entries {
+		event := struct {
+			Data *keptnv2.EvaluationFinishedEventData `json:"data"`
+		}{}
+		if err := json.Unmarshal(entry, &event); err != nil {
+			return nil, err
+		}
+		if event.Data != nil {
+			evaluations = append(evaluations, event.Data)
+			continue
+		}
+		evaluation := &keptnv2.EvaluationFinishedEventData{}
+		if err := json.Unmarshal(entry, evaluation); err != nil {
+			return nil, err
+		}
+		evaluations = append(evaluations, evaluation)
+	}
+	return evaluations, nil
+}
+
+// selectComparisonEvaluations applies the comparison settings of the SLO file to the previous evaluations, like the lighthouse-service does when retrieving them
+func selectComparisonEvaluations(evaluations []*keptnv2.EvaluationFinishedEventData, slo *keptn.ServiceLevelObjectives) []*keptnv2.EvaluationFinishedEventData {
+	selected := []*keptnv2.EvaluationFinishedEventData{}
+	numberOfComparisonResults := scoring.GetNumberOfComparisonResults(slo)
+	for _, evaluation := range evaluations {
+		if len(selected) == numberOfComparisonResults {
+			break
+		}
+		if scoring.IsComparableEvaluation(slo, evaluation) {
+			selected = append(selected, evaluation)
+		}
+	}
+	return selected
+}
+
+func printEvaluationResult(evaluationResult *keptnv2.EvaluationFinishedEventData, outputFormat string) error {
+	if outputFormat == "yaml" {
+		yamlBytes, err := yaml.Marshal(evaluationResult.Evaluation)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(yamlBytes))
+		return nil
+	} else if outputFormat == "json" {
+		jsonBytes, err := json.MarshalIndent(evaluationResult.Evaluation, "", "   ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 10, 8, 2, '\t', 0)
+	fmt.Fprintln(w, "SLI\tVALUE\tSTATUS\tSCORE\tTARGETS")
+	for _, indicatorResult := range evaluationResult.Evaluation.IndicatorResults {
+		value := indicatorResult.Value.Message
+		if indicatorResult.Value.Success {
+			value = strconv.FormatFloat(indicatorResult.Value.Value, 'f', -1, 64)
+		}
+		fmt.Fprintln(w, indicatorResult.Value.Metric+"\t"+value+"\t"+indicatorResult.Status+"\t"+strconv.FormatFloat(indicatorResult.Score, 'f', -1, 64)+"\t"+formatSLITargets(indicatorResult.Targets))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Printf("Score: %.2f\n", evaluationResult.Evaluation.Score)
+	fmt.Printf("Result: %s\n", evaluationResult.Evaluation.Result)
+	return nil
+}
+
+func formatSLITargets(targets []*keptnv2.SLITarget) string {
+	formattedTargets := []string{}
+	for _, target := range targets {
+		formattedTarget := target.Criteria
+		if target.TargetValue != 0 {
+			formattedTarget += " (target: " + strconv.FormatFloat(target.TargetValue, 'f', -1, 64) + ")"
+		}
+		if target.Violated {
+			formattedTarget += " violated"
+		}
+		formattedTargets = append(formattedTargets, formattedTarget)
+	}
+	return strings.Join(formattedTargets, ", ")
+}
+
+func init() {
+	evaluateCmd.AddCommand(evaluateSLOCmd)
+	evaluateSLOParams = &evaluateSLOCmdParams{}
+	evaluateSLOParams.SLOFile = evaluateSLOCmd.Flags().StringP("slo", "", "", "The SLO file to evaluate")
+	evaluateSLOCmd.MarkFlagRequired("slo")
+	evaluateSLOParams.SLIResultsFile = evaluateSLOCmd.Flags().StringP("sli-results", "", "", "A JSON file containing the SLI results")
+	evaluateSLOCmd.MarkFlagRequired("sli-results")
+	evaluateSLOParams.PreviousEvalFile = evaluateSLOCmd.Flags().StringP("previous", "", "", "A JSON file containing previous evaluation.finished events used for comparison criteria")
+	evaluateSLOParams.OutputFormat = evaluateSLOCmd.Flags().StringP("output", "o", "", "Output format. One of json|yaml")
+}
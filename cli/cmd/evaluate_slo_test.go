@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/keptn/keptn/cli/pkg/logging"
+)
+
+func init() {
+	logging.InitLoggers(os.Stdout, os.Stdout, os.Stderr)
+}
+
+const evaluateSLOTestSLO = `---
+spec_version: '1.0'
+comparison:
+  compare_with: "several_results"
+  number_of_comparison_results: 2
+  include_result_with_score: "pass"
+  aggregate_function: avg
+objectives:
+  - sli: response_time_p95
+    pass:
+      - criteria:
+          - "<=+10%"
+          - "<600"
+    warning:
+      - criteria:
+          - "<=800"
+  - sli: error_rate
+    weight: 2
+    pass:
+      - criteria:
+          - "=0"
+total_score:
+  pass: "90%"
+  warning: "75%"
+`
+
+const evaluateSLOTestSLIResults = `[
+  {"metric": "response_time_p95", "value": 350, "success": true},
+  {"metric": "error_rate", "value": 0, "success": true}
+]`
+
+const evaluateSLOTestPreviousEvaluations = `[
+  {"type": "sh.keptn.event.evaluation.finished", "data": {"result": "fail", "evaluation": {"indicatorResults": [{"value": {"metric": "response_time_p95", "value": 1000, "success": true}}]}}},
+  {"type": "sh.keptn.event.evaluation.finished", "data": {"result": "pass", "evaluation": {"indicatorResults": [{"value": {"metric": "response_time_p95", "value": 300, "success": true}}]}}},
+  {"result": "pass", "evaluation": {"indicatorResults": [{"value": {"metric": "response_time_p95", "value": 300, "success": true}}]}}
+]`
+
+func writeEvaluateSLOTestFiles(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "evaluate-slo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"slo.yaml":         evaluateSLOTestSLO,
+		"sli-results.json": evaluateSLOTestSLIResults,
+		"evaluations.json": evaluateSLOTestPreviousEvaluations,
+		"invalid-slo.yaml": strings.Replace(evaluateSLOTestSLO, `"=0"`, `"=0 and"`, 1),
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+// TestEvaluateSLO tests the evaluation of SLI results without previous evaluations
+func TestEvaluateSLO(t *testing.T) {
+	dir, cleanup := writeEvaluateSLOTestFiles(t)
+	defer cleanup()
+
+	r := newRedirector()
+	r.redirectStdOut()
+	_, err := executeActionCommandC("evaluate slo --slo=" + filepath.Join(dir, "slo.yaml") + " --sli-results=" + filepath.Join(dir, "sli-results.json") + " --previous=")
+	out := r.revertStdOut()
+
+	if err != nil {
+		t.Errorf(unexpectedErrMsg, err)
+	}
+	if !strings.Contains(out, "Score: 100.00") || !strings.Contains(out, "Result: pass") {
+		t.Errorf("unexpected output: %s", out)
+	}
+}
+
+// TestEvaluateSLOWithPreviousEvaluations tests that comparison criteria are evaluated against the passed previous evaluations
+func TestEvaluateSLOWithPreviousEvaluations(t *testing.T) {
+	dir, cleanup := writeEvaluateSLOTestFiles(t)
+	defer cleanup()
+
+	r := newRedirector()
+	r.redirectStdOut()
+	_, err := executeActionCommandC("evaluate slo --slo=" + filepath.Join(dir, "slo.yaml") + " --sli-results=" + filepath.Join(dir, "sli-results.json") + " --previous=" + filepath.Join(dir, "evaluations.json"))
+	out := r.revertStdOut()
+
+	if err != nil {
+		t.Errorf(unexpectedErrMsg, err)
+	}
+	// the failed evaluation is not taken into account, so 350 is compared with avg([300, 300]) + 10%
+	if !strings.Contains(out, "<=+10% (target: 330) violated") || !strings.Contains(out, "Score: 83.33") || !strings.Contains(out, "Result: warning") {
+		t.Errorf("unexpected output: %s", out)
+	}
+}
+
+// TestEvaluateSLOInvalidCriteria tests that invalid criteria are reported
+func TestEvaluateSLOInvalidCriteria(t *testing.T) {
+	dir, cleanup := writeEvaluateSLOTestFiles(t)
+	defer cleanup()
+
+	_, err := executeActionCommandC("evaluate slo --slo=" + filepath.Join(dir, "invalid-slo.yaml") + " --sli-results=" + filepath.Join(dir, "sli-results.json") + " --previous=")
+	if err == nil || !strings.Contains(err.Error(), "invalid criteria '=0 and' of SLI error_rate") {
+		t.Errorf("expected an error because of the invalid criteria, got %v", err)
+	}
+}
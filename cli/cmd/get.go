@@ -4,9 +4,9 @@ import "github.com/spf13/cobra"
 
 // getCmd represents the send command
 var getCmd = &cobra.Command{
-	Use:   "get [event | project | projects | stage | stages | service | services]",
-	Short: "Displays an event or Keptn entities such as project, stage, or service",
-	Long:  `Displays an event or Keptn entities such as project, stage, or service.`,
+	Use:   "get [event | project | projects | stage | stages | service | services | schedule | schedules]",
+	Short: "Displays an event or Keptn entities such as project, stage, service, or schedule",
+	Long:  `Displays an event or Keptn entities such as project, stage, service, or schedule.`,
 }
 
 func init() {
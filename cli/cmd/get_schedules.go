@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/keptn/keptn/cli/pkg/apiclient"
+	"github.com/keptn/keptn/cli/pkg/credentialmanager"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+type getScheduleStruct struct {
+	project      *string
+	stage        *string
+	service      *string
+	sequence     *string
+	outputFormat *string
+}
+
+var getSchedule getScheduleStruct
+
+// getScheduleCmd represents the get schedule command
+var getScheduleCmd = &cobra.Command{
+	Use:     "schedule [SCHEDULEID] --project=PROJECTNAME",
+	Aliases: []string{"schedules"},
+	Short:   "Get schedules of task sequences",
+	Long:    `Get all schedules or details for a given schedule within a Keptn project`,
+	Example: `keptn get schedules --project=sockshop
+ID                                    STAGE      SERVICE  SEQUENCE    CRON       NEXT TRIGGER
+4e1b7aa2-7c5e-4b5e-8a3a-2f2d3a0c9d11  hardening  carts    evaluation  0 2 * * *  2020-11-19T02:00:00Z
+
+keptn get schedules --project=sockshop --stage=hardening                          # List all schedules in the hardening stage
+
+keptn get schedule 4e1b7aa2-7c5e-4b5e-8a3a-2f2d3a0c9d11 --project=sockshop -o=json  # Get details of a schedule as json output
+`,
+	SilenceUsage: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		_, _, err := credentialmanager.NewCredentialManager(false).GetCreds(namespace)
+		if err != nil {
+			return errors.New(authErrorMsg)
+		}
+
+		if len(args) > 1 {
+			cmd.SilenceUsage = false
+			return errors.New("too many arguments set")
+		}
+
+		if *getSchedule.outputFormat != "" {
+			if *getSchedule.outputFormat != "yaml" && *getSchedule.outputFormat != "json" {
+				return errors.New("Invalid output format, only yaml or json allowed")
+			}
+		}
+
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		endPoint, apiToken, err := credentialmanager.NewCredentialManager(false).GetCreds(namespace)
+		if err != nil {
+			return errors.New(authErrorMsg)
+		}
+
+		if endPointErr := checkEndPointStatus(endPoint.String()); endPointErr != nil {
+			return fmt.Errorf("Error connecting to server: %s"+endPointErrorReasons,
+				endPointErr)
+		}
+
+		scheduleHandler := apiclient.NewAuthenticatedScheduleHandler(endPoint.String(), apiToken, "x-token", nil, endPoint.Scheme)
+
+		if mocking {
+			return nil
+		}
+
+		var schedules []apiclient.Schedule
+		if len(args) == 1 {
+			schedule, err := scheduleHandler.GetSchedule(*getSchedule.project, args[0])
+			if err != nil {
+				return err
+			}
+			schedules = []apiclient.Schedule{*schedule}
+		} else {
+			schedules, err = scheduleHandler.GetSchedules(*getSchedule.project, apiclient.ScheduleFilter{
+				Stage:    *getSchedule.stage,
+				Service:  *getSchedule.service,
+				Sequence: *getSchedule.sequence,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(schedules) == 0 {
+			fmt.Printf("No schedules found in project %s\n", *getSchedule.project)
+			return nil
+		}
+
+		return printSchedules(schedules, strings.ToLower(*getSchedule.outputFormat))
+	},
+}
+
+func printSchedules(schedules []apiclient.Schedule, outputFormat string) error {
+	if outputFormat == "yaml" {
+		for _, schedule := range schedules {
+			yamlBytes, err := yaml.Marshal(schedule)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(yamlBytes))
+		}
+		return nil
+	} else if outputFormat == "json" {
+		for _, schedule := range schedules {
+			jsonBytes, err := json.MarshalIndent(schedule, "", "   ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(jsonBytes))
+		}
+		return nil
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 10, 8, 2, '\t', 0)
+	fmt.Fprintln(w, "ID\tSTAGE\tSERVICE\tSEQUENCE\tCRON\tNEXT TRIGGER")
+	for _, schedule := range schedules {
+		fmt.Fprintln(w, schedule.ID+"\t"+schedule.Stage+"\t"+schedule.Service+"\t"+schedule.Sequence+"\t"+schedule.Cron+"\t"+schedule.NextTriggerAt)
+	}
+	return w.Flush()
+}
+
+func init() {
+	getCmd.AddCommand(getScheduleCmd)
+
+	getSchedule.project = getScheduleCmd.Flags().StringP("project", "", "",
+		"keptn project name")
+	getScheduleCmd.MarkFlagRequired("project")
+	getSchedule.stage = getScheduleCmd.Flags().StringP("stage", "", "",
+		"Only list schedules of the given stage")
+	getSchedule.service = getScheduleCmd.Flags().StringP("service", "", "",
+		"Only list schedules of the given service")
+	getSchedule.sequence = getScheduleCmd.Flags().StringP("sequence", "", "",
+		"Only list schedules of the given task sequence")
+	getSchedule.outputFormat = getScheduleCmd.Flags().StringP("output", "o", "",
+		"Output format. One of json|yaml")
+}
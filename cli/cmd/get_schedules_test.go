@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/keptn/keptn/cli/pkg/credentialmanager"
+	"github.com/keptn/keptn/cli/pkg/logging"
+)
+
+func init() {
+	logging.InitLoggers(os.Stdout, os.Stdout, os.Stderr)
+}
+
+// TestGetSchedules tests the get schedules command
+func TestGetSchedules(t *testing.T) {
+	credentialmanager.MockAuthCreds = true
+	checkEndPointStatusMock = true
+
+	cmd := fmt.Sprintf("get schedules --project=sockshop --stage=hardening --mock")
+	_, err := executeActionCommandC(cmd)
+	if err != nil {
+		t.Errorf(unexpectedErrMsg, err)
+	}
+}
+
+// TestGetSchedulesInvalidOutputFormat tests that the get schedules command rejects unknown output formats
+func TestGetSchedulesInvalidOutputFormat(t *testing.T) {
+	credentialmanager.MockAuthCreds = true
+	checkEndPointStatusMock = true
+
+	cmd := fmt.Sprintf("get schedules --project=sockshop --output=xml --mock")
+	_, err := executeActionCommandC(cmd)
+	if err == nil {
+		t.Error("expected an error because of the invalid output format")
+	}
+}
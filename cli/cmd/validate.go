@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// validateCmd implements the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate [slo]",
+	Short: `Validates Keptn resources locally`,
+	Long:  `Validates Keptn resources, such as an SLO file, locally without connecting to a Keptn cluster.`,
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/keptn/keptn/cli/pkg/file"
+	"github.com/keptn/keptn/lighthouse-service/pkg/scoring"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+type validateSLOCmdParams struct {
+	File         *string
+	OutputFormat *string
+}
+
+var validateSLOParams *validateSLOCmdParams
+
+// validateSLOCmd represents the validate slo command
+var validateSLOCmd = &cobra.Command{
+	Use:   "slo --file=SLOFILE",
+	Short: "Validates an SLO file locally",
+	Long: `Validates an SLO file using the same rules as the lighthouse-service, without connecting to a Keptn cluster.
+All problems found in the file are reported, e.g., invalid criteria, SLIs that are defined more than once, negative weights, invalid comparison settings and total score targets that can never be reached.
+The command fails if at least one problem is found, which allows to use it in a CI pipeline before an SLO file is added to a service.
+`,
+	Example: `keptn validate slo --file=./slo.yaml
+
+keptn validate slo --file=./slo.yaml --output=json`,
+	SilenceUsage: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if *validateSLOParams.OutputFormat != "" {
+			if *validateSLOParams.OutputFormat != "yaml" && *validateSLOParams.OutputFormat != "json" {
+				return errors.New("Invalid output format, only yaml or json allowed")
+			}
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sloContent, err := file.ReadFile(*validateSLOParams.File)
+		if err != nil {
+			return err
+		}
+
+		problems := scoring.ValidateSLOFile([]byte(sloContent))
+		if err := printSLOValidationProblems(problems, strings.ToLower(*validateSLOParams.OutputFormat)); err != nil {
+			return err
+		}
+		if len(problems) > 0 {
+			return fmt.Errorf("SLO file %s contains %d problem(s)", *validateSLOParams.File, len(problems))
+		}
+		return nil
+	},
+}
+
+func printSLOValidationProblems(problems []scoring.SLOValidationProblem, outputFormat string) error {
+	switch outputFormat {
+	case "json":
+		out, err := json.MarshalIndent(problems, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	case "yaml":
+		out, err := yaml.Marshal(problems)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(out))
+	default:
+		if len(problems) == 0 {
+			fmt.Println("SLO file is valid")
+			return nil
+		}
+		for _, problem := range problems {
+			fmt.Println("- " + problem.Message)
+		}
+	}
+	return nil
+}
+
+func init() {
+	validateCmd.AddCommand(validateSLOCmd)
+	validateSLOParams = &validateSLOCmdParams{}
+	validateSLOParams.File = validateSLOCmd.Flags().StringP("file", "f", "", "The SLO file to validate")
+	validateSLOCmd.MarkFlagRequired("file")
+	validateSLOParams.OutputFormat = validateSLOCmd.Flags().StringP("output", "o", "", "Output format. One of json|yaml")
+}
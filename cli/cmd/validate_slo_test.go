@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const validateSLOTestInvalidSLO = `---
+spec_version: '1.0'
+objectives:
+  - sli: response_time_p95
+    pass:
+      - criteria:
+          - "<=+10%%"
+  - sli: response_time_p95
+total_score:
+  pass: "120%"
+`
+
+// TestValidateSLO tests that a valid SLO file is accepted
+func TestValidateSLO(t *testing.T) {
+	dir, cleanup := writeEvaluateSLOTestFiles(t)
+	defer cleanup()
+
+	r := newRedirector()
+	r.redirectStdOut()
+	_, err := executeActionCommandC("validate slo --file=" + filepath.Join(dir, "slo.yaml") + " --output=")
+	out := r.revertStdOut()
+
+	if err != nil {
+		t.Errorf(unexpectedErrMsg, err)
+	}
+	if !strings.Contains(out, "SLO file is valid") {
+		t.Errorf("unexpected output: %s", out)
+	}
+}
+
+// TestValidateSLOWithProblems tests that all problems of an SLO file are reported
+func TestValidateSLOWithProblems(t *testing.T) {
+	dir, cleanup := writeEvaluateSLOTestFiles(t)
+	defer cleanup()
+	sloFile := filepath.Join(dir, "problems-slo.yaml")
+	if err := ioutil.WriteFile(sloFile, []byte(validateSLOTestInvalidSLO), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := newRedirector()
+	r.redirectStdOut()
+	_, err := executeActionCommandC("validate slo --file=" + sloFile + " --output=")
+	out := r.revertStdOut()
+
+	if err == nil || !strings.Contains(err.Error(), "contains 3 problem(s)") {
+		t.Errorf("expected an error because of the problems in the SLO file, got %v", err)
+	}
+	for _, expected := range []string{
+		"SLI response_time_p95 is defined more than once",
+		"invalid criteria '<=+10%%' of SLI response_time_p95",
+		"total_score.pass '120%' can never be reached",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected output to contain %s, got: %s", expected, out)
+		}
+	}
+}
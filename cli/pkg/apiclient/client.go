@@ -0,0 +1,89 @@
+package apiclient
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	apimodels "github.com/keptn/go-utils/pkg/api/models"
+)
+
+const shipyardControllerBaseURL = "shipyard-controller/v1"
+
+// client sends authenticated requests to the Keptn API for endpoints that are not covered by the go-utils API handlers
+type client struct {
+	baseURL    string
+	authToken  string
+	authHeader string
+	httpClient *http.Client
+	scheme     string
+}
+
+func newClient(baseURL string, authToken string, authHeader string, httpClient *http.Client, scheme string) client {
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+		}
+	}
+	baseURL = strings.TrimPrefix(baseURL, "http://")
+	baseURL = strings.TrimPrefix(baseURL, "https://")
+	baseURL = strings.TrimRight(baseURL, "/")
+	return client{
+		baseURL:    baseURL,
+		authToken:  authToken,
+		authHeader: authHeader,
+		httpClient: httpClient,
+		scheme:     scheme,
+	}
+}
+
+// do sends a request to the given path and decodes the response body into out, if out is not nil
+func (c client) do(method, path string, payload interface{}, out interface{}) error {
+	var body []byte
+	if payload != nil {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, c.scheme+"://"+c.baseURL+"/"+path, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.authHeader != "" && c.authToken != "" {
+		req.Header.Set(c.authHeader, c.authToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respErr := &apimodels.Error{}
+		if err := json.Unmarshal(respBody, respErr); err != nil || respErr.Message == nil {
+			return fmt.Errorf("request failed with status code %d", resp.StatusCode)
+		}
+		return errors.New(*respErr.Message)
+	}
+
+	if out != nil && len(respBody) > 0 {
+		return json.Unmarshal(respBody, out)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package apiclient
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// Schedule describes a task sequence that is triggered periodically, based on a cron expression
+type Schedule struct {
+	ID               string              `json:"id,omitempty" yaml:"id,omitempty"`
+	Project          string              `json:"project,omitempty" yaml:"project,omitempty"`
+	Stage            string              `json:"stage" yaml:"stage"`
+	Service          string              `json:"service" yaml:"service"`
+	Sequence         string              `json:"sequence" yaml:"sequence"`
+	Cron             string              `json:"cron" yaml:"cron"`
+	Labels           map[string]string   `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Evaluation       *ScheduleEvaluation `json:"evaluation,omitempty" yaml:"evaluation,omitempty"`
+	CreatedAt        string              `json:"createdAt,omitempty" yaml:"createdAt,omitempty"`
+	LastTriggeredAt  string              `json:"lastTriggeredAt,omitempty" yaml:"lastTriggeredAt,omitempty"`
+	LastKeptnContext string              `json:"lastKeptnContext,omitempty" yaml:"lastKeptnContext,omitempty"`
+	NextTriggerAt    string              `json:"nextTriggerAt,omitempty" yaml:"nextTriggerAt,omitempty"`
+}
+
+// ScheduleEvaluation defines the timeframe that is evaluated when a scheduled task sequence contains an evaluation
+type ScheduleEvaluation struct {
+	Timeframe string `json:"timeframe" yaml:"timeframe"`
+}
+
+type schedules struct {
+	Schedules   []Schedule `json:"schedules"`
+	NextPageKey string     `json:"nextPageKey,omitempty"`
+}
+
+// ScheduleFilter allows to filter the schedules of a project
+type ScheduleFilter struct {
+	Stage    string
+	Service  string
+	Sequence string
+}
+
+// ScheduleHandler manages the schedules of task sequences via the shipyard-controller API
+type ScheduleHandler struct {
+	client
+}
+
+// NewAuthenticatedScheduleHandler returns a new ScheduleHandler that authenticates at the API via the provided token
+func NewAuthenticatedScheduleHandler(baseURL string, authToken string, authHeader string, httpClient *http.Client, scheme string) *ScheduleHandler {
+	return &ScheduleHandler{
+		client: newClient(baseURL, authToken, authHeader, httpClient, scheme),
+	}
+}
+
+// CreateSchedule creates a new schedule in the given project
+func (s *ScheduleHandler) CreateSchedule(project string, schedule Schedule) (*Schedule, error) {
+	result := &Schedule{}
+	if err := s.do(http.MethodPost, shipyardControllerBaseURL+"/schedule/"+url.PathEscape(project), schedule, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetSchedules returns all schedules of the given project that match the filter
+func (s *ScheduleHandler) GetSchedules(project string, filter ScheduleFilter) ([]Schedule, error) {
+	result := []Schedule{}
+	nextPageKey := ""
+	for {
+		query := url.Values{}
+		if filter.Stage != "" {
+			query.Set("stage", filter.Stage)
+		}
+		if filter.Service != "" {
+			query.Set("service", filter.Service)
+		}
+		if filter.Sequence != "" {
+			query.Set("sequence", filter.Sequence)
+		}
+		if nextPageKey != "" {
+			query.Set("nextPageKey", nextPageKey)
+		}
+
+		received := &schedules{}
+		if err := s.do(http.MethodGet, shipyardControllerBaseURL+"/schedule/"+url.PathEscape(project)+"?"+query.Encode(), nil, received); err != nil {
+			return nil, err
+		}
+		result = append(result, received.Schedules...)
+		if received.NextPageKey == "" || received.NextPageKey == "0" {
+			break
+		}
+		nextPageKey = received.NextPageKey
+	}
+	return result, nil
+}
+
+// GetSchedule returns the schedule with the given ID
+func (s *ScheduleHandler) GetSchedule(project, scheduleID string) (*Schedule, error) {
+	result := &Schedule{}
+	if err := s.do(http.MethodGet, shipyardControllerBaseURL+"/schedule/"+url.PathEscape(project)+"/"+url.PathEscape(scheduleID), nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
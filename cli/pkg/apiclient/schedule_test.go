@@ -0,0 +1,76 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleHandler_CreateSchedule(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/shipyard-controller/v1/schedule/sockshop", r.URL.Path)
+		assert.Equal(t, "my-token", r.Header.Get("x-token"))
+
+		received := &Schedule{}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(received))
+		assert.Equal(t, "0 2 * * *", received.Cron)
+
+		received.ID = "my-schedule"
+		received.NextTriggerAt = "2020-11-19T02:00:00Z"
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(received)
+	}))
+	defer server.Close()
+
+	handler := NewAuthenticatedScheduleHandler(server.URL, "my-token", "x-token", nil, "http")
+	created, err := handler.CreateSchedule("sockshop", Schedule{
+		Stage:    "hardening",
+		Service:  "carts",
+		Sequence: "evaluation",
+		Cron:     "0 2 * * *",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "my-schedule", created.ID)
+	assert.Equal(t, "2020-11-19T02:00:00Z", created.NextTriggerAt)
+}
+
+func TestScheduleHandler_CreateScheduleInvalid(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"code":400,"message":"invalid schedule: no stage dev found in project sockshop"}`))
+	}))
+	defer server.Close()
+
+	handler := NewAuthenticatedScheduleHandler(server.URL, "my-token", "x-token", nil, "http")
+	created, err := handler.CreateSchedule("sockshop", Schedule{Stage: "dev", Service: "carts", Sequence: "delivery", Cron: "@daily"})
+
+	assert.Nil(t, created)
+	assert.EqualError(t, err, "invalid schedule: no stage dev found in project sockshop")
+}
+
+func TestScheduleHandler_GetSchedules(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "hardening", r.URL.Query().Get("stage"))
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("nextPageKey") == "" {
+			_, _ = w.Write([]byte(`{"schedules":[{"id":"schedule-1","stage":"hardening","service":"carts","sequence":"evaluation","cron":"@daily"}],"nextPageKey":"1"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"schedules":[{"id":"schedule-2","stage":"hardening","service":"carts-db","sequence":"evaluation","cron":"@hourly"}]}`))
+	}))
+	defer server.Close()
+
+	handler := NewAuthenticatedScheduleHandler(server.URL, "my-token", "x-token", nil, "http")
+	schedules, err := handler.GetSchedules("sockshop", ScheduleFilter{Stage: "hardening"})
+
+	assert.Nil(t, err)
+	assert.Len(t, schedules, 2)
+	assert.Equal(t, "schedule-1", schedules[0].ID)
+	assert.Equal(t, "schedule-2", schedules[1].ID)
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+
+	"github.com/keptn/keptn/lighthouse-service/event_handler"
+)
+
+// ReEvaluationPath is the path of the endpoint that recomputes stored evaluations
+const ReEvaluationPath = "/v1/evaluation/reevaluate"
+
+// ReEvaluator recomputes a stored evaluation
+type ReEvaluator interface {
+	ReEvaluate(request event_handler.ReEvaluationRequest) (*keptnv2.EvaluationFinishedEventData, error)
+}
+
+// NewReEvaluationHandler returns a handler that recomputes the evaluation identified by the body of a POST request and responds with the result
+func NewReEvaluationHandler(reEvaluator ReEvaluator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Code: http.StatusMethodNotAllowed, Message: "method " + r.Method + " is not allowed"})
+			return
+		}
+
+		request := event_handler.ReEvaluationRequest{}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSLOFileSize)).Decode(&request); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Code: http.StatusBadRequest, Message: "could not parse request: " + err.Error()})
+			return
+		}
+
+		result, err := reEvaluator.ReEvaluate(request)
+		if _, ok := err.(event_handler.InvalidReEvaluationRequestError); ok {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Code: http.StatusBadRequest, Message: err.Error()})
+			return
+		} else if err == event_handler.ErrEvaluationNotFound {
+			writeJSON(w, http.StatusNotFound, errorResponse{Code: http.StatusNotFound, Message: err.Error()})
+			return
+		} else if err != nil {
+			writeJSON(w, http.StatusInternalServerError, errorResponse{Code: http.StatusInternalServerError, Message: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
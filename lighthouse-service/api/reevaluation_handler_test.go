@@ -0,0 +1,85 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/keptn/keptn/lighthouse-service/event_handler"
+)
+
+type fakeReEvaluator struct {
+	result *keptnv2.EvaluationFinishedEventData
+	err    error
+}
+
+func (f *fakeReEvaluator) ReEvaluate(request event_handler.ReEvaluationRequest) (*keptnv2.EvaluationFinishedEventData, error) {
+	return f.result, f.err
+}
+
+func TestReEvaluationHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		body        string
+		reEvaluator *fakeReEvaluator
+		wantStatus  int
+	}{
+		{
+			name:        "re-evaluation",
+			method:      http.MethodPost,
+			body:        `{"project": "sockshop", "stage": "dev", "service": "carts", "keptnContext": "my-context"}`,
+			reEvaluator: &fakeReEvaluator{result: &keptnv2.EvaluationFinishedEventData{EventData: keptnv2.EventData{Result: keptnv2.ResultPass}}},
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "invalid request body",
+			method:      http.MethodPost,
+			body:        `{`,
+			reEvaluator: &fakeReEvaluator{},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "invalid request",
+			method:      http.MethodPost,
+			body:        `{}`,
+			reEvaluator: &fakeReEvaluator{err: event_handler.InvalidReEvaluationRequestError{}},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "evaluation not found",
+			method:      http.MethodPost,
+			body:        `{"project": "sockshop", "stage": "dev", "service": "carts", "keptnContext": "unknown"}`,
+			reEvaluator: &fakeReEvaluator{err: event_handler.ErrEvaluationNotFound},
+			wantStatus:  http.StatusNotFound,
+		},
+		{
+			name:        "datastore not available",
+			method:      http.MethodPost,
+			body:        `{"project": "sockshop", "stage": "dev", "service": "carts", "keptnContext": "my-context"}`,
+			reEvaluator: &fakeReEvaluator{err: errors.New("connection refused")},
+			wantStatus:  http.StatusInternalServerError,
+		},
+		{
+			name:        "wrong method",
+			method:      http.MethodGet,
+			reEvaluator: &fakeReEvaluator{},
+			wantStatus:  http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, ReEvaluationPath, strings.NewReader(tt.body))
+
+			NewReEvaluationHandler(tt.reEvaluator)(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/keptn/keptn/lighthouse-service/event_handler"
+)
+
+// ScoreTrendPath is the path of the endpoint that returns the score trend of a service
+const ScoreTrendPath = "/v1/evaluation/trend"
+
+// ScoreTrendProvider calculates the score trend of a service
+type ScoreTrendProvider interface {
+	GetScoreTrend(request event_handler.ScoreTrendRequest) (*event_handler.ScoreTrend, error)
+}
+
+// NewScoreTrendHandler returns a handler that responds to GET requests with the score trend of the service given by the query parameters
+// project, stage and service. The optional parameters window and bucket are durations, e.g. 168h, and regressionEvaluations is the
+// number of latest evaluations checked for regressions
+func NewScoreTrendHandler(provider ScoreTrendProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Code: http.StatusMethodNotAllowed, Message: "method " + r.Method + " is not allowed"})
+			return
+		}
+
+		query := r.URL.Query()
+		request := event_handler.ScoreTrendRequest{
+			Project: query.Get("project"),
+			Stage:   query.Get("stage"),
+			Service: query.Get("service"),
+		}
+		var err error
+		if window := query.Get("window"); window != "" {
+			if request.Window, err = time.ParseDuration(window); err != nil {
+				writeJSON(w, http.StatusBadRequest, errorResponse{Code: http.StatusBadRequest, Message: "invalid window '" + window + "': must be a duration, e.g. 168h"})
+				return
+			}
+		}
+		if bucket := query.Get("bucket"); bucket != "" {
+			if request.Bucket, err = time.ParseDuration(bucket); err != nil {
+				writeJSON(w, http.StatusBadRequest, errorResponse{Code: http.StatusBadRequest, Message: "invalid bucket '" + bucket + "': must be a duration, e.g. 24h"})
+				return
+			}
+		}
+		if regressionEvaluations := query.Get("regressionEvaluations"); regressionEvaluations != "" {
+			if request.RegressionEvaluations, err = strconv.Atoi(regressionEvaluations); err != nil {
+				writeJSON(w, http.StatusBadRequest, errorResponse{Code: http.StatusBadRequest, Message: "invalid regressionEvaluations '" + regressionEvaluations + "': must be a number"})
+				return
+			}
+		}
+
+		trend, err := provider.GetScoreTrend(request)
+		if _, ok := err.(event_handler.InvalidScoreTrendRequestError); ok {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Code: http.StatusBadRequest, Message: err.Error()})
+			return
+		} else if err != nil {
+			writeJSON(w, http.StatusInternalServerError, errorResponse{Code: http.StatusInternalServerError, Message: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, trend)
+	}
+}
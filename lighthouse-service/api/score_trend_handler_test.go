@@ -0,0 +1,99 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/keptn/keptn/lighthouse-service/event_handler"
+)
+
+type fakeScoreTrendProvider struct {
+	request event_handler.ScoreTrendRequest
+	err     error
+}
+
+func (f *fakeScoreTrendProvider) GetScoreTrend(request event_handler.ScoreTrendRequest) (*event_handler.ScoreTrend, error) {
+	f.request = request
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &event_handler.ScoreTrend{Project: request.Project, Stage: request.Stage, Service: request.Service}, nil
+}
+
+func TestScoreTrendHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		query       string
+		provider    *fakeScoreTrendProvider
+		wantStatus  int
+		wantRequest event_handler.ScoreTrendRequest
+	}{
+		{
+			name:       "score trend",
+			method:     http.MethodGet,
+			query:      "?project=sockshop&stage=dev&service=carts&window=24h&bucket=1h&regressionEvaluations=5",
+			provider:   &fakeScoreTrendProvider{},
+			wantStatus: http.StatusOK,
+			wantRequest: event_handler.ScoreTrendRequest{
+				Project:               "sockshop",
+				Stage:                 "dev",
+				Service:               "carts",
+				Window:                24 * time.Hour,
+				Bucket:                time.Hour,
+				RegressionEvaluations: 5,
+			},
+		},
+		{
+			name:       "invalid window",
+			method:     http.MethodGet,
+			query:      "?project=sockshop&stage=dev&service=carts&window=7d",
+			provider:   &fakeScoreTrendProvider{},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid number of evaluations",
+			method:     http.MethodGet,
+			query:      "?project=sockshop&stage=dev&service=carts&regressionEvaluations=three",
+			provider:   &fakeScoreTrendProvider{},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid request",
+			method:     http.MethodGet,
+			provider:   &fakeScoreTrendProvider{err: event_handler.InvalidScoreTrendRequestError{}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "datastore not available",
+			method:     http.MethodGet,
+			query:      "?project=sockshop&stage=dev&service=carts",
+			provider:   &fakeScoreTrendProvider{err: errors.New("connection refused")},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodPost,
+			provider:   &fakeScoreTrendProvider{},
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, ScoreTrendPath+tt.query, nil)
+
+			NewScoreTrendHandler(tt.provider)(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, tt.wantRequest, tt.provider.request)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/keptn/keptn/lighthouse-service/pkg/scoring"
+)
+
+// SLOValidationPath is the path of the endpoint that validates SLO files
+const SLOValidationPath = "/v1/slo/validate"
+
+// maxSLOFileSize limits the size of SLO files accepted by the validation endpoint
+const maxSLOFileSize = 1 << 20
+
+// SLOValidationResult is the response of the SLO validation endpoint
+type SLOValidationResult struct {
+	Valid    bool                           `json:"valid"`
+	Problems []scoring.SLOValidationProblem `json:"problems"`
+}
+
+type errorResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// HandleSLOValidation validates the SLO file contained in the body of a POST request and responds with all problems found in it
+func HandleSLOValidation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Code: http.StatusMethodNotAllowed, Message: "method " + r.Method + " is not allowed"})
+		return
+	}
+
+	content, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxSLOFileSize))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Code: http.StatusBadRequest, Message: "could not read SLO file: " + err.Error()})
+		return
+	}
+	if len(content) == 0 {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Code: http.StatusBadRequest, Message: "request body must contain an SLO file"})
+		return
+	}
+
+	problems := scoring.ValidateSLOFile(content)
+	writeJSON(w, http.StatusOK, SLOValidationResult{
+		Valid:    len(problems) == 0,
+		Problems: problems,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
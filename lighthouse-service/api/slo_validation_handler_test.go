@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleSLOValidation(t *testing.T) {
+	tests := []struct {
+		name             string
+		method           string
+		body             string
+		wantStatus       int
+		wantValid        bool
+		wantProblemCount int
+	}{
+		{
+			name:   "valid SLO file",
+			method: http.MethodPost,
+			body: `---
+spec_version: '0.1.0'
+objectives:
+  - sli: response_time_p95
+    pass:
+      - criteria:
+          - "<=+10%"
+          - "<600"
+total_score:
+  pass: "90%"
+  warning: "75%"
+`,
+			wantStatus: http.StatusOK,
+			wantValid:  true,
+		},
+		{
+			name:   "SLO file with problems",
+			method: http.MethodPost,
+			body: `---
+spec_version: '0.1.0'
+objectives:
+  - sli: response_time_p95
+    pass:
+      - criteria:
+          - "<=+10%%"
+  - sli: response_time_p95
+total_score:
+  pass: "120%"
+`,
+			wantStatus:       http.StatusOK,
+			wantValid:        false,
+			wantProblemCount: 3,
+		},
+		{
+			name:       "empty body",
+			method:     http.MethodPost,
+			body:       "",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, SLOValidationPath, strings.NewReader(tt.body))
+
+			HandleSLOValidation(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			result := &SLOValidationResult{}
+			assert.Nil(t, json.Unmarshal(w.Body.Bytes(), result))
+			assert.Equal(t, tt.wantValid, result.Valid)
+			assert.Equal(t, tt.wantProblemCount, len(result.Problems))
+		})
+	}
+}
@@ -5,15 +5,18 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
-	"github.com/ghodss/yaml"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/keptn/go-utils/pkg/api/models"
 	utils "github.com/keptn/go-utils/pkg/api/utils"
 	keptn "github.com/keptn/go-utils/pkg/lib"
 	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
 	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+
+	"github.com/keptn/keptn/lighthouse-service/pkg/scoring"
 )
 
 const eventbroker = "EVENTBROKER"
@@ -39,12 +42,24 @@ var ErrStageNotFound = errors.New("stage not found")
 // ErrServiceNotFound godoc
 var ErrServiceNotFound = errors.New("service not found")
 
-func getSLOs(project string, stage string, service string) (*keptn.ServiceLevelObjectives, error) {
+// getSLOs retrieves the slo.yaml files of the project, stage and service, and merges them into the effective SLO.
+// An objective of the service level slo.yaml overrides the objective with the same SLI of the stage level one, which in turn overrides the project level one.
+// Besides the SLO, the content of the effective SLO file without defaulted values is returned (see https://github.com/keptn/keptn/issues/1495)
+func getSLOs(project string, stage string, service string) (*keptn.ServiceLevelObjectives, []byte, error) {
 	endpoint, err := keptncommon.GetServiceEndpoint("CONFIGURATION_SERVICE")
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	resourceHandler := utils.NewResourceHandler(endpoint.String())
+
+	sloFiles := [][]byte{}
+	if projectSLOFile, err := resourceHandler.GetProjectResource(project, "slo.yaml"); err == nil && projectSLOFile != nil && projectSLOFile.ResourceContent != "" {
+		sloFiles = append(sloFiles, []byte(projectSLOFile.ResourceContent))
+	}
+	if stageSLOFile, err := resourceHandler.GetStageResource(project, stage, "slo.yaml"); err == nil && stageSLOFile != nil && stageSLOFile.ResourceContent != "" {
+		sloFiles = append(sloFiles, []byte(stageSLOFile.ResourceContent))
+	}
+
 	sloFile, err := resourceHandler.GetServiceResource(project, stage, service, "slo.yaml")
 	if err != nil {
 		// check if service/stage/project actually exist
@@ -52,65 +67,31 @@ func getSLOs(project string, stage string, service string) (*keptn.ServiceLevelO
 		_, err2 := serviceHandler.GetService(project, stage, service)
 		if err2 != nil {
 			if strings.Contains(strings.ToLower(err2.Error()), "project not found") {
-				return nil, ErrProjectNotFound
+				return nil, nil, ErrProjectNotFound
 			} else if strings.Contains(strings.ToLower(err2.Error()), "stage not found") {
-				return nil, ErrStageNotFound
+				return nil, nil, ErrStageNotFound
 			} else if strings.Contains(strings.ToLower(err2.Error()), "service not found") {
-				return nil, ErrServiceNotFound
+				return nil, nil, ErrServiceNotFound
 			}
-		} else {
-			return nil, ErrSLOFileNotFound
 		}
 	}
-	if sloFile == nil || sloFile.ResourceContent == "" {
-		return nil, ErrSLOFileNotFound
+	if err == nil && sloFile != nil && sloFile.ResourceContent != "" {
+		sloFiles = append(sloFiles, []byte(sloFile.ResourceContent))
 	}
-
-	slo, err := parseSLO([]byte(sloFile.ResourceContent))
-
-	if err != nil {
-		return nil, errors.New("Could not parse SLO file for service " + service + " in stage " + stage + " in project " + project)
+	if len(sloFiles) == 0 {
+		return nil, nil, ErrSLOFileNotFound
 	}
 
-	return slo, nil
-}
-
-func parseSLO(input []byte) (*keptn.ServiceLevelObjectives, error) {
-	slo := &keptn.ServiceLevelObjectives{}
-	err := yaml.Unmarshal([]byte(input), &slo)
-
+	sloFileContent, err := scoring.MergeSLOFiles(sloFiles...)
 	if err != nil {
-		return nil, err
-	}
-
-	if slo.Comparison == nil {
-		slo.Comparison = &keptn.SLOComparison{
-			CompareWith:               "single_result",
-			IncludeResultWithScore:    "all",
-			NumberOfComparisonResults: 1,
-			AggregateFunction:         "avg",
-		}
+		return nil, nil, errors.New("Could not parse SLO file for service " + service + " in stage " + stage + " in project " + project)
 	}
-
-	if slo.Comparison != nil {
-		if slo.Comparison.IncludeResultWithScore == "" {
-			slo.Comparison.IncludeResultWithScore = "all"
-		}
-		if slo.Comparison.NumberOfComparisonResults == 0 {
-			slo.Comparison.NumberOfComparisonResults = 3
-		}
-		if slo.Comparison.AggregateFunction == "" {
-			slo.Comparison.AggregateFunction = "avg"
-		}
-	}
-
-	for _, objective := range slo.Objectives {
-		if objective.Weight == 0 {
-			objective.Weight = 1
-		}
+	slo, err := scoring.ParseSLO(sloFileContent)
+	if err != nil {
+		return nil, nil, errors.New("Could not parse SLO file for service " + service + " in stage " + stage + " in project " + project)
 	}
 
-	return slo, nil
+	return slo, sloFileContent, nil
 }
 
 func sendEvent(shkeptncontext string, triggeredID, eventType string, keptnHandler *keptnv2.Keptn, data interface{}) error {
@@ -130,6 +111,26 @@ func sendEvent(shkeptncontext string, triggeredID, eventType string, keptnHandle
 	return keptnHandler.SendCloudEvent(event)
 }
 
+// toCloudEvent converts an event stored in mongodb-datastore into the cloudevent it has been received as
+func toCloudEvent(event *models.KeptnContextExtendedCE) cloudevents.Event {
+	ce := cloudevents.NewEvent()
+	ce.SetID(event.ID)
+	if event.Type != nil {
+		ce.SetType(*event.Type)
+	}
+	if event.Source != nil {
+		ce.SetSource(*event.Source)
+	}
+	ce.SetTime(time.Time(event.Time))
+	ce.SetDataContentType(cloudevents.ApplicationJSON)
+	ce.SetExtension("shkeptncontext", event.Shkeptncontext)
+	if event.Triggeredid != "" {
+		ce.SetExtension("triggeredid", event.Triggeredid)
+	}
+	ce.SetData(cloudevents.ApplicationJSON, event.Data)
+	return ce
+}
+
 func sendErroredFinishedEventWithMessage(shkeptncontext, triggeredID, message, sloFileContent string, keptnHandler *keptnv2.Keptn, incoming *keptnv2.GetSLIFinishedEventData) error {
 	data := keptnv2.EvaluationFinishedEventData{
 		EventData: keptnv2.EventData{
@@ -6,20 +6,17 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"math"
 	"net/http"
-	"regexp"
-	"sort"
-	"strconv"
+	"net/url"
 	"strings"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
-	"github.com/ghodss/yaml"
 	"github.com/mitchellh/mapstructure"
 
 	keptnapi "github.com/keptn/go-utils/pkg/api/utils"
-	keptn "github.com/keptn/go-utils/pkg/lib"
 	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+
+	"github.com/keptn/keptn/lighthouse-service/pkg/scoring"
 )
 
 type datastoreResult struct {
@@ -32,14 +29,6 @@ type datastoreResult struct {
 	}
 }
 
-type criteriaObject struct {
-	Operator        string
-	Value           float64
-	CheckPercentage bool
-	IsComparison    bool
-	CheckIncrease   bool
-}
-
 type EvaluateSLIHandler struct {
 	Event        cloudevents.Event
 	HTTPClient   *http.Client
@@ -53,6 +42,18 @@ func (eh *EvaluateSLIHandler) HandleEvent() error {
 	eh.Event.Context.ExtensionAs("shkeptncontext", &shkeptncontext)
 	err := eh.Event.DataAs(&e)
 
+	if err == nil {
+		// the SLIs of an evaluation can be retrieved by several SLI providers, whose results are merged before the evaluation
+		var getSLITriggeredID string
+		_ = eh.Event.Context.ExtensionAs("triggeredid", &getSLITriggeredID)
+		mergedResult, complete := pendingSLIRetrievals.addResult(getSLITriggeredID, e)
+		if !complete {
+			eh.KeptnHandler.Logger.Debug("Waiting for the results of further SLI providers before evaluating event " + eh.Event.ID())
+			return nil
+		}
+		e = mergedResult
+	}
+
 	triggeredEvents, err2 := eh.KeptnHandler.EventHandler.GetEvents(&keptnapi.EventFilter{
 		Project:      e.Project,
 		Stage:        e.Stage,
@@ -78,9 +79,41 @@ func (eh *EvaluateSLIHandler) HandleEvent() error {
 		return sendErroredFinishedEventWithMessage(shkeptncontext, "", msg, "", eh.KeptnHandler, e)
 	}
 
+	// get-sli.finished events can be delivered more than once, e.g., due to the polling of the distributor, but each evaluation.triggered event is evaluated only once
+	if !processedEvaluations.reserve(triggeredID) {
+		eh.KeptnHandler.Logger.Info("Evaluation of triggered event " + triggeredID + " has already been performed or is in progress. Ignoring event " + eh.Event.ID())
+		return nil
+	}
+	if eh.isEvaluated(e, triggeredID) {
+		processedEvaluations.complete(triggeredID)
+		eh.KeptnHandler.Logger.Info("Evaluation of triggered event " + triggeredID + " has already been performed. Ignoring event " + eh.Event.ID())
+		return nil
+	}
+
+	if err := eh.evaluate(shkeptncontext, triggeredID, e); err != nil {
+		// the evaluation.finished event has not been sent, so the evaluation can be performed again
+		processedEvaluations.release(triggeredID)
+		return err
+	}
+	processedEvaluations.complete(triggeredID)
+	return nil
+}
+
+// isEvaluated checks if mongodb-datastore already contains an evaluation.finished event for the given evaluation.triggered event
+func (eh *EvaluateSLIHandler) isEvaluated(e *keptnv2.GetSLIFinishedEventData, triggeredID string) bool {
+	filter := "data.project:" + e.Project + "%20AND%20triggeredid:" + url.QueryEscape(triggeredID)
+	evaluations, _, err := queryEvaluations(eh.HTTPClient, filter, 1)
+	if err != nil {
+		eh.KeptnHandler.Logger.Debug("Could not check for an existing evaluation of triggered event " + triggeredID + ": " + err.Error())
+		return false
+	}
+	return len(evaluations) > 0
+}
+
+func (eh *EvaluateSLIHandler) evaluate(shkeptncontext, triggeredID string, e *keptnv2.GetSLIFinishedEventData) error {
 	eh.KeptnHandler.Logger.Debug("Start to evaluate SLIs")
 	// compare the results based on the evaluation strategy
-	sloConfig, err := getSLOs(e.Project, e.Stage, e.Service)
+	sloConfig, sloFileContent, err := getSLOs(e.Project, e.Stage, e.Service)
 	if err != nil {
 		if err == ErrSLOFileNotFound {
 			evaluationDetails := keptnv2.EvaluationDetails{
@@ -107,25 +140,30 @@ func (eh *EvaluateSLIHandler) HandleEvent() error {
 		return sendErroredFinishedEventWithMessage(shkeptncontext, triggeredID, err.Error(), "", eh.KeptnHandler, e)
 	}
 
-	var sloFileContent []byte
-	// get the slo.yaml as a plain file to avoid confusion due to defaulted values (see https://github.com/keptn/keptn/issues/1495)
-	sloFileContentTmp, err := eh.KeptnHandler.GetKeptnResource("slo.yaml")
+	if err := scoring.ValidateSLOCriteria(sloConfig); err != nil {
+		msg := "Invalid SLO file: " + err.Error()
+		eh.KeptnHandler.Logger.Error(msg)
+		return sendErroredFinishedEventWithMessage(shkeptncontext, triggeredID, msg, string(sloFileContent), eh.KeptnHandler, e)
+	}
+
+	baseline, err := scoring.GetBaseline(sloFileContent, e.Labels)
 	if err != nil {
-		eh.KeptnHandler.Logger.Debug("Could not fetch slo.yaml from service repository: " + err.Error() + ". Will append internally used SLO object to evaluation.finished event.")
-		sloFileContent, _ = yaml.Marshal(sloConfig)
-	} else {
-		sloFileContent = []byte(sloFileContentTmp)
+		msg := "Invalid SLO file: " + err.Error()
+		eh.KeptnHandler.Logger.Error(msg)
+		return sendErroredFinishedEventWithMessage(shkeptncontext, triggeredID, msg, string(sloFileContent), eh.KeptnHandler, e)
 	}
 
 	// get results of previous evaluations from data store (mongodb-datastore)
-	numberOfPreviousResults := 3
-	if sloConfig.Comparison.CompareWith == "single_result" {
-		numberOfPreviousResults = 1
-	} else if sloConfig.Comparison.CompareWith == "several_results" {
-		numberOfPreviousResults = sloConfig.Comparison.NumberOfComparisonResults
+	var previousEvaluationEvents []*keptnv2.EvaluationFinishedEventData
+	var comparisonEventIDs []string
+	if baseline != nil {
+		// a pinned baseline replaces the last N evaluations as the reference of the comparison criteria
+		eh.KeptnHandler.Logger.Debug("Comparing with baseline: " + baseline.String())
+		previousEvaluationEvents, comparisonEventIDs, err = eh.getBaselineEvaluation(e, baseline, sloConfig.Comparison.IncludeResultWithScore)
+	} else {
+		numberOfPreviousResults := scoring.GetNumberOfComparisonResults(sloConfig)
+		previousEvaluationEvents, comparisonEventIDs, err = eh.getPreviousEvaluations(e, numberOfPreviousResults, sloConfig.Comparison.IncludeResultWithScore)
 	}
-
-	previousEvaluationEvents, comparisonEventIDs, err := eh.getPreviousEvaluations(e, numberOfPreviousResults, sloConfig.Comparison.IncludeResultWithScore)
 	if err != nil {
 		return sendErroredFinishedEventWithMessage(shkeptncontext, triggeredID, err.Error(), string(sloFileContent), eh.KeptnHandler, e)
 	}
@@ -137,15 +175,13 @@ func (eh *EvaluateSLIHandler) HandleEvent() error {
 		filteredPreviousEvaluationEvents = append(filteredPreviousEvaluationEvents, val)
 	}
 
-	evaluationResult, maximumAchievableScore, keySLIFailed := evaluateObjectives(e, sloConfig, filteredPreviousEvaluationEvents)
-	evaluationResult.Labels = e.Labels
-	evaluationResult.Evaluation.ComparedEvents = comparisonEventIDs
-
-	// calculate the total score
-	err = calculateScore(maximumAchievableScore, evaluationResult, sloConfig, keySLIFailed)
+	// calculate the results of the objectives and the total score
+	evaluationResult, err := scoring.Evaluate(e, sloConfig, filteredPreviousEvaluationEvents)
 	if err != nil {
 		return sendErroredFinishedEventWithMessage(shkeptncontext, triggeredID, err.Error(), string(sloFileContent), eh.KeptnHandler, e)
 	}
+	evaluationResult.Labels = e.Labels
+	evaluationResult.Evaluation.ComparedEvents = comparisonEventIDs
 	eh.KeptnHandler.Logger.Debug("Evaluation result: " + string(evaluationResult.Result))
 
 	evaluationResult.Evaluation.SLOFileContent = base64.StdEncoding.EncodeToString(sloFileContent)
@@ -161,399 +197,49 @@ func (eh *EvaluateSLIHandler) HandleEvent() error {
 		}
 	}
 
-	return sendEvent(shkeptncontext, triggeredEvents[0].ID, keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName), eh.KeptnHandler, evaluationResult)
-}
-
-func evaluateObjectives(e *keptnv2.GetSLIFinishedEventData, sloConfig *keptn.ServiceLevelObjectives, previousEvaluationEvents []*keptnv2.EvaluationFinishedEventData) (*keptnv2.EvaluationFinishedEventData, float64, bool) {
-	evaluationResult := &keptnv2.EvaluationFinishedEventData{
-		EventData: keptnv2.EventData{
-			Status:  "",
-			Project: e.Project,
-			Service: e.Service,
-			Stage:   e.Stage,
-		},
-		Evaluation: keptnv2.EvaluationDetails{
-			TimeStart: e.GetSLI.Start,
-			TimeEnd:   e.GetSLI.End,
-		},
-	}
-	var sliEvaluationResults []*keptnv2.SLIEvaluationResult
-	maximumAchievableScore := 0.0
-	keySLIFailed := false
-	for _, objective := range sloConfig.Objectives {
-		// only consider the SLI for the total score if pass criteria have been included
-		if len(objective.Pass) > 0 {
-			maximumAchievableScore += float64(objective.Weight)
-		}
-		sliEvaluationResult := &keptnv2.SLIEvaluationResult{}
-		result := getSLIResult(e.GetSLI.IndicatorValues, objective.SLI)
-
-		if result == nil {
-			// no result available => fail the objective
-			sliEvaluationResult.Value = &keptnv2.SLIResult{
-				Metric:  objective.SLI,
-				Success: false,
-				Message: "no value received from SLI provider",
-			}
-			sliEvaluationResult.Status = "fail"
-			sliEvaluationResult.Score = 0
-			continue
-		}
-		sliEvaluationResult.Value = (*keptnv2.SLIResult)(result)
-
-		// gather the previous results for the current SLI
-		var previousSLIResults []*keptnv2.SLIEvaluationResult
-
-		if previousEvaluationEvents != nil && len(previousEvaluationEvents) > 0 {
-			for _, event := range previousEvaluationEvents {
-				for _, prevSLIResult := range event.Evaluation.IndicatorResults {
-					if strings.Compare(prevSLIResult.Value.Metric, objective.SLI) == 0 {
-						previousSLIResults = append(previousSLIResults, prevSLIResult)
-					}
-				}
-			}
-		}
-
-		var passTargets []*keptnv2.SLITarget
-		var warningTargets []*keptnv2.SLITarget
-		isPassed := true
-		isWarning := true
-		if objective.Pass != nil && len(objective.Pass) > 0 {
-			isPassed, passTargets, _ = evaluateOrCombinedCriteria(sliEvaluationResult.Value, objective.Pass, previousSLIResults, sloConfig.Comparison)
-			if isPassed {
-				sliEvaluationResult.Score = float64(objective.Weight)
-				sliEvaluationResult.Status = "pass"
-			}
-		} else {
-			sliEvaluationResult.Status = "info"
-		}
-
-		if !isPassed {
-			if objective.Warning != nil && len(objective.Warning) > 0 {
-				isWarning, warningTargets, _ = evaluateOrCombinedCriteria(sliEvaluationResult.Value, objective.Warning, previousSLIResults, sloConfig.Comparison)
-				if isWarning {
-					sliEvaluationResult.Score = 0.5 * float64(objective.Weight)
-					sliEvaluationResult.Status = "warning"
-				}
-			} else {
-				isWarning = false
-			}
-		}
-
-		sliEvaluationResult.Targets = append(warningTargets, passTargets...)
-
-		if !isPassed && !isWarning {
-			if objective.KeySLI {
-				keySLIFailed = true
-			}
-			sliEvaluationResult.Status = "fail"
-			sliEvaluationResult.Score = 0
-		}
-
-		sliEvaluationResults = append(sliEvaluationResults, sliEvaluationResult)
-	}
-	evaluationResult.Evaluation.IndicatorResults = sliEvaluationResults
-
-	return evaluationResult, maximumAchievableScore, keySLIFailed
-}
-
-func calculateScore(maximumAchievableScore float64, evaluationResult *keptnv2.EvaluationFinishedEventData, sloConfig *keptn.ServiceLevelObjectives, keySLIFailed bool) error {
-	if maximumAchievableScore == 0 {
-		evaluationResult.Evaluation.Result = "pass"
-		evaluationResult.Result = keptnv2.ResultPass
-		evaluationResult.Status = keptnv2.StatusSucceeded
-		evaluationResult.Evaluation.Score = 100.0
-		return nil
-	}
-	totalScore := 0.0
-	for _, result := range evaluationResult.Evaluation.IndicatorResults {
-		totalScore += result.Score
-	}
-	achievedPercentage := 100.0 * (totalScore / maximumAchievableScore)
-	evaluationResult.Evaluation.Score = achievedPercentage
-	if sloConfig.TotalScore == nil || sloConfig.TotalScore.Pass == "" {
-		return errors.New("no target score defined")
-	}
-	passTargetPercentage, err := strconv.ParseFloat(strings.TrimSuffix(sloConfig.TotalScore.Pass, "%"), 64)
-	if err != nil {
-		return errors.New("could not parse pass target percentage")
-	}
-	if achievedPercentage >= passTargetPercentage && !keySLIFailed {
-		evaluationResult.Evaluation.Result = "pass"
-		evaluationResult.Result = keptnv2.ResultPass
-		evaluationResult.Status = keptnv2.StatusSucceeded
-	} else if sloConfig.TotalScore.Warning != "" && !keySLIFailed {
-		warnTargetPercentage, err := strconv.ParseFloat(strings.TrimSuffix(sloConfig.TotalScore.Warning, "%"), 64)
-
-		if err != nil {
-			return errors.New("could not parse warning target percentage")
-		}
-		if achievedPercentage >= warnTargetPercentage {
-			evaluationResult.Evaluation.Result = "warning"
-			evaluationResult.Result = keptnv2.ResultWarning
-			evaluationResult.Status = keptnv2.StatusSucceeded
-		} else {
-			evaluationResult.Evaluation.Result = "fail"
-			evaluationResult.Result = keptnv2.ResultFailed
-			evaluationResult.Status = keptnv2.StatusSucceeded
-		}
-	} else {
-		evaluationResult.Evaluation.Result = "fail"
-		evaluationResult.Result = keptnv2.ResultFailed
-		evaluationResult.Status = keptnv2.StatusSucceeded
-	}
-
-	return nil
-}
-
-func getSLIResult(results []*keptnv2.SLIResult, sli string) *keptnv2.SLIResult {
-	for _, sliResult := range results {
-		if sliResult.Metric == sli {
-			return sliResult
-		}
-	}
-
-	return nil
-}
-
-func evaluateOrCombinedCriteria(result *keptnv2.SLIResult, sloCriteria []*keptn.SLOCriteria, previousResults []*keptnv2.SLIEvaluationResult, comparison *keptn.SLOComparison) (bool, []*keptnv2.SLITarget, error) {
-	var satisfied bool
-	satisfied = false
-	var sliTargets []*keptnv2.SLITarget
-	for _, crit := range sloCriteria {
-		criteriaSatisfied, evaluatedTargets, _ := evaluateCriteriaSet(result, crit, previousResults, comparison)
-		if criteriaSatisfied {
-			// one matching criteria set is sufficient to satisfy the evaluation. Other criteria sets are evaluated nevertheless, to get potential violations
-			satisfied = true
-		}
-		for _, evaluatedTarget := range evaluatedTargets {
-			sliTargets = append(sliTargets, evaluatedTarget)
-		}
-	}
-
-	return satisfied, sliTargets, nil
-}
-
-// evaluateCriteria evaluates a set of criteria strings. Per definition, all criteria clauses within a SLOCriteria object have to be fulfilled to satisfy the SLOCriteria
-func evaluateCriteriaSet(result *keptnv2.SLIResult, sloCriteria *keptn.SLOCriteria, previousResults []*keptnv2.SLIEvaluationResult, comparison *keptn.SLOComparison) (bool, []*keptnv2.SLITarget, error) {
-	satisfied := true
-	var sliTargets []*keptnv2.SLITarget
-	for _, criteria := range sloCriteria.Criteria {
-		target := &keptnv2.SLITarget{
-			Criteria: criteria,
-		}
-		criteriaSatisfied, _ := evaluateSingleCriteria(result, criteria, previousResults, comparison, target)
-		if !criteriaSatisfied {
-			target.Violated = true
-			satisfied = false
-		} else {
-			target.Violated = false
-		}
-		sliTargets = append(sliTargets, target)
-	}
-
-	return satisfied, sliTargets, nil
-}
-
-func evaluateSingleCriteria(sliResult *keptnv2.SLIResult, criteria string, previousResults []*keptnv2.SLIEvaluationResult, comparison *keptn.SLOComparison, violation *keptnv2.SLITarget) (bool, error) {
-	if !sliResult.Success {
-		return false, errors.New("cannot evaluate invalid SLI result")
-	}
-
-	co, err := parseCriteriaString(criteria)
-
-	if err != nil {
-		return false, err
-	}
-
-	if !co.IsComparison {
-		// do a fixed threshold comparison
-		return evaluateFixedThreshold(sliResult, co, violation)
-	}
-
-	return evaluateComparison(sliResult, co, previousResults, comparison, violation)
-}
-
-func evaluateComparison(sliResult *keptnv2.SLIResult, co *criteriaObject, previousResults []*keptnv2.SLIEvaluationResult, comparison *keptn.SLOComparison, violation *keptnv2.SLITarget) (bool, error) {
-	// aggregate previous results
-	var aggregatedValue float64
-	var targetValue float64
-	var previousValues []float64
-
-	if len(previousResults) == 0 {
-		// if no comparison values are available, the evaluation passes
-		return true, nil
-	}
-
-	for _, val := range previousResults {
-		if val.Value.Success == true {
-			// always include
-			previousValues = append(previousValues, val.Value.Value)
-		}
-	}
-
-	if len(previousValues) == 0 {
-		// if no comparison values are available, the evaluation passes
-		return true, nil
-	}
-
-	// aggregate the previous values based on the passed aggregation function
-	switch comparison.AggregateFunction {
-	case "avg":
-		aggregatedValue = calculateAverage(previousValues)
-	case "p50":
-		aggregatedValue = calculatePercentile(sort.Float64Slice(previousValues), 0.5)
-	case "p90":
-		aggregatedValue = calculatePercentile(sort.Float64Slice(previousValues), 0.9)
-	case "p95":
-		aggregatedValue = calculatePercentile(sort.Float64Slice(previousValues), 0.95)
-	default:
-		break
-	}
-
-	// calculate the comparison value
-	if co.CheckPercentage && co.CheckIncrease {
-		targetValue = (aggregatedValue * (100.0 + co.Value)) / 100.0
-	} else if co.CheckPercentage && !co.CheckIncrease {
-		targetValue = (aggregatedValue * (100.0 - co.Value)) / 100.0
-	} else if !co.CheckPercentage && co.CheckIncrease {
-		targetValue = aggregatedValue + co.Value
-	} else if !co.CheckPercentage && !co.CheckIncrease {
-		targetValue = aggregatedValue - co.Value
-	}
-	violation.TargetValue = targetValue
-	// compare!
-	return evaluateValue(sliResult.Value, targetValue, co.Operator)
-}
-
-func calculateAverage(values []float64) float64 {
-	sum := 0.0
-
-	for _, value := range values {
-		sum += value
-	}
-	if len(values) > 0 {
-		return sum / float64(len(values))
-	}
-
-	return 0.0
+	return sendEvent(shkeptncontext, triggeredID, keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName), eh.KeptnHandler, evaluationResult)
 }
 
-func calculatePercentile(values sort.Float64Slice, perc float64) float64 {
-	if len(values) == 0 {
-		return 0.0
-	}
-	ps := []float64{perc}
-
-	scores := make([]float64, len(ps))
-	size := len(values)
-	if size > 0 {
-		sort.Sort(values)
-		for i, p := range ps {
-			pos := p * float64(size+1) //ALTERNATIVELY, DROP THE +1
-			if pos < 1.0 {
-				scores[i] = float64(values[0])
-			} else if pos >= float64(size) {
-				scores[i] = float64(values[size-1])
-			} else {
-				lower := float64(values[int(pos)-1])
-				upper := float64(values[int(pos)])
-				scores[i] = lower + (pos-math.Floor(pos))*(upper-lower)
-			}
-		}
-	}
-
-	return scores[0]
+// gets previous evaluation.finished events from mongodb-datastore
+func (eh *EvaluateSLIHandler) getPreviousEvaluations(e *keptnv2.GetSLIFinishedEventData, numberOfPreviousResults int, includeResult string) ([]*keptnv2.EvaluationFinishedEventData, []string, error) {
+	return queryEvaluations(eh.HTTPClient, getEvaluationFilter(e, includeResult), numberOfPreviousResults)
 }
 
-func evaluateFixedThreshold(sliResult *keptnv2.SLIResult, co *criteriaObject, violation *keptnv2.SLITarget) (bool, error) {
-	violation.TargetValue = co.Value
-	return evaluateValue(sliResult.Value, co.Value, co.Operator)
+// getBaselineEvaluation retrieves the evaluation pinned by a baseline. The include_result_with_score setting of the SLO only applies to baselines that refer to a label,
+// since a Keptn context or an evaluation ID pins the evaluation explicitly
+func (eh *EvaluateSLIHandler) getBaselineEvaluation(e *keptnv2.GetSLIFinishedEventData, baseline *scoring.Baseline, includeResult string) ([]*keptnv2.EvaluationFinishedEventData, []string, error) {
+	return getBaselineEvaluation(eh.HTTPClient, e, baseline, includeResult)
 }
 
-func evaluateValue(measured float64, expected float64, operator string) (bool, error) {
-	switch operator {
-	case "<":
-		return measured < expected, nil
-	case "<=":
-		return measured <= expected, nil
-	case "=":
-		return measured == expected, nil
-	case ">=":
-		return measured >= expected, nil
-	case ">":
-		return measured > expected, nil
+func getBaselineEvaluation(httpClient *http.Client, e *keptnv2.GetSLIFinishedEventData, baseline *scoring.Baseline, includeResult string) ([]*keptnv2.EvaluationFinishedEventData, []string, error) {
+	var filter string
+	switch {
+	case baseline.KeptnContext != "":
+		filter = getEvaluationFilter(e, "all") + "%20AND%20shkeptncontext:" + url.QueryEscape(baseline.KeptnContext)
+	case baseline.EvaluationID != "":
+		filter = getEvaluationFilter(e, "all") + "%20AND%20id:" + url.QueryEscape(baseline.EvaluationID)
 	default:
-		return false, errors.New("no operator set")
-	}
-}
-
-func parseCriteriaString(criteria string) (*criteriaObject, error) {
-	// example values: <+15%, <500, >-8%, =0
-	// possible operators: <, <=, =, >, >=
-	// regex: ^([<|<=|=|>|>=]{1,2})([+|-]{0,1}\\d*\.?\d*)([%]{0,1})
-	regex := `^([<|<=|=|>|>=]{1,2})([+|-]{0,1}\d*\.?\d*)([%]{0,1})`
-	var re *regexp.Regexp
-	re = regexp.MustCompile(regex)
-
-	// remove whitespaces
-	criteria = strings.Replace(criteria, " ", "", -1)
-
-	if !re.MatchString(criteria) {
-		return nil, errors.New("invalid criteria string")
-	}
-
-	c := &criteriaObject{}
-
-	operators := []string{"<=", "<", "=", ">=", ">"}
-
-	for _, operator := range operators {
-		if strings.HasPrefix(criteria, operator) {
-			c.Operator = operator
-			criteria = strings.TrimPrefix(criteria, operator)
-			break
+		key, value, err := baseline.ParseLabel()
+		if err != nil {
+			return nil, nil, err
 		}
+		filter = getEvaluationFilter(e, includeResult) + "%20AND%20data.labels." + url.QueryEscape(key) + ":" + url.QueryEscape(value)
 	}
 
-	if strings.HasSuffix(criteria, "%") {
-		c.CheckPercentage = true
-		c.IsComparison = true // Issue #1498: criteria containing '%' is always a comparison
-		c.CheckIncrease = true
-		criteria = strings.TrimSuffix(criteria, "%")
-	}
-
-	if strings.HasPrefix(criteria, "-") {
-		c.IsComparison = true
-		c.CheckIncrease = false
-		criteria = strings.TrimPrefix(criteria, "-")
-	} else if strings.HasPrefix(criteria, "+") {
-		c.IsComparison = true
-		c.CheckIncrease = true
-		criteria = strings.TrimPrefix(criteria, "+")
-	}
-
-	floatValue, err := strconv.ParseFloat(criteria, 64)
+	evaluations, eventIDs, err := queryEvaluations(httpClient, filter, 1)
 	if err != nil {
-		return nil, errors.New("could not parse criteria target value")
+		return nil, nil, err
 	}
-	c.Value = floatValue
-
-	return c, nil
+	if len(evaluations) == 0 && baseline.Label == "" {
+		return nil, nil, fmt.Errorf("could not find baseline: no %s", baseline.String())
+	}
+	return evaluations, eventIDs, nil
 }
 
-// gets previous evaluation.finished events from mongodb-datastore
-func (eh *EvaluateSLIHandler) getPreviousEvaluations(e *keptnv2.GetSLIFinishedEventData, numberOfPreviousResults int, includeResult string) ([]*keptnv2.EvaluationFinishedEventData, []string, error) {
-	var evaluationDoneEvents []*keptnv2.EvaluationFinishedEventData
-	var eventIDs []string
-
-	// previous results are fetched from mongodb datastore with source=lighthouse-service
-	queryString := fmt.Sprintf("source=%s&limit=%d&excludeInvalidated=true&",
-		"lighthouse-service", numberOfPreviousResults)
-
-	includeResult = strings.ToLower(includeResult)
-
-	filter := "filter=data.project:" + e.Project + "%20AND%20data.stage:" + e.Stage + "%20AND%20data.service:" + e.Service
-	switch includeResult {
+// getEvaluationFilter returns the filter for evaluations of the same service that match the include_result_with_score setting of an SLO
+func getEvaluationFilter(e *keptnv2.GetSLIFinishedEventData, includeResult string) string {
+	filter := "data.project:" + e.Project + "%20AND%20data.stage:" + e.Stage + "%20AND%20data.service:" + e.Service
+	switch strings.ToLower(includeResult) {
 	case "pass":
 		filter = filter + "%20AND%20data.result:pass"
 		break
@@ -563,12 +249,21 @@ func (eh *EvaluateSLIHandler) getPreviousEvaluations(e *keptnv2.GetSLIFinishedEv
 	default:
 		break
 	}
+	return filter
+}
 
-	queryString = queryString + filter
+// queryEvaluations retrieves the latest evaluation.finished events of the lighthouse-service that match a filter from mongodb-datastore
+func queryEvaluations(httpClient *http.Client, filter string, limit int) ([]*keptnv2.EvaluationFinishedEventData, []string, error) {
+	var evaluationDoneEvents []*keptnv2.EvaluationFinishedEventData
+	var eventIDs []string
+
+	// previous results are fetched from mongodb datastore with source=lighthouse-service
+	queryString := fmt.Sprintf("source=%s&limit=%d&excludeInvalidated=true&filter=%s",
+		"lighthouse-service", limit, filter)
 
 	req, err := http.NewRequest("GET", getDatastoreURL()+"/event/type/"+keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName)+"?"+queryString, nil)
 	req.Header.Set("Content-Type", "application/json")
-	resp, err := eh.HTTPClient.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -598,7 +293,7 @@ func (eh *EvaluateSLIHandler) getPreviousEvaluations(e *keptnv2.GetSLIFinishedEv
 		}
 		evaluationDoneEvents = append(evaluationDoneEvents, &evaluationDoneEvent)
 		eventIDs = append(eventIDs, event.ID)
-		if len(evaluationDoneEvents) == numberOfPreviousResults {
+		if len(evaluationDoneEvents) == limit {
 			return evaluationDoneEvents, eventIDs, nil
 		}
 	}
@@ -0,0 +1,64 @@
+package event_handler
+
+import (
+	"sync"
+	"time"
+)
+
+// evaluationCacheTTL is how long an evaluation is remembered after it has been performed
+const evaluationCacheTTL = 24 * time.Hour
+
+var processedEvaluations = newEvaluationCache(evaluationCacheTTL)
+
+// evaluationCache keeps track of the evaluation.triggered events that are being or have been evaluated, to ignore redelivered get-sli.finished events
+type evaluationCache struct {
+	mutex sync.Mutex
+	// evaluations maps the ID of an evaluation.triggered event to the time its evaluation has been completed. The zero time marks an evaluation in progress
+	evaluations map[string]time.Time
+	ttl         time.Duration
+	now         func() time.Time
+}
+
+func newEvaluationCache(ttl time.Duration) *evaluationCache {
+	return &evaluationCache{
+		evaluations: map[string]time.Time{},
+		ttl:         ttl,
+		now:         time.Now,
+	}
+}
+
+// reserve marks the evaluation of a triggered event as in progress. It returns false if the evaluation is already in progress or has been completed
+func (c *evaluationCache) reserve(triggeredID string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.removeExpired()
+	if _, ok := c.evaluations[triggeredID]; ok {
+		return false
+	}
+	c.evaluations[triggeredID] = time.Time{}
+	return true
+}
+
+// complete marks the evaluation of a triggered event as completed
+func (c *evaluationCache) complete(triggeredID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.evaluations[triggeredID] = c.now()
+}
+
+// release removes a triggered event whose evaluation has not been completed, so it can be evaluated again
+func (c *evaluationCache) release(triggeredID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.evaluations, triggeredID)
+}
+
+func (c *evaluationCache) removeExpired() {
+	now := c.now()
+	for triggeredID, completedAt := range c.evaluations {
+		if !completedAt.IsZero() && now.Sub(completedAt) > c.ttl {
+			delete(c.evaluations, triggeredID)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package event_handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluationCache(t *testing.T) {
+	now := time.Date(2021, 2, 1, 12, 0, 0, 0, time.UTC)
+	cache := newEvaluationCache(time.Hour)
+	cache.now = func() time.Time { return now }
+
+	assert.True(t, cache.reserve("triggered-1"))
+	// a redelivered event is ignored while the evaluation is in progress
+	assert.False(t, cache.reserve("triggered-1"))
+
+	// a released evaluation can be performed again
+	cache.release("triggered-1")
+	assert.True(t, cache.reserve("triggered-1"))
+
+	// a completed evaluation is ignored until it expires
+	cache.complete("triggered-1")
+	now = now.Add(59 * time.Minute)
+	assert.False(t, cache.reserve("triggered-1"))
+	now = now.Add(2 * time.Minute)
+	assert.True(t, cache.reserve("triggered-1"))
+
+	// evaluations in progress do not expire
+	assert.True(t, cache.reserve("triggered-2"))
+	now = now.Add(2 * time.Hour)
+	assert.False(t, cache.reserve("triggered-2"))
+}
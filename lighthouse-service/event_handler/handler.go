@@ -16,17 +16,16 @@ type EvaluationEventHandler interface {
 
 func NewEventHandler(event cloudevents.Event, logger *keptncommon.Logger) (EvaluationEventHandler, error) {
 	logger.Debug("Received event: " + event.Type())
-	serviceName := "lighthouse-service"
 
-	keptnHandler, err := keptnv2.NewKeptn(&event, keptncommon.KeptnOpts{
-		LoggingOptions: &keptncommon.LoggingOpts{ServiceName: &serviceName},
-	})
+	keptnHandler, err := newKeptnHandler(event)
 	if err != nil {
 		return nil, err
 	}
 	switch event.Type() {
 	case keptnv2.GetTriggeredEventType(keptnv2.EvaluationTaskName):
 		return &StartEvaluationHandler{Event: event, KeptnHandler: keptnHandler, SLIProviderConfig: K8sSLIProviderConfig{}}, nil
+	case keptnv2.GetTriggeredEventType(keptnv2.GetSLITaskName):
+		return &StaticSLIHandler{Event: event, KeptnHandler: keptnHandler}, nil
 	case keptnv2.GetFinishedEventType(keptnv2.GetSLITaskName):
 		return &EvaluateSLIHandler{Event: event, HTTPClient: &http.Client{}, KeptnHandler: keptnHandler}, nil
 	case keptn.ConfigureMonitoringEventType:
@@ -36,3 +35,10 @@ func NewEventHandler(event cloudevents.Event, logger *keptncommon.Logger) (Evalu
 		return nil, nil
 	}
 }
+
+func newKeptnHandler(event cloudevents.Event) (*keptnv2.Keptn, error) {
+	serviceName := "lighthouse-service"
+	return keptnv2.NewKeptn(&event, keptncommon.KeptnOpts{
+		LoggingOptions: &keptncommon.LoggingOpts{ServiceName: &serviceName},
+	})
+}
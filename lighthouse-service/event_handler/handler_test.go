@@ -45,6 +45,19 @@ func TestNewEventHandler(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "get-sli.triggered -> static SLI handler",
+			args: args{
+				event:  incomingEvent,
+				logger: nil,
+			},
+			eventType: keptnv2.GetTriggeredEventType(keptnv2.GetSLITaskName),
+			want: &StaticSLIHandler{
+				Event:        incomingEvent,
+				KeptnHandler: keptnHandler,
+			},
+			wantErr: false,
+		},
 		{
 			name: "get-sli.done -> evaluate-sli handler",
 			args: args{
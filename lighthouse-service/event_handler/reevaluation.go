@@ -0,0 +1,154 @@
+package event_handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+
+	"github.com/keptn/keptn/lighthouse-service/pkg/scoring"
+)
+
+// ErrEvaluationNotFound is returned if the evaluation to be re-evaluated does not exist
+var ErrEvaluationNotFound = errors.New("evaluation not found")
+
+// InvalidReEvaluationRequestError is returned if a re-evaluation request is incomplete or contains an invalid SLO file
+type InvalidReEvaluationRequestError struct {
+	reason string
+}
+
+func (e InvalidReEvaluationRequestError) Error() string {
+	return "invalid re-evaluation request: " + e.reason
+}
+
+// ReEvaluationRequest identifies an evaluation to be recomputed and the SLO file to recompute it with
+type ReEvaluationRequest struct {
+	Project      string `json:"project"`
+	Stage        string `json:"stage"`
+	Service      string `json:"service"`
+	KeptnContext string `json:"keptnContext"`
+	// SLO is the content of the SLO file the evaluation is recomputed with. If it is empty, the current SLO files of the service are used
+	SLO string `json:"slo,omitempty"`
+}
+
+// ReEvaluator recomputes stored evaluations from their SLI values, without querying the SLI provider again
+type ReEvaluator struct {
+	HTTPClient *http.Client
+	// GetSLOs retrieves the effective SLO of a service if the request does not contain an SLO file
+	GetSLOs func(project, stage, service string) ([]byte, error)
+}
+
+// NewReEvaluator creates a ReEvaluator that uses the SLO files stored in the configuration-service
+func NewReEvaluator() *ReEvaluator {
+	return &ReEvaluator{
+		HTTPClient: &http.Client{},
+		GetSLOs: func(project, stage, service string) ([]byte, error) {
+			_, sloFileContent, err := getSLOs(project, stage, service)
+			return sloFileContent, err
+		},
+	}
+}
+
+// ReEvaluate recomputes the latest evaluation of a service within a Keptn context. The SLI values of the original evaluation are evaluated against the SLO,
+// and compared with the same evaluations as the original evaluation, unless the SLO pins a baseline. The result is returned, but no event is sent
+func (r *ReEvaluator) ReEvaluate(request ReEvaluationRequest) (*keptnv2.EvaluationFinishedEventData, error) {
+	if request.Project == "" || request.Stage == "" || request.Service == "" || request.KeptnContext == "" {
+		return nil, InvalidReEvaluationRequestError{reason: "project, stage, service and keptnContext must be set"}
+	}
+	e := &keptnv2.GetSLIFinishedEventData{
+		EventData: keptnv2.EventData{
+			Project: request.Project,
+			Stage:   request.Stage,
+			Service: request.Service,
+		},
+	}
+
+	evaluations, _, err := queryEvaluations(r.HTTPClient, getEvaluationFilter(e, "all")+"%20AND%20shkeptncontext:"+url.QueryEscape(request.KeptnContext), 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(evaluations) == 0 {
+		return nil, ErrEvaluationNotFound
+	}
+	original := evaluations[0]
+
+	sloFileContent := []byte(request.SLO)
+	if len(sloFileContent) == 0 {
+		if sloFileContent, err = r.GetSLOs(request.Project, request.Stage, request.Service); err != nil {
+			return nil, err
+		}
+	}
+	slo, err := scoring.ParseSLO(sloFileContent)
+	if err != nil {
+		return nil, InvalidReEvaluationRequestError{reason: "could not parse SLO file: " + err.Error()}
+	}
+	if err := scoring.ValidateSLOCriteria(slo); err != nil {
+		return nil, InvalidReEvaluationRequestError{reason: "invalid SLO file: " + err.Error()}
+	}
+	baseline, err := scoring.GetBaseline(sloFileContent, original.Labels)
+	if err != nil {
+		return nil, InvalidReEvaluationRequestError{reason: err.Error()}
+	}
+
+	// the stored SLI values of the original evaluation replace the values of the SLI provider
+	e.Labels = original.Labels
+	e.GetSLI.Start = original.Evaluation.TimeStart
+	e.GetSLI.End = original.Evaluation.TimeEnd
+	for _, indicatorResult := range original.Evaluation.IndicatorResults {
+		if indicatorResult != nil && indicatorResult.Value != nil {
+			e.GetSLI.IndicatorValues = append(e.GetSLI.IndicatorValues, indicatorResult.Value)
+		}
+	}
+
+	var previousEvaluations []*keptnv2.EvaluationFinishedEventData
+	var comparedEventIDs []string
+	if baseline != nil {
+		previousEvaluations, comparedEventIDs, err = getBaselineEvaluation(r.HTTPClient, e, baseline, slo.Comparison.IncludeResultWithScore)
+	} else {
+		previousEvaluations, comparedEventIDs, err = r.getComparedEvaluations(e, original.Evaluation.ComparedEvents)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	evaluationResult, err := scoring.Evaluate(e, slo, previousEvaluations)
+	if err != nil {
+		return nil, err
+	}
+	evaluationResult.Labels = e.Labels
+	evaluationResult.Evaluation.ComparedEvents = comparedEventIDs
+	evaluationResult.Evaluation.SLOFileContent = base64.StdEncoding.EncodeToString(sloFileContent)
+	return evaluationResult, nil
+}
+
+// getComparedEvaluations retrieves the evaluations with the given IDs, in the order of the IDs
+func (r *ReEvaluator) getComparedEvaluations(e *keptnv2.GetSLIFinishedEventData, eventIDs []string) ([]*keptnv2.EvaluationFinishedEventData, []string, error) {
+	if len(eventIDs) == 0 {
+		return nil, nil, nil
+	}
+	escapedIDs := []string{}
+	for _, eventID := range eventIDs {
+		escapedIDs = append(escapedIDs, url.QueryEscape(eventID))
+	}
+	evaluations, foundIDs, err := queryEvaluations(r.HTTPClient, getEvaluationFilter(e, "all")+"%20AND%20id:"+strings.Join(escapedIDs, ","), len(eventIDs))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	evaluationsByID := map[string]*keptnv2.EvaluationFinishedEventData{}
+	for index, foundID := range foundIDs {
+		evaluationsByID[foundID] = evaluations[index]
+	}
+	var orderedEvaluations []*keptnv2.EvaluationFinishedEventData
+	var orderedIDs []string
+	for _, eventID := range eventIDs {
+		if evaluation, ok := evaluationsByID[eventID]; ok {
+			orderedEvaluations = append(orderedEvaluations, evaluation)
+			orderedIDs = append(orderedIDs, eventID)
+		}
+	}
+	return orderedEvaluations, orderedIDs, nil
+}
@@ -0,0 +1,121 @@
+package event_handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/stretchr/testify/assert"
+)
+
+const reEvaluationTestSLO = `---
+spec_version: '1.0'
+comparison:
+  compare_with: "several_results"
+  number_of_comparison_results: 2
+  include_result_with_score: "all"
+  aggregate_function: avg
+objectives:
+  - sli: response_time_p95
+    pass:
+      - criteria:
+          - "<=+10%"
+total_score:
+  pass: "90%"
+  warning: "75%"
+`
+
+func getReEvaluationTestEvaluation(value float64, comparedEvents []string) *keptnv2.EvaluationFinishedEventData {
+	return &keptnv2.EvaluationFinishedEventData{
+		EventData: keptnv2.EventData{Project: "sockshop", Stage: "dev", Service: "carts", Result: keptnv2.ResultFailed, Labels: map[string]string{"buildId": "123"}},
+		Evaluation: keptnv2.EvaluationDetails{
+			TimeStart: "2021-02-01T10:00:00Z",
+			TimeEnd:   "2021-02-01T10:10:00Z",
+			IndicatorResults: []*keptnv2.SLIEvaluationResult{
+				{Value: &keptnv2.SLIResult{Metric: "response_time_p95", Value: value, Success: true}},
+			},
+			ComparedEvents: comparedEvents,
+		},
+	}
+}
+
+func TestReEvaluator_ReEvaluate(t *testing.T) {
+	var receivedFilters []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter := r.URL.Query().Get("filter")
+		receivedFilters = append(receivedFilters, filter)
+
+		result := datastoreResult{}
+		add := func(id string, data *keptnv2.EvaluationFinishedEventData) {
+			result.Events = append(result.Events, struct {
+				Data interface{} `json:"data"`
+				ID   string      `json:"id"`
+			}{Data: data, ID: id})
+		}
+		if strings.Contains(filter, "shkeptncontext:my-context") {
+			add("original", getReEvaluationTestEvaluation(320, []string{"previous-1", "previous-2"}))
+		} else if strings.Contains(filter, "id:previous-1,previous-2") {
+			// the datastore does not return the events in the order of the IDs
+			add("previous-2", getReEvaluationTestEvaluation(300, nil))
+			add("previous-1", getReEvaluationTestEvaluation(280, nil))
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(200)
+		marshal, _ := json.Marshal(&result)
+		w.Write(marshal)
+	}))
+	defer ts.Close()
+	_ = os.Setenv("MONGODB_DATASTORE", strings.TrimPrefix(ts.URL, "http://"))
+
+	reEvaluator := &ReEvaluator{
+		HTTPClient: &http.Client{},
+		GetSLOs: func(project, stage, service string) ([]byte, error) {
+			return nil, errors.New("the SLO of the request is expected to be used")
+		},
+	}
+
+	result, err := reEvaluator.ReEvaluate(ReEvaluationRequest{
+		Project:      "sockshop",
+		Stage:        "dev",
+		Service:      "carts",
+		KeptnContext: "my-context",
+		SLO:          reEvaluationTestSLO,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"data.project:sockshop AND data.stage:dev AND data.service:carts AND shkeptncontext:my-context",
+		"data.project:sockshop AND data.stage:dev AND data.service:carts AND id:previous-1,previous-2",
+	}, receivedFilters)
+
+	// 320 is compared with avg([280, 300]) + 10% = 319
+	assert.Equal(t, keptnv2.ResultFailed, result.Result)
+	assert.Equal(t, []string{"previous-1", "previous-2"}, result.Evaluation.ComparedEvents)
+	assert.Equal(t, map[string]string{"buildId": "123"}, result.Labels)
+	assert.Equal(t, "2021-02-01T10:00:00Z", result.Evaluation.TimeStart)
+	assert.Equal(t, 320.0, result.Evaluation.IndicatorResults[0].Value.Value)
+
+	// a less strict SLO lets the same SLI values pass
+	result, err = reEvaluator.ReEvaluate(ReEvaluationRequest{
+		Project:      "sockshop",
+		Stage:        "dev",
+		Service:      "carts",
+		KeptnContext: "my-context",
+		SLO:          strings.Replace(reEvaluationTestSLO, "<=+10%", "<=+20%", 1),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, keptnv2.ResultPass, result.Result)
+
+	_, err = reEvaluator.ReEvaluate(ReEvaluationRequest{Project: "sockshop", Stage: "dev", Service: "carts", KeptnContext: "unknown-context", SLO: reEvaluationTestSLO})
+	assert.Equal(t, ErrEvaluationNotFound, err)
+
+	_, err = reEvaluator.ReEvaluate(ReEvaluationRequest{Project: "sockshop", Stage: "dev", Service: "carts", KeptnContext: "my-context", SLO: strings.Replace(reEvaluationTestSLO, "<=+10%", "<=+10% and", 1)})
+	assert.IsType(t, InvalidReEvaluationRequestError{}, err)
+
+	_, err = reEvaluator.ReEvaluate(ReEvaluationRequest{Project: "sockshop", Stage: "dev"})
+	assert.IsType(t, InvalidReEvaluationRequestError{}, err)
+}
@@ -0,0 +1,399 @@
+package event_handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+)
+
+// trendPageSize is the number of events requested per page from the mongodb-datastore, which is its maximum page size
+const trendPageSize = 100
+
+// maxTrendPages limits the number of pages requested per event type, i.e. the number of events aggregated into a score trend
+const maxTrendPages = 50
+
+const maxTrendWindow = 90 * 24 * time.Hour
+
+const maxRegressionEvaluations = 100
+
+const defaultTrendWindow = 7 * 24 * time.Hour
+
+const defaultRegressionEvaluations = 3
+
+// datastoreTimeFormat is the format of the fromTime parameter of the mongodb-datastore
+const datastoreTimeFormat = "2006-01-02T15:04:05.000Z"
+
+// InvalidScoreTrendRequestError is returned if a score trend request is incomplete or contains invalid parameters
+type InvalidScoreTrendRequestError struct {
+	reason string
+}
+
+func (e InvalidScoreTrendRequestError) Error() string {
+	return "invalid score trend request: " + e.reason
+}
+
+// ScoreTrendRequest identifies the service and time window of a score trend
+type ScoreTrendRequest struct {
+	Project string
+	Stage   string
+	Service string
+	// Window is the time span before now whose evaluations are taken into account. It defaults to 7 days
+	Window time.Duration
+	// Bucket is the time span whose evaluations are aggregated into one data point. If it is zero, each evaluation is a data point
+	Bucket time.Duration
+	// RegressionEvaluations is the number of latest evaluations in which the score or an SLI must get worse each time to be reported as regression.
+	// It defaults to 3
+	RegressionEvaluations int
+}
+
+// ScoreTrend contains the time series of the score and SLI values of a service, as well as the regressions among its latest evaluations
+type ScoreTrend struct {
+	Project     string                  `json:"project"`
+	Stage       string                  `json:"stage"`
+	Service     string                  `json:"service"`
+	From        string                  `json:"from"`
+	To          string                  `json:"to"`
+	Bucket      string                  `json:"bucket,omitempty"`
+	Evaluations int                     `json:"evaluations"`
+	Score       []TrendPoint            `json:"score"`
+	Indicators  map[string][]TrendPoint `json:"indicators"`
+	Regressions []Regression            `json:"regressions"`
+}
+
+// TrendPoint is the average of the values within a bucket, which starts at Time
+type TrendPoint struct {
+	Time  string  `json:"time"`
+	Value float64 `json:"value"`
+	Count int     `json:"count"`
+}
+
+// Regression describes a total score or SLI that got worse in each of the latest evaluations
+type Regression struct {
+	// SLI is the name of the SLI that regressed. It is empty if the total score regressed
+	SLI     string    `json:"sli,omitempty"`
+	Values  []float64 `json:"values"`
+	Message string    `json:"message"`
+}
+
+// trendEvent is an event of the mongodb-datastore, including its time
+type trendEvent struct {
+	ID          string      `json:"id"`
+	Triggeredid string      `json:"triggeredid"`
+	Data        interface{} `json:"data"`
+	Time        string      `json:"time"`
+}
+
+// trendDatastoreResult is a page of events of the mongodb-datastore
+type trendDatastoreResult struct {
+	NextPageKey string        `json:"nextPageKey"`
+	Events      []*trendEvent `json:"events"`
+}
+
+// trendEvaluation is an evaluation.finished event with the time it has been sent
+type trendEvaluation struct {
+	time       time.Time
+	evaluation *keptnv2.EvaluationFinishedEventData
+}
+
+// ScoreTrendProvider calculates score trends from the evaluation.finished events stored in the mongodb-datastore
+type ScoreTrendProvider struct {
+	HTTPClient *http.Client
+	now        func() time.Time
+}
+
+// NewScoreTrendProvider creates a ScoreTrendProvider
+func NewScoreTrendProvider() *ScoreTrendProvider {
+	return &ScoreTrendProvider{HTTPClient: &http.Client{}, now: time.Now}
+}
+
+// GetScoreTrend returns the score and SLI value time series of the evaluations of a service within the requested window,
+// and flags the total score and SLIs that got worse in each of the latest evaluations
+func (p *ScoreTrendProvider) GetScoreTrend(request ScoreTrendRequest) (*ScoreTrend, error) {
+	if request.Project == "" || request.Stage == "" || request.Service == "" {
+		return nil, InvalidScoreTrendRequestError{reason: "project, stage and service must be set"}
+	}
+	if request.Window == 0 {
+		request.Window = defaultTrendWindow
+	}
+	if request.RegressionEvaluations == 0 {
+		request.RegressionEvaluations = defaultRegressionEvaluations
+	}
+	if request.Window < 0 || request.Bucket < 0 {
+		return nil, InvalidScoreTrendRequestError{reason: "window and bucket must not be negative"}
+	}
+	if request.Window > maxTrendWindow {
+		return nil, InvalidScoreTrendRequestError{reason: fmt.Sprintf("the window must not be longer than %s", maxTrendWindow.String())}
+	}
+	if request.RegressionEvaluations < 2 || request.RegressionEvaluations > maxRegressionEvaluations {
+		return nil, InvalidScoreTrendRequestError{reason: fmt.Sprintf("the number of evaluations for the regression detection must be between 2 and %d", maxRegressionEvaluations)}
+	}
+
+	to := p.now().UTC()
+	from := to.Add(-request.Window)
+	evaluations, err := p.queryTrendEvaluations(request, from)
+	if err != nil {
+		return nil, err
+	}
+
+	trend := &ScoreTrend{
+		Project:     request.Project,
+		Stage:       request.Stage,
+		Service:     request.Service,
+		From:        from.Format(time.RFC3339),
+		To:          to.Format(time.RFC3339),
+		Evaluations: len(evaluations),
+	}
+	if request.Bucket > 0 {
+		trend.Bucket = request.Bucket.String()
+	}
+	trend.Score, trend.Indicators = getTrendTimeSeries(evaluations, from, request.Bucket)
+	trend.Regressions = getRegressions(evaluations, request.RegressionEvaluations)
+	return trend, nil
+}
+
+// queryTrendEvaluations retrieves the evaluations of a service since the given time, sorted by time in ascending order. Invalidated evaluations are left out
+func (p *ScoreTrendProvider) queryTrendEvaluations(request ScoreTrendRequest, from time.Time) ([]*trendEvaluation, error) {
+	query := url.Values{}
+	query.Set("project", request.Project)
+	query.Set("stage", request.Stage)
+	query.Set("service", request.Service)
+	query.Set("source", "lighthouse-service")
+	query.Set("type", keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName))
+	finishedEvents, err := p.queryTrendEvents(query, from)
+	if err != nil {
+		return nil, err
+	}
+
+	// evaluations are invalidated after they have been sent, so the invalidated events of the evaluations within the window are sent within the window as well
+	query = url.Values{}
+	query.Set("project", request.Project)
+	query.Set("type", "sh.keptn.event."+keptnv2.EvaluationTaskName+".invalidated")
+	invalidatedEvents, err := p.queryTrendEvents(query, from)
+	if err != nil {
+		return nil, err
+	}
+	invalidated := map[string]bool{}
+	for _, event := range invalidatedEvents {
+		invalidated[event.Triggeredid] = true
+	}
+
+	evaluations := []*trendEvaluation{}
+	for _, event := range finishedEvents {
+		if invalidated[event.ID] {
+			continue
+		}
+		eventTime, err := time.Parse(time.RFC3339, event.Time)
+		if err != nil {
+			continue
+		}
+		bytes, err := json.Marshal(event.Data)
+		if err != nil {
+			continue
+		}
+		evaluation := &keptnv2.EvaluationFinishedEventData{}
+		if err := json.Unmarshal(bytes, evaluation); err != nil {
+			continue
+		}
+		evaluations = append(evaluations, &trendEvaluation{time: eventTime, evaluation: evaluation})
+	}
+	sort.SliceStable(evaluations, func(i, j int) bool {
+		return evaluations[i].time.Before(evaluations[j].time)
+	})
+	return evaluations, nil
+}
+
+// queryTrendEvents retrieves the events matching the query that have been stored since the given time, requesting page after page until all of them are retrieved.
+// If there are more than maxTrendPages pages, the request is rejected
+func (p *ScoreTrendProvider) queryTrendEvents(query url.Values, from time.Time) ([]*trendEvent, error) {
+	query.Set("fromTime", from.Format(datastoreTimeFormat))
+	query.Set("pageSize", strconv.Itoa(trendPageSize))
+
+	events := []*trendEvent{}
+	received := map[string]bool{}
+	for page := 0; page < maxTrendPages; page++ {
+		req, err := http.NewRequest("GET", getDatastoreURL()+"/event?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := p.HTTPClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != 200 {
+			return nil, errors.New("could not retrieve " + query.Get("type") + " events")
+		}
+		result := &trendDatastoreResult{}
+		if err := json.Unmarshal(body, result); err != nil {
+			return nil, err
+		}
+		for _, event := range result.Events {
+			// events stored while paging shift the pages, so that an event can be returned twice
+			if received[event.ID] {
+				continue
+			}
+			received[event.ID] = true
+			events = append(events, event)
+		}
+
+		if result.NextPageKey == "" || result.NextPageKey == "0" {
+			return events, nil
+		}
+		query.Set("nextPageKey", result.NextPageKey)
+	}
+	return nil, InvalidScoreTrendRequestError{reason: fmt.Sprintf("the window contains more than %d %s events, use a shorter window", maxTrendPages*trendPageSize, query.Get("type"))}
+}
+
+// getTrendTimeSeries aggregates the scores and SLI values of the evaluations into buckets, starting at the given time.
+// SLIs whose value could not be retrieved are left out of the average
+func getTrendTimeSeries(evaluations []*trendEvaluation, from time.Time, bucket time.Duration) ([]TrendPoint, map[string][]TrendPoint) {
+	type sum struct {
+		total float64
+		count int
+	}
+	type bucketSums struct {
+		start      time.Time
+		score      sum
+		indicators map[string]*sum
+	}
+
+	buckets := []*bucketSums{}
+	for _, e := range evaluations {
+		start := e.time
+		if bucket > 0 {
+			start = from.Add(e.time.Sub(from) / bucket * bucket)
+		}
+		if len(buckets) == 0 || !buckets[len(buckets)-1].start.Equal(start) || bucket == 0 {
+			buckets = append(buckets, &bucketSums{start: start, indicators: map[string]*sum{}})
+		}
+		current := buckets[len(buckets)-1]
+		current.score.total += e.evaluation.Evaluation.Score
+		current.score.count++
+		for _, indicatorResult := range e.evaluation.Evaluation.IndicatorResults {
+			if indicatorResult == nil || indicatorResult.Value == nil || !indicatorResult.Value.Success {
+				continue
+			}
+			metric := indicatorResult.Value.Metric
+			if current.indicators[metric] == nil {
+				current.indicators[metric] = &sum{}
+			}
+			current.indicators[metric].total += indicatorResult.Value.Value
+			current.indicators[metric].count++
+		}
+	}
+
+	score := []TrendPoint{}
+	indicators := map[string][]TrendPoint{}
+	for _, b := range buckets {
+		bucketTime := b.start.UTC().Format(time.RFC3339)
+		score = append(score, TrendPoint{Time: bucketTime, Value: b.score.total / float64(b.score.count), Count: b.score.count})
+		for metric, s := range b.indicators {
+			indicators[metric] = append(indicators[metric], TrendPoint{Time: bucketTime, Value: s.total / float64(s.count), Count: s.count})
+		}
+	}
+	return score, indicators
+}
+
+// getRegressions returns the total score and SLIs that got worse in each of the given number of latest evaluations.
+// Whether a lower or a higher value of an SLI is worse is derived from the criteria of its latest targets, e.g. "<=600" or ">=95%".
+// SLIs without an unambiguous direction are not checked
+func getRegressions(evaluations []*trendEvaluation, count int) []Regression {
+	regressions := []Regression{}
+	if len(evaluations) < count {
+		return regressions
+	}
+	latest := evaluations[len(evaluations)-count:]
+
+	scores := []float64{}
+	for _, e := range latest {
+		scores = append(scores, e.evaluation.Evaluation.Score)
+	}
+	if isStrictlyMonotonic(scores, false) {
+		regressions = append(regressions, Regression{
+			Values:  scores,
+			Message: fmt.Sprintf("score decreased in each of the last %d evaluations: %s", count, formatTrendValues(scores)),
+		})
+	}
+
+	lastEvaluation := latest[len(latest)-1].evaluation
+	metrics := []string{}
+	for _, indicatorResult := range lastEvaluation.Evaluation.IndicatorResults {
+		if indicatorResult != nil && indicatorResult.Value != nil {
+			metrics = append(metrics, indicatorResult.Value.Metric)
+		}
+	}
+	sort.Strings(metrics)
+
+	for _, metric := range metrics {
+		values := []float64{}
+		var increasingIsWorse, decreasingIsWorse bool
+		for _, e := range latest {
+			for _, indicatorResult := range e.evaluation.Evaluation.IndicatorResults {
+				if indicatorResult != nil && indicatorResult.Value != nil && indicatorResult.Value.Metric == metric && indicatorResult.Value.Success {
+					values = append(values, indicatorResult.Value.Value)
+					if e.evaluation == lastEvaluation {
+						increasingIsWorse, decreasingIsWorse = getWorseDirection(indicatorResult.Targets)
+					}
+					break
+				}
+			}
+		}
+		if len(values) < count || increasingIsWorse == decreasingIsWorse {
+			continue
+		}
+		if isStrictlyMonotonic(values, increasingIsWorse) {
+			regressions = append(regressions, Regression{
+				SLI:     metric,
+				Values:  values,
+				Message: fmt.Sprintf("SLI %s got worse in each of the last %d evaluations: %s", metric, count, formatTrendValues(values)),
+			})
+		}
+	}
+	return regressions
+}
+
+// getWorseDirection derives from the criteria of the targets whether increasing or decreasing values are worse
+func getWorseDirection(targets []*keptnv2.SLITarget) (bool, bool) {
+	var increasingIsWorse, decreasingIsWorse bool
+	for _, target := range targets {
+		if target == nil {
+			continue
+		}
+		criteria := strings.TrimSpace(target.Criteria)
+		if strings.HasPrefix(criteria, "<") {
+			increasingIsWorse = true
+		} else if strings.HasPrefix(criteria, ">") {
+			decreasingIsWorse = true
+		}
+	}
+	return increasingIsWorse, decreasingIsWorse
+}
+
+func isStrictlyMonotonic(values []float64, increasing bool) bool {
+	for i := 1; i < len(values); i++ {
+		if increasing && values[i] <= values[i-1] || !increasing && values[i] >= values[i-1] {
+			return false
+		}
+	}
+	return len(values) > 1
+}
+
+func formatTrendValues(values []float64) string {
+	formatted := []string{}
+	for _, value := range values {
+		formatted = append(formatted, strconv.FormatFloat(value, 'f', -1, 64))
+	}
+	return strings.Join(formatted, ", ")
+}
@@ -0,0 +1,174 @@
+package event_handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/stretchr/testify/assert"
+)
+
+func getTrendTestEvaluation(score float64, responseTime float64) *keptnv2.EvaluationFinishedEventData {
+	return &keptnv2.EvaluationFinishedEventData{
+		EventData: keptnv2.EventData{Project: "sockshop", Stage: "dev", Service: "carts"},
+		Evaluation: keptnv2.EvaluationDetails{
+			Score: score,
+			IndicatorResults: []*keptnv2.SLIEvaluationResult{
+				{
+					Value:   &keptnv2.SLIResult{Metric: "response_time_p95", Value: responseTime, Success: true},
+					Targets: []*keptnv2.SLITarget{{Criteria: "<=+10%"}, {Criteria: "<600"}},
+				},
+				{
+					Value:   &keptnv2.SLIResult{Metric: "throughput", Value: 1000, Success: true},
+					Targets: []*keptnv2.SLITarget{{Criteria: ">=800"}},
+				},
+				{
+					Value: &keptnv2.SLIResult{Metric: "error_rate", Success: false, Message: "query failed"},
+				},
+			},
+		},
+	}
+}
+
+func TestScoreTrendProvider_GetScoreTrend(t *testing.T) {
+	now := time.Date(2021, 2, 8, 12, 0, 0, 0, time.UTC)
+	receivedQueries := []url.Values{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		receivedQueries = append(receivedQueries, query)
+		var result map[string]interface{}
+		if query.Get("type") == keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName) {
+			// the datastore returns the latest events first, page by page
+			if query.Get("nextPageKey") == "" {
+				result = map[string]interface{}{"nextPageKey": "3", "events": []map[string]interface{}{
+					{"id": "5", "time": "2021-02-08T11:45:00.000Z", "data": getTrendTestEvaluation(0, 900)},
+					{"id": "4", "time": "2021-02-08T11:30:00.000Z", "data": getTrendTestEvaluation(50, 500)},
+					{"id": "3", "time": "2021-02-08T10:45:00.000Z", "data": getTrendTestEvaluation(75, 400)},
+				}}
+			} else {
+				result = map[string]interface{}{"events": []map[string]interface{}{
+					// returned again, since an event has been stored in the meantime
+					{"id": "3", "time": "2021-02-08T10:45:00.000Z", "data": getTrendTestEvaluation(75, 400)},
+					{"id": "2", "time": "2021-02-08T10:15:00.000Z", "data": getTrendTestEvaluation(100, 300)},
+					{"id": "1", "time": "2021-02-08T09:30:00.000Z", "data": getTrendTestEvaluation(100, 350)},
+				}}
+			}
+		} else {
+			result = map[string]interface{}{"events": []map[string]interface{}{
+				{"id": "invalidation-1", "triggeredid": "5", "time": "2021-02-08T11:50:00.000Z"},
+			}}
+		}
+		body, _ := json.Marshal(result)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	defer ts.Close()
+	os.Setenv("MONGODB_DATASTORE", strings.TrimPrefix(ts.URL, "http://"))
+	defer os.Unsetenv("MONGODB_DATASTORE")
+
+	provider := &ScoreTrendProvider{HTTPClient: &http.Client{}, now: func() time.Time { return now }}
+	trend, err := provider.GetScoreTrend(ScoreTrendRequest{Project: "sockshop", Stage: "dev", Service: "carts", Window: 4 * time.Hour, Bucket: time.Hour})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(receivedQueries))
+	assert.Equal(t, "2021-02-08T08:00:00.000Z", receivedQueries[0].Get("fromTime"))
+	assert.Equal(t, "sockshop", receivedQueries[0].Get("project"))
+	assert.Equal(t, "dev", receivedQueries[0].Get("stage"))
+	assert.Equal(t, "carts", receivedQueries[0].Get("service"))
+	assert.Equal(t, "3", receivedQueries[1].Get("nextPageKey"))
+	assert.Equal(t, "sh.keptn.event.evaluation.invalidated", receivedQueries[2].Get("type"))
+
+	assert.Equal(t, "2021-02-08T08:00:00Z", trend.From)
+	assert.Equal(t, "2021-02-08T12:00:00Z", trend.To)
+	assert.Equal(t, "1h0m0s", trend.Bucket)
+	assert.Equal(t, 4, trend.Evaluations)
+	assert.Equal(t, []TrendPoint{
+		{Time: "2021-02-08T09:00:00Z", Value: 100, Count: 1},
+		{Time: "2021-02-08T10:00:00Z", Value: 87.5, Count: 2},
+		{Time: "2021-02-08T11:00:00Z", Value: 50, Count: 1},
+	}, trend.Score)
+	assert.Equal(t, []TrendPoint{
+		{Time: "2021-02-08T09:00:00Z", Value: 350, Count: 1},
+		{Time: "2021-02-08T10:00:00Z", Value: 350, Count: 2},
+		{Time: "2021-02-08T11:00:00Z", Value: 500, Count: 1},
+	}, trend.Indicators["response_time_p95"])
+	assert.Nil(t, trend.Indicators["error_rate"])
+
+	assert.Equal(t, []Regression{
+		{Values: []float64{100, 75, 50}, Message: "score decreased in each of the last 3 evaluations: 100, 75, 50"},
+		{SLI: "response_time_p95", Values: []float64{300, 400, 500}, Message: "SLI response_time_p95 got worse in each of the last 3 evaluations: 300, 400, 500"},
+	}, trend.Regressions)
+
+	_, err = provider.GetScoreTrend(ScoreTrendRequest{Project: "sockshop", Stage: "dev", Service: "carts", RegressionEvaluations: 1})
+	assert.IsType(t, InvalidScoreTrendRequestError{}, err)
+
+	_, err = provider.GetScoreTrend(ScoreTrendRequest{Project: "sockshop"})
+	assert.IsType(t, InvalidScoreTrendRequestError{}, err)
+}
+
+func TestGetRegressions(t *testing.T) {
+	evaluations := []*trendEvaluation{
+		{evaluation: getTrendTestEvaluation(100, 300)},
+		{evaluation: getTrendTestEvaluation(90, 300)},
+		{evaluation: getTrendTestEvaluation(80, 350)},
+	}
+	// the response time did not get worse in each evaluation
+	assert.Equal(t, []Regression{
+		{Values: []float64{100, 90, 80}, Message: "score decreased in each of the last 3 evaluations: 100, 90, 80"},
+	}, getRegressions(evaluations, 3))
+
+	assert.Equal(t, []Regression{
+		{Values: []float64{90, 80}, Message: "score decreased in each of the last 2 evaluations: 90, 80"},
+		{SLI: "response_time_p95", Values: []float64{300, 350}, Message: "SLI response_time_p95 got worse in each of the last 2 evaluations: 300, 350"},
+	}, getRegressions(evaluations, 2))
+
+	// not enough evaluations
+	assert.Equal(t, []Regression{}, getRegressions(evaluations, 4))
+}
+
+func TestGetWorseDirection(t *testing.T) {
+	increasingIsWorse, decreasingIsWorse := getWorseDirection([]*keptnv2.SLITarget{{Criteria: "<=+10%"}, {Criteria: "<600"}})
+	assert.True(t, increasingIsWorse)
+	assert.False(t, decreasingIsWorse)
+
+	increasingIsWorse, decreasingIsWorse = getWorseDirection([]*keptnv2.SLITarget{{Criteria: ">=95"}})
+	assert.False(t, increasingIsWorse)
+	assert.True(t, decreasingIsWorse)
+
+	increasingIsWorse, decreasingIsWorse = getWorseDirection([]*keptnv2.SLITarget{{Criteria: "=0"}})
+	assert.False(t, increasingIsWorse)
+	assert.False(t, decreasingIsWorse)
+}
+
+func TestScoreTrendProvider_GetScoreTrend_tooManyEvents(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// the datastore always returns a further page
+		body, _ := json.Marshal(map[string]interface{}{"nextPageKey": strconv.Itoa(requests), "events": []map[string]interface{}{
+			{"id": strconv.Itoa(requests), "time": "2021-02-08T11:45:00.000Z", "data": getTrendTestEvaluation(100, 300)},
+		}})
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	defer ts.Close()
+	os.Setenv("MONGODB_DATASTORE", strings.TrimPrefix(ts.URL, "http://"))
+	defer os.Unsetenv("MONGODB_DATASTORE")
+
+	provider := &ScoreTrendProvider{HTTPClient: &http.Client{}, now: time.Now}
+	_, err := provider.GetScoreTrend(ScoreTrendRequest{Project: "sockshop", Stage: "dev", Service: "carts"})
+	assert.IsType(t, InvalidScoreTrendRequestError{}, err)
+	assert.Equal(t, maxTrendPages, requests)
+
+	// windows longer than the maximum window are rejected without querying the datastore
+	requests = 0
+	_, err = provider.GetScoreTrend(ScoreTrendRequest{Project: "sockshop", Stage: "dev", Service: "carts", Window: maxTrendWindow + time.Hour})
+	assert.IsType(t, InvalidScoreTrendRequestError{}, err)
+	assert.Equal(t, 0, requests)
+}
@@ -0,0 +1,368 @@
+package event_handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/keptn/go-utils/pkg/api/models"
+	utils "github.com/keptn/go-utils/pkg/api/utils"
+	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+)
+
+const sliRetrievalTimeoutEnvVar = "SLI_RETRIEVAL_TIMEOUT"
+
+// defaultSLIRetrievalTimeout is used if SLI_RETRIEVAL_TIMEOUT is not set or invalid
+const defaultSLIRetrievalTimeout = 10 * time.Minute
+
+var pendingSLIRetrievals = newSLIRetrievalRegistry()
+
+// getSLIRetrievalTimeout returns how long lighthouse waits for the get-sli.finished events of an evaluation. A timeout of 0 disables waiting for a limited time
+func getSLIRetrievalTimeout() (time.Duration, error) {
+	value := os.Getenv(sliRetrievalTimeoutEnvVar)
+	if value == "" {
+		return defaultSLIRetrievalTimeout, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return defaultSLIRetrievalTimeout, err
+	}
+	return timeout, nil
+}
+
+// sliProviderRequest is a get-sli.triggered event sent to one SLI provider
+type sliProviderRequest struct {
+	// ID is the ID of the get-sli.triggered event
+	ID          string
+	SLIProvider string
+	Indicators  []string
+}
+
+// groupIndicatorsBySLIProvider assigns each indicator to the SLI provider configured for it in the SLO file, or to the default SLI provider.
+// The requests are ordered by the first indicator assigned to them. If there are no indicators, the default SLI provider is requested without indicators
+func groupIndicatorsBySLIProvider(indicators []string, sliProviders map[string]string, defaultSLIProvider string) []*sliProviderRequest {
+	requests := []*sliProviderRequest{}
+	requestsBySLIProvider := map[string]*sliProviderRequest{}
+	for _, indicator := range indicators {
+		sliProvider := sliProviders[indicator]
+		if sliProvider == "" {
+			sliProvider = defaultSLIProvider
+		}
+		request, ok := requestsBySLIProvider[sliProvider]
+		if !ok {
+			request = &sliProviderRequest{SLIProvider: sliProvider}
+			requestsBySLIProvider[sliProvider] = request
+			requests = append(requests, request)
+		}
+		request.Indicators = append(request.Indicators, indicator)
+	}
+	if len(requests) == 0 {
+		requests = append(requests, &sliProviderRequest{SLIProvider: defaultSLIProvider})
+	}
+	return requests
+}
+
+// getSLITriggeredEventData is the payload of the get-sli.triggered events sent by lighthouse. The deadline of the SLI retrieval is stored with the events,
+// so that the retrieval can be resumed if lighthouse is restarted before all SLI providers have responded
+type getSLITriggeredEventData struct {
+	keptnv2.GetSLITriggeredEventData
+	SLIRetrievalDeadline string `json:"sliRetrievalDeadline,omitempty"`
+}
+
+// getSLIRetrievalDeadline returns the deadline of an SLI retrieval started at the given time, or the zero time if the timeout is disabled
+func getSLIRetrievalDeadline(now time.Time, timeout time.Duration) time.Time {
+	if timeout <= 0 {
+		return time.Time{}
+	}
+	return now.Add(timeout).UTC().Truncate(time.Second)
+}
+
+// sliRetrieval is an evaluation that waits for the get-sli.finished events of its SLI providers
+type sliRetrieval struct {
+	requests []*sliProviderRequest
+	results  map[string]*keptnv2.GetSLIFinishedEventData
+	timer    *time.Timer
+}
+
+// sliRetrievalRegistry keeps track of the evaluations that wait for get-sli.finished events, keyed by the IDs of their get-sli.triggered events
+type sliRetrievalRegistry struct {
+	mutex      sync.Mutex
+	retrievals map[string]*sliRetrieval
+}
+
+func newSLIRetrievalRegistry() *sliRetrievalRegistry {
+	return &sliRetrievalRegistry{retrievals: map[string]*sliRetrieval{}}
+}
+
+// register adds an evaluation that waits for the results of the given requests. If they are not complete at the deadline, onTimeout is called with the SLI providers that did not respond.
+// A zero deadline disables the timeout
+func (r *sliRetrievalRegistry) register(requests []*sliProviderRequest, deadline time.Time, onTimeout func(missingSLIProviders []string)) *sliRetrieval {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	retrieval := &sliRetrieval{
+		requests: requests,
+		results:  map[string]*keptnv2.GetSLIFinishedEventData{},
+	}
+	for _, request := range requests {
+		r.retrievals[request.ID] = retrieval
+	}
+	if !deadline.IsZero() {
+		retrieval.timer = time.AfterFunc(time.Until(deadline), func() {
+			if missingSLIProviders := r.expire(retrieval); len(missingSLIProviders) > 0 {
+				onTimeout(missingSLIProviders)
+			}
+		})
+	}
+	return retrieval
+}
+
+// cancel removes an evaluation, e.g., because its get-sli.triggered events could not be sent
+func (r *sliRetrievalRegistry) cancel(retrieval *sliRetrieval) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.remove(retrieval)
+}
+
+// addResult stores the result of a get-sli.triggered event and returns the merged results of all SLI providers of the evaluation once they are complete.
+// Results of unknown get-sli.triggered events, e.g., whose SLI retrieval has not been restored after lighthouse has been restarted, are returned as they are
+func (r *sliRetrievalRegistry) addResult(getSLITriggeredID string, result *keptnv2.GetSLIFinishedEventData) (*keptnv2.GetSLIFinishedEventData, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	retrieval, ok := r.retrievals[getSLITriggeredID]
+	if !ok {
+		return result, true
+	}
+	retrieval.results[getSLITriggeredID] = result
+	if len(retrieval.results) < len(retrieval.requests) {
+		return nil, false
+	}
+	r.remove(retrieval)
+	return mergeSLIResults(retrieval), true
+}
+
+// expire removes an evaluation that has not been completed and returns the SLI providers that did not respond
+func (r *sliRetrievalRegistry) expire(retrieval *sliRetrieval) []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.retrievals[retrieval.requests[0].ID] != retrieval {
+		// the evaluation has been completed in the meantime
+		return nil
+	}
+	r.remove(retrieval)
+
+	missingSLIProviders := []string{}
+	for _, request := range retrieval.requests {
+		if _, ok := retrieval.results[request.ID]; !ok {
+			missingSLIProviders = append(missingSLIProviders, request.SLIProvider)
+		}
+	}
+	sort.Strings(missingSLIProviders)
+	return missingSLIProviders
+}
+
+func (r *sliRetrievalRegistry) remove(retrieval *sliRetrieval) {
+	if retrieval.timer != nil {
+		retrieval.timer.Stop()
+	}
+	for _, request := range retrieval.requests {
+		if r.retrievals[request.ID] == retrieval {
+			delete(r.retrievals, request.ID)
+		}
+	}
+}
+
+// mergeSLIResults merges the results of all SLI providers of an evaluation into a single result. If any SLI provider failed, the merged result fails as well
+func mergeSLIResults(retrieval *sliRetrieval) *keptnv2.GetSLIFinishedEventData {
+	var merged *keptnv2.GetSLIFinishedEventData
+	messages := []string{}
+	for _, request := range retrieval.requests {
+		result := retrieval.results[request.ID]
+		if merged == nil {
+			merged = &keptnv2.GetSLIFinishedEventData{
+				EventData: result.EventData,
+				GetSLI: keptnv2.GetSLIFinished{
+					Start: result.GetSLI.Start,
+					End:   result.GetSLI.End,
+				},
+			}
+		}
+		merged.GetSLI.IndicatorValues = append(merged.GetSLI.IndicatorValues, result.GetSLI.IndicatorValues...)
+		if result.Status == keptnv2.StatusErrored {
+			merged.Status = keptnv2.StatusErrored
+		}
+		if result.Result == keptnv2.ResultFailed {
+			merged.Result = keptnv2.ResultFailed
+		}
+		if result.Message != "" {
+			messages = append(messages, request.SLIProvider+": "+result.Message)
+		}
+	}
+	if len(retrieval.requests) > 1 {
+		merged.Message = strings.Join(messages, "; ")
+	}
+	return merged
+}
+
+// sliRetrievalResumeWindow limits how far back lighthouse looks for pending SLI retrievals when it is started
+const sliRetrievalResumeWindow = 24 * time.Hour
+
+// storedSLIRetrieval is an SLI retrieval restored from the get-sli.triggered events stored in mongodb-datastore
+type storedSLIRetrieval struct {
+	evaluationTriggeredID string
+	keptnContext          string
+	requests              []*sliProviderRequest
+	deadline              time.Time
+}
+
+// getStoredSLIRetrievals groups the get-sli.triggered events sent by lighthouse by the evaluation.triggered event they belong to.
+// Events without a triggeredid, i.e., sent by a previous version of lighthouse, are ignored
+func getStoredSLIRetrievals(getSLITriggeredEvents []*models.KeptnContextExtendedCE) []*storedSLIRetrieval {
+	retrievals := []*storedSLIRetrieval{}
+	retrievalsByTriggeredID := map[string]*storedSLIRetrieval{}
+	for _, event := range getSLITriggeredEvents {
+		if event.Source == nil || *event.Source != "lighthouse-service" || event.Triggeredid == "" {
+			continue
+		}
+		data := &getSLITriggeredEventData{}
+		if err := keptnv2.Decode(event.Data, data); err != nil {
+			continue
+		}
+		// retrievals without deadline, i.e. sent while the timeout has been disabled, are resumed without timeout
+		var deadline time.Time
+		if data.SLIRetrievalDeadline != "" {
+			var err error
+			if deadline, err = time.Parse(time.RFC3339, data.SLIRetrievalDeadline); err != nil {
+				continue
+			}
+		}
+		retrieval, ok := retrievalsByTriggeredID[event.Triggeredid]
+		if !ok {
+			retrieval = &storedSLIRetrieval{evaluationTriggeredID: event.Triggeredid, keptnContext: event.Shkeptncontext, deadline: deadline}
+			retrievalsByTriggeredID[event.Triggeredid] = retrieval
+			retrievals = append(retrievals, retrieval)
+		}
+		retrieval.requests = append(retrieval.requests, &sliProviderRequest{ID: event.ID, SLIProvider: data.GetSLI.SLIProvider, Indicators: data.GetSLI.Indicators})
+	}
+	return retrievals
+}
+
+// ResumeSLIRetrievals restores the SLI retrievals that were pending when lighthouse has been stopped from the events stored in mongodb-datastore.
+// Their timeouts are driven by the deadlines stored with the get-sli.triggered events, and get-sli.finished events that have been stored in the meantime are evaluated
+func ResumeSLIRetrievals() {
+	logger := keptncommon.NewLogger("", "", "lighthouse-service")
+
+	endpoint, err := keptncommon.GetServiceEndpoint("CONFIGURATION_SERVICE")
+	if err != nil {
+		logger.Error("Could not resume pending SLI retrievals: " + err.Error())
+		return
+	}
+	projects, err := utils.NewProjectHandler(endpoint.String()).GetAllProjects()
+	if err != nil {
+		logger.Error("Could not resume pending SLI retrievals: " + err.Error())
+		return
+	}
+	eventHandler := utils.NewEventHandler(getDatastoreURL())
+	httpClient := &http.Client{}
+	for _, project := range projects {
+		if err := resumeSLIRetrievalsOfProject(eventHandler, httpClient, project.ProjectName, time.Now()); err != nil {
+			logger.Error("Could not resume pending SLI retrievals of project " + project.ProjectName + ": " + err.Error())
+		}
+	}
+}
+
+func resumeSLIRetrievalsOfProject(eventHandler *utils.EventHandler, httpClient *http.Client, project string, now time.Time) error {
+	getSLITriggeredEvents, errObj := eventHandler.GetEvents(&utils.EventFilter{
+		Project:   project,
+		EventType: keptnv2.GetTriggeredEventType(keptnv2.GetSLITaskName),
+		FromTime:  now.Add(-sliRetrievalResumeWindow).UTC().Format(datastoreTimeFormat),
+	})
+	if errObj != nil {
+		return errors.New(*errObj.Message)
+	}
+
+	for _, retrieval := range getStoredSLIRetrievals(getSLITriggeredEvents) {
+		filter := "data.project:" + url.QueryEscape(project) + "%20AND%20triggeredid:" + url.QueryEscape(retrieval.evaluationTriggeredID)
+		if evaluations, _, err := queryEvaluations(httpClient, filter, 1); err != nil {
+			return err
+		} else if len(evaluations) > 0 {
+			continue
+		}
+		if err := resumeSLIRetrieval(eventHandler, project, retrieval); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resumeSLIRetrieval waits for the results of a stored SLI retrieval again and handles the get-sli.finished events that have already been stored for it
+func resumeSLIRetrieval(eventHandler *utils.EventHandler, project string, retrieval *storedSLIRetrieval) error {
+	evaluationTriggeredEvents, errObj := eventHandler.GetEvents(&utils.EventFilter{
+		Project:      project,
+		EventType:    keptnv2.GetTriggeredEventType(keptnv2.EvaluationTaskName),
+		KeptnContext: retrieval.keptnContext,
+		EventID:      retrieval.evaluationTriggeredID,
+	})
+	if errObj != nil {
+		return errors.New(*errObj.Message)
+	}
+	if len(evaluationTriggeredEvents) == 0 {
+		return nil
+	}
+	evaluationTriggeredEvent := toCloudEvent(evaluationTriggeredEvents[0])
+	keptnHandler, err := newKeptnHandler(evaluationTriggeredEvent)
+	if err != nil {
+		return err
+	}
+	eh := &StartEvaluationHandler{Event: evaluationTriggeredEvent, KeptnHandler: keptnHandler}
+	if err := eh.resumeSLIRetrieval(retrieval.requests, retrieval.deadline); err != nil {
+		return err
+	}
+	if retrieval.deadline.IsZero() {
+		keptnHandler.Logger.Info("Resumed the SLI retrieval of evaluation.triggered event " + retrieval.evaluationTriggeredID + " without timeout")
+	} else {
+		keptnHandler.Logger.Info(fmt.Sprintf("Resumed the SLI retrieval of evaluation.triggered event %s with deadline %s", retrieval.evaluationTriggeredID, retrieval.deadline.Format(time.RFC3339)))
+	}
+
+	getSLIFinishedEvents, errObj := eventHandler.GetEvents(&utils.EventFilter{
+		Project:      project,
+		EventType:    keptnv2.GetFinishedEventType(keptnv2.GetSLITaskName),
+		KeptnContext: retrieval.keptnContext,
+	})
+	if errObj != nil {
+		return errors.New(*errObj.Message)
+	}
+	for _, getSLIFinishedEvent := range getSLIFinishedEvents {
+		if !retrieval.hasRequest(getSLIFinishedEvent.Triggeredid) {
+			continue
+		}
+		event := toCloudEvent(getSLIFinishedEvent)
+		keptnHandler, err := newKeptnHandler(event)
+		if err != nil {
+			return err
+		}
+		handler := &EvaluateSLIHandler{Event: event, HTTPClient: &http.Client{}, KeptnHandler: keptnHandler}
+		if err := handler.HandleEvent(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *storedSLIRetrieval) hasRequest(getSLITriggeredID string) bool {
+	for _, request := range r.requests {
+		if request.ID == getSLITriggeredID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,200 @@
+package event_handler
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/keptn/go-utils/pkg/api/models"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupIndicatorsBySLIProvider(t *testing.T) {
+	requests := groupIndicatorsBySLIProvider(
+		[]string{"response_time_p95", "error_rate", "throughput", "cpu_usage"},
+		map[string]string{"error_rate": "dynatrace", "cpu_usage": "dynatrace"},
+		"prometheus",
+	)
+	assert.Equal(t, []*sliProviderRequest{
+		{SLIProvider: "prometheus", Indicators: []string{"response_time_p95", "throughput"}},
+		{SLIProvider: "dynatrace", Indicators: []string{"error_rate", "cpu_usage"}},
+	}, requests)
+
+	// without an SLO file, the default SLI provider is requested without indicators
+	assert.Equal(t, []*sliProviderRequest{{SLIProvider: "prometheus"}}, groupIndicatorsBySLIProvider(nil, nil, "prometheus"))
+}
+
+func getSLIRetrievalTestResult(metric string, result keptnv2.ResultType, message string) *keptnv2.GetSLIFinishedEventData {
+	return &keptnv2.GetSLIFinishedEventData{
+		EventData: keptnv2.EventData{Project: "sockshop", Stage: "dev", Service: "carts", Result: result, Message: message},
+		GetSLI: keptnv2.GetSLIFinished{
+			Start:           "2021-02-01T10:00:00Z",
+			End:             "2021-02-01T10:10:00Z",
+			IndicatorValues: []*keptnv2.SLIResult{{Metric: metric, Value: 1, Success: result == keptnv2.ResultPass}},
+		},
+	}
+}
+
+func TestSLIRetrievalRegistry_addResult(t *testing.T) {
+	registry := newSLIRetrievalRegistry()
+	registry.register([]*sliProviderRequest{
+		{ID: "get-sli-1", SLIProvider: "prometheus", Indicators: []string{"response_time_p95"}},
+		{ID: "get-sli-2", SLIProvider: "dynatrace", Indicators: []string{"error_rate"}},
+	}, time.Time{}, nil)
+
+	// results of unknown get-sli.triggered events are evaluated as they are
+	unknown := getSLIRetrievalTestResult("throughput", keptnv2.ResultPass, "")
+	result, complete := registry.addResult("unknown", unknown)
+	assert.True(t, complete)
+	assert.Equal(t, unknown, result)
+
+	result, complete = registry.addResult("get-sli-2", getSLIRetrievalTestResult("error_rate", keptnv2.ResultFailed, "query failed"))
+	assert.False(t, complete)
+	assert.Nil(t, result)
+
+	result, complete = registry.addResult("get-sli-1", getSLIRetrievalTestResult("response_time_p95", keptnv2.ResultPass, ""))
+	assert.True(t, complete)
+	assert.Equal(t, keptnv2.ResultFailed, result.Result)
+	assert.Equal(t, "dynatrace: query failed", result.Message)
+	assert.Equal(t, "2021-02-01T10:00:00Z", result.GetSLI.Start)
+	assert.Equal(t, 2, len(result.GetSLI.IndicatorValues))
+	assert.Equal(t, "response_time_p95", result.GetSLI.IndicatorValues[0].Metric)
+	assert.Equal(t, "error_rate", result.GetSLI.IndicatorValues[1].Metric)
+
+	// the evaluation has been removed from the registry
+	assert.Empty(t, registry.retrievals)
+}
+
+func TestSLIRetrievalRegistry_timeout(t *testing.T) {
+	registry := newSLIRetrievalRegistry()
+	missing := make(chan []string, 1)
+	registry.register([]*sliProviderRequest{
+		{ID: "get-sli-1", SLIProvider: "prometheus"},
+		{ID: "get-sli-2", SLIProvider: "dynatrace"},
+	}, time.Now().Add(10*time.Millisecond), func(missingSLIProviders []string) {
+		missing <- missingSLIProviders
+	})
+	registry.addResult("get-sli-1", getSLIRetrievalTestResult("response_time_p95", keptnv2.ResultPass, ""))
+
+	select {
+	case missingSLIProviders := <-missing:
+		assert.Equal(t, []string{"dynatrace"}, missingSLIProviders)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout has not been handled")
+	}
+
+	// a late result is evaluated as it is, since the evaluation is not waiting anymore
+	_, complete := registry.addResult("get-sli-2", getSLIRetrievalTestResult("error_rate", keptnv2.ResultPass, ""))
+	assert.True(t, complete)
+}
+
+func TestSLIRetrievalRegistry_completedBeforeTimeout(t *testing.T) {
+	registry := newSLIRetrievalRegistry()
+	timedOut := make(chan bool, 1)
+	registry.register([]*sliProviderRequest{{ID: "get-sli-1", SLIProvider: "prometheus"}}, time.Now().Add(50*time.Millisecond), func(missingSLIProviders []string) {
+		timedOut <- true
+	})
+	_, complete := registry.addResult("get-sli-1", getSLIRetrievalTestResult("response_time_p95", keptnv2.ResultPass, ""))
+	assert.True(t, complete)
+
+	select {
+	case <-timedOut:
+		t.Error("completed evaluation has timed out")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestSLIRetrievalRegistry_deadlinePassedWhileStopped(t *testing.T) {
+	registry := newSLIRetrievalRegistry()
+	missing := make(chan []string, 1)
+	// a resumed retrieval whose deadline has passed while lighthouse was stopped times out immediately
+	registry.register([]*sliProviderRequest{{ID: "get-sli-1", SLIProvider: "prometheus"}}, time.Now().Add(-time.Minute), func(missingSLIProviders []string) {
+		missing <- missingSLIProviders
+	})
+
+	select {
+	case missingSLIProviders := <-missing:
+		assert.Equal(t, []string{"prometheus"}, missingSLIProviders)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout has not been handled")
+	}
+}
+
+func TestGetSLIRetrievalDeadline(t *testing.T) {
+	now := time.Date(2021, 2, 1, 10, 0, 0, 500, time.UTC)
+	assert.Equal(t, time.Date(2021, 2, 1, 10, 10, 0, 0, time.UTC), getSLIRetrievalDeadline(now, 10*time.Minute))
+	assert.True(t, getSLIRetrievalDeadline(now, 0).IsZero())
+}
+
+func TestGetStoredSLIRetrievals(t *testing.T) {
+	eventType := keptnv2.GetTriggeredEventType(keptnv2.GetSLITaskName)
+	newGetSLITriggeredEvent := func(id, source, triggeredID, sliProvider, deadline string) *models.KeptnContextExtendedCE {
+		return &models.KeptnContextExtendedCE{
+			ID:             id,
+			Source:         &source,
+			Shkeptncontext: "context-1",
+			Triggeredid:    triggeredID,
+			Type:           &eventType,
+			Data: map[string]interface{}{
+				"project":              "sockshop",
+				"get-sli":              map[string]interface{}{"sliProvider": sliProvider, "indicators": []string{"response_time_p95"}},
+				"sliRetrievalDeadline": deadline,
+			},
+		}
+	}
+
+	retrievals := getStoredSLIRetrievals([]*models.KeptnContextExtendedCE{
+		newGetSLITriggeredEvent("get-sli-2", "lighthouse-service", "evaluation-1", "dynatrace", "2021-02-01T10:10:00Z"),
+		newGetSLITriggeredEvent("get-sli-1", "lighthouse-service", "evaluation-1", "prometheus", "2021-02-01T10:10:00Z"),
+		// sent by a previous version of lighthouse
+		newGetSLITriggeredEvent("get-sli-3", "lighthouse-service", "", "prometheus", ""),
+		// sent without timeout
+		newGetSLITriggeredEvent("get-sli-4", "lighthouse-service", "evaluation-2", "prometheus", ""),
+		// not sent by lighthouse
+		newGetSLITriggeredEvent("get-sli-5", "my-service", "evaluation-3", "prometheus", "2021-02-01T10:10:00Z"),
+	})
+
+	assert.Equal(t, []*storedSLIRetrieval{
+		{
+			evaluationTriggeredID: "evaluation-1",
+			keptnContext:          "context-1",
+			requests: []*sliProviderRequest{
+				{ID: "get-sli-2", SLIProvider: "dynatrace", Indicators: []string{"response_time_p95"}},
+				{ID: "get-sli-1", SLIProvider: "prometheus", Indicators: []string{"response_time_p95"}},
+			},
+			deadline: time.Date(2021, 2, 1, 10, 10, 0, 0, time.UTC),
+		},
+		{
+			evaluationTriggeredID: "evaluation-2",
+			keptnContext:          "context-1",
+			requests: []*sliProviderRequest{
+				{ID: "get-sli-4", SLIProvider: "prometheus", Indicators: []string{"response_time_p95"}},
+			},
+		},
+	}, retrievals)
+}
+
+func TestGetSLIRetrievalTimeout(t *testing.T) {
+	defer os.Unsetenv(sliRetrievalTimeoutEnvVar)
+
+	os.Unsetenv(sliRetrievalTimeoutEnvVar)
+	timeout, err := getSLIRetrievalTimeout()
+	assert.Nil(t, err)
+	assert.Equal(t, defaultSLIRetrievalTimeout, timeout)
+
+	os.Setenv(sliRetrievalTimeoutEnvVar, "30m")
+	timeout, err = getSLIRetrievalTimeout()
+	assert.Nil(t, err)
+	assert.Equal(t, 30*time.Minute, timeout)
+
+	os.Setenv(sliRetrievalTimeoutEnvVar, "0")
+	timeout, err = getSLIRetrievalTimeout()
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), timeout)
+
+	os.Setenv(sliRetrievalTimeoutEnvVar, "ten minutes")
+	timeout, err = getSLIRetrievalTimeout()
+	assert.NotNil(t, err)
+	assert.Equal(t, defaultSLIRetrievalTimeout, timeout)
+}
@@ -4,10 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strings"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
 
 	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+
+	"github.com/keptn/keptn/lighthouse-service/pkg/scoring"
 )
 
 type StartEvaluationHandler struct {
@@ -47,12 +52,16 @@ func (eh *StartEvaluationHandler) HandleEvent() error {
 	indicators := []string{}
 	var filters = []*keptnv2.SLIFilter{}
 	// get SLO file
-	objectives, err := getSLOs(e.Project, e.Stage, e.Service)
+	var sliProviders map[string]string
+	objectives, sloFileContent, err := getSLOs(e.Project, e.Stage, e.Service)
 	if err == nil && objectives != nil {
 		eh.KeptnHandler.Logger.Info("SLO file found")
 		for _, objective := range objectives.Objectives {
 			indicators = append(indicators, objective.SLI)
 		}
+		if sliProviders, err = scoring.ParseSLIProviders(sloFileContent); err != nil {
+			return eh.sendEvaluationFinishedWithErrorEvent(keptnContext, evaluationStartTimestamp, evaluationEndTimestamp, e, "invalid sli_providers in SLO file: "+err.Error())
+		}
 
 		if objectives.Filter != nil {
 			for key, value := range objectives.Filter {
@@ -85,7 +94,7 @@ func (eh *StartEvaluationHandler) HandleEvent() error {
 	sliProvider, err = eh.SLIProviderConfig.GetSLIProvider(e.Project)
 	if err != nil {
 		sliProvider, err = eh.SLIProviderConfig.GetDefaultSLIProvider()
-		if err != nil {
+		if err != nil && len(sliProviders) == 0 {
 			eh.KeptnHandler.Logger.Error("no SLI-provider configured for project " + e.Project + ", no evaluation conducted")
 			evaluationDetails := keptnv2.EvaluationDetails{
 				IndicatorResults: nil,
@@ -110,12 +119,77 @@ func (eh *StartEvaluationHandler) HandleEvent() error {
 			return sendEvent(keptnContext, eh.Event.ID(), keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName), eh.KeptnHandler, &evaluationFinishedData)
 		}
 	}
-	// send a new event to trigger the SLI retrieval
-	eh.KeptnHandler.Logger.Debug("SLI provider for project " + e.Project + " is: " + sliProvider)
-	err = eh.sendInternalGetSLIEvent(keptnContext, e.Project, e.Stage, e.Service, sliProvider, indicators, evaluationStartTimestamp, evaluationEndTimestamp, filters, e.Labels)
+
+	// SLIs can be retrieved from different SLI providers, as configured by the sli_providers of the SLO file
+	requests := groupIndicatorsBySLIProvider(indicators, sliProviders, sliProvider)
+	for _, request := range requests {
+		if request.SLIProvider == "" {
+			return eh.sendEvaluationFinishedWithErrorEvent(keptnContext, evaluationStartTimestamp, evaluationEndTimestamp, e,
+				fmt.Sprintf("no SLI provider configured for project %s to retrieve the SLIs %s", e.Project, strings.Join(request.Indicators, ", ")))
+		}
+		request.ID = uuid.New().String()
+	}
+
+	timeout, err := getSLIRetrievalTimeout()
+	if err != nil {
+		eh.KeptnHandler.Logger.Error(fmt.Sprintf("Invalid %s: %s. Using default timeout of %s", sliRetrievalTimeoutEnvVar, err.Error(), timeout.String()))
+	}
+	deadline := getSLIRetrievalDeadline(time.Now(), timeout)
+	retrieval := eh.waitForSLIRetrieval(keptnContext, evaluationStartTimestamp, evaluationEndTimestamp, e, requests, deadline)
+
+	// send new events to trigger the SLI retrieval
+	for _, request := range requests {
+		eh.KeptnHandler.Logger.Debug("Retrieving SLIs " + strings.Join(request.Indicators, ", ") + " of project " + e.Project + " from SLI provider " + request.SLIProvider)
+		err = eh.sendInternalGetSLIEvent(keptnContext, request.ID, e.Project, e.Stage, e.Service, request.SLIProvider, request.Indicators, evaluationStartTimestamp, evaluationEndTimestamp, filters, e.Labels, deadline)
+		if err != nil {
+			pendingSLIRetrievals.cancel(retrieval)
+			eh.KeptnHandler.Logger.Error("Could not send get-sli.triggered event: " + err.Error())
+			return eh.sendEvaluationFinishedWithErrorEvent(keptnContext, evaluationStartTimestamp, evaluationEndTimestamp, e, "could not trigger the retrieval of SLIs from SLI provider "+request.SLIProvider+": "+err.Error())
+		}
+	}
 	return nil
 }
 
+// waitForSLIRetrieval registers the evaluation until the get-sli.finished events of all requests have been received, and fails it if they are not complete at the deadline
+func (eh *StartEvaluationHandler) waitForSLIRetrieval(keptnContext, start, end string, e *keptnv2.EvaluationTriggeredEventData, requests []*sliProviderRequest, deadline time.Time) *sliRetrieval {
+	return pendingSLIRetrievals.register(requests, deadline, func(missingSLIProviders []string) {
+		eh.handleSLIRetrievalTimeout(keptnContext, start, end, e, missingSLIProviders, deadline)
+	})
+}
+
+// resumeSLIRetrieval waits for the get-sli.finished events of requests that have been sent before lighthouse has been restarted
+func (eh *StartEvaluationHandler) resumeSLIRetrieval(requests []*sliProviderRequest, deadline time.Time) error {
+	var keptnContext string
+	_ = eh.Event.ExtensionAs("shkeptncontext", &keptnContext)
+
+	e := &keptnv2.EvaluationTriggeredEventData{}
+	if err := eh.Event.DataAs(e); err != nil {
+		return err
+	}
+	evaluationStartTimestamp, evaluationEndTimestamp, err := getEvaluationTimestamps(e)
+	if err != nil {
+		return err
+	}
+	eh.waitForSLIRetrieval(keptnContext, evaluationStartTimestamp, evaluationEndTimestamp, e, requests, deadline)
+	return nil
+}
+
+// handleSLIRetrievalTimeout fails an evaluation whose SLI providers did not send their get-sli.finished events before the deadline, unless it is already being evaluated
+func (eh *StartEvaluationHandler) handleSLIRetrievalTimeout(keptnContext, start, end string, e *keptnv2.EvaluationTriggeredEventData, missingSLIProviders []string, deadline time.Time) {
+	triggeredID := eh.Event.ID()
+	if !processedEvaluations.reserve(triggeredID) {
+		return
+	}
+	message := fmt.Sprintf("evaluation failed because no get-sli.finished event has been received from SLI provider %s until %s", strings.Join(missingSLIProviders, ", "), deadline.Format(time.RFC3339))
+	eh.KeptnHandler.Logger.Error(message)
+	if err := eh.sendEvaluationFinishedWithErrorEvent(keptnContext, start, end, e, message); err != nil {
+		processedEvaluations.release(triggeredID)
+		eh.KeptnHandler.Logger.Error("Could not send evaluation.finished event: " + err.Error())
+		return
+	}
+	processedEvaluations.complete(triggeredID)
+}
+
 func (eh *StartEvaluationHandler) sendEvaluationFinishedWithErrorEvent(keptnContext, start, end string, e *keptnv2.EvaluationTriggeredEventData, message string) error {
 	evaluationDetails := keptnv2.EvaluationDetails{
 		IndicatorResults: nil,
@@ -149,30 +223,38 @@ func getEvaluationTimestamps(e *keptnv2.EvaluationTriggeredEventData) (string, s
 	return "", "", errors.New("evaluation.triggered event does not contain evaluation timeframe")
 }
 
-func (eh *StartEvaluationHandler) sendInternalGetSLIEvent(shkeptncontext string, project string, stage string, service string, sliProvider string, indicators []string, start string, end string, filters []*keptnv2.SLIFilter, labels map[string]string) error {
+func (eh *StartEvaluationHandler) sendInternalGetSLIEvent(shkeptncontext string, eventID string, project string, stage string, service string, sliProvider string, indicators []string, start string, end string, filters []*keptnv2.SLIFilter, labels map[string]string, deadline time.Time) error {
 	source, _ := url.Parse("lighthouse-service")
 
-	getSLIEvent := keptnv2.GetSLITriggeredEventData{
-		EventData: keptnv2.EventData{
-			Project: project,
-			Stage:   stage,
-			Service: service,
-			Labels:  labels,
-		},
-		GetSLI: keptnv2.GetSLI{
-			SLIProvider:   sliProvider,
-			Start:         start,
-			End:           end,
-			Indicators:    indicators,
-			CustomFilters: filters,
+	getSLIEvent := getSLITriggeredEventData{
+		GetSLITriggeredEventData: keptnv2.GetSLITriggeredEventData{
+			EventData: keptnv2.EventData{
+				Project: project,
+				Stage:   stage,
+				Service: service,
+				Labels:  labels,
+			},
+			GetSLI: keptnv2.GetSLI{
+				SLIProvider:   sliProvider,
+				Start:         start,
+				End:           end,
+				Indicators:    indicators,
+				CustomFilters: filters,
+			},
 		},
 	}
+	if !deadline.IsZero() {
+		getSLIEvent.SLIRetrievalDeadline = deadline.Format(time.RFC3339)
+	}
 
 	event := cloudevents.NewEvent()
+	event.SetID(eventID)
 	event.SetType(keptnv2.GetTriggeredEventType(keptnv2.GetSLITaskName))
 	event.SetSource(source.String())
 	event.SetDataContentType(cloudevents.ApplicationJSON)
 	event.SetExtension("shkeptncontext", shkeptncontext)
+	// the triggeredid links the get-sli.triggered events of an evaluation to its evaluation.triggered event
+	event.SetExtension("triggeredid", eh.Event.ID())
 	event.SetData(cloudevents.ApplicationJSON, getSLIEvent)
 
 	eh.KeptnHandler.Logger.Debug("Send event: " + keptnv2.GetTriggeredEventType(keptnv2.GetSLITaskName))
@@ -0,0 +1,175 @@
+package event_handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/ghodss/yaml"
+
+	utils "github.com/keptn/go-utils/pkg/api/utils"
+	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+)
+
+// StaticSLIProvider is the name of the built-in SLI provider, which answers get-sli.triggered events with static values
+// instead of querying a monitoring backend
+const StaticSLIProvider = "static"
+
+// staticSLIValuesResourceURI is the URI of the resource containing the static SLI values
+const staticSLIValuesResourceURI = "sli-values.yaml"
+
+// staticSLIValueLabelPrefix is the prefix of event labels that set the value of an SLI, e.g. sli.response_time_p95=320
+const staticSLIValueLabelPrefix = "sli."
+
+// staticSLIValues is the content of a sli-values.yaml resource
+type staticSLIValues struct {
+	SpecVersion string             `json:"spec_version,omitempty"`
+	Indicators  map[string]float64 `json:"indicators"`
+}
+
+// StaticSLIHandler answers get-sli.triggered events for the static SLI provider with the values of the sli-values.yaml resources
+// and the labels of the event, which allows to evaluate objectives without a monitoring backend, e.g. in tests
+type StaticSLIHandler struct {
+	Event        cloudevents.Event
+	KeptnHandler *keptnv2.Keptn
+}
+
+func (eh *StaticSLIHandler) HandleEvent() error {
+	var keptnContext string
+	_ = eh.Event.ExtensionAs("shkeptncontext", &keptnContext)
+
+	e := &keptnv2.GetSLITriggeredEventData{}
+	err := eh.Event.DataAs(e)
+	if err != nil {
+		eh.KeptnHandler.Logger.Error("Could not parse event payload: " + err.Error())
+		return err
+	}
+
+	// get-sli.triggered events for other SLI providers are handled by the respective SLI services
+	if e.GetSLI.SLIProvider != StaticSLIProvider {
+		return nil
+	}
+
+	startedEvent := keptnv2.GetSLIStartedEventData{
+		EventData: keptnv2.EventData{
+			Project: e.Project,
+			Stage:   e.Stage,
+			Service: e.Service,
+			Labels:  e.Labels,
+			Status:  keptnv2.StatusSucceeded,
+		},
+	}
+	err = sendEvent(keptnContext, eh.Event.ID(), keptnv2.GetStartedEventType(keptnv2.GetSLITaskName), eh.KeptnHandler, startedEvent)
+	if err != nil {
+		eh.KeptnHandler.Logger.Error("Could not send get-sli.started event: " + err.Error())
+		return err
+	}
+
+	finishedEvent := keptnv2.GetSLIFinishedEventData{
+		EventData: keptnv2.EventData{
+			Project: e.Project,
+			Stage:   e.Stage,
+			Service: e.Service,
+			Labels:  e.Labels,
+			Status:  keptnv2.StatusSucceeded,
+			Result:  keptnv2.ResultPass,
+		},
+		GetSLI: keptnv2.GetSLIFinished{
+			Start: e.GetSLI.Start,
+			End:   e.GetSLI.End,
+		},
+	}
+
+	values, err := getStaticSLIValues(e.Project, e.Stage, e.Service, e.Labels)
+	if err != nil {
+		eh.KeptnHandler.Logger.Error("Could not retrieve static SLI values: " + err.Error())
+		finishedEvent.Status = keptnv2.StatusErrored
+		finishedEvent.Result = keptnv2.ResultFailed
+		finishedEvent.Message = err.Error()
+	} else {
+		finishedEvent.GetSLI.IndicatorValues = getStaticSLIResults(e.GetSLI.Indicators, values)
+	}
+
+	return sendEvent(keptnContext, eh.Event.ID(), keptnv2.GetFinishedEventType(keptnv2.GetSLITaskName), eh.KeptnHandler, finishedEvent)
+}
+
+// getStaticSLIValues merges the sli-values.yaml resources of the project, stage and service, where a more specific level overrides
+// the values of a more general one. Labels of the event prefixed with "sli." override the values of all resources.
+func getStaticSLIValues(project, stage, service string, labels map[string]string) (map[string]float64, error) {
+	endpoint, err := keptncommon.GetServiceEndpoint("CONFIGURATION_SERVICE")
+	if err != nil {
+		return nil, err
+	}
+	resourceHandler := utils.NewResourceHandler(endpoint.String())
+
+	resourceContents := []string{}
+	if resource, err := resourceHandler.GetProjectResource(project, staticSLIValuesResourceURI); err == nil && resource != nil {
+		resourceContents = append(resourceContents, resource.ResourceContent)
+	}
+	if resource, err := resourceHandler.GetStageResource(project, stage, staticSLIValuesResourceURI); err == nil && resource != nil {
+		resourceContents = append(resourceContents, resource.ResourceContent)
+	}
+	if resource, err := resourceHandler.GetServiceResource(project, stage, service, staticSLIValuesResourceURI); err == nil && resource != nil {
+		resourceContents = append(resourceContents, resource.ResourceContent)
+	}
+
+	values := map[string]float64{}
+	for _, content := range resourceContents {
+		parsed, err := parseStaticSLIValues([]byte(content))
+		if err != nil {
+			return nil, err
+		}
+		for sli, value := range parsed {
+			values[sli] = value
+		}
+	}
+
+	for key, label := range labels {
+		if !strings.HasPrefix(key, staticSLIValueLabelPrefix) {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(label), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value '%s' of label %s: must be a number", label, key)
+		}
+		values[strings.TrimPrefix(key, staticSLIValueLabelPrefix)] = value
+	}
+	return values, nil
+}
+
+// parseStaticSLIValues parses the content of a sli-values.yaml resource
+func parseStaticSLIValues(content []byte) (map[string]float64, error) {
+	sliValues := &staticSLIValues{}
+	if err := yaml.Unmarshal(content, sliValues); err != nil {
+		return nil, errors.New("could not parse " + staticSLIValuesResourceURI + ": " + err.Error())
+	}
+	if sliValues.Indicators == nil {
+		return map[string]float64{}, nil
+	}
+	return sliValues.Indicators, nil
+}
+
+// getStaticSLIResults returns the values of the requested indicators. An indicator without value is reported as failed.
+func getStaticSLIResults(indicators []string, values map[string]float64) []*keptnv2.SLIResult {
+	results := []*keptnv2.SLIResult{}
+	for _, indicator := range indicators {
+		value, ok := values[indicator]
+		if !ok {
+			results = append(results, &keptnv2.SLIResult{
+				Metric:  indicator,
+				Success: false,
+				Message: fmt.Sprintf("no value for SLI %s defined in %s or the labels of the event", indicator, staticSLIValuesResourceURI),
+			})
+			continue
+		}
+		results = append(results, &keptnv2.SLIResult{
+			Metric:  indicator,
+			Value:   value,
+			Success: true,
+		})
+	}
+	return results
+}
@@ -0,0 +1,75 @@
+package event_handler
+
+import (
+	b64 "encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/keptn/go-utils/pkg/api/models"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+)
+
+func TestGetStaticSLIValues(t *testing.T) {
+	resources := map[string]string{
+		"/v1/project/sockshop/resource/sli-values.yaml": `spec_version: "1.0"
+indicators:
+  response_time_p95: 300
+  error_rate: 0.1`,
+		"/v1/project/sockshop/stage/dev/service/carts/resource/sli-values.yaml": `spec_version: "1.0"
+indicators:
+  response_time_p95: 320
+  throughput: 1000`,
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := resources[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		uri := "sli-values.yaml"
+		resource, _ := json.Marshal(&models.Resource{ResourceURI: &uri, ResourceContent: b64.StdEncoding.EncodeToString([]byte(content))})
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(resource)
+	}))
+	defer ts.Close()
+
+	os.Setenv("CONFIGURATION_SERVICE", ts.URL)
+	defer os.Unsetenv("CONFIGURATION_SERVICE")
+
+	values, err := getStaticSLIValues("sockshop", "dev", "carts", map[string]string{"sli.error_rate": "0.5", "owner": "team-a"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]float64{"response_time_p95": 320, "error_rate": 0.5, "throughput": 1000}, values)
+
+	_, err = getStaticSLIValues("sockshop", "dev", "carts", map[string]string{"sli.error_rate": "low"})
+	assert.NotNil(t, err)
+	assert.True(t, strings.Contains(err.Error(), "invalid value 'low' of label sli.error_rate"))
+
+	// without any sli-values.yaml, only the labels are taken into account
+	values, err = getStaticSLIValues("sockshop", "production", "carts", map[string]string{"sli.throughput": "900"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]float64{"response_time_p95": 300, "error_rate": 0.1, "throughput": 900}, values)
+}
+
+func TestParseStaticSLIValues(t *testing.T) {
+	values, err := parseStaticSLIValues([]byte(`spec_version: "1.0"`))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]float64{}, values)
+
+	_, err = parseStaticSLIValues([]byte(`indicators:
+  response_time_p95: fast`))
+	assert.NotNil(t, err)
+}
+
+func TestGetStaticSLIResults(t *testing.T) {
+	results := getStaticSLIResults([]string{"response_time_p95", "error_rate"}, map[string]float64{"response_time_p95": 320, "throughput": 1000})
+	assert.Equal(t, []*keptnv2.SLIResult{
+		{Metric: "response_time_p95", Value: 320, Success: true},
+		{Metric: "error_rate", Success: false, Message: "no value for SLI error_rate defined in sli-values.yaml or the labels of the event"},
+	}, results)
+}
@@ -2,19 +2,26 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/kelseyhightower/envconfig"
 
 	keptnapi "github.com/keptn/go-utils/pkg/api/utils"
 	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
+	"github.com/keptn/keptn/lighthouse-service/api"
 	"github.com/keptn/keptn/lighthouse-service/event_handler"
 )
 
+// sliRetrievalResumeTimeout limits how long receiving events is delayed by resuming the pending SLI retrievals on startup
+const sliRetrievalResumeTimeout = 30 * time.Second
+
 type envConfig struct {
-	// Port on which to listen for cloudevents
+	// Port on which to listen for cloudevents and requests to the REST endpoints
 	Port int    `envconfig:"RCV_PORT" default:"8080"`
 	Path string `envconfig:"RCV_PATH" default:"/"`
 }
@@ -33,15 +40,34 @@ func _main(args []string, env envConfig) int {
 	ctx := context.Background()
 	ctx = cloudevents.WithEncodingStructured(ctx)
 
-	p, err := cloudevents.NewHTTP(cloudevents.WithPath(env.Path), cloudevents.WithPort(env.Port))
+	p, err := cloudevents.NewHTTP()
 	if err != nil {
 		log.Fatalf("failed to create client, %v", err)
 	}
-	c, err := cloudevents.NewClient(p)
+	receiver, err := cloudevents.NewHTTPReceiveHandler(ctx, p, gotEvent)
 	if err != nil {
-		log.Fatalf("failed to create client, %v", err)
+		log.Fatalf("failed to create handler, %v", err)
 	}
-	log.Fatal(c.StartReceiver(ctx, gotEvent))
+
+	// pending SLI retrievals are restored before events are received, so that get-sli.finished events are merged with the results of the other SLI providers.
+	// If this takes too long, e.g. because the datastore is not available, events are received while the SLI retrievals are still being restored
+	resumed := make(chan struct{})
+	go func() {
+		event_handler.ResumeSLIRetrievals()
+		close(resumed)
+	}()
+	select {
+	case <-resumed:
+	case <-time.After(sliRetrievalResumeTimeout):
+		log.Printf("Pending SLI retrievals have not been resumed within %s, receiving events in the meantime", sliRetrievalResumeTimeout.String())
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(api.SLOValidationPath, api.HandleSLOValidation)
+	mux.HandleFunc(api.ReEvaluationPath, api.NewReEvaluationHandler(event_handler.NewReEvaluator()))
+	mux.HandleFunc(api.ScoreTrendPath, api.NewScoreTrendHandler(event_handler.NewScoreTrendProvider()))
+	mux.Handle(env.Path, receiver)
+	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", env.Port), mux))
 
 	return 0
 }
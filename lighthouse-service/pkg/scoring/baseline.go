@@ -0,0 +1,111 @@
+package scoring
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// Labels of the evaluation.triggered event that pin the baseline of an evaluation. They take precedence over the baseline of the SLO file
+const (
+	BaselineKeptnContextLabel = "baseline-keptn-context"
+	BaselineEvaluationIDLabel = "baseline-evaluation-id"
+	BaselineLabelLabel        = "baseline-label"
+)
+
+// Baseline pins the previous evaluation that the comparison criteria of an SLO are evaluated against, instead of the last N evaluations.
+// Exactly one of its properties is expected to be set
+type Baseline struct {
+	// KeptnContext pins the evaluation of the given Keptn context
+	KeptnContext string `json:"keptn_context,omitempty" yaml:"keptn_context,omitempty"`
+	// EvaluationID pins the evaluation.finished event with the given ID
+	EvaluationID string `json:"evaluation_id,omitempty" yaml:"evaluation_id,omitempty"`
+	// Label pins the latest evaluation with the given label, in the format key=value
+	Label string `json:"label,omitempty" yaml:"label,omitempty"`
+}
+
+// IsSet checks if any property of the baseline is set
+func (b *Baseline) IsSet() bool {
+	return b != nil && (b.KeptnContext != "" || b.EvaluationID != "" || b.Label != "")
+}
+
+// String returns a human readable representation of the baseline
+func (b *Baseline) String() string {
+	switch {
+	case b == nil:
+		return ""
+	case b.KeptnContext != "":
+		return "evaluation of keptnContext " + b.KeptnContext
+	case b.EvaluationID != "":
+		return "evaluation " + b.EvaluationID
+	case b.Label != "":
+		return "latest evaluation with label " + b.Label
+	default:
+		return ""
+	}
+}
+
+// ParseLabel returns the key and the value of the label a baseline refers to
+func (b *Baseline) ParseLabel() (string, string, error) {
+	split := strings.SplitN(b.Label, "=", 2)
+	if len(split) != 2 || split[0] == "" || split[1] == "" {
+		return "", "", fmt.Errorf("invalid baseline label '%s': must be in the format key=value", b.Label)
+	}
+	// the label is used in the filter of a datastore query, in which ':' and ',' separate the filter criteria
+	if strings.ContainsAny(b.Label, ":,") {
+		return "", "", fmt.Errorf("invalid baseline label '%s': must not contain ':' or ','", b.Label)
+	}
+	return split[0], split[1], nil
+}
+
+// Validate checks if exactly one property of the baseline is set and if its label is in the format key=value
+func (b *Baseline) Validate() error {
+	set := 0
+	for _, property := range []string{b.KeptnContext, b.EvaluationID, b.Label} {
+		if property != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("exactly one of keptn_context, evaluation_id and label must be set")
+	}
+	if b.Label != "" {
+		if _, _, err := b.ParseLabel(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseBaseline returns the baseline of an SLO file, or nil if it does not pin a baseline
+func ParseBaseline(input []byte) (*Baseline, error) {
+	file := &sloFileExtensions{}
+	if err := yaml.Unmarshal(input, file); err != nil {
+		return nil, err
+	}
+	if !file.Baseline.IsSet() {
+		return nil, nil
+	}
+	if err := file.Baseline.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid baseline: %s", err.Error())
+	}
+	return file.Baseline, nil
+}
+
+// GetBaseline returns the baseline pinned by the labels of an evaluation, or the baseline of the SLO file if the labels do not pin one
+func GetBaseline(sloFileContent []byte, labels map[string]string) (*Baseline, error) {
+	baseline := &Baseline{
+		KeptnContext: labels[BaselineKeptnContextLabel],
+		EvaluationID: labels[BaselineEvaluationIDLabel],
+		Label:        labels[BaselineLabelLabel],
+	}
+	if baseline.IsSet() {
+		if err := baseline.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid baseline labels: %s", err.Error())
+		}
+		return baseline, nil
+	}
+	return ParseBaseline(sloFileContent)
+}
@@ -0,0 +1,101 @@
+package scoring
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBaseline(t *testing.T) {
+	tests := []struct {
+		name             string
+		sloFileContent   string
+		expectedBaseline *Baseline
+		expectedError    error
+	}{
+		{
+			name:             "no baseline",
+			sloFileContent:   "spec_version: '1.0'",
+			expectedBaseline: nil,
+		},
+		{
+			name: "keptn context",
+			sloFileContent: `spec_version: '1.0'
+baseline:
+  keptn_context: "a4b3c2d1"`,
+			expectedBaseline: &Baseline{KeptnContext: "a4b3c2d1"},
+		},
+		{
+			name: "label",
+			sloFileContent: `spec_version: '1.0'
+baseline:
+  label: "release=true"`,
+			expectedBaseline: &Baseline{Label: "release=true"},
+		},
+		{
+			name: "more than one property",
+			sloFileContent: `spec_version: '1.0'
+baseline:
+  keptn_context: "a4b3c2d1"
+  evaluation_id: "e1"`,
+			expectedError: errors.New("invalid baseline: exactly one of keptn_context, evaluation_id and label must be set"),
+		},
+		{
+			name: "label without value",
+			sloFileContent: `spec_version: '1.0'
+baseline:
+  label: "release"`,
+			expectedError: errors.New("invalid baseline: invalid baseline label 'release': must be in the format key=value"),
+		},
+		{
+			name: "label value with colon",
+			sloFileContent: `spec_version: '1.0'
+baseline:
+  label: "release=2021-02-01T10:00"`,
+			expectedError: errors.New("invalid baseline: invalid baseline label 'release=2021-02-01T10:00': must not contain ':' or ','"),
+		},
+		{
+			name: "label value with comma",
+			sloFileContent: `spec_version: '1.0'
+baseline:
+  label: "release=true,stage=prod"`,
+			expectedError: errors.New("invalid baseline: invalid baseline label 'release=true,stage=prod': must not contain ':' or ','"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseline, err := ParseBaseline([]byte(tt.sloFileContent))
+			assert.Equal(t, tt.expectedBaseline, baseline)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestGetBaseline(t *testing.T) {
+	sloFileContent := []byte(`spec_version: '1.0'
+baseline:
+  label: "release=true"`)
+
+	// the labels of the evaluation take precedence over the SLO file
+	baseline, err := GetBaseline(sloFileContent, map[string]string{BaselineEvaluationIDLabel: "e1", "buildId": "123"})
+	assert.Nil(t, err)
+	assert.Equal(t, &Baseline{EvaluationID: "e1"}, baseline)
+
+	baseline, err = GetBaseline(sloFileContent, map[string]string{"buildId": "123"})
+	assert.Nil(t, err)
+	assert.Equal(t, &Baseline{Label: "release=true"}, baseline)
+
+	_, err = GetBaseline(sloFileContent, map[string]string{BaselineEvaluationIDLabel: "e1", BaselineKeptnContextLabel: "a4b3c2d1"})
+	assert.NotNil(t, err)
+}
+
+func TestMergeSLOFilesKeepsBaseline(t *testing.T) {
+	content, err := MergeSLOFiles([]byte(`baseline:
+  label: "release=true"`), []byte(`spec_version: '1.0'`))
+	assert.Nil(t, err)
+
+	baseline, err := ParseBaseline(content)
+	assert.Nil(t, err)
+	assert.Equal(t, &Baseline{Label: "release=true"}, baseline)
+}
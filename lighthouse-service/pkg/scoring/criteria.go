@@ -0,0 +1,646 @@
+package scoring
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	keptn "github.com/keptn/go-utils/pkg/lib"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+)
+
+// A criteria string is parsed into an abstract syntax tree based on the following grammar:
+//
+//	criteria          = or-expression
+//	or-expression     = and-expression { "or" and-expression }
+//	and-expression    = not-expression { "and" not-expression }
+//	not-expression    = "not" not-expression | primary
+//	primary           = sli-comparison | range | comparison | "(" or-expression ")"
+//	sli-comparison    = operator ( ("+" | "-") number [ "%" ] | number [ "%" ] | arithmetic )
+//	range             = [ "-" ] number ".." [ "-" ] number
+//	comparison        = arithmetic operator arithmetic
+//	arithmetic        = term { ("+" | "-") term }
+//	term              = factor { ("*" | "/") factor }
+//	factor            = number | sli-name | "-" factor | "(" arithmetic ")"
+//	operator          = "<" | "<=" | "=" | ">=" | ">"
+//
+// An sli-comparison compares the value of the evaluated SLI, e.g. <=+10% or <500, while a comparison can refer to the values of other SLIs, e.g. error_count / throughput < 0.01
+
+type criteriaTokenType int
+
+const (
+	tokenNumber criteriaTokenType = iota
+	tokenIdentifier
+	tokenComparisonOperator
+	tokenArithmeticOperator
+	tokenPercent
+	tokenRange
+	tokenLeftParenthesis
+	tokenRightParenthesis
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenEnd
+)
+
+type criteriaToken struct {
+	tokenType criteriaTokenType
+	text      string
+	// position is the position of the token within the criteria string, starting with 1
+	position int
+}
+
+func (t criteriaToken) String() string {
+	if t.tokenType == tokenEnd {
+		return "end of criteria"
+	}
+	return fmt.Sprintf("'%s' at position %d", t.text, t.position)
+}
+
+// criteriaSyntaxError describes why a criteria string could not be parsed
+type criteriaSyntaxError struct {
+	position int
+	message  string
+}
+
+func (e *criteriaSyntaxError) Error() string {
+	return e.message
+}
+
+func newUnexpectedTokenError(token criteriaToken, expected string) *criteriaSyntaxError {
+	return &criteriaSyntaxError{
+		position: token.position,
+		message:  fmt.Sprintf("unexpected %s, expected %s", token.String(), expected),
+	}
+}
+
+func tokenizeCriteria(criteria string) ([]criteriaToken, error) {
+	tokens := []criteriaToken{}
+	for i := 0; i < len(criteria); {
+		c := criteria[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+			continue
+		case isDigit(c) || (c == '.' && i+1 < len(criteria) && isDigit(criteria[i+1])):
+			for i < len(criteria) && isDigit(criteria[i]) {
+				i++
+			}
+			// a dot belongs to the number unless it is part of a range, e.g. 200..500
+			if i < len(criteria) && criteria[i] == '.' && !(i+1 < len(criteria) && criteria[i+1] == '.') {
+				i++
+				for i < len(criteria) && isDigit(criteria[i]) {
+					i++
+				}
+			}
+			tokens = append(tokens, criteriaToken{tokenType: tokenNumber, text: criteria[start:i]})
+		case c == '.' && i+1 < len(criteria) && criteria[i+1] == '.':
+			i += 2
+			tokens = append(tokens, criteriaToken{tokenType: tokenRange, text: ".."})
+		case isLetter(c):
+			for i < len(criteria) && (isLetter(criteria[i]) || isDigit(criteria[i])) {
+				i++
+			}
+			text := criteria[start:i]
+			switch strings.ToLower(text) {
+			case "and":
+				tokens = append(tokens, criteriaToken{tokenType: tokenAnd, text: text})
+			case "or":
+				tokens = append(tokens, criteriaToken{tokenType: tokenOr, text: text})
+			case "not":
+				tokens = append(tokens, criteriaToken{tokenType: tokenNot, text: text})
+			default:
+				tokens = append(tokens, criteriaToken{tokenType: tokenIdentifier, text: text})
+			}
+		case c == '<' || c == '>' || c == '=':
+			i++
+			if i < len(criteria) && criteria[i] == '=' {
+				i++
+			}
+			text := criteria[start:i]
+			if text == "==" {
+				text = "="
+			}
+			tokens = append(tokens, criteriaToken{tokenType: tokenComparisonOperator, text: text})
+		case c == '+' || c == '-' || c == '*' || c == '/':
+			i++
+			tokens = append(tokens, criteriaToken{tokenType: tokenArithmeticOperator, text: string(c)})
+		case c == '%':
+			i++
+			tokens = append(tokens, criteriaToken{tokenType: tokenPercent, text: "%"})
+		case c == '(':
+			i++
+			tokens = append(tokens, criteriaToken{tokenType: tokenLeftParenthesis, text: "("})
+		case c == ')':
+			i++
+			tokens = append(tokens, criteriaToken{tokenType: tokenRightParenthesis, text: ")"})
+		default:
+			return nil, &criteriaSyntaxError{
+				position: i + 1,
+				message:  fmt.Sprintf("unexpected character '%c' at position %d", c, i+1),
+			}
+		}
+		tokens[len(tokens)-1].position = start + 1
+	}
+	tokens = append(tokens, criteriaToken{tokenType: tokenEnd, position: len(criteria) + 1})
+	return tokens, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+}
+
+// criteriaContext contains the values a criteria is evaluated against
+type criteriaContext struct {
+	sliResult       *keptnv2.SLIResult
+	indicatorValues []*keptnv2.SLIResult
+	previousResults []*keptnv2.SLIEvaluationResult
+	comparison      *keptn.SLOComparison
+	target          *keptnv2.SLITarget
+}
+
+// criteriaNode is a node of a parsed criteria that evaluates to true or false
+type criteriaNode interface {
+	evaluate(ctx *criteriaContext) (bool, error)
+}
+
+// valueNode is a node of a parsed criteria that evaluates to a number
+type valueNode interface {
+	value(ctx *criteriaContext) (float64, error)
+}
+
+type orNode struct {
+	left  criteriaNode
+	right criteriaNode
+}
+
+func (n *orNode) evaluate(ctx *criteriaContext) (bool, error) {
+	left, err := n.left.evaluate(ctx)
+	if err != nil {
+		return false, err
+	}
+	if left {
+		return true, nil
+	}
+	return n.right.evaluate(ctx)
+}
+
+type andNode struct {
+	left  criteriaNode
+	right criteriaNode
+}
+
+func (n *andNode) evaluate(ctx *criteriaContext) (bool, error) {
+	left, err := n.left.evaluate(ctx)
+	if err != nil || !left {
+		return false, err
+	}
+	return n.right.evaluate(ctx)
+}
+
+type notNode struct {
+	operand criteriaNode
+}
+
+func (n *notNode) evaluate(ctx *criteriaContext) (bool, error) {
+	result, err := n.operand.evaluate(ctx)
+	if err != nil {
+		return false, err
+	}
+	return !result, nil
+}
+
+// sliComparisonNode compares the value of the evaluated SLI with a fixed threshold or with the results of previous evaluations
+type sliComparisonNode struct {
+	criteria *criteriaObject
+}
+
+func (n *sliComparisonNode) evaluate(ctx *criteriaContext) (bool, error) {
+	if !n.criteria.IsComparison {
+		return evaluateFixedThreshold(ctx.sliResult, n.criteria, ctx.target)
+	}
+	return evaluateComparison(ctx.sliResult, n.criteria, ctx.previousResults, ctx.comparison, ctx.target)
+}
+
+// rangeNode checks if the value of the evaluated SLI is within the bounds of a range, including the bounds
+type rangeNode struct {
+	lower float64
+	upper float64
+}
+
+func (n *rangeNode) evaluate(ctx *criteriaContext) (bool, error) {
+	return ctx.sliResult.Value >= n.lower && ctx.sliResult.Value <= n.upper, nil
+}
+
+// comparisonNode compares the results of two arithmetic expressions
+type comparisonNode struct {
+	left     valueNode
+	operator string
+	right    valueNode
+}
+
+func (n *comparisonNode) evaluate(ctx *criteriaContext) (bool, error) {
+	left, err := n.left.value(ctx)
+	if err != nil {
+		return false, err
+	}
+	right, err := n.right.value(ctx)
+	if err != nil {
+		return false, err
+	}
+	return evaluateValue(left, right, n.operator)
+}
+
+type numberNode struct {
+	number float64
+}
+
+func (n *numberNode) value(ctx *criteriaContext) (float64, error) {
+	return n.number, nil
+}
+
+// sliValueNode refers to the value of an SLI by its name
+type sliValueNode struct {
+	name string
+}
+
+func (n *sliValueNode) value(ctx *criteriaContext) (float64, error) {
+	result := getSLIResult(ctx.indicatorValues, n.name)
+	if result == nil || !result.Success {
+		return 0, fmt.Errorf("no value available for SLI %s", n.name)
+	}
+	return result.Value, nil
+}
+
+// evaluatedSLIValueNode refers to the value of the evaluated SLI
+type evaluatedSLIValueNode struct{}
+
+func (n *evaluatedSLIValueNode) value(ctx *criteriaContext) (float64, error) {
+	return ctx.sliResult.Value, nil
+}
+
+type negationNode struct {
+	operand valueNode
+}
+
+func (n *negationNode) value(ctx *criteriaContext) (float64, error) {
+	value, err := n.operand.value(ctx)
+	return -value, err
+}
+
+type arithmeticNode struct {
+	left     valueNode
+	operator string
+	right    valueNode
+}
+
+func (n *arithmeticNode) value(ctx *criteriaContext) (float64, error) {
+	left, err := n.left.value(ctx)
+	if err != nil {
+		return 0, err
+	}
+	right, err := n.right.value(ctx)
+	if err != nil {
+		return 0, err
+	}
+	switch n.operator {
+	case "+":
+		return left + right, nil
+	case "-":
+		return left - right, nil
+	case "*":
+		return left * right, nil
+	default:
+		if right == 0 {
+			return 0, errors.New("division by zero")
+		}
+		return left / right, nil
+	}
+}
+
+type criteriaParser struct {
+	tokens   []criteriaToken
+	position int
+}
+
+// parseCriteria parses a criteria string into an abstract syntax tree
+func parseCriteria(criteria string) (criteriaNode, error) {
+	tokens, err := tokenizeCriteria(criteria)
+	if err != nil {
+		return nil, err
+	}
+	p := &criteriaParser{tokens: tokens}
+	if p.peek().tokenType == tokenEnd {
+		return nil, errors.New("criteria must not be empty")
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if token := p.peek(); token.tokenType != tokenEnd {
+		return nil, newUnexpectedTokenError(token, "'and', 'or' or end of criteria")
+	}
+	return node, nil
+}
+
+func (p *criteriaParser) peek() criteriaToken {
+	return p.tokens[p.position]
+}
+
+func (p *criteriaParser) next() criteriaToken {
+	token := p.tokens[p.position]
+	if token.tokenType != tokenEnd {
+		p.position++
+	}
+	return token
+}
+
+func (p *criteriaParser) parseOr() (criteriaNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().tokenType == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *criteriaParser) parseAnd() (criteriaNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().tokenType == tokenAnd {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *criteriaParser) parseNot() (criteriaNode, error) {
+	if p.peek().tokenType == tokenNot {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *criteriaParser) parsePrimary() (criteriaNode, error) {
+	switch token := p.peek(); {
+	case token.tokenType == tokenComparisonOperator:
+		return p.parseSLIComparison()
+	case token.tokenType == tokenLeftParenthesis:
+		// a parenthesis either groups criteria, e.g. (<500 or >1000) and <2000, or is part of an arithmetic expression, e.g. (a + b) / c < 1
+		start := p.position
+		node, comparisonErr := p.parseComparison()
+		if comparisonErr == nil {
+			return node, nil
+		}
+		p.position = start
+		node, groupErr := p.parseGroup()
+		if groupErr == nil {
+			return node, nil
+		}
+		return nil, getFurthestSyntaxError(comparisonErr, groupErr)
+	case p.isRange():
+		return p.parseRange()
+	default:
+		return p.parseComparison()
+	}
+}
+
+func (p *criteriaParser) parseGroup() (criteriaNode, error) {
+	p.next()
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if token := p.next(); token.tokenType != tokenRightParenthesis {
+		return nil, newUnexpectedTokenError(token, "')'")
+	}
+	return node, nil
+}
+
+func (p *criteriaParser) parseSLIComparison() (criteriaNode, error) {
+	co := &criteriaObject{Operator: p.next().text}
+
+	// a signed number is compared with the results of previous evaluations
+	if sign := p.peek(); sign.tokenType == tokenArithmeticOperator && (sign.text == "+" || sign.text == "-") {
+		p.next()
+		number, err := p.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+		co.Value = number
+		co.IsComparison = true
+		co.CheckIncrease = sign.text == "+"
+		if p.peek().tokenType == tokenPercent {
+			p.next()
+			co.CheckPercentage = true
+		}
+		return &sliComparisonNode{criteria: co}, nil
+	}
+
+	right, err := p.parseArithmetic()
+	if err != nil {
+		return nil, err
+	}
+	number, ok := right.(*numberNode)
+	if !ok {
+		return &comparisonNode{left: &evaluatedSLIValueNode{}, operator: co.Operator, right: right}, nil
+	}
+	co.Value = number.number
+	if p.peek().tokenType == tokenPercent {
+		// Issue #1498: criteria containing '%' is always a comparison
+		p.next()
+		co.CheckPercentage = true
+		co.IsComparison = true
+		co.CheckIncrease = true
+	}
+	return &sliComparisonNode{criteria: co}, nil
+}
+
+func (p *criteriaParser) isRange() bool {
+	offset := p.position
+	if token := p.tokens[offset]; token.tokenType == tokenArithmeticOperator && token.text == "-" {
+		offset++
+	}
+	return offset+1 < len(p.tokens) && p.tokens[offset].tokenType == tokenNumber && p.tokens[offset+1].tokenType == tokenRange
+}
+
+func (p *criteriaParser) parseRange() (criteriaNode, error) {
+	lower, err := p.parseSignedNumber()
+	if err != nil {
+		return nil, err
+	}
+	p.next()
+	upper, err := p.parseSignedNumber()
+	if err != nil {
+		return nil, err
+	}
+	if lower > upper {
+		return nil, fmt.Errorf("invalid range %v..%v: the lower bound must not be greater than the upper bound", lower, upper)
+	}
+	return &rangeNode{lower: lower, upper: upper}, nil
+}
+
+func (p *criteriaParser) parseComparison() (criteriaNode, error) {
+	left, err := p.parseArithmetic()
+	if err != nil {
+		return nil, err
+	}
+	operator := p.next()
+	if operator.tokenType != tokenComparisonOperator {
+		return nil, newUnexpectedTokenError(operator, "a comparison operator")
+	}
+	right, err := p.parseArithmetic()
+	if err != nil {
+		return nil, err
+	}
+	return &comparisonNode{left: left, operator: operator.text, right: right}, nil
+}
+
+func (p *criteriaParser) parseArithmetic() (valueNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for token := p.peek(); token.tokenType == tokenArithmeticOperator && (token.text == "+" || token.text == "-"); token = p.peek() {
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithmeticNode{left: left, operator: token.text, right: right}
+	}
+	return left, nil
+}
+
+func (p *criteriaParser) parseTerm() (valueNode, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for token := p.peek(); token.tokenType == tokenArithmeticOperator && (token.text == "*" || token.text == "/"); token = p.peek() {
+		p.next()
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithmeticNode{left: left, operator: token.text, right: right}
+	}
+	return left, nil
+}
+
+func (p *criteriaParser) parseFactor() (valueNode, error) {
+	token := p.peek()
+	switch {
+	case token.tokenType == tokenNumber:
+		number, err := p.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+		return &numberNode{number: number}, nil
+	case token.tokenType == tokenIdentifier:
+		p.next()
+		return &sliValueNode{name: token.text}, nil
+	case token.tokenType == tokenArithmeticOperator && token.text == "-":
+		p.next()
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return &negationNode{operand: operand}, nil
+	case token.tokenType == tokenLeftParenthesis:
+		p.next()
+		node, err := p.parseArithmetic()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.tokenType != tokenRightParenthesis {
+			return nil, newUnexpectedTokenError(closing, "')'")
+		}
+		return node, nil
+	default:
+		return nil, newUnexpectedTokenError(token, "a number or SLI name")
+	}
+}
+
+func (p *criteriaParser) parseNumber() (float64, error) {
+	token := p.next()
+	if token.tokenType != tokenNumber {
+		return 0, newUnexpectedTokenError(token, "a number")
+	}
+	number, err := strconv.ParseFloat(token.text, 64)
+	if err != nil {
+		return 0, &criteriaSyntaxError{position: token.position, message: fmt.Sprintf("invalid number %s", token.String())}
+	}
+	return number, nil
+}
+
+func (p *criteriaParser) parseSignedNumber() (float64, error) {
+	if token := p.peek(); token.tokenType == tokenArithmeticOperator && token.text == "-" {
+		p.next()
+		number, err := p.parseNumber()
+		return -number, err
+	}
+	return p.parseNumber()
+}
+
+// getFurthestSyntaxError returns the error that occurred at the later position, as it describes the problem more precisely
+func getFurthestSyntaxError(first, second error) error {
+	firstSyntaxError, ok := first.(*criteriaSyntaxError)
+	if !ok {
+		return first
+	}
+	secondSyntaxError, ok := second.(*criteriaSyntaxError)
+	if !ok {
+		return second
+	}
+	if secondSyntaxError.position > firstSyntaxError.position {
+		return second
+	}
+	return first
+}
+
+// getReferencedSLIs returns the names of all SLIs a criteria refers to
+func getReferencedSLIs(node interface{}) []string {
+	switch n := node.(type) {
+	case *orNode:
+		return append(getReferencedSLIs(n.left), getReferencedSLIs(n.right)...)
+	case *andNode:
+		return append(getReferencedSLIs(n.left), getReferencedSLIs(n.right)...)
+	case *notNode:
+		return getReferencedSLIs(n.operand)
+	case *comparisonNode:
+		return append(getReferencedSLIs(n.left), getReferencedSLIs(n.right)...)
+	case *arithmeticNode:
+		return append(getReferencedSLIs(n.left), getReferencedSLIs(n.right)...)
+	case *negationNode:
+		return getReferencedSLIs(n.operand)
+	case *sliValueNode:
+		return []string{n.name}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,182 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	keptnmodelsv2 "github.com/keptn/go-utils/pkg/lib"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+)
+
+func TestParseCriteria_InvalidCriteria(t *testing.T) {
+	tests := []struct {
+		Criteria      string
+		ExpectedError string
+	}{
+		{
+			Criteria:      "",
+			ExpectedError: "criteria must not be empty",
+		},
+		{
+			Criteria:      "<=10ms",
+			ExpectedError: "unexpected 'ms' at position 5, expected 'and', 'or' or end of criteria",
+		},
+		{
+			Criteria:      "error_count / < 0.01",
+			ExpectedError: "unexpected '<' at position 15, expected a number or SLI name",
+		},
+		{
+			Criteria:      "(<500 or >1000",
+			ExpectedError: "unexpected end of criteria, expected ')'",
+		},
+		{
+			Criteria:      "(error_count + 1) / throughput",
+			ExpectedError: "unexpected end of criteria, expected a comparison operator",
+		},
+		{
+			Criteria:      "<500 and",
+			ExpectedError: "unexpected end of criteria, expected a number or SLI name",
+		},
+		{
+			Criteria:      "500..200",
+			ExpectedError: "invalid range 500..200: the lower bound must not be greater than the upper bound",
+		},
+		{
+			Criteria:      "<500 & >100",
+			ExpectedError: "unexpected character '&' at position 6",
+		},
+		{
+			Criteria:      "<=+",
+			ExpectedError: "unexpected end of criteria, expected a number",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Criteria, func(t *testing.T) {
+			node, err := parseCriteria(test.Criteria)
+			assert.Nil(t, node)
+			assert.EqualError(t, err, test.ExpectedError)
+		})
+	}
+}
+
+func TestEvaluateSingleCriteria_Expressions(t *testing.T) {
+	indicatorValues := []*keptnv2.SLIResult{
+		{Metric: "response_time", Value: 300, Success: true},
+		{Metric: "error_count", Value: 5, Success: true},
+		{Metric: "throughput", Value: 1000, Success: true},
+		{Metric: "failed_sli", Success: false},
+	}
+	previousResults := getPreviousSLIEvaluationResults(280, 280)
+
+	tests := []struct {
+		Criteria            string
+		ExpectedResult      bool
+		ExpectedTargetValue float64
+	}{
+		{Criteria: "200..500", ExpectedResult: true},
+		{Criteria: "300..300", ExpectedResult: true},
+		{Criteria: "-10..299.5", ExpectedResult: false},
+		{Criteria: "error_count / throughput < 0.01", ExpectedResult: true},
+		{Criteria: "error_count / throughput < 0.005", ExpectedResult: false},
+		{Criteria: "(error_count + 5) / throughput <= 0.01", ExpectedResult: true},
+		{Criteria: "< throughput / 2", ExpectedResult: true},
+		{Criteria: "<400 and >200", ExpectedResult: true},
+		{Criteria: "<400 and >300", ExpectedResult: false},
+		{Criteria: ">400 or =300", ExpectedResult: true},
+		{Criteria: "not 400..500", ExpectedResult: true},
+		{Criteria: "NOT (<400 AND error_count = 5)", ExpectedResult: false},
+		{Criteria: "(<200 or >250) and error_count < 10", ExpectedResult: true},
+		{Criteria: "<=+10% and <1000", ExpectedResult: true},
+		{Criteria: "not error_count > 1 or <=+10%", ExpectedResult: true},
+		// criteria referring to an SLI without value are never satisfied
+		{Criteria: "failed_sli < 1", ExpectedResult: false},
+		{Criteria: "not failed_sli < 1", ExpectedResult: false},
+		{Criteria: "unknown_sli < 1", ExpectedResult: false},
+		{Criteria: "error_count / (throughput - 1000) < 1", ExpectedResult: false},
+		// the target value is reported for criteria consisting of a single comparison of the SLI value
+		{Criteria: "<=+10%", ExpectedResult: true, ExpectedTargetValue: 308},
+		{Criteria: "<250", ExpectedResult: false, ExpectedTargetValue: 250},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Criteria, func(t *testing.T) {
+			target := &keptnv2.SLITarget{Criteria: test.Criteria}
+			result, _ := evaluateSingleCriteria(
+				indicatorValues[0],
+				indicatorValues,
+				test.Criteria,
+				previousResults,
+				&keptnmodelsv2.SLOComparison{CompareWith: "several_results", NumberOfComparisonResults: 2, AggregateFunction: "avg"},
+				target,
+			)
+			assert.Equal(t, test.ExpectedResult, result)
+			assert.InDelta(t, test.ExpectedTargetValue, target.TargetValue, 0.0001)
+		})
+	}
+}
+
+func TestValidateSLOCriteria(t *testing.T) {
+	tests := []struct {
+		Name          string
+		SLO           *keptnmodelsv2.ServiceLevelObjectives
+		ExpectedError string
+	}{
+		{
+			Name: "valid criteria",
+			SLO: &keptnmodelsv2.ServiceLevelObjectives{
+				Objectives: []*keptnmodelsv2.SLO{
+					{
+						SLI:  "response_time",
+						Pass: []*keptnmodelsv2.SLOCriteria{{Criteria: []string{"<=+10%", "200..500"}}},
+					},
+					{
+						SLI:     "error_count",
+						Pass:    []*keptnmodelsv2.SLOCriteria{{Criteria: []string{"error_count / throughput < 0.01"}}},
+						Warning: []*keptnmodelsv2.SLOCriteria{{Criteria: []string{"error_count / throughput < 0.05 and not <0"}}},
+					},
+					{
+						SLI: "throughput",
+					},
+				},
+			},
+		},
+		{
+			Name: "syntax error",
+			SLO: &keptnmodelsv2.ServiceLevelObjectives{
+				Objectives: []*keptnmodelsv2.SLO{
+					{
+						SLI:     "response_time",
+						Pass:    []*keptnmodelsv2.SLOCriteria{{Criteria: []string{"<=+10%"}}},
+						Warning: []*keptnmodelsv2.SLOCriteria{{Criteria: []string{"<=800 or"}}},
+					},
+				},
+			},
+			ExpectedError: "invalid criteria '<=800 or' of SLI response_time: unexpected end of criteria, expected a number or SLI name",
+		},
+		{
+			Name: "unknown SLI",
+			SLO: &keptnmodelsv2.ServiceLevelObjectives{
+				Objectives: []*keptnmodelsv2.SLO{
+					{
+						SLI:  "error_count",
+						Pass: []*keptnmodelsv2.SLOCriteria{{Criteria: []string{"error_count / throughput < 0.01"}}},
+					},
+				},
+			},
+			ExpectedError: "invalid criteria 'error_count / throughput < 0.01' of SLI error_count: SLI throughput is not listed in the objectives",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := ValidateSLOCriteria(test.SLO)
+			if test.ExpectedError == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.EqualError(t, err, test.ExpectedError)
+			}
+		})
+	}
+}
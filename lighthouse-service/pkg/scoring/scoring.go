@@ -0,0 +1,465 @@
+// Package scoring evaluates SLI values against the objectives of an SLO file and calculates the total score of an evaluation.
+// It is used by the lighthouse-service and by the Keptn CLI to evaluate SLOs locally.
+package scoring
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	keptn "github.com/keptn/go-utils/pkg/lib"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+)
+
+// minStatisticalComparisonValues is the number of previous values required to compare a value with their distribution
+const minStatisticalComparisonValues = 2
+
+// noVarianceMessage is added to the message of an SLI result whose statistical comparison has been skipped since all previous values are identical
+const noVarianceMessage = "not enough variance in the previous results for a statistical comparison"
+
+// madScaleFactor scales the median absolute deviation to an estimate of the standard deviation of normally distributed values
+const madScaleFactor = 1.4826
+
+type criteriaObject struct {
+	Operator        string
+	Value           float64
+	CheckPercentage bool
+	IsComparison    bool
+	CheckIncrease   bool
+}
+
+// Evaluate evaluates the SLI values of a get-sli.finished event against the objectives of an SLO and calculates the total score.
+// The previous evaluations are used for the comparison criteria of the objectives
+func Evaluate(e *keptnv2.GetSLIFinishedEventData, sloConfig *keptn.ServiceLevelObjectives, previousEvaluationEvents []*keptnv2.EvaluationFinishedEventData) (*keptnv2.EvaluationFinishedEventData, error) {
+	evaluationResult, maximumAchievableScore, keySLIFailed := EvaluateObjectives(e, sloConfig, previousEvaluationEvents)
+	if err := CalculateScore(maximumAchievableScore, evaluationResult, sloConfig, keySLIFailed); err != nil {
+		return nil, err
+	}
+	return evaluationResult, nil
+}
+
+// EvaluateObjectives evaluates the SLI values against each objective. It returns the results of the objectives, the maximum achievable score and whether a key SLI has failed
+func EvaluateObjectives(e *keptnv2.GetSLIFinishedEventData, sloConfig *keptn.ServiceLevelObjectives, previousEvaluationEvents []*keptnv2.EvaluationFinishedEventData) (*keptnv2.EvaluationFinishedEventData, float64, bool) {
+	evaluationResult := &keptnv2.EvaluationFinishedEventData{
+		EventData: keptnv2.EventData{
+			Status:  "",
+			Project: e.Project,
+			Service: e.Service,
+			Stage:   e.Stage,
+		},
+		Evaluation: keptnv2.EvaluationDetails{
+			TimeStart: e.GetSLI.Start,
+			TimeEnd:   e.GetSLI.End,
+		},
+	}
+	var sliEvaluationResults []*keptnv2.SLIEvaluationResult
+	maximumAchievableScore := 0.0
+	keySLIFailed := false
+	for _, objective := range sloConfig.Objectives {
+		// only consider the SLI for the total score if pass criteria have been included
+		if len(objective.Pass) > 0 {
+			maximumAchievableScore += float64(objective.Weight)
+		}
+		sliEvaluationResult := &keptnv2.SLIEvaluationResult{}
+		result := getSLIResult(e.GetSLI.IndicatorValues, objective.SLI)
+
+		if result == nil {
+			// no result available => fail the objective
+			sliEvaluationResult.Value = &keptnv2.SLIResult{
+				Metric:  objective.SLI,
+				Success: false,
+				Message: "no value received from SLI provider",
+			}
+			sliEvaluationResult.Status = "fail"
+			sliEvaluationResult.Score = 0
+			continue
+		}
+		sliEvaluationResult.Value = (*keptnv2.SLIResult)(result)
+
+		// gather the previous results for the current SLI
+		var previousSLIResults []*keptnv2.SLIEvaluationResult
+
+		if previousEvaluationEvents != nil && len(previousEvaluationEvents) > 0 {
+			for _, event := range previousEvaluationEvents {
+				for _, prevSLIResult := range event.Evaluation.IndicatorResults {
+					if strings.Compare(prevSLIResult.Value.Metric, objective.SLI) == 0 {
+						previousSLIResults = append(previousSLIResults, prevSLIResult)
+					}
+				}
+			}
+		}
+
+		var passTargets []*keptnv2.SLITarget
+		var warningTargets []*keptnv2.SLITarget
+		isPassed := true
+		isWarning := true
+		if objective.Pass != nil && len(objective.Pass) > 0 {
+			isPassed, passTargets, _ = evaluateOrCombinedCriteria(sliEvaluationResult.Value, e.GetSLI.IndicatorValues, objective.Pass, previousSLIResults, sloConfig.Comparison)
+			if isPassed {
+				sliEvaluationResult.Score = float64(objective.Weight)
+				sliEvaluationResult.Status = "pass"
+			}
+		} else {
+			sliEvaluationResult.Status = "info"
+		}
+
+		if !isPassed {
+			if objective.Warning != nil && len(objective.Warning) > 0 {
+				isWarning, warningTargets, _ = evaluateOrCombinedCriteria(sliEvaluationResult.Value, e.GetSLI.IndicatorValues, objective.Warning, previousSLIResults, sloConfig.Comparison)
+				if isWarning {
+					sliEvaluationResult.Score = 0.5 * float64(objective.Weight)
+					sliEvaluationResult.Status = "warning"
+				}
+			} else {
+				isWarning = false
+			}
+		}
+
+		sliEvaluationResult.Targets = append(warningTargets, passTargets...)
+
+		if !isPassed && !isWarning {
+			if objective.KeySLI {
+				keySLIFailed = true
+			}
+			sliEvaluationResult.Status = "fail"
+			sliEvaluationResult.Score = 0
+		}
+
+		sliEvaluationResults = append(sliEvaluationResults, sliEvaluationResult)
+	}
+	evaluationResult.Evaluation.IndicatorResults = sliEvaluationResults
+
+	return evaluationResult, maximumAchievableScore, keySLIFailed
+}
+
+// CalculateScore sets the total score and result of an evaluation, based on the results of its objectives
+func CalculateScore(maximumAchievableScore float64, evaluationResult *keptnv2.EvaluationFinishedEventData, sloConfig *keptn.ServiceLevelObjectives, keySLIFailed bool) error {
+	if maximumAchievableScore == 0 {
+		evaluationResult.Evaluation.Result = "pass"
+		evaluationResult.Result = keptnv2.ResultPass
+		evaluationResult.Status = keptnv2.StatusSucceeded
+		evaluationResult.Evaluation.Score = 100.0
+		return nil
+	}
+	totalScore := 0.0
+	for _, result := range evaluationResult.Evaluation.IndicatorResults {
+		totalScore += result.Score
+	}
+	achievedPercentage := 100.0 * (totalScore / maximumAchievableScore)
+	evaluationResult.Evaluation.Score = achievedPercentage
+	if sloConfig.TotalScore == nil || sloConfig.TotalScore.Pass == "" {
+		return errors.New("no target score defined")
+	}
+	passTargetPercentage, err := strconv.ParseFloat(strings.TrimSuffix(sloConfig.TotalScore.Pass, "%"), 64)
+	if err != nil {
+		return errors.New("could not parse pass target percentage")
+	}
+	if achievedPercentage >= passTargetPercentage && !keySLIFailed {
+		evaluationResult.Evaluation.Result = "pass"
+		evaluationResult.Result = keptnv2.ResultPass
+		evaluationResult.Status = keptnv2.StatusSucceeded
+	} else if sloConfig.TotalScore.Warning != "" && !keySLIFailed {
+		warnTargetPercentage, err := strconv.ParseFloat(strings.TrimSuffix(sloConfig.TotalScore.Warning, "%"), 64)
+
+		if err != nil {
+			return errors.New("could not parse warning target percentage")
+		}
+		if achievedPercentage >= warnTargetPercentage {
+			evaluationResult.Evaluation.Result = "warning"
+			evaluationResult.Result = keptnv2.ResultWarning
+			evaluationResult.Status = keptnv2.StatusSucceeded
+		} else {
+			evaluationResult.Evaluation.Result = "fail"
+			evaluationResult.Result = keptnv2.ResultFailed
+			evaluationResult.Status = keptnv2.StatusSucceeded
+		}
+	} else {
+		evaluationResult.Evaluation.Result = "fail"
+		evaluationResult.Result = keptnv2.ResultFailed
+		evaluationResult.Status = keptnv2.StatusSucceeded
+	}
+
+	return nil
+}
+
+func getSLIResult(results []*keptnv2.SLIResult, sli string) *keptnv2.SLIResult {
+	for _, sliResult := range results {
+		if sliResult.Metric == sli {
+			return sliResult
+		}
+	}
+
+	return nil
+}
+
+func evaluateOrCombinedCriteria(result *keptnv2.SLIResult, indicatorValues []*keptnv2.SLIResult, sloCriteria []*keptn.SLOCriteria, previousResults []*keptnv2.SLIEvaluationResult, comparison *keptn.SLOComparison) (bool, []*keptnv2.SLITarget, error) {
+	var satisfied bool
+	satisfied = false
+	var sliTargets []*keptnv2.SLITarget
+	for _, crit := range sloCriteria {
+		criteriaSatisfied, evaluatedTargets, _ := evaluateCriteriaSet(result, indicatorValues, crit, previousResults, comparison)
+		if criteriaSatisfied {
+			// one matching criteria set is sufficient to satisfy the evaluation. Other criteria sets are evaluated nevertheless, to get potential violations
+			satisfied = true
+		}
+		for _, evaluatedTarget := range evaluatedTargets {
+			sliTargets = append(sliTargets, evaluatedTarget)
+		}
+	}
+
+	return satisfied, sliTargets, nil
+}
+
+// evaluateCriteria evaluates a set of criteria strings. Per definition, all criteria clauses within a SLOCriteria object have to be fulfilled to satisfy the SLOCriteria
+func evaluateCriteriaSet(result *keptnv2.SLIResult, indicatorValues []*keptnv2.SLIResult, sloCriteria *keptn.SLOCriteria, previousResults []*keptnv2.SLIEvaluationResult, comparison *keptn.SLOComparison) (bool, []*keptnv2.SLITarget, error) {
+	satisfied := true
+	var sliTargets []*keptnv2.SLITarget
+	for _, criteria := range sloCriteria.Criteria {
+		target := &keptnv2.SLITarget{
+			Criteria: criteria,
+		}
+		criteriaSatisfied, _ := evaluateSingleCriteria(result, indicatorValues, criteria, previousResults, comparison, target)
+		if !criteriaSatisfied {
+			target.Violated = true
+			satisfied = false
+		} else {
+			target.Violated = false
+		}
+		sliTargets = append(sliTargets, target)
+	}
+
+	return satisfied, sliTargets, nil
+}
+
+func evaluateSingleCriteria(sliResult *keptnv2.SLIResult, indicatorValues []*keptnv2.SLIResult, criteria string, previousResults []*keptnv2.SLIEvaluationResult, comparison *keptn.SLOComparison, violation *keptnv2.SLITarget) (bool, error) {
+	if !sliResult.Success {
+		return false, errors.New("cannot evaluate invalid SLI result")
+	}
+
+	node, err := parseCriteria(criteria)
+	if err != nil {
+		return false, err
+	}
+
+	ctx := &criteriaContext{
+		sliResult:       sliResult,
+		indicatorValues: indicatorValues,
+		previousResults: previousResults,
+		comparison:      comparison,
+		target:          violation,
+	}
+	if _, ok := node.(*sliComparisonNode); !ok {
+		// a target value can only be reported for criteria consisting of a single comparison of the SLI value
+		ctx.target = &keptnv2.SLITarget{}
+	}
+	return node.evaluate(ctx)
+}
+
+func evaluateComparison(sliResult *keptnv2.SLIResult, co *criteriaObject, previousResults []*keptnv2.SLIEvaluationResult, comparison *keptn.SLOComparison, violation *keptnv2.SLITarget) (bool, error) {
+	// aggregate previous results
+	var aggregatedValue float64
+	var targetValue float64
+	var previousValues []float64
+
+	if len(previousResults) == 0 {
+		// if no comparison values are available, the evaluation passes
+		return true, nil
+	}
+
+	for _, val := range previousResults {
+		if val.Value.Success == true {
+			// always include
+			previousValues = append(previousValues, val.Value.Value)
+		}
+	}
+
+	if len(previousValues) == 0 {
+		// if no comparison values are available, the evaluation passes
+		return true, nil
+	}
+
+	// the statistical aggregate functions compare the value with the distribution of the previous values.
+	// In this case, absolute deltas are interpreted as multiples of the deviation, e.g. <=+2 means at most two standard deviations above the mean
+	if isStatisticalAggregateFunction(comparison.AggregateFunction) {
+		if len(previousValues) < minStatisticalComparisonValues {
+			// a deviation can not be determined from a single value, so the evaluation passes
+			return true, nil
+		}
+		var deviation float64
+		if comparison.AggregateFunction == "stddev" {
+			aggregatedValue = calculateAverage(previousValues)
+			deviation = calculateStandardDeviation(previousValues)
+		} else {
+			aggregatedValue = calculatePercentile(sort.Float64Slice(previousValues), 0.5)
+			deviation = calculateMedianAbsoluteDeviation(previousValues)
+		}
+		if deviation == 0 && !co.CheckPercentage {
+			// without variance, every value different from the previous ones would be an infinite number of deviations away,
+			// so the evaluation passes and the SLI result is marked accordingly
+			addSLIResultMessage(sliResult, noVarianceMessage)
+			return true, nil
+		}
+
+		delta := co.Value * deviation
+		if co.CheckPercentage {
+			delta = (aggregatedValue * co.Value) / 100.0
+		}
+		if co.CheckIncrease {
+			targetValue = aggregatedValue + delta
+		} else {
+			targetValue = aggregatedValue - delta
+		}
+		violation.TargetValue = targetValue
+		return evaluateValue(sliResult.Value, targetValue, co.Operator)
+	}
+
+	// aggregate the previous values based on the passed aggregation function
+	switch comparison.AggregateFunction {
+	case "avg":
+		aggregatedValue = calculateAverage(previousValues)
+	case "p50":
+		aggregatedValue = calculatePercentile(sort.Float64Slice(previousValues), 0.5)
+	case "p90":
+		aggregatedValue = calculatePercentile(sort.Float64Slice(previousValues), 0.9)
+	case "p95":
+		aggregatedValue = calculatePercentile(sort.Float64Slice(previousValues), 0.95)
+	default:
+		break
+	}
+
+	// calculate the comparison value
+	if co.CheckPercentage && co.CheckIncrease {
+		targetValue = (aggregatedValue * (100.0 + co.Value)) / 100.0
+	} else if co.CheckPercentage && !co.CheckIncrease {
+		targetValue = (aggregatedValue * (100.0 - co.Value)) / 100.0
+	} else if !co.CheckPercentage && co.CheckIncrease {
+		targetValue = aggregatedValue + co.Value
+	} else if !co.CheckPercentage && !co.CheckIncrease {
+		targetValue = aggregatedValue - co.Value
+	}
+	violation.TargetValue = targetValue
+	// compare!
+	return evaluateValue(sliResult.Value, targetValue, co.Operator)
+}
+
+// addSLIResultMessage appends the message to the message of the SLI result, unless it already contains it
+func addSLIResultMessage(sliResult *keptnv2.SLIResult, message string) {
+	if strings.Contains(sliResult.Message, message) {
+		return
+	}
+	if sliResult.Message != "" {
+		sliResult.Message += "; "
+	}
+	sliResult.Message += message
+}
+
+func calculateAverage(values []float64) float64 {
+	sum := 0.0
+
+	for _, value := range values {
+		sum += value
+	}
+	if len(values) > 0 {
+		return sum / float64(len(values))
+	}
+
+	return 0.0
+}
+
+// calculateStandardDeviation returns the sample standard deviation of the values
+func calculateStandardDeviation(values []float64) float64 {
+	if len(values) < 2 {
+		return 0.0
+	}
+	mean := calculateAverage(values)
+	sumOfSquares := 0.0
+	for _, value := range values {
+		sumOfSquares += (value - mean) * (value - mean)
+	}
+	return math.Sqrt(sumOfSquares / float64(len(values)-1))
+}
+
+// calculateMedianAbsoluteDeviation returns the median of the absolute deviations from the median of the values.
+// The result is scaled to be comparable with the standard deviation of normally distributed values, but is far less affected by outliers
+func calculateMedianAbsoluteDeviation(values []float64) float64 {
+	if len(values) == 0 {
+		return 0.0
+	}
+	sortedValues := make(sort.Float64Slice, len(values))
+	copy(sortedValues, values)
+	median := calculatePercentile(sortedValues, 0.5)
+
+	deviations := make(sort.Float64Slice, len(values))
+	for i, value := range values {
+		deviations[i] = math.Abs(value - median)
+	}
+	return madScaleFactor * calculatePercentile(deviations, 0.5)
+}
+
+func isStatisticalAggregateFunction(aggregateFunction string) bool {
+	return aggregateFunction == "stddev" || aggregateFunction == "mad"
+}
+
+func calculatePercentile(values sort.Float64Slice, perc float64) float64 {
+	if len(values) == 0 {
+		return 0.0
+	}
+	ps := []float64{perc}
+
+	scores := make([]float64, len(ps))
+	size := len(values)
+	if size > 0 {
+		sort.Sort(values)
+		for i, p := range ps {
+			pos := p * float64(size+1) //ALTERNATIVELY, DROP THE +1
+			if pos < 1.0 {
+				scores[i] = float64(values[0])
+			} else if pos >= float64(size) {
+				scores[i] = float64(values[size-1])
+			} else {
+				lower := float64(values[int(pos)-1])
+				upper := float64(values[int(pos)])
+				scores[i] = lower + (pos-math.Floor(pos))*(upper-lower)
+			}
+		}
+	}
+
+	return scores[0]
+}
+
+func evaluateFixedThreshold(sliResult *keptnv2.SLIResult, co *criteriaObject, violation *keptnv2.SLITarget) (bool, error) {
+	violation.TargetValue = co.Value
+	return evaluateValue(sliResult.Value, co.Value, co.Operator)
+}
+
+func evaluateValue(measured float64, expected float64, operator string) (bool, error) {
+	switch operator {
+	case "<":
+		return measured < expected, nil
+	case "<=":
+		return measured <= expected, nil
+	case "=":
+		return measured == expected, nil
+	case ">=":
+		return measured >= expected, nil
+	case ">":
+		return measured > expected, nil
+	default:
+		return false, errors.New("no operator set")
+	}
+}
+
+// parseCriteriaString parses a criteria consisting of a single comparison of the SLI value, e.g. <=+10% or <500
+func parseCriteriaString(criteria string) (*criteriaObject, error) {
+	node, err := parseCriteria(criteria)
+	if err != nil {
+		return nil, err
+	}
+	sliComparison, ok := node.(*sliComparisonNode)
+	if !ok {
+		return nil, errors.New("criteria is not a single comparison of the SLI value")
+	}
+	return sliComparison.criteria, nil
+}
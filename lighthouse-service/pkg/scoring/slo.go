@@ -0,0 +1,214 @@
+package scoring
+
+import (
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	keptn "github.com/keptn/go-utils/pkg/lib"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+)
+
+// ParseSLO parses the content of an SLO file and sets the default values of omitted properties
+func ParseSLO(input []byte) (*keptn.ServiceLevelObjectives, error) {
+	slo, err := unmarshalSLO(input)
+	if err != nil {
+		return nil, err
+	}
+	setSLODefaults(slo)
+	return slo, nil
+}
+
+// MergeSLOFiles merges SLO files ordered from the most general to the most specific one, e.g., the slo.yaml files of a project, stage and service,
+// and returns the content of the effective SLO file. The baseline of the most specific file that pins one is kept, and the SLI providers are merged per SLI.
+// Default values are not set, i.e., the result can be parsed using ParseSLO
+func MergeSLOFiles(files ...[]byte) ([]byte, error) {
+	if len(files) == 1 {
+		return files[0], nil
+	}
+	slos := []*keptn.ServiceLevelObjectives{}
+	extensions := sloFileExtensions{}
+	for _, file := range files {
+		slo, err := unmarshalSLO(file)
+		if err != nil {
+			return nil, err
+		}
+		slos = append(slos, slo)
+
+		fileExtensions := &sloFileExtensions{}
+		if err := yaml.Unmarshal(file, fileExtensions); err != nil {
+			return nil, err
+		}
+		extensions.merge(fileExtensions)
+	}
+	return yaml.Marshal(struct {
+		keptn.ServiceLevelObjectives
+		sloFileExtensions
+	}{
+		ServiceLevelObjectives: *MergeSLOs(slos...),
+		sloFileExtensions:      extensions,
+	})
+}
+
+// sloFileExtensions contains the properties of an SLO file that are not part of keptn.ServiceLevelObjectives
+type sloFileExtensions struct {
+	Baseline     *Baseline         `json:"baseline,omitempty"`
+	SLIProviders map[string]string `json:"sli_providers,omitempty"`
+}
+
+// merge overrides the baseline and the SLI providers with the ones of a more specific SLO file
+func (e *sloFileExtensions) merge(override *sloFileExtensions) {
+	if override.Baseline.IsSet() {
+		e.Baseline = override.Baseline
+	}
+	for sli, provider := range override.SLIProviders {
+		if e.SLIProviders == nil {
+			e.SLIProviders = map[string]string{}
+		}
+		e.SLIProviders[sli] = provider
+	}
+}
+
+// ParseSLIProviders returns the SLI providers of an SLO file, mapping the name of an SLI to the provider that retrieves its value.
+// SLIs that are not listed are retrieved by the SLI provider configured for the project
+func ParseSLIProviders(input []byte) (map[string]string, error) {
+	file := &sloFileExtensions{}
+	if err := yaml.Unmarshal(input, file); err != nil {
+		return nil, err
+	}
+	return file.SLIProviders, nil
+}
+
+// MergeSLOs merges SLOs ordered from the most general to the most specific one. Objectives are merged per SLI name, i.e., an objective
+// of a more specific SLO replaces the objective with the same SLI of a more general one. Filters are merged per key, and the properties of the
+// comparison and the total score are overridden if they are set in a more specific SLO
+func MergeSLOs(slos ...*keptn.ServiceLevelObjectives) *keptn.ServiceLevelObjectives {
+	merged := &keptn.ServiceLevelObjectives{}
+	objectiveIndexes := map[string]int{}
+	for _, slo := range slos {
+		if slo == nil {
+			continue
+		}
+		if slo.SpecVersion != "" {
+			merged.SpecVersion = slo.SpecVersion
+		}
+		for key, value := range slo.Filter {
+			if merged.Filter == nil {
+				merged.Filter = map[string]string{}
+			}
+			merged.Filter[key] = value
+		}
+		if slo.Comparison != nil {
+			merged.Comparison = mergeSLOComparison(merged.Comparison, slo.Comparison)
+		}
+		for _, objective := range slo.Objectives {
+			if objective == nil {
+				continue
+			}
+			if index, ok := objectiveIndexes[objective.SLI]; ok {
+				merged.Objectives[index] = objective
+				continue
+			}
+			objectiveIndexes[objective.SLI] = len(merged.Objectives)
+			merged.Objectives = append(merged.Objectives, objective)
+		}
+		if slo.TotalScore != nil {
+			merged.TotalScore = mergeSLOScore(merged.TotalScore, slo.TotalScore)
+		}
+	}
+	return merged
+}
+
+func mergeSLOComparison(base, override *keptn.SLOComparison) *keptn.SLOComparison {
+	if base == nil {
+		result := *override
+		return &result
+	}
+	result := *base
+	if override.CompareWith != "" {
+		result.CompareWith = override.CompareWith
+	}
+	if override.IncludeResultWithScore != "" {
+		result.IncludeResultWithScore = override.IncludeResultWithScore
+	}
+	if override.NumberOfComparisonResults != 0 {
+		result.NumberOfComparisonResults = override.NumberOfComparisonResults
+	}
+	if override.AggregateFunction != "" {
+		result.AggregateFunction = override.AggregateFunction
+	}
+	return &result
+}
+
+func mergeSLOScore(base, override *keptn.SLOScore) *keptn.SLOScore {
+	if base == nil {
+		result := *override
+		return &result
+	}
+	result := *base
+	if override.Pass != "" {
+		result.Pass = override.Pass
+	}
+	if override.Warning != "" {
+		result.Warning = override.Warning
+	}
+	return &result
+}
+
+func unmarshalSLO(input []byte) (*keptn.ServiceLevelObjectives, error) {
+	slo := &keptn.ServiceLevelObjectives{}
+	if err := yaml.Unmarshal(input, &slo); err != nil {
+		return nil, err
+	}
+	return slo, nil
+}
+
+func setSLODefaults(slo *keptn.ServiceLevelObjectives) {
+	if slo.Comparison == nil {
+		slo.Comparison = &keptn.SLOComparison{
+			CompareWith:               "single_result",
+			IncludeResultWithScore:    "all",
+			NumberOfComparisonResults: 1,
+			AggregateFunction:         "avg",
+		}
+	}
+
+	if slo.Comparison.IncludeResultWithScore == "" {
+		slo.Comparison.IncludeResultWithScore = "all"
+	}
+	if slo.Comparison.NumberOfComparisonResults == 0 {
+		slo.Comparison.NumberOfComparisonResults = 3
+	}
+	if slo.Comparison.AggregateFunction == "" {
+		slo.Comparison.AggregateFunction = "avg"
+	}
+
+	for _, objective := range slo.Objectives {
+		if objective.Weight == 0 {
+			objective.Weight = 1
+		}
+	}
+}
+
+// GetNumberOfComparisonResults returns how many previous evaluations are taken into account for the comparison criteria of an SLO
+func GetNumberOfComparisonResults(slo *keptn.ServiceLevelObjectives) int {
+	if slo.Comparison.CompareWith == "single_result" {
+		return 1
+	} else if slo.Comparison.CompareWith == "several_results" {
+		return slo.Comparison.NumberOfComparisonResults
+	}
+	return 3
+}
+
+// IsComparableEvaluation checks if a previous evaluation has a result that is taken into account for the comparison criteria of an SLO,
+// based on its include_result_with_score property
+func IsComparableEvaluation(slo *keptn.ServiceLevelObjectives, evaluation *keptnv2.EvaluationFinishedEventData) bool {
+	switch strings.ToLower(slo.Comparison.IncludeResultWithScore) {
+	case "pass":
+		return evaluation.Result == keptnv2.ResultPass
+	case "pass_or_warn":
+		return evaluation.Result == keptnv2.ResultPass || evaluation.Result == keptnv2.ResultWarning
+	default:
+		return true
+	}
+}
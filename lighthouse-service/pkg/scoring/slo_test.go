@@ -1,4 +1,4 @@
-package event_handler
+package scoring
 
 import (
 	"testing"
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	keptn "github.com/keptn/go-utils/pkg/lib"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
 )
 
 type getSLOTestObject struct {
@@ -337,9 +338,154 @@ total_score:
 
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
-			objectives, err := parseSLO([]byte(test.SLOFileContent))
+			objectives, err := ParseSLO([]byte(test.SLOFileContent))
 			assert.EqualValues(t, test.ExpectedSLO, objectives)
 			assert.EqualValues(t, test.ExpectedError, err)
 		})
 	}
 }
+
+func TestGetNumberOfComparisonResults(t *testing.T) {
+	assert.Equal(t, 1, GetNumberOfComparisonResults(&keptn.ServiceLevelObjectives{Comparison: &keptn.SLOComparison{CompareWith: "single_result", NumberOfComparisonResults: 5}}))
+	assert.Equal(t, 5, GetNumberOfComparisonResults(&keptn.ServiceLevelObjectives{Comparison: &keptn.SLOComparison{CompareWith: "several_results", NumberOfComparisonResults: 5}}))
+	assert.Equal(t, 3, GetNumberOfComparisonResults(&keptn.ServiceLevelObjectives{Comparison: &keptn.SLOComparison{}}))
+}
+
+func TestIsComparableEvaluation(t *testing.T) {
+	getSLO := func(includeResultWithScore string) *keptn.ServiceLevelObjectives {
+		return &keptn.ServiceLevelObjectives{Comparison: &keptn.SLOComparison{IncludeResultWithScore: includeResultWithScore}}
+	}
+	getEvaluation := func(result keptnv2.ResultType) *keptnv2.EvaluationFinishedEventData {
+		return &keptnv2.EvaluationFinishedEventData{EventData: keptnv2.EventData{Result: result}}
+	}
+
+	assert.True(t, IsComparableEvaluation(getSLO("pass"), getEvaluation(keptnv2.ResultPass)))
+	assert.False(t, IsComparableEvaluation(getSLO("pass"), getEvaluation(keptnv2.ResultWarning)))
+	assert.True(t, IsComparableEvaluation(getSLO("pass_or_warn"), getEvaluation(keptnv2.ResultWarning)))
+	assert.False(t, IsComparableEvaluation(getSLO("pass_or_warn"), getEvaluation(keptnv2.ResultFailed)))
+	assert.True(t, IsComparableEvaluation(getSLO("all"), getEvaluation(keptnv2.ResultFailed)))
+}
+
+func TestMergeSLOs(t *testing.T) {
+	projectSLO := &keptn.ServiceLevelObjectives{
+		SpecVersion: "1.0",
+		Filter:      map[string]string{"handler": "ItemsController", "job": "project"},
+		Comparison: &keptn.SLOComparison{
+			CompareWith:               "several_results",
+			IncludeResultWithScore:    "pass",
+			NumberOfComparisonResults: 3,
+			AggregateFunction:         "avg",
+		},
+		Objectives: []*keptn.SLO{
+			{SLI: "response_time_p95", Pass: []*keptn.SLOCriteria{{Criteria: []string{"<600"}}}},
+			{SLI: "error_rate", Pass: []*keptn.SLOCriteria{{Criteria: []string{"<1"}}}},
+		},
+		TotalScore: &keptn.SLOScore{Pass: "90%", Warning: "75%"},
+	}
+	stageSLO := &keptn.ServiceLevelObjectives{
+		Comparison: &keptn.SLOComparison{NumberOfComparisonResults: 5},
+		Objectives: []*keptn.SLO{
+			{SLI: "error_rate", Pass: []*keptn.SLOCriteria{{Criteria: []string{"<0.5"}}}},
+		},
+		TotalScore: &keptn.SLOScore{Pass: "95%"},
+	}
+	serviceSLO := &keptn.ServiceLevelObjectives{
+		Filter: map[string]string{"job": "service"},
+		Objectives: []*keptn.SLO{
+			{SLI: "response_time_p95", Pass: []*keptn.SLOCriteria{{Criteria: []string{"<300"}}}, KeySLI: true},
+			{SLI: "throughput"},
+		},
+	}
+
+	expected := &keptn.ServiceLevelObjectives{
+		SpecVersion: "1.0",
+		Filter:      map[string]string{"handler": "ItemsController", "job": "service"},
+		Comparison: &keptn.SLOComparison{
+			CompareWith:               "several_results",
+			IncludeResultWithScore:    "pass",
+			NumberOfComparisonResults: 5,
+			AggregateFunction:         "avg",
+		},
+		Objectives: []*keptn.SLO{
+			{SLI: "response_time_p95", Pass: []*keptn.SLOCriteria{{Criteria: []string{"<300"}}}, KeySLI: true},
+			{SLI: "error_rate", Pass: []*keptn.SLOCriteria{{Criteria: []string{"<0.5"}}}},
+			{SLI: "throughput"},
+		},
+		TotalScore: &keptn.SLOScore{Pass: "95%", Warning: "75%"},
+	}
+
+	assert.Equal(t, expected, MergeSLOs(projectSLO, stageSLO, nil, serviceSLO))
+	// the merged SLOs are not modified
+	assert.Equal(t, 3, projectSLO.Comparison.NumberOfComparisonResults)
+	assert.Equal(t, "90%", projectSLO.TotalScore.Pass)
+}
+
+func TestMergeSLOFiles(t *testing.T) {
+	projectSLOFile := `---
+spec_version: '1.0'
+comparison:
+  compare_with: "several_results"
+  number_of_comparison_results: 3
+objectives:
+  - sli: response_time_p95
+    pass:
+      - criteria:
+          - "<600"
+  - sli: error_rate
+    pass:
+      - criteria:
+          - "<1"
+total_score:
+  pass: "90%"
+  warning: "75%"
+`
+	serviceSLOFile := `---
+objectives:
+  - sli: error_rate
+    weight: 2
+    pass:
+      - criteria:
+          - "=0"
+`
+
+	// a single file is kept as is
+	content, err := MergeSLOFiles([]byte(serviceSLOFile))
+	assert.Nil(t, err)
+	assert.Equal(t, serviceSLOFile, string(content))
+
+	content, err = MergeSLOFiles([]byte(projectSLOFile), []byte(serviceSLOFile))
+	assert.Nil(t, err)
+	slo, err := ParseSLO(content)
+	assert.Nil(t, err)
+	assert.Equal(t, &keptn.ServiceLevelObjectives{
+		SpecVersion: "1.0",
+		Comparison: &keptn.SLOComparison{
+			CompareWith:               "several_results",
+			IncludeResultWithScore:    "all",
+			NumberOfComparisonResults: 3,
+			AggregateFunction:         "avg",
+		},
+		Objectives: []*keptn.SLO{
+			{SLI: "response_time_p95", Pass: []*keptn.SLOCriteria{{Criteria: []string{"<600"}}}, Weight: 1},
+			{SLI: "error_rate", Pass: []*keptn.SLOCriteria{{Criteria: []string{"=0"}}}, Weight: 2},
+		},
+		TotalScore: &keptn.SLOScore{Pass: "90%", Warning: "75%"},
+	}, slo)
+	// defaulted values are not part of the merged file content
+	assert.NotContains(t, string(content), "include_result_with_score: all")
+
+	_, err = MergeSLOFiles([]byte(projectSLOFile), []byte("objectives: invalid"))
+	assert.NotNil(t, err)
+}
+
+func TestMergeSLOFilesMergesSLIProviders(t *testing.T) {
+	content, err := MergeSLOFiles([]byte(`sli_providers:
+  error_rate: dynatrace
+  throughput: dynatrace`), []byte(`sli_providers:
+  throughput: prometheus`))
+	assert.Nil(t, err)
+
+	sliProviders, err := ParseSLIProviders(content)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"error_rate": "dynatrace", "throughput": "prometheus"}, sliProviders)
+}
@@ -0,0 +1,231 @@
+package scoring
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	keptn "github.com/keptn/go-utils/pkg/lib"
+)
+
+var validCompareWithValues = []string{"single_result", "several_results"}
+
+var validIncludeResultWithScoreValues = []string{"all", "pass", "pass_or_warn"}
+
+var validAggregateFunctions = []string{"avg", "p50", "p90", "p95", "stddev", "mad"}
+
+// SLOValidationProblem describes a problem of an SLO file that leads to a failed or unexpected evaluation
+type SLOValidationProblem struct {
+	// SLI is the name of the SLI the problem refers to. It is empty for problems that do not refer to a single objective
+	SLI     string `json:"sli,omitempty" yaml:"sli,omitempty"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// ValidateSLOFile parses the content of an SLO file and returns all problems found in it, including an invalid baseline and SLI providers
+func ValidateSLOFile(content []byte) []SLOValidationProblem {
+	slo, err := ParseSLO(content)
+	if err != nil {
+		return []SLOValidationProblem{{Message: "could not parse SLO file: " + err.Error()}}
+	}
+	problems := ValidateSLO(slo)
+	if _, err := ParseBaseline(content); err != nil {
+		problems = append(problems, SLOValidationProblem{Message: err.Error()})
+	}
+	sliProviders, err := ParseSLIProviders(content)
+	if err != nil {
+		return append(problems, SLOValidationProblem{Message: "invalid sli_providers: " + err.Error()})
+	}
+	return append(problems, validateSLIProviders(slo, sliProviders)...)
+}
+
+// validateSLIProviders checks if the SLI providers only refer to SLIs listed in the objectives
+func validateSLIProviders(slo *keptn.ServiceLevelObjectives, sliProviders map[string]string) []SLOValidationProblem {
+	problems := []SLOValidationProblem{}
+	slis := map[string]bool{}
+	for _, objective := range slo.Objectives {
+		if objective != nil {
+			slis[objective.SLI] = true
+		}
+	}
+	names := []string{}
+	for sli := range sliProviders {
+		names = append(names, sli)
+	}
+	sort.Strings(names)
+	for _, sli := range names {
+		if sliProviders[sli] == "" {
+			problems = append(problems, SLOValidationProblem{SLI: sli, Message: fmt.Sprintf("sli_providers: no SLI provider set for SLI %s", sli)})
+		} else if !slis[sli] {
+			problems = append(problems, SLOValidationProblem{SLI: sli, Message: fmt.Sprintf("sli_providers: SLI %s is not listed in the objectives", sli)})
+		}
+	}
+	return problems
+}
+
+// ValidateSLO returns all problems found in the comparison settings, objectives and total score of an SLO
+func ValidateSLO(slo *keptn.ServiceLevelObjectives) []SLOValidationProblem {
+	problems := validateComparison(slo.Comparison)
+	problems = append(problems, validateObjectives(slo)...)
+	problems = append(problems, validateCriteria(slo)...)
+	problems = append(problems, validateTotalScore(slo)...)
+	return problems
+}
+
+// ValidateSLOCriteria checks if all pass and warning criteria of an SLO can be parsed and only refer to SLIs listed in the objectives
+func ValidateSLOCriteria(slo *keptn.ServiceLevelObjectives) error {
+	if problems := validateCriteria(slo); len(problems) > 0 {
+		return errors.New(problems[0].Message)
+	}
+	return nil
+}
+
+func validateComparison(comparison *keptn.SLOComparison) []SLOValidationProblem {
+	problems := []SLOValidationProblem{}
+	if comparison == nil {
+		return problems
+	}
+	if comparison.CompareWith != "" && !containsString(validCompareWithValues, comparison.CompareWith) {
+		problems = append(problems, SLOValidationProblem{
+			Message: fmt.Sprintf("invalid comparison.compare_with '%s': must be one of %s", comparison.CompareWith, strings.Join(validCompareWithValues, ", ")),
+		})
+	}
+	if !containsString(validIncludeResultWithScoreValues, strings.ToLower(comparison.IncludeResultWithScore)) {
+		problems = append(problems, SLOValidationProblem{
+			Message: fmt.Sprintf("invalid comparison.include_result_with_score '%s': must be one of %s", comparison.IncludeResultWithScore, strings.Join(validIncludeResultWithScoreValues, ", ")),
+		})
+	}
+	if !containsString(validAggregateFunctions, comparison.AggregateFunction) {
+		problems = append(problems, SLOValidationProblem{
+			Message: fmt.Sprintf("invalid comparison.aggregate_function '%s': must be one of %s", comparison.AggregateFunction, strings.Join(validAggregateFunctions, ", ")),
+		})
+	}
+	if comparison.NumberOfComparisonResults < 1 {
+		problems = append(problems, SLOValidationProblem{
+			Message: fmt.Sprintf("invalid comparison.number_of_comparison_results %d: must be greater than zero", comparison.NumberOfComparisonResults),
+		})
+	}
+	return problems
+}
+
+func validateObjectives(slo *keptn.ServiceLevelObjectives) []SLOValidationProblem {
+	problems := []SLOValidationProblem{}
+	definedSLIs := map[string]bool{}
+	for index, objective := range slo.Objectives {
+		if objective == nil {
+			continue
+		}
+		if objective.SLI == "" {
+			problems = append(problems, SLOValidationProblem{Message: fmt.Sprintf("objective %d has no sli", index+1)})
+			continue
+		}
+		if definedSLIs[objective.SLI] {
+			problems = append(problems, SLOValidationProblem{SLI: objective.SLI, Message: fmt.Sprintf("SLI %s is defined more than once", objective.SLI)})
+		}
+		definedSLIs[objective.SLI] = true
+
+		if objective.Weight < 0 {
+			problems = append(problems, SLOValidationProblem{SLI: objective.SLI, Message: fmt.Sprintf("weight of SLI %s must not be negative, but is %d", objective.SLI, objective.Weight)})
+		}
+		if len(objective.Pass) == 0 && len(objective.Warning) > 0 {
+			problems = append(problems, SLOValidationProblem{SLI: objective.SLI, Message: fmt.Sprintf("warning criteria of SLI %s are ignored because it has no pass criteria", objective.SLI)})
+		}
+		if len(objective.Pass) == 0 && objective.KeySLI {
+			problems = append(problems, SLOValidationProblem{SLI: objective.SLI, Message: fmt.Sprintf("key SLI %s has no pass criteria and can therefore never fail the evaluation", objective.SLI)})
+		}
+	}
+	return problems
+}
+
+// validateCriteria checks if all pass and warning criteria can be parsed and only refer to SLIs listed in the objectives
+func validateCriteria(slo *keptn.ServiceLevelObjectives) []SLOValidationProblem {
+	problems := []SLOValidationProblem{}
+	slis := map[string]bool{}
+	for _, objective := range slo.Objectives {
+		if objective != nil {
+			slis[objective.SLI] = true
+		}
+	}
+
+	for _, objective := range slo.Objectives {
+		if objective == nil {
+			continue
+		}
+		criteriaSets := append(append([]*keptn.SLOCriteria{}, objective.Pass...), objective.Warning...)
+		for _, criteriaSet := range criteriaSets {
+			if criteriaSet == nil {
+				continue
+			}
+			for _, criteria := range criteriaSet.Criteria {
+				node, err := parseCriteria(criteria)
+				if err != nil {
+					problems = append(problems, SLOValidationProblem{
+						SLI:     objective.SLI,
+						Message: fmt.Sprintf("invalid criteria '%s' of SLI %s: %s", criteria, objective.SLI, err.Error()),
+					})
+					continue
+				}
+				for _, sli := range getReferencedSLIs(node) {
+					if !slis[sli] {
+						problems = append(problems, SLOValidationProblem{
+							SLI:     objective.SLI,
+							Message: fmt.Sprintf("invalid criteria '%s' of SLI %s: SLI %s is not listed in the objectives", criteria, objective.SLI, sli),
+						})
+					}
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// validateTotalScore checks if the total score targets can be parsed and if the pass target can be reached
+func validateTotalScore(slo *keptn.ServiceLevelObjectives) []SLOValidationProblem {
+	problems := []SLOValidationProblem{}
+	hasPassCriteria := false
+	for _, objective := range slo.Objectives {
+		if objective != nil && len(objective.Pass) > 0 {
+			hasPassCriteria = true
+		}
+	}
+
+	if slo.TotalScore == nil || slo.TotalScore.Pass == "" {
+		if hasPassCriteria {
+			problems = append(problems, SLOValidationProblem{Message: "total_score.pass is not set"})
+		}
+		return problems
+	}
+
+	passTarget, err := parseScoreTarget(slo.TotalScore.Pass)
+	if err != nil {
+		problems = append(problems, SLOValidationProblem{Message: fmt.Sprintf("invalid total_score.pass '%s': must be a percentage, e.g. 90%%", slo.TotalScore.Pass)})
+	} else if passTarget > 100 {
+		problems = append(problems, SLOValidationProblem{Message: fmt.Sprintf("total_score.pass '%s' can never be reached because the maximum score is 100%%", slo.TotalScore.Pass)})
+	}
+
+	if slo.TotalScore.Warning == "" {
+		return problems
+	}
+	warningTarget, warningErr := parseScoreTarget(slo.TotalScore.Warning)
+	if warningErr != nil {
+		problems = append(problems, SLOValidationProblem{Message: fmt.Sprintf("invalid total_score.warning '%s': must be a percentage, e.g. 75%%", slo.TotalScore.Warning)})
+	} else if err == nil && warningTarget > passTarget {
+		problems = append(problems, SLOValidationProblem{Message: fmt.Sprintf("total_score.warning '%s' must not be greater than total_score.pass '%s'", slo.TotalScore.Warning, slo.TotalScore.Pass)})
+	}
+	return problems
+}
+
+// parseScoreTarget parses a target of the total score the same way CalculateScore does
+func parseScoreTarget(target string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSuffix(target, "%"), 64)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSLOFile(t *testing.T) {
+	tests := []struct {
+		name             string
+		sloFileContent   string
+		expectedProblems []SLOValidationProblem
+	}{
+		{
+			name: "valid SLO file",
+			sloFileContent: `---
+spec_version: '1.0'
+comparison:
+  compare_with: "several_results"
+  include_result_with_score: "pass"
+  number_of_comparison_results: 3
+  aggregate_function: stddev
+objectives:
+  - sli: response_time_p95
+    pass:
+      - criteria:
+          - "<=+10%"
+    warning:
+      - criteria:
+          - "<=+20%"
+  - sli: error_rate
+    key_sli: true
+    pass:
+      - criteria:
+          - "<1 and response_time_p95 < 600"
+  - sli: throughput
+total_score:
+  pass: "90%"
+  warning: "75%"
+`,
+			expectedProblems: []SLOValidationProblem{},
+		},
+		{
+			name:           "unparseable SLO file",
+			sloFileContent: `objectives: "no list"`,
+			expectedProblems: []SLOValidationProblem{
+				{Message: "could not parse SLO file: error unmarshaling JSON: json: cannot unmarshal string into Go struct field .objectives of type []*keptn.SLO"},
+			},
+		},
+		{
+			name: "all problems are reported",
+			sloFileContent: `---
+spec_version: '1.0'
+comparison:
+  compare_with: "all_results"
+  include_result_with_score: "pass"
+  number_of_comparison_results: -1
+  aggregate_function: max
+objectives:
+  - sli: response_time_p95
+    weight: -1
+    pass:
+      - criteria:
+          - "<=+10%%"
+          - "<600 and unknown_sli < 1"
+  - sli: response_time_p95
+  - sli: error_rate
+    key_sli: true
+    warning:
+      - criteria:
+          - "<1"
+  - weight: 2
+total_score:
+  pass: "110%"
+  warning: "75"
+`,
+			expectedProblems: []SLOValidationProblem{
+				{Message: "invalid comparison.compare_with 'all_results': must be one of single_result, several_results"},
+				{Message: "invalid comparison.aggregate_function 'max': must be one of avg, p50, p90, p95, stddev, mad"},
+				{Message: "invalid comparison.number_of_comparison_results -1: must be greater than zero"},
+				{SLI: "response_time_p95", Message: "weight of SLI response_time_p95 must not be negative, but is -1"},
+				{SLI: "response_time_p95", Message: "SLI response_time_p95 is defined more than once"},
+				{SLI: "error_rate", Message: "warning criteria of SLI error_rate are ignored because it has no pass criteria"},
+				{SLI: "error_rate", Message: "key SLI error_rate has no pass criteria and can therefore never fail the evaluation"},
+				{Message: "objective 4 has no sli"},
+				{SLI: "response_time_p95", Message: "invalid criteria '<=+10%%' of SLI response_time_p95: unexpected '%' at position 7, expected 'and', 'or' or end of criteria"},
+				{SLI: "response_time_p95", Message: "invalid criteria '<600 and unknown_sli < 1' of SLI response_time_p95: SLI unknown_sli is not listed in the objectives"},
+				{Message: "total_score.pass '110%' can never be reached because the maximum score is 100%"},
+			},
+		},
+		{
+			name: "invalid SLI providers",
+			sloFileContent: `---
+spec_version: '1.0'
+sli_providers:
+  response_time_p95: dynatrace
+  throughput: ""
+  error_rate: dynatrace
+objectives:
+  - sli: response_time_p95
+`,
+			expectedProblems: []SLOValidationProblem{
+				{SLI: "error_rate", Message: "sli_providers: SLI error_rate is not listed in the objectives"},
+				{SLI: "throughput", Message: "sli_providers: no SLI provider set for SLI throughput"},
+			},
+		},
+		{
+			name: "missing total score",
+			sloFileContent: `---
+spec_version: '1.0'
+objectives:
+  - sli: response_time_p95
+    pass:
+      - criteria:
+          - "<600"
+`,
+			expectedProblems: []SLOValidationProblem{
+				{Message: "total_score.pass is not set"},
+			},
+		},
+		{
+			name: "invalid total score",
+			sloFileContent: `---
+spec_version: '1.0'
+objectives:
+  - sli: response_time_p95
+    pass:
+      - criteria:
+          - "<600"
+total_score:
+  pass: "ninety"
+  warning: "95%"
+`,
+			expectedProblems: []SLOValidationProblem{
+				{Message: "invalid total_score.pass 'ninety': must be a percentage, e.g. 90%"},
+			},
+		},
+		{
+			name: "warning greater than pass",
+			sloFileContent: `---
+spec_version: '1.0'
+objectives:
+  - sli: response_time_p95
+    pass:
+      - criteria:
+          - "<600"
+total_score:
+  pass: "75%"
+  warning: "90%"
+`,
+			expectedProblems: []SLOValidationProblem{
+				{Message: "total_score.warning '90%' must not be greater than total_score.pass '75%'"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedProblems, ValidateSLOFile([]byte(tt.sloFileContent)))
+		})
+	}
+}
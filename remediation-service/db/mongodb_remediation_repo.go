@@ -6,6 +6,7 @@ import (
 	"github.com/keptn/keptn/remediation-service/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"strings"
 	"time"
 )
 
@@ -16,17 +17,110 @@ type RemediationMongoDBRepo struct {
 
 const remediationCollectionNameSuffix = "-remediations"
 
+// executedActionCollectionNameSuffix is the suffix of the collection that keeps the actions triggered by remediations after the remediations have finished
+const executedActionCollectionNameSuffix = "-remediation-history"
+
 func (mdbrepo *RemediationMongoDBRepo) GetRemediations(keptnContext, project string) ([]*models.Remediation, error) {
-	result := []*models.Remediation{}
+	return mdbrepo.findRemediations(project, bson.M{"keptnContext": keptnContext})
+}
+
+// GetOpenRemediations returns the entries of all remediations of the project that have not been finished yet
+func (mdbrepo *RemediationMongoDBRepo) GetOpenRemediations(project string) ([]*models.Remediation, error) {
+	return mdbrepo.findRemediations(project, bson.M{})
+}
+
+// CreateExecutedAction adds an action that has been triggered by a remediation to the history of the project
+func (mdbrepo *RemediationMongoDBRepo) CreateExecutedAction(project string, action *models.Remediation) error {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := mdbrepo.getExecutedActionCollection(project)
+
+	_, err = collection.InsertOne(ctx, action)
+	if err != nil {
+		return fmt.Errorf("could not store executed action %s for context %s: %s", action.Action, action.KeptnContext, err.Error())
+	}
+	return nil
+}
+
+// GetExecutedActions returns the actions that have been triggered for the service since the given time
+func (mdbrepo *RemediationMongoDBRepo) GetExecutedActions(project, stage, service string, since time.Time) ([]*models.Remediation, error) {
 	err := mdbrepo.DbConnection.EnsureDBConnection()
 	if err != nil {
 		return nil, err
 	}
+	return mdbrepo.find(mdbrepo.getExecutedActionCollection(project), bson.M{
+		"stage":   stage,
+		"service": service,
+		"time":    bson.M{"$gte": since.UTC().Format(time.RFC3339)},
+	})
+}
+
+// SetEvaluationDue sets the time at which the effect of the action of the entry with the given event ID is evaluated. An empty time removes it
+func (mdbrepo *RemediationMongoDBRepo) SetEvaluationDue(keptnContext, project, eventID, evaluationDue string) error {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return err
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	collection := mdbrepo.getRemediationCollection(project)
-	cursor, err := collection.Find(ctx, bson.M{"keptnContext": keptnContext})
+
+	_, err = collection.UpdateOne(ctx, bson.M{"keptnContext": keptnContext, "eventId": eventID}, bson.M{"$set": bson.M{"evaluationDue": evaluationDue}})
+	if err != nil {
+		return fmt.Errorf("could not set evaluation due time of remediation with context %s: %s", keptnContext, err.Error())
+	}
+	return nil
+}
+
+// GetDueEvaluations returns the entries of the running remediations of the project whose evaluation is due at the given time
+func (mdbrepo *RemediationMongoDBRepo) GetDueEvaluations(project string, now time.Time) ([]*models.Remediation, error) {
+	return mdbrepo.findRemediations(project, bson.M{
+		"evaluationDue": bson.M{"$gt": "", "$lte": now.UTC().Format(time.RFC3339)},
+	})
+}
+
+// GetProjects returns the projects that have running remediations
+func (mdbrepo *RemediationMongoDBRepo) GetProjects() ([]string, error) {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collectionNames, err := mdbrepo.DbConnection.Client.Database(databaseName).ListCollectionNames(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("could not list remediation collections: %s", err.Error())
+	}
+	projects := []string{}
+	for _, collectionName := range collectionNames {
+		if strings.HasSuffix(collectionName, remediationCollectionNameSuffix) {
+			projects = append(projects, strings.TrimSuffix(collectionName, remediationCollectionNameSuffix))
+		}
+	}
+	return projects, nil
+}
+
+func (mdbrepo *RemediationMongoDBRepo) findRemediations(project string, filter bson.M) ([]*models.Remediation, error) {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return nil, err
+	}
+	return mdbrepo.find(mdbrepo.getRemediationCollection(project), filter)
+}
+
+func (mdbrepo *RemediationMongoDBRepo) find(collection *mongo.Collection, filter bson.M) ([]*models.Remediation, error) {
+	result := []*models.Remediation{}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := collection.Find(ctx, filter)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, nil
@@ -94,3 +188,7 @@ func (mdbrepo *RemediationMongoDBRepo) getRemediationCollection(project string)
 	projectCollection := mdbrepo.DbConnection.Client.Database(databaseName).Collection(project + remediationCollectionNameSuffix)
 	return projectCollection
 }
+
+func (mdbrepo *RemediationMongoDBRepo) getExecutedActionCollection(project string) *mongo.Collection {
+	return mdbrepo.DbConnection.Client.Database(databaseName).Collection(project + executedActionCollectionNameSuffix)
+}
@@ -1,10 +1,19 @@
 package db
 
-import "github.com/keptn/keptn/remediation-service/models"
+import (
+	"github.com/keptn/keptn/remediation-service/models"
+	"time"
+)
 
 // IRemediationRepo godoc
 type IRemediationRepo interface {
 	GetRemediations(keptnContext, project string) ([]*models.Remediation, error)
+	GetOpenRemediations(project string) ([]*models.Remediation, error)
 	CreateRemediation(project string, remediation *models.Remediation) error
 	DeleteRemediation(keptnContext, project string) error
+	CreateExecutedAction(project string, action *models.Remediation) error
+	GetExecutedActions(project, stage, service string, since time.Time) ([]*models.Remediation, error)
+	SetEvaluationDue(keptnContext, project, eventID, evaluationDue string) error
+	GetDueEvaluations(project string, now time.Time) ([]*models.Remediation, error)
+	GetProjects() ([]string, error)
 }
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
@@ -15,11 +16,8 @@ type ActionFinishedEventHandler struct {
 	KeptnHandler *keptnv2.Keptn
 	Event        cloudevents.Event
 	Remediation  *RemediationHandler
-	WaitFunction waitFunction
 }
 
-type waitFunction func()
-
 // HandleEvent handles the incoming event
 func (eh *ActionFinishedEventHandler) HandleEvent() error {
 	actionFinishedEvent := &keptnv2.ActionFinishedEventData{}
@@ -31,21 +29,34 @@ func (eh *ActionFinishedEventHandler) HandleEvent() error {
 	}
 	eh.KeptnHandler.Logger.Info(fmt.Sprintf("Received action.finished event for Remediation action. result = %v, status = %v", actionFinishedEvent.Result, actionFinishedEvent.Status))
 
-	if eh.WaitFunction == nil {
-		eh.WaitFunction = func() {
+	remediations, err := eh.Remediation.getRemediationsByContext()
+	if err != nil {
+		eh.KeptnHandler.Logger.Error("could not retrieve open remediation: " + err.Error())
+	}
+	executedAction := getLastExecutedAction(remediations)
+
+	// the escalation action hands the problem over, e.g. to a human, so its effect is not evaluated
+	if executedAction != nil && executedAction.Escalated {
+		msg := fmt.Sprintf("Remediation has been escalated with action %s because all remediation actions failed", executedAction.Action)
+		eh.KeptnHandler.Logger.Info(msg)
+		return eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusSucceeded, keptnv2.ResultFailed, msg)
+	}
 
-			waitTime := getWaitTime()
-			eh.KeptnHandler.Logger.Info(fmt.Sprintf("Waiting for %s for action to take effect", waitTime.String()))
-			<-time.After(waitTime)
-		}
+	if executedAction == nil {
+		eh.KeptnHandler.Logger.Error("Could not schedule the evaluation of the action because no executed action is stored for keptnContext " + eh.KeptnHandler.KeptnContext)
+		eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusErrored, keptnv2.ResultFailed, "could not schedule the evaluation of the action")
+		return errors.New("no executed action found")
 	}
-	eh.WaitFunction()
-	eh.KeptnHandler.Logger.Info("Wait time is over. Sending start-evaluation event.")
 
-	err = eh.Remediation.sendEvaluationTriggeredEvent()
+	// the evaluation is triggered by the RemediationDispatcher once the wait time is over, also if the remediation-service is restarted in the meantime
+	waitTime := eh.Remediation.getActionWaitTime(executedAction)
+	evaluationDue := time.Now().Add(waitTime).UTC().Format(time.RFC3339)
+	eh.KeptnHandler.Logger.Info(fmt.Sprintf("Waiting for %s for action to take effect. The evaluation is due at %s", waitTime.String(), evaluationDue))
+
+	err = eh.Remediation.RemediationRepo.SetEvaluationDue(eh.KeptnHandler.KeptnContext, eh.KeptnHandler.Event.GetProject(), executedAction.EventID, evaluationDue)
 	if err != nil {
-		eh.KeptnHandler.Logger.Error("Could not send start-evaluation event: " + err.Error())
-		eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusErrored, keptnv2.ResultFailed, "could not send start-evaluation event")
+		eh.KeptnHandler.Logger.Error("Could not store the evaluation due time: " + err.Error())
+		eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusErrored, keptnv2.ResultFailed, "could not schedule the evaluation of the action")
 		return err
 	}
 	return nil
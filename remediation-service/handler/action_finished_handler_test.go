@@ -2,12 +2,13 @@ package handler
 
 import (
 	cloudevents "github.com/cloudevents/sdk-go/v2"
-	"github.com/go-openapi/strfmt"
 	keptnapi "github.com/keptn/go-utils/pkg/api/models"
 	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
 	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
 	"github.com/keptn/keptn/remediation-service/handler/fake"
+	"github.com/keptn/keptn/remediation-service/models"
 	"testing"
+	"time"
 )
 
 const actionFinishedEvent = `{
@@ -37,9 +38,35 @@ func TestActionFinishedEventHandler_HandleEvent(t *testing.T) {
 		fields                     fields
 		wantErr                    bool
 		expectedEventOnEventbroker []*keptnapi.KeptnContextExtendedCE
+		returnedRemediations       []*models.Remediation
+		expectedEvaluationDue      time.Duration
 	}{
 		{
-			name: "received action.finished, send start-evaluation event",
+			name: "received action.finished, store evaluation due time",
+			fields: fields{
+				Event: createTestCloudEvent(keptnv2.GetFinishedEventType(keptnv2.ActionTaskName), actionFinishedEvent),
+			},
+			wantErr:                    false,
+			expectedEventOnEventbroker: []*keptnapi.KeptnContextExtendedCE{},
+			returnedRemediations:       getPreviousRemediations(&models.Remediation{Action: "togglefeature", ActionIndex: 0, Attempt: 1, WaitTime: "5m"}),
+			expectedEvaluationDue:      5 * time.Minute,
+		},
+		{
+			name: "received action.finished without executed action, send remediation.finished event",
+			fields: fields{
+				Event: createTestCloudEvent(keptnv2.GetFinishedEventType(keptnv2.ActionTaskName), actionFinishedEvent),
+			},
+			wantErr: true,
+			expectedEventOnEventbroker: []*keptnapi.KeptnContextExtendedCE{
+				{
+					Contenttype:    "application/json",
+					Shkeptncontext: testKeptnContext,
+					Type:           stringp(keptnv2.GetFinishedEventType(keptnv2.RemediationTaskName)),
+				},
+			},
+		},
+		{
+			name: "received action.finished of escalation action, send remediation.finished event",
 			fields: fields{
 				Event: createTestCloudEvent(keptnv2.GetFinishedEventType(keptnv2.ActionTaskName), actionFinishedEvent),
 			},
@@ -47,16 +74,11 @@ func TestActionFinishedEventHandler_HandleEvent(t *testing.T) {
 			expectedEventOnEventbroker: []*keptnapi.KeptnContextExtendedCE{
 				{
 					Contenttype:    "application/json",
-					Data:           nil,
-					Extensions:     nil,
-					ID:             "",
 					Shkeptncontext: testKeptnContext,
-					Source:         nil,
-					Specversion:    "",
-					Time:           strfmt.DateTime{},
-					Type:           stringp(keptnv2.GetTriggeredEventType(keptnv2.EvaluationTaskName)),
+					Type:           stringp(keptnv2.GetFinishedEventType(keptnv2.RemediationTaskName)),
 				},
 			},
+			returnedRemediations: getPreviousRemediations(&models.Remediation{Action: "page", ActionIndex: 2, Attempt: 1, Escalated: true}),
 		},
 	}
 	for _, tt := range tests {
@@ -70,6 +92,7 @@ func TestActionFinishedEventHandler_HandleEvent(t *testing.T) {
 			})
 
 			fakeRemediationRepo := &fake.RemediationRepo{}
+			fakeRemediationRepo.Remediations = tt.returnedRemediations
 			remediation := &RemediationHandler{
 				Keptn:           testKeptnHandler,
 				RemediationRepo: fakeRemediationRepo,
@@ -79,13 +102,28 @@ func TestActionFinishedEventHandler_HandleEvent(t *testing.T) {
 				KeptnHandler: testKeptnHandler,
 				Event:        tt.fields.Event,
 				Remediation:  remediation,
-				WaitFunction: func() {},
 			}
+			start := time.Now()
 			if err := eh.HandleEvent(); (err != nil) != tt.wantErr {
 				t.Errorf("HandleEvent() error = %v, wantErr %v", err, tt.wantErr)
 			}
 
-			if mockEV.ReceivedAllRequests {
+			if tt.expectedEvaluationDue > 0 {
+				executedAction := getLastExecutedAction(fakeRemediationRepo.Remediations)
+				evaluationDue, err := time.Parse(time.RFC3339, executedAction.EvaluationDue)
+				if err != nil {
+					t.Fatalf("Invalid evaluation due time %s: %v", executedAction.EvaluationDue, err)
+				}
+				if evaluationDue.Before(start.Add(tt.expectedEvaluationDue).Truncate(time.Second)) || evaluationDue.After(time.Now().Add(tt.expectedEvaluationDue)) {
+					t.Errorf("Evaluation due at %s, expected after %s", executedAction.EvaluationDue, tt.expectedEvaluationDue.String())
+				}
+			}
+
+			if len(tt.expectedEventOnEventbroker) == 0 {
+				if len(mockEV.ReceivedEvents) > 0 {
+					t.Errorf("Received %d unexpected events", len(mockEV.ReceivedEvents))
+				}
+			} else if mockEV.ReceivedAllRequests {
 				t.Log("Received all required events")
 			} else {
 				t.Errorf("Did not receive all required events")
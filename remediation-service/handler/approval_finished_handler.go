@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"fmt"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/remediation-service/models"
+)
+
+// ApprovalFinishedEventHandler handles approval.finished events for actions that have been proposed by remediations in recommend mode
+type ApprovalFinishedEventHandler struct {
+	KeptnHandler *keptnv2.Keptn
+	Event        cloudevents.Event
+	Remediation  *RemediationHandler
+}
+
+// HandleEvent handles the event
+func (eh *ApprovalFinishedEventHandler) HandleEvent() error {
+	approvalFinishedEventData := &keptnv2.ApprovalFinishedEventData{}
+
+	err := eh.Event.DataAs(approvalFinishedEventData)
+	if err != nil {
+		eh.KeptnHandler.Logger.Error("Could not parse approval.finished event: " + err.Error())
+		return err
+	}
+
+	var triggeredID string
+	_ = eh.Event.ExtensionAs("triggeredid", &triggeredID)
+
+	remediations, err := eh.Remediation.getRemediationsByContext()
+	if err != nil {
+		eh.KeptnHandler.Logger.Error(fmt.Sprintf("could not retrieve open remediations for keptnContext %s: %s", eh.KeptnHandler.KeptnContext, err.Error()))
+		return err
+	}
+
+	// approvals of other tasks, e.g. of a delivery, are handled by the respective services
+	proposedAction := getProposedAction(remediations, triggeredID)
+	if proposedAction == nil {
+		eh.KeptnHandler.Logger.Debug(fmt.Sprintf("No action of a remediation is waiting for the approval %s", triggeredID))
+		return nil
+	}
+
+	// the approval is handled only once, even if the approval.finished event is sent again
+	err = eh.Remediation.createRemediation(&models.Remediation{
+		Action:  proposedAction.Action,
+		EventID: triggeredID,
+		Type:    keptnv2.GetFinishedEventType(keptnv2.ApprovalTaskName),
+	})
+	if err != nil {
+		eh.KeptnHandler.Logger.Error("Could not create remediation: " + err.Error())
+		return err
+	}
+
+	if approvalFinishedEventData.Result != keptnv2.ResultPass {
+		msg := fmt.Sprintf("Action %s has not been approved", proposedAction.Action)
+		eh.KeptnHandler.Logger.Info(msg)
+		return eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusSucceeded, keptnv2.ResultFailed, msg)
+	}
+
+	// get remediation.yaml
+	resource, err := eh.Remediation.getRemediationFile()
+	if err != nil {
+		eh.KeptnHandler.Logger.Info(err.Error())
+		_ = eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusErrored, keptnv2.ResultFailed, err.Error())
+		return err
+	}
+
+	remediationData, err := eh.Remediation.getRemediation(resource)
+	if err != nil {
+		eh.KeptnHandler.Logger.Error(err.Error())
+		_ = eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusErrored, keptnv2.ResultFailed, err.Error())
+		return err
+	}
+
+	remediationTriggeredEvent, err := eh.Remediation.getRemediationTriggeredEvent(remediations)
+	if err != nil {
+		eh.KeptnHandler.Logger.Error(err.Error())
+		_ = eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusErrored, keptnv2.ResultFailed, err.Error())
+		return err
+	}
+
+	// the approved action is taken from the remediation.yaml, since the remediation entry does not contain its value
+	var action *remediationAction
+	if remediation := getRemediationForProblem(remediationData, remediationTriggeredEvent.Problem); remediation != nil {
+		action = remediation.getAction(proposedAction.ActionIndex, proposedAction.Escalated)
+	}
+	if action == nil || action.Action != proposedAction.Action {
+		err := fmt.Errorf("approved action %s is no longer configured in the remediation.yaml", proposedAction.Action)
+		eh.KeptnHandler.Logger.Error(err.Error())
+		_ = eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusErrored, keptnv2.ResultFailed, err.Error())
+		return err
+	}
+
+	eh.KeptnHandler.Logger.Info(fmt.Sprintf("Action %s has been approved", action.Action))
+	err = eh.Remediation.sendAction(&remediationStep{
+		action:      action,
+		actionIndex: proposedAction.ActionIndex,
+		attempt:     proposedAction.Attempt,
+		escalation:  proposedAction.Escalated,
+	}, remediationTriggeredEvent.Problem)
+	if err != nil {
+		eh.KeptnHandler.Logger.Error(err.Error())
+		_ = eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusErrored, keptnv2.ResultFailed, err.Error())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	keptnapi "github.com/keptn/go-utils/pkg/api/models"
+	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/remediation-service/handler/fake"
+	"github.com/keptn/keptn/remediation-service/models"
+)
+
+const approvalFinishedEventPayloadWithResultPass = `{
+    "project": "sockshop",
+    "stage": "production",
+    "service": "service",
+    "status": "succeeded",
+    "result": "pass"
+  }`
+
+const approvalFinishedEventPayloadWithResultFailed = `{
+    "project": "sockshop",
+    "stage": "production",
+    "service": "service",
+    "status": "succeeded",
+    "result": "failed"
+  }`
+
+// getRemediationsWithProposedAction returns the remediation entries of a remediation in recommend mode that waits for the approval of an action
+func getRemediationsWithProposedAction() []*models.Remediation {
+	return []*models.Remediation{
+		previousRemediations[0],
+		{
+			Action:       "togglefeature",
+			EventID:      "test-id-2",
+			KeptnContext: testKeptnContext,
+			Type:         keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName),
+			Attempt:      1,
+		},
+		{
+			Action:       "togglefeature",
+			EventID:      "approval-id",
+			KeptnContext: testKeptnContext,
+			Type:         keptnv2.GetTriggeredEventType(keptnv2.ApprovalTaskName),
+			Attempt:      1,
+		},
+	}
+}
+
+func TestApprovalFinishedEventHandler_HandleEvent(t *testing.T) {
+	tests := []struct {
+		name                        string
+		payload                     string
+		triggeredID                 string
+		returnedRemediations        []*models.Remediation
+		expectedCreatedRemediations []*models.Remediation
+		expectedEventOnEventbroker  []*keptnapi.KeptnContextExtendedCE
+		expectedExecutedActions     int
+	}{
+		{
+			name:                 "proposed action approved, action triggered",
+			payload:              approvalFinishedEventPayloadWithResultPass,
+			triggeredID:          "approval-id",
+			returnedRemediations: getRemediationsWithProposedAction(),
+			expectedCreatedRemediations: []*models.Remediation{
+				{
+					Action:       "togglefeature",
+					KeptnContext: testKeptnContext,
+					Type:         keptnv2.GetFinishedEventType(keptnv2.ApprovalTaskName),
+				},
+			},
+			expectedEventOnEventbroker: []*keptnapi.KeptnContextExtendedCE{
+				{
+					Contenttype:    "application/json",
+					Shkeptncontext: testKeptnContext,
+					Type:           stringp(keptnv2.GetTriggeredEventType(keptnv2.ActionTaskName)),
+				},
+			},
+			expectedExecutedActions: 1,
+		},
+		{
+			name:                        "proposed action not approved, remediation finished",
+			payload:                     approvalFinishedEventPayloadWithResultFailed,
+			triggeredID:                 "approval-id",
+			returnedRemediations:        getRemediationsWithProposedAction(),
+			expectedCreatedRemediations: []*models.Remediation{},
+			expectedEventOnEventbroker: []*keptnapi.KeptnContextExtendedCE{
+				{
+					Contenttype:    "application/json",
+					Shkeptncontext: testKeptnContext,
+					Type:           stringp(keptnv2.GetFinishedEventType(keptnv2.RemediationTaskName)),
+				},
+			},
+		},
+		{
+			name:                        "approval of another task is ignored",
+			payload:                     approvalFinishedEventPayloadWithResultPass,
+			triggeredID:                 "other-approval-id",
+			returnedRemediations:        getRemediationsWithProposedAction(),
+			expectedCreatedRemediations: []*models.Remediation{},
+			expectedEventOnEventbroker:  []*keptnapi.KeptnContextExtendedCE{},
+		},
+		{
+			name:        "approval that has already been handled is ignored",
+			payload:     approvalFinishedEventPayloadWithResultPass,
+			triggeredID: "approval-id",
+			returnedRemediations: append(getRemediationsWithProposedAction(), &models.Remediation{
+				Action:       "togglefeature",
+				EventID:      "approval-id",
+				KeptnContext: testKeptnContext,
+				Type:         keptnv2.GetFinishedEventType(keptnv2.ApprovalTaskName),
+			}),
+			expectedCreatedRemediations: []*models.Remediation{},
+			expectedEventOnEventbroker:  []*keptnapi.KeptnContextExtendedCE{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCS := NewMockConfigurationService(remediationYamlResourceWithValidRemediation)
+			defer mockCS.Server.Close()
+
+			mockEV := NewMockEventbroker(tt.expectedEventOnEventbroker)
+			defer mockEV.Server.Close()
+
+			mockDS := NewMockDatastore(map[string]string{"test-id-1": previousRemediationTriggeredEvent})
+			defer mockDS.Server.Close()
+
+			event := createTestCloudEvent(keptnv2.GetFinishedEventType(keptnv2.ApprovalTaskName), tt.payload)
+			event.SetExtension("triggeredid", tt.triggeredID)
+			testKeptnHandler, _ := keptnv2.NewKeptn(&event, keptncommon.KeptnOpts{
+				EventBrokerURL:          mockEV.Server.URL,
+				ConfigurationServiceURL: mockCS.Server.URL,
+			})
+
+			fakeRemediationRepo := &fake.RemediationRepo{}
+			fakeRemediationRepo.Remediations = tt.returnedRemediations
+			remediation := &RemediationHandler{
+				Keptn:           testKeptnHandler,
+				RemediationRepo: fakeRemediationRepo,
+			}
+
+			eh := &ApprovalFinishedEventHandler{
+				KeptnHandler: testKeptnHandler,
+				Event:        event,
+				Remediation:  remediation,
+			}
+			if err := eh.HandleEvent(); err != nil {
+				t.Errorf("HandleEvent() error = %v", err)
+			}
+
+			if diff := deep.Equal(tt.expectedCreatedRemediations, fakeRemediationRepo.GetReceivedRemediations()); len(diff) > 0 {
+				t.Errorf("Did not create all required remediations")
+				for _, d := range diff {
+					t.Log(d)
+				}
+			}
+
+			if len(fakeRemediationRepo.ExecutedActions) != tt.expectedExecutedActions {
+				t.Errorf("Stored %d executed actions, expected %d", len(fakeRemediationRepo.ExecutedActions), tt.expectedExecutedActions)
+			}
+
+			if len(mockEV.ExpectedEvents) == 0 && len(mockEV.ReceivedEvents) == 0 {
+				t.Log("Received all required events on eventbroker")
+			} else if mockEV.ReceivedAllRequests {
+				t.Log("Received all required events on eventbroker")
+			} else {
+				t.Errorf("Did not receive all required events on eventbroker")
+			}
+		})
+	}
+}
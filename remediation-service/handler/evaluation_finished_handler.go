@@ -1,14 +1,10 @@
 package handler
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
-	keptnapi "github.com/keptn/go-utils/pkg/api/utils"
 	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
-	"github.com/keptn/keptn/remediation-service/models"
-	"os"
 )
 
 // EvaluationFinishedEventHandler handles incoming evaluation.finished events
@@ -39,12 +35,6 @@ func (eh *EvaluationFinishedEventHandler) HandleEvent() error {
 		return nil
 	}
 
-	if evaluationDoneEventData.Result == "pass" || evaluationDoneEventData.Result == "warning" {
-		msg := "RemediationHandler successful. RemediationHandler actions resulted in evaluation result: " + string(evaluationDoneEventData.Result)
-		eh.KeptnHandler.Logger.Info(msg)
-		return eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusSucceeded, keptnv2.ResultPass, msg)
-	}
-
 	// get remediation.yaml
 	resource, err := eh.Remediation.getRemediationFile()
 	if err != nil {
@@ -61,104 +51,52 @@ func (eh *EvaluationFinishedEventHandler) HandleEvent() error {
 		return err
 	}
 
-	remediationStatusChangedEvent, err := eh.getLastRemediationStatusChangedEvent(remediations)
-	if err != nil {
+	executedAction := getLastExecutedAction(remediations)
+	if executedAction == nil {
+		err := errors.New("no previously executed remediation actions have been found")
 		eh.KeptnHandler.Logger.Error(err.Error())
 		_ = eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusErrored, keptnv2.ResultFailed, err.Error())
 		return err
 	}
 
-	newActionIndex := remediationStatusChangedEvent.Remediation.ActionIndex + 1
-
-	remediationTriggeredEvent, err := eh.getRemediationTriggeredEvent(remediations)
+	remediationTriggeredEvent, err := eh.Remediation.getRemediationTriggeredEvent(remediations)
 	if err != nil {
 		eh.KeptnHandler.Logger.Error(err.Error())
 		_ = eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusErrored, keptnv2.ResultFailed, err.Error())
 		return err
 	}
 
-	nextAction := eh.Remediation.getActionForProblemType(*remediationData, remediationTriggeredEvent.Problem.ProblemTitle, newActionIndex)
-
-	if nextAction != nil {
-		err = eh.Remediation.triggerAction(nextAction, newActionIndex, remediationTriggeredEvent.Problem)
-		if err != nil {
-			eh.KeptnHandler.Logger.Error(err.Error())
-			_ = eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusErrored, keptnv2.ResultFailed, err.Error())
-			return err
-		}
-		return nil
-	}
-
-	msg := "No further remediation action configured for problem type " + remediationTriggeredEvent.Problem.ProblemTitle
-	eh.KeptnHandler.Logger.Info(msg)
-	return eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusSucceeded, keptnv2.ResultFailed, msg)
-}
+	remediation := getRemediationForProblem(remediationData, remediationTriggeredEvent.Problem)
 
-func (eh *EvaluationFinishedEventHandler) getLastRemediationStatusChangedEvent(remediations []*models.Remediation) (*keptnv2.RemediationStatusChangedEventData, error) {
-	var lastRemediationStatusChanged *models.Remediation
-	for index := range remediations {
-		remediation := remediations[len(remediations)-1-index]
-		if remediation.Type == keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName) {
-			lastRemediationStatusChanged = remediation
-			break
+	// the success criteria of the executed action decide whether the remediation was successful
+	var successCriteria *remediationSuccessCriteria
+	if remediation != nil {
+		if action := remediation.getAction(executedAction.ActionIndex, executedAction.Escalated); action != nil {
+			successCriteria = action.SuccessCriteria
 		}
 	}
-
-	if lastRemediationStatusChanged == nil {
-		return nil, errors.New("no previously executed remediation actions have been found")
-	}
-
-	eventHandler := keptnapi.NewEventHandler(os.Getenv(datastoreConnection))
-
-	events, errorObj := eventHandler.GetEvents(&keptnapi.EventFilter{
-		EventID: lastRemediationStatusChanged.EventID,
-		Project: eh.KeptnHandler.Event.GetProject(),
-	})
-
-	if errorObj != nil {
-		return nil, fmt.Errorf("could not retrieve remediation action with ID %s: %s", lastRemediationStatusChanged.EventID, *errorObj.Message)
-	}
-	if len(events) == 0 {
-		return nil, fmt.Errorf("could not retrieve remediation action with ID %s: no event found.", lastRemediationStatusChanged.EventID)
-	}
-	remediationStatusChangedEvent := &keptnv2.RemediationStatusChangedEventData{}
-
-	if err := keptnv2.Decode(events[0].Data, remediationStatusChangedEvent); err != nil {
-		return nil, fmt.Errorf("could not decode remediation.status.changed event: %s", err.Error())
+	if successCriteria.isMet(evaluationDoneEventData) {
+		msg := "RemediationHandler successful. RemediationHandler actions resulted in evaluation result: " + string(evaluationDoneEventData.Result)
+		eh.KeptnHandler.Logger.Info(msg)
+		return eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusSucceeded, keptnv2.ResultPass, msg)
 	}
-	return remediationStatusChangedEvent, nil
-}
 
-func (eh *EvaluationFinishedEventHandler) getRemediationTriggeredEvent(remediations []*models.Remediation) (*keptnv2.RemediationTriggeredEventData, error) {
-	var remediationTriggered *models.Remediation
-	for _, remediation := range remediations {
-		if remediation.Type == keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName) {
-			remediationTriggered = remediation
-			break
+	if remediation != nil {
+		if nextStep := remediation.getNextStep(executedAction.ActionIndex, executedAction.Attempt, executedAction.Escalated); nextStep != nil {
+			if nextStep.escalation {
+				eh.KeptnHandler.Logger.Info("All remediation actions failed, escalating with action " + nextStep.action.Action)
+			}
+			err = eh.Remediation.executeStep(remediationData, nextStep, remediationTriggeredEvent.Problem)
+			if err != nil {
+				eh.KeptnHandler.Logger.Error(err.Error())
+				_ = eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusErrored, keptnv2.ResultFailed, err.Error())
+				return err
+			}
+			return nil
 		}
 	}
 
-	if remediationTriggered == nil {
-		return nil, errors.New("no previously executed remediation actions have been found")
-	}
-
-	eventHandler := keptnapi.NewEventHandler(os.Getenv(datastoreConnection))
-
-	events, errorObj := eventHandler.GetEvents(&keptnapi.EventFilter{
-		EventID: remediationTriggered.EventID,
-		Project: eh.KeptnHandler.Event.GetProject(),
-	})
-
-	if errorObj != nil || len(events) == 0 {
-		return nil, fmt.Errorf("could not retrieve remediation action with ID %s: %s", remediationTriggered.EventID, *errorObj.Message)
-	}
-	remediationTriggeredEvent := &keptnv2.RemediationTriggeredEventData{}
-
-	marshal, _ := json.Marshal(events[0].Data)
-	err := json.Unmarshal(marshal, remediationTriggeredEvent)
-
-	if err != nil {
-		return nil, fmt.Errorf("could not decode remediation.triggered event: %s", err.Error())
-	}
-	return remediationTriggeredEvent, nil
+	msg := "No further remediation action configured for problem type " + remediationTriggeredEvent.Problem.ProblemTitle
+	eh.KeptnHandler.Logger.Info(msg)
+	return eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusSucceeded, keptnv2.ResultFailed, msg)
 }
@@ -32,6 +32,14 @@ var previousRemediations = []*models.Remediation{
 
 var emptyRemediations = []*models.Remediation{}
 
+// getPreviousRemediations returns the remediation entries of a remediation whose last executed action is the given one
+func getPreviousRemediations(executedAction *models.Remediation) []*models.Remediation {
+	executedAction.EventID = "test-id-2"
+	executedAction.KeptnContext = testKeptnContext
+	executedAction.Type = keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName)
+	return []*models.Remediation{previousRemediations[0], executedAction}
+}
+
 const previousRemediationStatusChangedEvent = `{
     "nextPageKey": "0",
     "events": [
@@ -105,27 +113,27 @@ const evaluationDoneEventPayloadWithResultFailed = `{
     "project": "sockshop",
     "stage": "production", 
     "service": "service",
-    "result": "failed",
+    "result": "failed"
   }`
 
 const evaluationDoneEventPayloadWithResultPass = `{
     "project": "sockshop",
     "stage": "production", 
     "service": "service",
-    "result": "pass",
+    "result": "pass"
   }`
 
 const evaluationDoneEventPayloadWithResultWarning = `{
     "project": "sockshop",
     "stage": "production", 
     "service": "service",
-    "result": "warning",
+    "result": "warning"
   }`
 
 const evaluationDoneEventWithIrrelevantTestStrategyPayload = `{
     "project": "sockshop",
     "stage": "production", 
-    "service": "service",
+    "service": "service"
   }`
 
 type MockDatastore struct {
@@ -192,6 +200,8 @@ func TestEvaluationDoneEventHandler_HandleEvent(t *testing.T) {
 					Action:       "escalate",
 					KeptnContext: testKeptnContext,
 					Type:         keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName),
+					ActionIndex:  1,
+					Attempt:      1,
 				},
 			},
 			expectedEventOnEventbroker: []*keptnapi.KeptnContextExtendedCE{
@@ -224,6 +234,126 @@ func TestEvaluationDoneEventHandler_HandleEvent(t *testing.T) {
 				"test-id-2": previousRemediationStatusChangedEvent,
 			},
 		},
+		{
+			name: "repeat action until its maximum number of attempts is reached",
+			fields: fields{
+				Event: createTestCloudEvent(keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName), evaluationDoneEventPayloadWithResultFailed),
+			},
+			wantErr:                         false,
+			returnedRemediationYamlResource: getRemediationYamlResource(remediationYamlWithEscalationChain),
+			expectedCreatedRemediations: []*models.Remediation{
+				{
+					Action:       "scaling",
+					KeptnContext: testKeptnContext,
+					Type:         keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName),
+					Attempt:      2,
+					WaitTime:     "2m",
+				},
+			},
+			expectedEventOnEventbroker: []*keptnapi.KeptnContextExtendedCE{
+				{
+					Contenttype:    "application/json",
+					Shkeptncontext: testKeptnContext,
+					Type:           stringp(keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName)),
+				},
+				{
+					Contenttype:    "application/json",
+					Shkeptncontext: testKeptnContext,
+					Type:           stringp(keptnv2.GetTriggeredEventType(keptnv2.ActionTaskName)),
+				},
+			},
+			returnedRemediations: getPreviousRemediations(&models.Remediation{Action: "scaling", Attempt: 1, WaitTime: "2m"}),
+			returnedEvents: map[string]string{
+				"test-id-1": previousRemediationTriggeredEvent,
+			},
+		},
+		{
+			name: "trigger next action if the success criteria of the action are not met",
+			fields: fields{
+				Event: createTestCloudEvent(keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName), evaluationDoneEventPayloadWithResultWarning),
+			},
+			wantErr:                         false,
+			returnedRemediationYamlResource: getRemediationYamlResource(remediationYamlWithEscalationChain),
+			expectedCreatedRemediations: []*models.Remediation{
+				{
+					Action:       "togglefeature",
+					KeptnContext: testKeptnContext,
+					Type:         keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName),
+					ActionIndex:  1,
+					Attempt:      1,
+				},
+			},
+			expectedEventOnEventbroker: []*keptnapi.KeptnContextExtendedCE{
+				{
+					Contenttype:    "application/json",
+					Shkeptncontext: testKeptnContext,
+					Type:           stringp(keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName)),
+				},
+				{
+					Contenttype:    "application/json",
+					Shkeptncontext: testKeptnContext,
+					Type:           stringp(keptnv2.GetTriggeredEventType(keptnv2.ActionTaskName)),
+				},
+			},
+			returnedRemediations: getPreviousRemediations(&models.Remediation{Action: "scaling", Attempt: 2, WaitTime: "2m"}),
+			returnedEvents: map[string]string{
+				"test-id-1": previousRemediationTriggeredEvent,
+			},
+		},
+		{
+			name: "escalate after all actions failed",
+			fields: fields{
+				Event: createTestCloudEvent(keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName), evaluationDoneEventPayloadWithResultFailed),
+			},
+			wantErr:                         false,
+			returnedRemediationYamlResource: getRemediationYamlResource(remediationYamlWithEscalationChain),
+			expectedCreatedRemediations: []*models.Remediation{
+				{
+					Action:       "page",
+					KeptnContext: testKeptnContext,
+					Type:         keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName),
+					ActionIndex:  2,
+					Attempt:      1,
+					Escalated:    true,
+				},
+			},
+			expectedEventOnEventbroker: []*keptnapi.KeptnContextExtendedCE{
+				{
+					Contenttype:    "application/json",
+					Shkeptncontext: testKeptnContext,
+					Type:           stringp(keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName)),
+				},
+				{
+					Contenttype:    "application/json",
+					Shkeptncontext: testKeptnContext,
+					Type:           stringp(keptnv2.GetTriggeredEventType(keptnv2.ActionTaskName)),
+				},
+			},
+			returnedRemediations: getPreviousRemediations(&models.Remediation{Action: "togglefeature", ActionIndex: 1, Attempt: 1}),
+			returnedEvents: map[string]string{
+				"test-id-1": previousRemediationTriggeredEvent,
+			},
+		},
+		{
+			name: "complete remediation if the success criteria of the action are met",
+			fields: fields{
+				Event: createTestCloudEvent(keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName), evaluationDoneEventPayloadWithResultPass),
+			},
+			wantErr:                         false,
+			returnedRemediationYamlResource: getRemediationYamlResource(remediationYamlWithEscalationChain),
+			expectedCreatedRemediations:     []*models.Remediation{},
+			expectedEventOnEventbroker: []*keptnapi.KeptnContextExtendedCE{
+				{
+					Contenttype:    "application/json",
+					Shkeptncontext: testKeptnContext,
+					Type:           stringp(keptnv2.GetFinishedEventType(keptnv2.RemediationTaskName)),
+				},
+			},
+			returnedRemediations: getPreviousRemediations(&models.Remediation{Action: "togglefeature", ActionIndex: 1, Attempt: 1}),
+			returnedEvents: map[string]string{
+				"test-id-1": previousRemediationTriggeredEvent,
+			},
+		},
 		{
 			name: "all actions executed - send finished event",
 			fields: fields{
@@ -374,3 +504,19 @@ func TestEvaluationDoneEventHandler_HandleEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestRemediationHandler_getRemediationTriggeredEvent_notFound(t *testing.T) {
+	mockDS := NewMockDatastore(map[string]string{"test-id-1": `{"events": []}`})
+	defer mockDS.Server.Close()
+
+	event := createTestCloudEvent(keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName), evaluationDoneEventPayloadWithResultFailed)
+	testKeptnHandler, _ := keptnv2.NewKeptn(&event, keptncommon.KeptnOpts{})
+	remediation := &RemediationHandler{
+		Keptn:           testKeptnHandler,
+		RemediationRepo: &fake.RemediationRepo{},
+	}
+
+	if _, err := remediation.getRemediationTriggeredEvent(previousRemediations); err == nil {
+		t.Errorf("getRemediationTriggeredEvent() returned no error for a remediation.triggered event that has not been stored")
+	}
+}
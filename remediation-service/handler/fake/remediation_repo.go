@@ -1,12 +1,19 @@
 package fake
 
-import "github.com/keptn/keptn/remediation-service/models"
+import (
+	"github.com/keptn/keptn/remediation-service/models"
+	"time"
+)
 
 type RemediationRepo struct {
 	// Remediations describes the current state of the remediation repo (incl. remediations that are assumed to be available when a unit test is started)
 	Remediations []*models.Remediation
 	// ReceivedRemediations acts as a kind of recorder to keep track of what remediations have been added/deleted during a test
 	ReceivedRemediations []*models.Remediation
+	// ExecutedActions describes the history of actions triggered by remediations
+	ExecutedActions []*models.Remediation
+	// Project is the project the remediations belong to
+	Project string
 }
 
 func (r *RemediationRepo) GetRemediations(keptnContext, project string) ([]*models.Remediation, error) {
@@ -20,6 +27,12 @@ func (r *RemediationRepo) GetRemediations(keptnContext, project string) ([]*mode
 	return result, nil
 }
 
+func (r *RemediationRepo) GetOpenRemediations(project string) ([]*models.Remediation, error) {
+	result := []*models.Remediation{}
+	result = append(result, r.Remediations...)
+	return result, nil
+}
+
 func (r *RemediationRepo) CreateRemediation(project string, remediation *models.Remediation) error {
 	if r.Remediations == nil {
 		r.Remediations = []*models.Remediation{}
@@ -57,3 +70,55 @@ func (r *RemediationRepo) GetReceivedRemediations() []*models.Remediation {
 	}
 	return r.ReceivedRemediations
 }
+
+func (r *RemediationRepo) CreateExecutedAction(project string, action *models.Remediation) error {
+	r.ExecutedActions = append(r.ExecutedActions, action)
+	return nil
+}
+
+func (r *RemediationRepo) GetExecutedActions(project, stage, service string, since time.Time) ([]*models.Remediation, error) {
+	result := []*models.Remediation{}
+	for _, action := range r.ExecutedActions {
+		actionTime, err := time.Parse(time.RFC3339, action.Time)
+		if err != nil {
+			return nil, err
+		}
+		if action.Stage == stage && action.Service == service && !actionTime.Before(since) {
+			result = append(result, action)
+		}
+	}
+	return result, nil
+}
+
+func (r *RemediationRepo) SetEvaluationDue(keptnContext, project, eventID, evaluationDue string) error {
+	for _, remediation := range r.Remediations {
+		if remediation.KeptnContext == keptnContext && remediation.EventID == eventID {
+			remediation.EvaluationDue = evaluationDue
+		}
+	}
+	return nil
+}
+
+func (r *RemediationRepo) GetDueEvaluations(project string, now time.Time) ([]*models.Remediation, error) {
+	result := []*models.Remediation{}
+	for _, remediation := range r.Remediations {
+		if remediation.EvaluationDue == "" {
+			continue
+		}
+		evaluationDue, err := time.Parse(time.RFC3339, remediation.EvaluationDue)
+		if err != nil {
+			return nil, err
+		}
+		if !evaluationDue.After(now) {
+			result = append(result, remediation)
+		}
+	}
+	return result, nil
+}
+
+func (r *RemediationRepo) GetProjects() ([]string, error) {
+	if len(r.Remediations) == 0 {
+		return []string{}, nil
+	}
+	return []string{r.Project}, nil
+}
@@ -1,19 +1,20 @@
 package handler
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	keptnapi "github.com/keptn/go-utils/pkg/api/utils"
 	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
 	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
 	"github.com/keptn/keptn/remediation-service/db"
 	"github.com/keptn/keptn/remediation-service/models"
 	"net/url"
+	"os"
 	"strings"
 	"time"
 
-	"github.com/keptn/go-utils/pkg/lib/v0_1_4"
-
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/ghodss/yaml"
 	configmodels "github.com/keptn/go-utils/pkg/api/models"
@@ -61,12 +62,25 @@ func NewHandler(event cloudevents.Event) (Handler, error) {
 			Event:        event,
 			Remediation:  remediationHandler,
 		}, nil
+	case keptn.ProblemEventType:
+		return &ProblemEventHandler{
+			KeptnHandler: keptnHandler,
+			Logger:       keptnHandler.Logger,
+			Event:        event,
+			Remediation:  remediationHandler,
+		}, nil
 	case keptnv2.GetFinishedEventType(keptnv2.EvaluationTaskName):
 		return &EvaluationFinishedEventHandler{
 			KeptnHandler: keptnHandler,
 			Event:        event,
 			Remediation:  remediationHandler,
 		}, nil
+	case keptnv2.GetFinishedEventType(keptnv2.ApprovalTaskName):
+		return &ApprovalFinishedEventHandler{
+			KeptnHandler: keptnHandler,
+			Event:        event,
+			Remediation:  remediationHandler,
+		}, nil
 	case keptnv2.GetFinishedEventType(keptnv2.ActionTaskName):
 		return &ActionFinishedEventHandler{
 			KeptnHandler: keptnHandler,
@@ -84,18 +98,6 @@ type RemediationHandler struct {
 	RemediationRepo db.IRemediationRepo
 }
 
-func (r *RemediationHandler) getActionForProblemType(remediationData v0_1_4.Remediation, problemType string, index int) *v0_1_4.RemediationActionsOnOpen {
-	for _, remediation := range remediationData.Spec.Remediations {
-		if strings.HasPrefix(problemType, remediation.ProblemType) {
-			r.Keptn.Logger.Info("Found remediation for problem type " + remediation.ProblemType)
-			if len(remediation.ActionsOnOpen) > index {
-				return &remediation.ActionsOnOpen[index]
-			}
-		}
-	}
-	return nil
-}
-
 func (r *RemediationHandler) sendRemediationTriggeredEvent(problemDetails *keptn.ProblemEventData) error {
 	source, _ := url.Parse("remediation-service")
 
@@ -127,7 +129,15 @@ func (r *RemediationHandler) sendRemediationTriggeredEvent(problemDetails *keptn
 	event.SetExtension("shkeptncontext", r.Keptn.KeptnContext)
 	event.SetData(cloudevents.ApplicationJSON, eventData)
 
-	err := r.createRemediation(event.ID(), keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName), "")
+	err := r.createRemediation(&models.Remediation{
+		EventID:   event.ID(),
+		Type:      keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName),
+		ProblemID: problemDetails.ProblemID,
+		PID:       problemDetails.PID,
+		Stage:     r.Keptn.KeptnBase.Event.GetStage(),
+		Service:   r.Keptn.KeptnBase.Event.GetService(),
+		Labels:    r.Keptn.KeptnBase.Event.GetLabels(),
+	})
 	if err != nil {
 		r.Keptn.Logger.Error("Could not create remediation: " + err.Error())
 		return err
@@ -141,15 +151,10 @@ func (r *RemediationHandler) sendRemediationTriggeredEvent(problemDetails *keptn
 	return nil
 }
 
-func (r *RemediationHandler) createRemediation(eventID, remediationEventType, action string) error {
-	newRemediation := &models.Remediation{
-		Action:       action,
-		EventID:      eventID,
-		KeptnContext: r.Keptn.KeptnBase.KeptnContext,
-		Type:         remediationEventType,
-	}
-
-	return r.RemediationRepo.CreateRemediation(r.Keptn.KeptnBase.Event.GetProject(), newRemediation)
+func (r *RemediationHandler) createRemediation(remediation *models.Remediation) error {
+	remediation.KeptnContext = r.Keptn.KeptnBase.KeptnContext
+	remediation.Time = time.Now().UTC().Format(time.RFC3339)
+	return r.RemediationRepo.CreateRemediation(r.Keptn.KeptnBase.Event.GetProject(), remediation)
 }
 
 func (r *RemediationHandler) deleteRemediation() error {
@@ -160,10 +165,34 @@ func (r *RemediationHandler) getRemediationsByContext() ([]*models.Remediation,
 	return r.RemediationRepo.GetRemediations(r.Keptn.KeptnBase.KeptnContext, r.Keptn.KeptnBase.Event.GetProject())
 }
 
-func (r *RemediationHandler) sendEvaluationTriggeredEvent() error {
+// getLastExecutedAction returns the remediation entry of the action that has been triggered last
+func getLastExecutedAction(remediations []*models.Remediation) *models.Remediation {
+	for index := range remediations {
+		remediation := remediations[len(remediations)-1-index]
+		if remediation.Type == keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName) {
+			return remediation
+		}
+	}
+	return nil
+}
+
+// getActionWaitTime returns the time to wait for an action to take effect, as configured for the action in the remediation.yaml, or the default wait time
+func (r *RemediationHandler) getActionWaitTime(executedAction *models.Remediation) time.Duration {
+	if executedAction == nil || executedAction.WaitTime == "" {
+		return getWaitTime()
+	}
+	waitTime, err := time.ParseDuration(executedAction.WaitTime)
+	if err != nil {
+		r.Keptn.Logger.Error(fmt.Sprintf("Invalid wait time %s of action %s, using default wait time: %s", executedAction.WaitTime, executedAction.Action, err.Error()))
+		return getWaitTime()
+	}
+	return waitTime
+}
+
+// sendEvaluationTriggeredEvent triggers the evaluation of the time frame of the given length that ends at evaluationEnd
+func (r *RemediationHandler) sendEvaluationTriggeredEvent(evaluationEnd time.Time, waitTime time.Duration) error {
 	source, _ := url.Parse("remediation-service")
 
-	waitTime := getWaitTime()
 	evaluationTriggeredEventData := &keptnv2.EvaluationTriggeredEventData{
 		EventData: keptnv2.EventData{
 			Project: r.Keptn.Event.GetProject(),
@@ -175,15 +204,15 @@ func (r *RemediationHandler) sendEvaluationTriggeredEvent() error {
 			Start string `json:"start"`
 			End   string `json:"end"`
 		}{
-			Start: time.Now().Add(-waitTime).Format(time.RFC3339),
-			End:   time.Now().Format(time.RFC3339),
+			Start: evaluationEnd.Add(-waitTime).Format(time.RFC3339),
+			End:   evaluationEnd.Format(time.RFC3339),
 		},
 		Evaluation: struct {
 			Start string `json:"start"`
 			End   string `json:"end"`
 		}{
-			Start: time.Now().Add(-waitTime).Format(time.RFC3339),
-			End:   time.Now().Format(time.RFC3339),
+			Start: evaluationEnd.Add(-waitTime).Format(time.RFC3339),
+			End:   evaluationEnd.Format(time.RFC3339),
 		},
 	}
 
@@ -253,7 +282,7 @@ func (r *RemediationHandler) sendRemediationFinishedEvent(status keptnv2.StatusT
 	return nil
 }
 
-func (r *RemediationHandler) getActionTriggeredEventData(problemDetails keptnv2.ProblemDetails, action *v0_1_4.RemediationActionsOnOpen) (keptnv2.ActionTriggeredEventData, error) {
+func (r *RemediationHandler) getActionTriggeredEventData(problemDetails keptnv2.ProblemDetails, action *remediationAction) (keptnv2.ActionTriggeredEventData, error) {
 	return keptnv2.ActionTriggeredEventData{
 		EventData: keptnv2.EventData{
 			Project: r.Keptn.Event.GetProject(),
@@ -290,7 +319,7 @@ func (r *RemediationHandler) sendActionTriggeredEvent(actionTriggeredEventData k
 	return nil
 }
 
-func (r *RemediationHandler) sendRemediationStatusChangedEvent(action *v0_1_4.RemediationActionsOnOpen, actionIndex int) error {
+func (r *RemediationHandler) sendRemediationStatusChangedEvent(step *remediationStep, message string) error {
 
 	triggeredID := ""
 	remediations, err := r.getRemediationsByContext()
@@ -314,11 +343,11 @@ func (r *RemediationHandler) sendRemediationStatusChangedEvent(action *v0_1_4.Re
 				Labels:  r.Keptn.Event.GetLabels(),
 				Status:  keptnv2.StatusSucceeded,
 				Result:  "",
-				Message: "",
+				Message: message,
 			},
 			Remediation: keptnv2.Remediation{
-				ActionIndex: actionIndex,
-				ActionName:  action.Action,
+				ActionIndex: step.actionIndex,
+				ActionName:  step.action.Action,
 			},
 		}
 
@@ -335,7 +364,15 @@ func (r *RemediationHandler) sendRemediationStatusChangedEvent(action *v0_1_4.Re
 	}
 	event.SetData(cloudevents.ApplicationJSON, remediationStatusChangedEventData)
 
-	err = r.createRemediation(event.ID(), keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName), action.Action)
+	err = r.createRemediation(&models.Remediation{
+		Action:      step.action.Action,
+		EventID:     event.ID(),
+		Type:        keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName),
+		ActionIndex: step.actionIndex,
+		Attempt:     step.attempt,
+		WaitTime:    step.action.WaitTime,
+		Escalated:   step.escalation,
+	})
 	if err != nil {
 		r.Keptn.Logger.Error("Could not create remediation: " + err.Error())
 		return err
@@ -369,8 +406,8 @@ func (r *RemediationHandler) getRemediationFile() (*configmodels.Resource, error
 	return resource, nil
 }
 
-func (r *RemediationHandler) getRemediation(resource *configmodels.Resource) (*v0_1_4.Remediation, error) {
-	remediationData := &v0_1_4.Remediation{}
+func (r *RemediationHandler) getRemediation(resource *configmodels.Resource) (*remediationConfig, error) {
+	remediationData := &remediationConfig{}
 	err := yaml.Unmarshal([]byte(resource.ResourceContent), remediationData)
 	if err != nil {
 		return nil, fmt.Errorf("could not parse remediation.yaml: %s", err.Error())
@@ -379,16 +416,102 @@ func (r *RemediationHandler) getRemediation(resource *configmodels.Resource) (*v
 	if remediationData.ApiVersion != remediationSpecVersion {
 		return nil, fmt.Errorf("remediation.yaml file does not conform to remediation spec %s", remediationSpecVersion)
 	}
+	if err := remediationData.validate(); err != nil {
+		return nil, fmt.Errorf("invalid remediation.yaml: %s", err.Error())
+	}
 	return remediationData, nil
 }
 
-func (r *RemediationHandler) triggerAction(action *v0_1_4.RemediationActionsOnOpen, actionIndex int, problemDetails keptnv2.ProblemDetails) error {
-	err := r.sendRemediationStatusChangedEvent(action, actionIndex)
+// getSuppressionReason checks the action of the step against the limits of the remediation.yaml and the actions that have been triggered
+// for the service before. It returns why the action must not be triggered, or an empty string if it can be triggered. Escalation actions
+// are never suppressed
+func (r *RemediationHandler) getSuppressionReason(limits *remediationLimits, step *remediationStep) (string, error) {
+	if limits == nil || step.escalation {
+		return "", nil
+	}
+	cooldown := limits.getCooldown()
+	if cooldown <= 0 && limits.MaxActionsPerHour <= 0 {
+		return "", nil
+	}
+
+	now := time.Now()
+	since := now.Add(-time.Hour)
+	if cooldown > time.Hour {
+		since = now.Add(-cooldown)
+	}
+	stage := r.Keptn.KeptnBase.Event.GetStage()
+	service := r.Keptn.KeptnBase.Event.GetService()
+	executedActions, err := r.RemediationRepo.GetExecutedActions(r.Keptn.KeptnBase.Event.GetProject(), stage, service, since)
+	if err != nil {
+		return "", fmt.Errorf("could not retrieve executed actions: %s", err.Error())
+	}
+
+	actionsWithinLastHour := 0
+	for _, executedAction := range executedActions {
+		if executedAction.Escalated {
+			continue
+		}
+		executionTime, err := time.Parse(time.RFC3339, executedAction.Time)
+		if err != nil {
+			continue
+		}
+		if cooldown > 0 && executedAction.KeptnContext != r.Keptn.KeptnBase.KeptnContext && now.Sub(executionTime) < cooldown {
+			return fmt.Sprintf("Action %s has been suppressed because another remediation triggered action %s for service %s in stage %s at %s, which is within the cooldown of %s",
+				step.action.Action, executedAction.Action, service, stage, executedAction.Time, cooldown.String()), nil
+		}
+		if now.Sub(executionTime) < time.Hour {
+			actionsWithinLastHour++
+		}
+	}
+	if limits.MaxActionsPerHour > 0 && actionsWithinLastHour >= limits.MaxActionsPerHour {
+		return fmt.Sprintf("Action %s has been suppressed because %d actions have been triggered for service %s in stage %s within the last hour, which is the maximum of %d actions per hour",
+			step.action.Action, actionsWithinLastHour, service, stage, limits.MaxActionsPerHour), nil
+	}
+	return "", nil
+}
+
+// executeStep triggers the action of the step unless the limits of the remediation.yaml suppress it. A suppressed action finishes the
+// remediation with a message explaining why the action has not been triggered. In recommend mode, the action is only proposed
+func (r *RemediationHandler) executeStep(config *remediationConfig, step *remediationStep, problemDetails keptnv2.ProblemDetails) error {
+	reason, err := r.getSuppressionReason(config.Spec.Limits, step)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		r.Keptn.Logger.Info(reason)
+		return r.sendRemediationFinishedEvent(keptnv2.StatusSucceeded, keptnv2.ResultFailed, reason)
+	}
+	if config.Spec.Mode == remediationModeRecommend {
+		return r.proposeAction(step)
+	}
+	return r.triggerAction(step, problemDetails)
+}
+
+func (r *RemediationHandler) triggerAction(step *remediationStep, problemDetails keptnv2.ProblemDetails) error {
+	err := r.sendRemediationStatusChangedEvent(step, "")
 	if err != nil {
 		return fmt.Errorf("could not send remediation.status.changed event: %s", err.Error())
 	}
+	return r.sendAction(step, problemDetails)
+}
+
+// proposeAction announces the action of the step with a remediation.status.changed event and requests an approval for it with an
+// approval.triggered event. The action is triggered when the approval.finished event approving it is received
+func (r *RemediationHandler) proposeAction(step *remediationStep) error {
+	msg := fmt.Sprintf("Remediation proposes action %s. The action is triggered after it has been approved.", step.action.Action)
+	r.Keptn.Logger.Info(msg)
+	if err := r.sendRemediationStatusChangedEvent(step, msg); err != nil {
+		return fmt.Errorf("could not send remediation.status.changed event: %s", err.Error())
+	}
+	if err := r.sendApprovalTriggeredEvent(step, msg); err != nil {
+		return fmt.Errorf("could not send approval.triggered event: %s", err.Error())
+	}
+	return nil
+}
 
-	actionTriggeredEventData, err := r.getActionTriggeredEventData(problemDetails, action)
+// sendAction sends the action.triggered event for the action of the step and adds the action to the history of executed actions
+func (r *RemediationHandler) sendAction(step *remediationStep, problemDetails keptnv2.ProblemDetails) error {
+	actionTriggeredEventData, err := r.getActionTriggeredEventData(problemDetails, step.action)
 	if err != nil {
 		return fmt.Errorf("could not create action.triggered event: %s", err.Error())
 	}
@@ -396,5 +519,120 @@ func (r *RemediationHandler) triggerAction(action *v0_1_4.RemediationActionsOnOp
 	if err := r.sendActionTriggeredEvent(actionTriggeredEventData); err != nil {
 		return fmt.Errorf("could not send action.triggered event: %s", err.Error())
 	}
+
+	// the history of executed actions outlives the remediation and is used to enforce the limits of the remediation.yaml
+	err = r.RemediationRepo.CreateExecutedAction(r.Keptn.KeptnBase.Event.GetProject(), &models.Remediation{
+		Action:       step.action.Action,
+		KeptnContext: r.Keptn.KeptnBase.KeptnContext,
+		Type:         keptnv2.GetTriggeredEventType(keptnv2.ActionTaskName),
+		Escalated:    step.escalation,
+		Stage:        r.Keptn.KeptnBase.Event.GetStage(),
+		Service:      r.Keptn.KeptnBase.Event.GetService(),
+		Time:         time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		r.Keptn.Logger.Error("Could not store executed action: " + err.Error())
+	}
 	return nil
 }
+
+func (r *RemediationHandler) sendApprovalTriggeredEvent(step *remediationStep, message string) error {
+	source, _ := url.Parse("remediation-service")
+
+	approvalTriggeredEventData := &keptnv2.ApprovalTriggeredEventData{
+		EventData: keptnv2.EventData{
+			Project: r.Keptn.Event.GetProject(),
+			Service: r.Keptn.Event.GetService(),
+			Stage:   r.Keptn.Event.GetStage(),
+			Labels:  r.Keptn.Event.GetLabels(),
+			Message: message,
+		},
+		Approval: keptnv2.Approval{
+			Pass:    keptnv2.ApprovalManual,
+			Warning: keptnv2.ApprovalManual,
+		},
+	}
+
+	event := cloudevents.NewEvent()
+	event.SetID(uuid.New().String())
+	event.SetType(keptnv2.GetTriggeredEventType(keptnv2.ApprovalTaskName))
+	event.SetSource(source.String())
+	event.SetDataContentType(cloudevents.ApplicationJSON)
+	event.SetExtension("shkeptncontext", r.Keptn.KeptnContext)
+	event.SetData(cloudevents.ApplicationJSON, approvalTriggeredEventData)
+
+	err := r.createRemediation(&models.Remediation{
+		Action:      step.action.Action,
+		EventID:     event.ID(),
+		Type:        keptnv2.GetTriggeredEventType(keptnv2.ApprovalTaskName),
+		ActionIndex: step.actionIndex,
+		Attempt:     step.attempt,
+		WaitTime:    step.action.WaitTime,
+		Escalated:   step.escalation,
+	})
+	if err != nil {
+		r.Keptn.Logger.Error("Could not create remediation: " + err.Error())
+		return err
+	}
+	err = r.Keptn.SendCloudEvent(event)
+	if err != nil {
+		r.Keptn.Logger.Error("Could not send approval.triggered event: " + err.Error())
+		return err
+	}
+	return nil
+}
+
+// getProposedAction returns the remediation entry of the action whose approval has been requested with the given approval.triggered event,
+// or nil if the event does not belong to the remediation or the approval has already been handled
+func getProposedAction(remediations []*models.Remediation, approvalTriggeredID string) *models.Remediation {
+	var proposedAction *models.Remediation
+	for _, remediation := range remediations {
+		if remediation.EventID != approvalTriggeredID || approvalTriggeredID == "" {
+			continue
+		}
+		if remediation.Type == keptnv2.GetFinishedEventType(keptnv2.ApprovalTaskName) {
+			return nil
+		}
+		if remediation.Type == keptnv2.GetTriggeredEventType(keptnv2.ApprovalTaskName) {
+			proposedAction = remediation
+		}
+	}
+	return proposedAction
+}
+
+// getRemediationTriggeredEvent retrieves the remediation.triggered event the remediation has been started with from the datastore
+func (r *RemediationHandler) getRemediationTriggeredEvent(remediations []*models.Remediation) (*keptnv2.RemediationTriggeredEventData, error) {
+	var remediationTriggered *models.Remediation
+	for _, remediation := range remediations {
+		if remediation.Type == keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName) {
+			remediationTriggered = remediation
+			break
+		}
+	}
+
+	if remediationTriggered == nil {
+		return nil, errors.New("no previously executed remediation actions have been found")
+	}
+
+	eventHandler := keptnapi.NewEventHandler(os.Getenv(datastoreConnection))
+
+	events, errorObj := eventHandler.GetEvents(&keptnapi.EventFilter{
+		EventID: remediationTriggered.EventID,
+		Project: r.Keptn.Event.GetProject(),
+	})
+
+	if errorObj != nil {
+		return nil, fmt.Errorf("could not retrieve remediation action with ID %s: %s", remediationTriggered.EventID, *errorObj.Message)
+	} else if len(events) == 0 {
+		return nil, errors.New("no remediation.triggered event found")
+	}
+	remediationTriggeredEvent := &keptnv2.RemediationTriggeredEventData{}
+
+	marshal, _ := json.Marshal(events[0].Data)
+	err := json.Unmarshal(marshal, remediationTriggeredEvent)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not decode remediation.triggered event: %s", err.Error())
+	}
+	return remediationTriggeredEvent, nil
+}
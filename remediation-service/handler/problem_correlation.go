@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	keptn "github.com/keptn/go-utils/pkg/lib"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/remediation-service/models"
+)
+
+const correlationWindowInMinutes = 15
+
+// getCorrelationWindow returns the time after the start of a remediation in which new problems of the same stage and service are
+// correlated with it. It can be set with PROBLEM_CORRELATION_WINDOW, e.g. 30m, where 0 disables the correlation by stage and service
+func getCorrelationWindow() time.Duration {
+	window, err := time.ParseDuration(os.Getenv("PROBLEM_CORRELATION_WINDOW"))
+	if err != nil {
+		window = correlationWindowInMinutes * time.Minute
+	}
+	return window
+}
+
+const maxRemediationAgeInHours = 24
+
+// getMaxRemediationAge returns the time after the start of a remediation after which it is expired, e.g. because the action.finished or
+// evaluation.finished event of an action has never been received. It can be set with MAX_REMEDIATION_AGE, e.g. 12h, where 0 disables the expiry
+func getMaxRemediationAge() time.Duration {
+	maxAge, err := time.ParseDuration(os.Getenv("MAX_REMEDIATION_AGE"))
+	if err != nil {
+		maxAge = maxRemediationAgeInHours * time.Hour
+	}
+	return maxAge
+}
+
+// getOpenRemediations returns the entries of all running remediations of the project, grouped by their keptn context.
+// Remediations that are older than the maximum age are finished and left out
+func (r *RemediationHandler) getOpenRemediations() (map[string][]*models.Remediation, error) {
+	remediations, err := r.RemediationRepo.GetOpenRemediations(r.Keptn.KeptnBase.Event.GetProject())
+	if err != nil {
+		return nil, err
+	}
+	openRemediations := map[string][]*models.Remediation{}
+	for _, remediation := range remediations {
+		openRemediations[remediation.KeptnContext] = append(openRemediations[remediation.KeptnContext], remediation)
+	}
+
+	maxAge := getMaxRemediationAge()
+	for _, keptnContext := range getExpiredRemediations(openRemediations, maxAge, time.Now()) {
+		if err := r.expireRemediation(keptnContext, openRemediations[keptnContext], maxAge); err != nil {
+			r.Keptn.Logger.Error("Could not expire remediation of keptnContext " + keptnContext + ": " + err.Error())
+			continue
+		}
+		delete(openRemediations, keptnContext)
+	}
+	return openRemediations, nil
+}
+
+// getExpiredRemediations returns the keptn contexts of the running remediations that have been started more than maxAge ago.
+// Remediations stored without start time are not expired
+func getExpiredRemediations(openRemediations map[string][]*models.Remediation, maxAge time.Duration, now time.Time) []string {
+	expired := []string{}
+	if maxAge <= 0 {
+		return expired
+	}
+	for keptnContext, remediations := range openRemediations {
+		for _, remediation := range remediations {
+			if remediation.Type != keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName) {
+				continue
+			}
+			if start, err := time.Parse(time.RFC3339, remediation.Time); err == nil && now.Sub(start) > maxAge {
+				expired = append(expired, keptnContext)
+			}
+			break
+		}
+	}
+	sort.Strings(expired)
+	return expired
+}
+
+// expireRemediation finishes a remediation that has not been finished within the maximum age, and removes its entries
+func (r *RemediationHandler) expireRemediation(keptnContext string, remediations []*models.Remediation, maxAge time.Duration) error {
+	source, _ := url.Parse("remediation-service")
+	project := r.Keptn.KeptnBase.Event.GetProject()
+	message := fmt.Sprintf("remediation has been expired because it has not been finished within %s", maxAge.String())
+	r.Keptn.Logger.Info(fmt.Sprintf("Remediation of keptnContext %s has been expired because it has not been finished within %s", keptnContext, maxAge.String()))
+
+	remediationFinishedEventData := &keptnv2.RemediationFinishedEventData{
+		EventData: keptnv2.EventData{
+			Project: project,
+			Status:  keptnv2.StatusErrored,
+			Result:  keptnv2.ResultFailed,
+			Message: message,
+		},
+	}
+	event := cloudevents.NewEvent()
+	event.SetType(keptnv2.GetFinishedEventType(keptnv2.RemediationTaskName))
+	event.SetSource(source.String())
+	event.SetDataContentType(cloudevents.ApplicationJSON)
+	event.SetExtension("shkeptncontext", keptnContext)
+	for _, remediation := range remediations {
+		if remediation.Type == keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName) {
+			remediationFinishedEventData.Stage = remediation.Stage
+			remediationFinishedEventData.Service = remediation.Service
+			event.SetExtension("triggeredid", remediation.EventID)
+			break
+		}
+	}
+	event.SetData(cloudevents.ApplicationJSON, remediationFinishedEventData)
+
+	// the entries are only removed after the remediation.finished event has been sent, otherwise the expiry is retried later
+	if err := r.Keptn.SendCloudEvent(event); err != nil {
+		return err
+	}
+	return r.RemediationRepo.DeleteRemediation(keptnContext, project)
+}
+
+// recordProblemEvent stores a problem event with the running remediation of the given keptn context, i.e. a correlated problem.open event
+// or a problem event that closes one of the problems of the remediation
+func (r *RemediationHandler) recordProblemEvent(keptnContext string, event cloudevents.Event, problem *keptn.ProblemEventData) error {
+	entry := newProblemEntry(problem)
+	entry.EventID = event.ID()
+	entry.KeptnContext = keptnContext
+	entry.Type = event.Type()
+	entry.Time = time.Now().UTC().Format(time.RFC3339)
+	return r.RemediationRepo.CreateRemediation(r.Keptn.KeptnBase.Event.GetProject(), entry)
+}
+
+// continueRemediation makes the handler send the events of the remediation with the given keptn context, e.g. when the event
+// that is handled has been correlated with a remediation of another keptn context
+func (r *RemediationHandler) continueRemediation(keptnContext string) {
+	r.Keptn.KeptnBase.KeptnContext = keptnContext
+}
+
+// newProblemEntry returns a remediation entry that identifies the given problem
+func newProblemEntry(problem *keptn.ProblemEventData) *models.Remediation {
+	return &models.Remediation{
+		ProblemID: problem.ProblemID,
+		PID:       problem.PID,
+		Stage:     problem.Stage,
+		Service:   problem.Service,
+	}
+}
+
+// isProblemEntry checks if the entry refers to a problem the remediation has been started for or that has been correlated with it
+func isProblemEntry(remediation *models.Remediation) bool {
+	return remediation.Type == keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName) || remediation.Type == keptn.ProblemOpenEventType
+}
+
+// isSameProblem checks if two entries refer to the same problem, i.e. have the same problem ID or PID
+func isSameProblem(a, b *models.Remediation) bool {
+	return (a.ProblemID != "" && a.ProblemID == b.ProblemID) || (a.PID != "" && a.PID == b.PID)
+}
+
+// getRemediationOfProblem returns the keptn context of the running remediation the problem belongs to, or an empty string if there is none
+func getRemediationOfProblem(openRemediations map[string][]*models.Remediation, problem *models.Remediation) string {
+	for keptnContext, remediations := range openRemediations {
+		for _, remediation := range remediations {
+			if isProblemEntry(remediation) && isSameProblem(remediation, problem) {
+				return keptnContext
+			}
+		}
+	}
+	return ""
+}
+
+// getCorrelatedRemediation returns the keptn context of the running remediation a new problem belongs to: the remediation of the same problem
+// or, if there is none, the latest remediation of the same stage and service that has been started within the correlation window
+func getCorrelatedRemediation(openRemediations map[string][]*models.Remediation, problem *models.Remediation, window time.Duration, now time.Time) string {
+	if keptnContext := getRemediationOfProblem(openRemediations, problem); keptnContext != "" {
+		return keptnContext
+	}
+	if window <= 0 {
+		return ""
+	}
+
+	correlatedContext := ""
+	var latestStart time.Time
+	for keptnContext, remediations := range openRemediations {
+		for _, remediation := range remediations {
+			if remediation.Type != keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName) ||
+				remediation.Stage != problem.Stage || remediation.Service != problem.Service {
+				continue
+			}
+			start, err := time.Parse(time.RFC3339, remediation.Time)
+			if err != nil || now.Sub(start) > window || (correlatedContext != "" && !start.After(latestStart)) {
+				continue
+			}
+			correlatedContext = keptnContext
+			latestStart = start
+		}
+	}
+	return correlatedContext
+}
+
+// getOpenProblems returns the entries of the problems of a remediation that have not been closed yet. Problems stored without
+// problem ID and PID are closed by any closed problem of the remediation
+func getOpenProblems(remediations []*models.Remediation) []*models.Remediation {
+	closedProblems := []*models.Remediation{}
+	for _, remediation := range remediations {
+		if remediation.Type == keptn.ProblemEventType {
+			closedProblems = append(closedProblems, remediation)
+		}
+	}
+
+	openProblems := []*models.Remediation{}
+	for _, remediation := range remediations {
+		if !isProblemEntry(remediation) {
+			continue
+		}
+		closed := remediation.ProblemID == "" && remediation.PID == "" && len(closedProblems) > 0
+		for _, closedProblem := range closedProblems {
+			if isSameProblem(remediation, closedProblem) {
+				closed = true
+				break
+			}
+		}
+		if !closed {
+			openProblems = append(openProblems, remediation)
+		}
+	}
+	return openProblems
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/remediation-service/models"
+)
+
+func TestGetCorrelatedRemediation(t *testing.T) {
+	now := time.Date(2021, 2, 1, 12, 0, 0, 0, time.UTC)
+	newRemediation := func(keptnContext, problemID, service string, startedBefore time.Duration) *models.Remediation {
+		return &models.Remediation{
+			KeptnContext: keptnContext,
+			Type:         keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName),
+			ProblemID:    problemID,
+			Stage:        "production",
+			Service:      service,
+			Time:         now.Add(-startedBefore).Format(time.RFC3339),
+		}
+	}
+	openRemediations := map[string][]*models.Remediation{
+		"context-1": {newRemediation("context-1", "problem-1", "carts", 30*time.Minute)},
+		"context-2": {newRemediation("context-2", "problem-2", "carts", 10*time.Minute)},
+		"context-3": {newRemediation("context-3", "problem-3", "carts", 5*time.Minute)},
+		"context-4": {newRemediation("context-4", "problem-4", "orders", 1*time.Minute)},
+	}
+
+	tests := []struct {
+		name            string
+		problem         *models.Remediation
+		window          time.Duration
+		expectedContext string
+	}{
+		{
+			name:            "same problem ID outside the correlation window",
+			problem:         &models.Remediation{ProblemID: "problem-1", Stage: "production", Service: "carts"},
+			window:          15 * time.Minute,
+			expectedContext: "context-1",
+		},
+		{
+			name:            "latest remediation of the same service within the correlation window",
+			problem:         &models.Remediation{ProblemID: "problem-5", Stage: "production", Service: "carts"},
+			window:          15 * time.Minute,
+			expectedContext: "context-3",
+		},
+		{
+			name:            "no remediation of the same service within the correlation window",
+			problem:         &models.Remediation{ProblemID: "problem-5", Stage: "production", Service: "carts"},
+			window:          2 * time.Minute,
+			expectedContext: "",
+		},
+		{
+			name:            "correlation by service disabled",
+			problem:         &models.Remediation{ProblemID: "problem-5", Stage: "production", Service: "orders"},
+			window:          0,
+			expectedContext: "",
+		},
+		{
+			name:            "no remediation of the same stage",
+			problem:         &models.Remediation{PID: "pid-5", Stage: "staging", Service: "orders"},
+			window:          15 * time.Minute,
+			expectedContext: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if keptnContext := getCorrelatedRemediation(openRemediations, tt.problem, tt.window, now); keptnContext != tt.expectedContext {
+				t.Errorf("getCorrelatedRemediation() = %s, expected %s", keptnContext, tt.expectedContext)
+			}
+		})
+	}
+}
+
+func TestGetExpiredRemediations(t *testing.T) {
+	now := time.Date(2021, 2, 1, 12, 0, 0, 0, time.UTC)
+	openRemediations := map[string][]*models.Remediation{
+		"context-1": {
+			{KeptnContext: "context-1", Type: keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName), Time: now.Add(-25 * time.Hour).Format(time.RFC3339)},
+			{KeptnContext: "context-1", Type: keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName), Time: now.Add(-time.Hour).Format(time.RFC3339)},
+		},
+		"context-2": {
+			{KeptnContext: "context-2", Type: keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName), Time: now.Add(-time.Hour).Format(time.RFC3339)},
+		},
+		// stored without start time
+		"context-3": {
+			{KeptnContext: "context-3", Type: keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName)},
+		},
+	}
+
+	if expired := getExpiredRemediations(openRemediations, 24*time.Hour, now); len(expired) != 1 || expired[0] != "context-1" {
+		t.Errorf("getExpiredRemediations() = %v, expected [context-1]", expired)
+	}
+	if expired := getExpiredRemediations(openRemediations, 30*time.Minute, now); len(expired) != 2 || expired[0] != "context-1" || expired[1] != "context-2" {
+		t.Errorf("getExpiredRemediations() = %v, expected [context-1 context-2]", expired)
+	}
+	if expired := getExpiredRemediations(openRemediations, 0, now); len(expired) != 0 {
+		t.Errorf("getExpiredRemediations() = %v, expected no expired remediations if the expiry is disabled", expired)
+	}
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"fmt"
+
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	keptn "github.com/keptn/go-utils/pkg/lib"
 	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
@@ -29,10 +31,43 @@ func (eh *ProblemEventHandler) HandleEvent() error {
 	eh.Logger.Debug("Received problem event with state " + problemEvent.State)
 
 	// this service should only react to events with STATE=CLOSED. Opened problems are handled by the ProblemOpenEventHandler
-	if problemEvent.State == "CLOSED" {
-		msg := "Problem " + problemEvent.PID + " of type " + problemEvent.ProblemTitle + " has been closed."
-		eh.Logger.Info(msg)
-		return eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusSucceeded, keptnv2.ResultPass, msg)
+	if problemEvent.State != "CLOSED" {
+		return nil
+	}
+
+	openRemediations, err := eh.Remediation.getOpenRemediations()
+	if err != nil {
+		eh.Logger.Error("could not retrieve open remediations: " + err.Error())
+		return err
+	}
+
+	// the problem may have been correlated with the remediation of another keptn context
+	keptnContext := getRemediationOfProblem(openRemediations, newProblemEntry(problemEvent))
+	if keptnContext == "" && len(openRemediations[eh.Remediation.Keptn.KeptnContext]) > 0 {
+		keptnContext = eh.Remediation.Keptn.KeptnContext
+	}
+	if keptnContext == "" {
+		eh.Logger.Info(fmt.Sprintf("No open remediation for problem %s of type %s", problemEvent.PID, problemEvent.ProblemTitle))
+		return nil
 	}
-	return nil
+
+	if err := eh.Remediation.recordProblemEvent(keptnContext, eh.Event, problemEvent); err != nil {
+		eh.Logger.Error("could not store closed problem: " + err.Error())
+		return err
+	}
+	eh.Remediation.continueRemediation(keptnContext)
+
+	remediations, err := eh.Remediation.getRemediationsByContext()
+	if err != nil {
+		eh.Logger.Error("could not retrieve open remediation: " + err.Error())
+		return err
+	}
+	if openProblems := getOpenProblems(remediations); len(openProblems) > 0 {
+		eh.Logger.Info(fmt.Sprintf("Problem %s of type %s has been closed. Remediation continues because %d correlated problems are still open.", problemEvent.PID, problemEvent.ProblemTitle, len(openProblems)))
+		return nil
+	}
+
+	msg := "Problem " + problemEvent.PID + " of type " + problemEvent.ProblemTitle + " has been closed."
+	eh.Logger.Info(msg)
+	return eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusSucceeded, keptnv2.ResultPass, msg)
 }
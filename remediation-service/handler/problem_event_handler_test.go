@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	keptnapi "github.com/keptn/go-utils/pkg/api/models"
+	keptn "github.com/keptn/go-utils/pkg/lib"
+	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/remediation-service/handler/fake"
+	"github.com/keptn/keptn/remediation-service/models"
+)
+
+const closedResponseTimeProblemEventPayload = `{
+    "State": "CLOSED",
+    "PID": "93a5-3fas-a09d-8ckf",
+    "ProblemID": "ab81-941c-f198",
+    "ProblemTitle": "Response time degradation",
+    "ImpactedEntity": "carts-primary",
+    "project": "sockshop",
+    "stage": "production",
+    "service": "service"
+  }`
+
+const openResponseTimeProblemEventPayload = `{
+    "State": "OPEN",
+    "PID": "93a5-3fas-a09d-8ckf",
+    "ProblemID": "ab81-941c-f198",
+    "ProblemTitle": "Response time degradation",
+    "ImpactedEntity": "carts-primary",
+    "project": "sockshop",
+    "stage": "production",
+    "service": "service"
+  }`
+
+func TestProblemEventHandler_HandleEvent(t *testing.T) {
+	remediationTriggered := &models.Remediation{
+		KeptnContext: "running-context",
+		Type:         keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName),
+		ProblemID:    "ab81-941c-f198",
+		PID:          "93a5-3fas-a09d-8ckf",
+		Stage:        "production",
+		Service:      "service",
+	}
+	correlatedProblem := &models.Remediation{
+		KeptnContext: "running-context",
+		Type:         keptn.ProblemOpenEventType,
+		ProblemID:    "762",
+		Stage:        "production",
+		Service:      "service",
+	}
+	closedProblem := &models.Remediation{
+		KeptnContext: "running-context",
+		Type:         keptn.ProblemEventType,
+		ProblemID:    "ab81-941c-f198",
+		PID:          "93a5-3fas-a09d-8ckf",
+		Stage:        "production",
+		Service:      "service",
+	}
+
+	tests := []struct {
+		name                        string
+		payload                     string
+		existingRemediations        []*models.Remediation
+		expectedRemediations        []*models.Remediation
+		expectedEventOnEventbroker  []*keptnapi.KeptnContextExtendedCE
+		expectedFinishedEventResult keptnv2.ResultType
+	}{
+		{
+			name:                 "problem of a running remediation closed, remediation finished",
+			payload:              closedResponseTimeProblemEventPayload,
+			existingRemediations: []*models.Remediation{remediationTriggered},
+			expectedRemediations: []*models.Remediation{},
+			expectedEventOnEventbroker: []*keptnapi.KeptnContextExtendedCE{
+				{
+					Contenttype:    "application/json",
+					Shkeptncontext: "running-context",
+					Type:           stringp(keptnv2.GetFinishedEventType(keptnv2.RemediationTaskName)),
+				},
+			},
+			expectedFinishedEventResult: keptnv2.ResultPass,
+		},
+		{
+			name:                       "problem closed while a correlated problem is still open, remediation continues",
+			payload:                    closedResponseTimeProblemEventPayload,
+			existingRemediations:       []*models.Remediation{remediationTriggered, correlatedProblem},
+			expectedRemediations:       []*models.Remediation{remediationTriggered, correlatedProblem, closedProblem},
+			expectedEventOnEventbroker: []*keptnapi.KeptnContextExtendedCE{},
+		},
+		{
+			name:                       "problem without running remediation closed",
+			payload:                    closedResponseTimeProblemEventPayload,
+			existingRemediations:       []*models.Remediation{correlatedProblem},
+			expectedRemediations:       []*models.Remediation{correlatedProblem},
+			expectedEventOnEventbroker: []*keptnapi.KeptnContextExtendedCE{},
+		},
+		{
+			name:                       "open problem is ignored",
+			payload:                    openResponseTimeProblemEventPayload,
+			existingRemediations:       []*models.Remediation{remediationTriggered},
+			expectedRemediations:       []*models.Remediation{remediationTriggered},
+			expectedEventOnEventbroker: []*keptnapi.KeptnContextExtendedCE{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockEV := NewMockEventbroker(tt.expectedEventOnEventbroker)
+			defer mockEV.Server.Close()
+
+			event := createTestCloudEvent(keptn.ProblemEventType, tt.payload)
+			testKeptnHandler, _ := keptnv2.NewKeptn(&event, keptncommon.KeptnOpts{
+				EventBrokerURL: mockEV.Server.URL,
+			})
+
+			fakeRemediationRepo := &fake.RemediationRepo{}
+			fakeRemediationRepo.Remediations = append([]*models.Remediation{}, tt.existingRemediations...)
+			remediation := &RemediationHandler{
+				Keptn:           testKeptnHandler,
+				RemediationRepo: fakeRemediationRepo,
+			}
+
+			eh := &ProblemEventHandler{
+				KeptnHandler: testKeptnHandler,
+				Logger:       testKeptnHandler.Logger,
+				Event:        event,
+				Remediation:  remediation,
+			}
+			if err := eh.HandleEvent(); err != nil {
+				t.Errorf("HandleEvent() error = %v", err)
+			}
+
+			if diff := deep.Equal(fakeRemediationRepo.Remediations, tt.expectedRemediations); len(diff) > 0 {
+				t.Errorf("Unexpected remediations after handling the event")
+				for _, d := range diff {
+					t.Log(d)
+				}
+			}
+
+			if len(tt.expectedEventOnEventbroker) == 0 {
+				if len(mockEV.ReceivedEvents) > 0 {
+					t.Errorf("Received %d unexpected events", len(mockEV.ReceivedEvents))
+				}
+				return
+			}
+			if !mockEV.ReceivedAllRequests {
+				t.Errorf("Did not receive all required events")
+				return
+			}
+			finishedEventData := &keptnv2.RemediationFinishedEventData{}
+			if err := keptnv2.Decode(mockEV.ReceivedEvents[0].Data, finishedEventData); err != nil {
+				t.Fatalf("could not decode remediation.finished event: %v", err)
+			}
+			if finishedEventData.Result != tt.expectedFinishedEventResult {
+				t.Errorf("remediation.finished event has result %s, expected %s", finishedEventData.Result, tt.expectedFinishedEventResult)
+			}
+		})
+	}
+}
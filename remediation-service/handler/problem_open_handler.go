@@ -3,6 +3,7 @@ package handler
 import (
 	"fmt"
 	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	keptn "github.com/keptn/go-utils/pkg/lib"
@@ -28,6 +29,14 @@ func (eh *ProblemOpenEventHandler) HandleEvent() error {
 
 	eh.KeptnHandler.Logger.Debug("Received problem event with state " + problemEvent.State)
 
+	// problems that have been sent again or that share the root cause of a problem with a running remediation are attached to it
+	correlated, err := eh.correlateProblem(problemEvent)
+	if err != nil {
+		eh.KeptnHandler.Logger.Error("Could not correlate problem with running remediations: " + err.Error())
+	} else if correlated {
+		return nil
+	}
+
 	// check if remediation should be performed
 	autoRemediate, err := eh.isRemediationEnabled()
 	if err != nil {
@@ -69,32 +78,29 @@ func (eh *ProblemOpenEventHandler) HandleEvent() error {
 		return err
 	}
 
-	problemType := problemEvent.ProblemTitle
-
-	actionIndex := 0
-	action := eh.Remediation.getActionForProblemType(*remediationData, problemType, actionIndex)
-	if action == nil {
-		action = eh.Remediation.getActionForProblemType(*remediationData, "default", actionIndex)
+	problem := keptnv2.ProblemDetails{
+		State:          problemEvent.State,
+		ProblemID:      problemEvent.ProblemID,
+		ProblemTitle:   problemEvent.ProblemTitle,
+		ProblemDetails: problemEvent.ProblemDetails,
+		PID:            problemEvent.PID,
+		ProblemURL:     problemEvent.ProblemURL,
+		ImpactedEntity: problemEvent.ImpactedEntity,
+		Tags:           problemEvent.Tags,
 	}
 
-	if action != nil {
-		err = eh.Remediation.triggerAction(action, actionIndex, keptnv2.ProblemDetails{
-			State:          problemEvent.State,
-			ProblemID:      problemEvent.ProblemID,
-			ProblemTitle:   problemEvent.ProblemTitle,
-			ProblemDetails: problemEvent.ProblemDetails,
-			PID:            problemEvent.PID,
-			ProblemURL:     problemEvent.ProblemURL,
-			ImpactedEntity: problemEvent.ImpactedEntity,
-			Tags:           problemEvent.Tags,
-		})
+	remediation := getRemediationForProblem(remediationData, problem)
+
+	if remediation != nil {
+		eh.KeptnHandler.Logger.Info("Found remediation for " + remediation.getDescription())
+		err = eh.Remediation.executeStep(remediationData, remediation.getFirstStep(), problem)
 		if err != nil {
 			eh.KeptnHandler.Logger.Error(err.Error())
 			_ = eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusErrored, keptnv2.ResultFailed, err.Error())
 			return err
 		}
 	} else {
-		msg := "No remediation configured for problem type " + problemType
+		msg := "No remediation configured for problem type " + problemEvent.ProblemTitle
 		eh.KeptnHandler.Logger.Info(msg)
 		return eh.Remediation.sendRemediationFinishedEvent(keptnv2.StatusSucceeded, keptnv2.ResultPass, "triggered all actions")
 	}
@@ -102,6 +108,24 @@ func (eh *ProblemOpenEventHandler) HandleEvent() error {
 	return nil
 }
 
+// correlateProblem attaches the problem to the running remediation of the same problem or of the same stage and service within the
+// correlation window. It returns false if the problem has not been correlated with a running remediation
+func (eh *ProblemOpenEventHandler) correlateProblem(problemEvent *keptn.ProblemEventData) (bool, error) {
+	openRemediations, err := eh.Remediation.getOpenRemediations()
+	if err != nil {
+		return false, err
+	}
+	keptnContext := getCorrelatedRemediation(openRemediations, newProblemEntry(problemEvent), getCorrelationWindow(), time.Now())
+	if keptnContext == "" {
+		return false, nil
+	}
+	if err := eh.Remediation.recordProblemEvent(keptnContext, eh.Event, problemEvent); err != nil {
+		return false, err
+	}
+	eh.KeptnHandler.Logger.Info(fmt.Sprintf("Problem %s of type %s has been correlated with the running remediation of keptnContext %s", problemEvent.PID, problemEvent.ProblemTitle, keptnContext))
+	return true, nil
+}
+
 func (eh *ProblemOpenEventHandler) isRemediationEnabled() (bool, error) {
 	remediationFile, err := eh.Remediation.getRemediationFile()
 	if err != nil {
@@ -17,6 +17,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 )
 
 const shipyardContent = `stages:
@@ -247,6 +248,8 @@ func TestProblemOpenEventHandler_HandleEvent(t *testing.T) {
 		fields                          fields
 		wantErr                         bool
 		returnedRemediationYamlResource string
+		existingRemediations            []*models.Remediation
+		existingExecutedActions         []*models.Remediation
 		expectedCreatedRemediations     []*models.Remediation
 		expectedEventOnEventbroker      []*keptnapi.KeptnContextExtendedCE
 	}{
@@ -262,11 +265,16 @@ func TestProblemOpenEventHandler_HandleEvent(t *testing.T) {
 					Action:       "",
 					KeptnContext: testKeptnContext,
 					Type:         keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName),
+					ProblemID:    "ab81-941c-f198",
+					PID:          "93a5-3fas-a09d-8ckf",
+					Stage:        "production",
+					Service:      "service",
 				},
 				{
 					Action:       "togglefeature",
 					KeptnContext: testKeptnContext,
 					Type:         keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName),
+					Attempt:      1,
 				},
 			},
 			expectedEventOnEventbroker: []*keptnapi.KeptnContextExtendedCE{
@@ -360,6 +368,213 @@ func TestProblemOpenEventHandler_HandleEvent(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "problem sent again, problem is attached to the running remediation",
+			fields: fields{
+				Event: createTestCloudEvent(keptn.ProblemOpenEventType, responseTimeProblemEventPayload),
+			},
+			wantErr:                         false,
+			returnedRemediationYamlResource: remediationYamlResourceWithValidRemediation,
+			existingRemediations: []*models.Remediation{
+				{
+					KeptnContext: "running-context",
+					Type:         keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName),
+					ProblemID:    "ab81-941c-f198",
+					Stage:        "production",
+					Service:      "service",
+				},
+			},
+			expectedCreatedRemediations: []*models.Remediation{
+				{
+					KeptnContext: "running-context",
+					Type:         keptn.ProblemOpenEventType,
+					ProblemID:    "ab81-941c-f198",
+					PID:          "93a5-3fas-a09d-8ckf",
+					Stage:        "production",
+					Service:      "service",
+				},
+			},
+			expectedEventOnEventbroker: []*keptnapi.KeptnContextExtendedCE{},
+		},
+		{
+			name: "problem of an expired remediation sent again, expired remediation finished and new remediation started",
+			fields: fields{
+				Event: createTestCloudEvent(keptn.ProblemOpenEventType, responseTimeProblemEventPayload),
+			},
+			wantErr:                         false,
+			returnedRemediationYamlResource: remediationYamlResourceWithValidRemediation,
+			existingRemediations: []*models.Remediation{
+				{
+					KeptnContext: "expired-context",
+					Type:         keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName),
+					ProblemID:    "ab81-941c-f198",
+					Stage:        "production",
+					Service:      "service",
+					Time:         time.Now().Add(-25 * time.Hour).UTC().Format(time.RFC3339),
+				},
+			},
+			expectedCreatedRemediations: []*models.Remediation{
+				{
+					KeptnContext: testKeptnContext,
+					Type:         keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName),
+					ProblemID:    "ab81-941c-f198",
+					PID:          "93a5-3fas-a09d-8ckf",
+					Stage:        "production",
+					Service:      "service",
+				},
+				{
+					Action:       "togglefeature",
+					KeptnContext: testKeptnContext,
+					Type:         keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName),
+					Attempt:      1,
+				},
+			},
+			expectedEventOnEventbroker: []*keptnapi.KeptnContextExtendedCE{
+				{
+					Contenttype:    "application/json",
+					Shkeptncontext: "expired-context",
+					Type:           stringp(keptnv2.GetFinishedEventType(keptnv2.RemediationTaskName)),
+				},
+				{
+					Contenttype:    "application/json",
+					Shkeptncontext: testKeptnContext,
+					Type:           stringp(keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName)),
+				},
+				{
+					Contenttype:    "application/json",
+					Shkeptncontext: testKeptnContext,
+					Type:           stringp(keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName)),
+				},
+				{
+					Contenttype:    "application/json",
+					Shkeptncontext: testKeptnContext,
+					Type:           stringp(keptnv2.GetTriggeredEventType(keptnv2.ActionTaskName)),
+				},
+			},
+		},
+		{
+			name: "problem of the same service within the correlation window, problem is attached to the running remediation",
+			fields: fields{
+				Event: createTestCloudEvent(keptn.ProblemOpenEventType, unknownProblemEventPayload),
+			},
+			wantErr:                         false,
+			returnedRemediationYamlResource: remediationYamlResourceWithValidRemediation,
+			existingRemediations: []*models.Remediation{
+				{
+					KeptnContext: "running-context",
+					Type:         keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName),
+					ProblemID:    "ab81-941c-f198",
+					Stage:        "production",
+					Service:      "service",
+					Time:         time.Now().Add(-5 * time.Minute).UTC().Format(time.RFC3339),
+				},
+			},
+			expectedCreatedRemediations: []*models.Remediation{
+				{
+					KeptnContext: "running-context",
+					Type:         keptn.ProblemOpenEventType,
+					ProblemID:    "762",
+					Stage:        "production",
+					Service:      "service",
+				},
+			},
+			expectedEventOnEventbroker: []*keptnapi.KeptnContextExtendedCE{},
+		},
+		{
+			name: "action suppressed by the cooldown after an action of another remediation",
+			fields: fields{
+				Event: createTestCloudEvent(keptn.ProblemOpenEventType, responseTimeProblemEventPayload),
+			},
+			wantErr: false,
+			returnedRemediationYamlResource: getRemediationYamlResource(`apiVersion: spec.keptn.sh/0.1.4
+kind: Remediation
+spec:
+  limits:
+    cooldown: 30m
+  remediations:
+  - problemType: "Response time degradation"
+    actionsOnOpen:
+    - action: scaling
+      value: 1`),
+			existingExecutedActions: []*models.Remediation{
+				{
+					Action:       "scaling",
+					KeptnContext: "previous-context",
+					Type:         keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName),
+					Stage:        "production",
+					Service:      "service",
+					Time:         time.Now().Add(-10 * time.Minute).UTC().Format(time.RFC3339),
+				},
+			},
+			expectedCreatedRemediations: []*models.Remediation{},
+			expectedEventOnEventbroker: []*keptnapi.KeptnContextExtendedCE{
+				{
+					Contenttype:    "application/json",
+					Shkeptncontext: testKeptnContext,
+					Type:           stringp(keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName)),
+				},
+				{
+					Contenttype:    "application/json",
+					Shkeptncontext: testKeptnContext,
+					Type:           stringp(keptnv2.GetFinishedEventType(keptnv2.RemediationTaskName)),
+				},
+			},
+		},
+		{
+			name: "remediation in recommend mode, action proposed",
+			fields: fields{
+				Event: createTestCloudEvent(keptn.ProblemOpenEventType, responseTimeProblemEventPayload),
+			},
+			wantErr: false,
+			returnedRemediationYamlResource: getRemediationYamlResource(`apiVersion: spec.keptn.sh/0.1.4
+kind: Remediation
+spec:
+  mode: recommend
+  remediations:
+  - problemType: "Response time degradation"
+    actionsOnOpen:
+    - action: scaling
+      value: 1`),
+			expectedCreatedRemediations: []*models.Remediation{
+				{
+					KeptnContext: testKeptnContext,
+					Type:         keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName),
+					ProblemID:    "ab81-941c-f198",
+					PID:          "93a5-3fas-a09d-8ckf",
+					Stage:        "production",
+					Service:      "service",
+				},
+				{
+					Action:       "scaling",
+					KeptnContext: testKeptnContext,
+					Type:         keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName),
+					Attempt:      1,
+				},
+				{
+					Action:       "scaling",
+					KeptnContext: testKeptnContext,
+					Type:         keptnv2.GetTriggeredEventType(keptnv2.ApprovalTaskName),
+					Attempt:      1,
+				},
+			},
+			expectedEventOnEventbroker: []*keptnapi.KeptnContextExtendedCE{
+				{
+					Contenttype:    "application/json",
+					Shkeptncontext: testKeptnContext,
+					Type:           stringp(keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName)),
+				},
+				{
+					Contenttype:    "application/json",
+					Shkeptncontext: testKeptnContext,
+					Type:           stringp(keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName)),
+				},
+				{
+					Contenttype:    "application/json",
+					Shkeptncontext: testKeptnContext,
+					Type:           stringp(keptnv2.GetTriggeredEventType(keptnv2.ApprovalTaskName)),
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -376,6 +591,8 @@ func TestProblemOpenEventHandler_HandleEvent(t *testing.T) {
 			})
 
 			fakeRemediationRepo := &fake.RemediationRepo{}
+			fakeRemediationRepo.Remediations = tt.existingRemediations
+			fakeRemediationRepo.ExecutedActions = tt.existingExecutedActions
 			remediation := &RemediationHandler{
 				Keptn:           testKeptnHandler,
 				RemediationRepo: fakeRemediationRepo,
@@ -397,7 +614,11 @@ func TestProblemOpenEventHandler_HandleEvent(t *testing.T) {
 				}
 			}
 
-			if mockEV.ReceivedAllRequests {
+			if len(tt.expectedEventOnEventbroker) == 0 {
+				if len(mockEV.ReceivedEvents) > 0 {
+					t.Errorf("Received %d unexpected events", len(mockEV.ReceivedEvents))
+				}
+			} else if mockEV.ReceivedAllRequests {
 				t.Log("Received all required events")
 			} else {
 				t.Errorf("Did not receive all required events")
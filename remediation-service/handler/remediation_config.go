@@ -0,0 +1,306 @@
+package handler
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/keptn/go-utils/pkg/lib/v0_1_4"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+)
+
+const defaultMaxAttempts = 1
+
+// remediationModeAutomatic triggers the actions of a remediation without approval. It is the default mode
+const remediationModeAutomatic = "automatic"
+
+// remediationModeRecommend only proposes the actions of a remediation and triggers each of them after it has been approved
+const remediationModeRecommend = "recommend"
+
+// defaultProblemType is the problem type of the remediation that is used if no other remediation matches the problem
+const defaultProblemType = "default"
+
+// remediationConfig is the content of a remediation.yaml. Besides the remediation spec 0.1.4, it contains the escalation chain of each remediation
+type remediationConfig struct {
+	ApiVersion string                     `json:"apiVersion"`
+	Kind       string                     `json:"kind"`
+	Metadata   v0_1_4.RemediationMetadata `json:"metadata"`
+	Spec       remediationConfigSpec      `json:"spec"`
+}
+
+type remediationConfigSpec struct {
+	// Mode decides whether actions are triggered automatically or only proposed and triggered after an approval
+	Mode string `json:"mode,omitempty"`
+	// Limits restrict how often actions are triggered for the service the remediation.yaml belongs to
+	Limits       *remediationLimits `json:"limits,omitempty"`
+	Remediations []remediationMap   `json:"remediations"`
+}
+
+// remediationLimits prevent remediation loops, e.g. scaling up a service again and again because each new problem triggers the same action
+type remediationLimits struct {
+	// Cooldown is the time after an action of a remediation in which other remediations do not trigger actions for the service, e.g. 30m
+	Cooldown string `json:"cooldown,omitempty"`
+	// MaxActionsPerHour is the maximum number of actions triggered for the service within an hour. Escalation actions are not limited
+	MaxActionsPerHour int `json:"maxActionsPerHour,omitempty"`
+}
+
+// remediationMap maps a problem type to the actions that are executed one after another until the problem is remediated
+type remediationMap struct {
+	// ProblemType matches problem titles starting with it. If it contains the wildcards * or ?, it has to match the whole title
+	ProblemType string `json:"problemType"`
+	// ProblemTitleRegex is a regular expression the problem title has to match
+	ProblemTitleRegex string `json:"problemTitleRegex,omitempty"`
+	// Tags are the tags the problem has to have, e.g. service:carts
+	Tags []string `json:"tags,omitempty"`
+	// ImpactedEntity is the entity impacted by the problem. It may contain the wildcards * and ?
+	ImpactedEntity string `json:"impactedEntity,omitempty"`
+	// Priority decides which remediation is used if several match the problem. The remediation with the highest priority wins,
+	// remediations with equal priority are taken in the order of the file
+	Priority      int                 `json:"priority,omitempty"`
+	ActionsOnOpen []remediationAction `json:"actionsOnOpen"`
+	// Escalation is the action that is triggered after all actions failed to remediate the problem, e.g. to page a human
+	Escalation *remediationAction `json:"escalation,omitempty"`
+}
+
+// remediationAction is a step of the escalation chain of a remediation
+type remediationAction struct {
+	v0_1_4.RemediationActionsOnOpen
+	// WaitTime is the time to wait after the action has finished before its effect is evaluated, e.g. 5m. It overrides WAIT_TIME_MINUTES
+	WaitTime string `json:"waitTime,omitempty"`
+	// MaxAttempts is the number of times the action is executed before the next action is triggered. It defaults to 1
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+	// SuccessCriteria define which evaluation results are considered a successful remediation
+	SuccessCriteria *remediationSuccessCriteria `json:"successCriteria,omitempty"`
+}
+
+// remediationSuccessCriteria define when the evaluation after an action is considered a successful remediation
+type remediationSuccessCriteria struct {
+	// Result is the worst evaluation result that is considered successful, i.e. pass or warning (default)
+	Result string `json:"result,omitempty"`
+	// Score is the minimum score of the evaluation
+	Score *float64 `json:"score,omitempty"`
+}
+
+// remediationStep identifies an action of the escalation chain and the attempt to execute it
+type remediationStep struct {
+	action      *remediationAction
+	actionIndex int
+	attempt     int
+	escalation  bool
+}
+
+func (a *remediationAction) getMaxAttempts() int {
+	if a.MaxAttempts < 1 {
+		return defaultMaxAttempts
+	}
+	return a.MaxAttempts
+}
+
+func (a *remediationAction) validate() error {
+	if a.WaitTime != "" {
+		if waitTime, err := time.ParseDuration(a.WaitTime); err != nil || waitTime < 0 {
+			return fmt.Errorf("invalid waitTime '%s' of action %s: must be a duration, e.g. 5m", a.WaitTime, a.Action)
+		}
+	}
+	if a.MaxAttempts < 0 {
+		return fmt.Errorf("invalid maxAttempts %d of action %s: must not be negative", a.MaxAttempts, a.Action)
+	}
+	if a.SuccessCriteria != nil && a.SuccessCriteria.Result != "" && a.SuccessCriteria.Result != string(keptnv2.ResultPass) && a.SuccessCriteria.Result != string(keptnv2.ResultWarning) {
+		return fmt.Errorf("invalid successCriteria.result '%s' of action %s: must be pass or warning", a.SuccessCriteria.Result, a.Action)
+	}
+	return nil
+}
+
+// getCooldown returns the cooldown of the limits, or 0 if no cooldown is configured
+func (l *remediationLimits) getCooldown() time.Duration {
+	if l == nil || l.Cooldown == "" {
+		return 0
+	}
+	cooldown, err := time.ParseDuration(l.Cooldown)
+	if err != nil {
+		return 0
+	}
+	return cooldown
+}
+
+func (l *remediationLimits) validate() error {
+	if l.Cooldown != "" {
+		if cooldown, err := time.ParseDuration(l.Cooldown); err != nil || cooldown < 0 {
+			return fmt.Errorf("invalid limits.cooldown '%s': must be a duration, e.g. 30m", l.Cooldown)
+		}
+	}
+	if l.MaxActionsPerHour < 0 {
+		return fmt.Errorf("invalid limits.maxActionsPerHour %d: must not be negative", l.MaxActionsPerHour)
+	}
+	return nil
+}
+
+func (c *remediationConfig) validate() error {
+	if c.Spec.Mode != "" && c.Spec.Mode != remediationModeAutomatic && c.Spec.Mode != remediationModeRecommend {
+		return fmt.Errorf("invalid mode '%s': must be %s or %s", c.Spec.Mode, remediationModeAutomatic, remediationModeRecommend)
+	}
+	if c.Spec.Limits != nil {
+		if err := c.Spec.Limits.validate(); err != nil {
+			return err
+		}
+	}
+	for _, remediation := range c.Spec.Remediations {
+		if remediation.ProblemTitleRegex != "" {
+			if _, err := regexp.Compile(remediation.ProblemTitleRegex); err != nil {
+				return fmt.Errorf("invalid problemTitleRegex '%s': %s", remediation.ProblemTitleRegex, err.Error())
+			}
+		}
+		for index := range remediation.ActionsOnOpen {
+			if err := remediation.ActionsOnOpen[index].validate(); err != nil {
+				return err
+			}
+		}
+		if remediation.Escalation != nil {
+			if err := remediation.Escalation.validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isMet checks if the result of the evaluation after an action meets the success criteria. Without success criteria, pass and warning are successful
+func (c *remediationSuccessCriteria) isMet(evaluation *keptnv2.EvaluationFinishedEventData) bool {
+	result := keptnv2.ResultWarning
+	if c != nil && c.Result != "" {
+		result = keptnv2.ResultType(c.Result)
+	}
+	if evaluation.Result != keptnv2.ResultPass && (result == keptnv2.ResultPass || evaluation.Result != keptnv2.ResultWarning) {
+		return false
+	}
+	if c != nil && c.Score != nil && evaluation.Evaluation.Score < *c.Score {
+		return false
+	}
+	return true
+}
+
+// getRemediationForProblem returns the remediation with actions and the highest priority that matches the given problem.
+// If no remediation matches, the remediation of the problem type default is returned
+func getRemediationForProblem(config *remediationConfig, problem keptnv2.ProblemDetails) *remediationMap {
+	matches := []*remediationMap{}
+	var defaultRemediation *remediationMap
+	for index := range config.Spec.Remediations {
+		remediation := &config.Spec.Remediations[index]
+		if len(remediation.ActionsOnOpen) == 0 {
+			continue
+		}
+		if remediation.isDefault() {
+			if defaultRemediation == nil {
+				defaultRemediation = remediation
+			}
+			continue
+		}
+		if remediation.matches(problem) {
+			matches = append(matches, remediation)
+		}
+	}
+	if len(matches) == 0 {
+		return defaultRemediation
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Priority > matches[j].Priority
+	})
+	return matches[0]
+}
+
+// isDefault checks if the remediation is the fallback for problems no other remediation matches
+func (m *remediationMap) isDefault() bool {
+	return m.ProblemType == defaultProblemType && m.ProblemTitleRegex == "" && len(m.Tags) == 0 && m.ImpactedEntity == ""
+}
+
+// matches checks if the problem title, tags and impacted entity of the problem match all criteria of the remediation
+func (m *remediationMap) matches(problem keptnv2.ProblemDetails) bool {
+	if strings.ContainsAny(m.ProblemType, "*?") {
+		if !matchesWildcard(m.ProblemType, problem.ProblemTitle) {
+			return false
+		}
+	} else if !strings.HasPrefix(problem.ProblemTitle, m.ProblemType) {
+		return false
+	}
+	if m.ProblemTitleRegex != "" {
+		if matched, err := regexp.MatchString(m.ProblemTitleRegex, problem.ProblemTitle); err != nil || !matched {
+			return false
+		}
+	}
+	if m.ImpactedEntity != "" && !matchesWildcard(m.ImpactedEntity, problem.ImpactedEntity) {
+		return false
+	}
+	problemTags := map[string]bool{}
+	for _, tag := range strings.Split(problem.Tags, ",") {
+		problemTags[strings.TrimSpace(tag)] = true
+	}
+	for _, tag := range m.Tags {
+		if !problemTags[strings.TrimSpace(tag)] {
+			return false
+		}
+	}
+	return true
+}
+
+// getDescription returns the criteria of the remediation for log messages
+func (m *remediationMap) getDescription() string {
+	if m.ProblemType != "" {
+		return "problem type " + m.ProblemType
+	}
+	if m.ProblemTitleRegex != "" {
+		return "problem title regex " + m.ProblemTitleRegex
+	}
+	if m.ImpactedEntity != "" {
+		return "impacted entity " + m.ImpactedEntity
+	}
+	return "tags " + strings.Join(m.Tags, ",")
+}
+
+// matchesWildcard checks if the whole value matches the pattern, where * matches any sequence of characters and ? a single character
+func matchesWildcard(pattern, value string) bool {
+	expression := regexp.QuoteMeta(pattern)
+	expression = strings.ReplaceAll(expression, `\*`, ".*")
+	expression = strings.ReplaceAll(expression, `\?`, ".")
+	matched, err := regexp.MatchString("^"+expression+"$", value)
+	return err == nil && matched
+}
+
+// getFirstStep returns the first action of the escalation chain
+func (m *remediationMap) getFirstStep() *remediationStep {
+	return &remediationStep{action: &m.ActionsOnOpen[0], actionIndex: 0, attempt: 1}
+}
+
+// getAction returns the action that has been executed in the given step of the escalation chain
+func (m *remediationMap) getAction(actionIndex int, escalated bool) *remediationAction {
+	if escalated {
+		return m.Escalation
+	}
+	if actionIndex < 0 || actionIndex >= len(m.ActionsOnOpen) {
+		return nil
+	}
+	return &m.ActionsOnOpen[actionIndex]
+}
+
+// getNextStep returns the step of the escalation chain after the given one: the same action is repeated until its maximum number of attempts
+// is reached, then the next action is triggered. After the last action, the escalation action is triggered, if one is configured
+func (m *remediationMap) getNextStep(actionIndex, attempt int, escalated bool) *remediationStep {
+	if escalated {
+		return nil
+	}
+	if attempt < 1 {
+		// remediations that have been started before attempts were tracked
+		attempt = 1
+	}
+	if action := m.getAction(actionIndex, false); action != nil && attempt < action.getMaxAttempts() {
+		return &remediationStep{action: action, actionIndex: actionIndex, attempt: attempt + 1}
+	}
+	if actionIndex+1 < len(m.ActionsOnOpen) {
+		return &remediationStep{action: &m.ActionsOnOpen[actionIndex+1], actionIndex: actionIndex + 1, attempt: 1}
+	}
+	if m.Escalation != nil {
+		return &remediationStep{action: m.Escalation, actionIndex: len(m.ActionsOnOpen), attempt: 1, escalation: true}
+	}
+	return nil
+}
@@ -0,0 +1,416 @@
+package handler
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	configmodels "github.com/keptn/go-utils/pkg/api/models"
+	keptn "github.com/keptn/go-utils/pkg/lib"
+	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/remediation-service/handler/fake"
+	"github.com/keptn/keptn/remediation-service/models"
+)
+
+const remediationYamlWithEscalationChain = `apiVersion: spec.keptn.sh/0.1.4
+kind: Remediation
+metadata:
+  name: remediation-configuration
+spec:
+  remediations:
+  - problemType: "Response time degradation"
+    actionsOnOpen:
+    - name: Scale up
+      action: scaling
+      value: 1
+      waitTime: 2m
+      maxAttempts: 2
+      successCriteria:
+        result: pass
+        score: 90
+    - name: Toggle feature flag
+      action: togglefeature
+      value:
+        EnablePromotion: off
+    escalation:
+      name: Page on-call engineer
+      action: page
+      value:
+        team: carts-oncall
+  - problemType: default
+    actionsOnOpen:
+    - action: escalate`
+
+// getRemediationYamlResource returns the response of the configuration-service for a remediation.yaml with the given content
+func getRemediationYamlResource(content string) string {
+	return `{
+      "resourceContent": "` + base64.StdEncoding.EncodeToString([]byte(content)) + `",
+      "resourceURI": "remediation.yaml"
+    }`
+}
+
+func TestRemediationHandler_getRemediation(t *testing.T) {
+	r := &RemediationHandler{}
+	config, err := r.getRemediation(&configmodels.Resource{ResourceContent: remediationYamlWithEscalationChain})
+	if err != nil {
+		t.Fatalf("getRemediation() error = %v", err)
+	}
+
+	remediation := getRemediationForProblem(config, keptnv2.ProblemDetails{ProblemTitle: "Response time degradation"})
+	if remediation == nil || len(remediation.ActionsOnOpen) != 2 {
+		t.Fatalf("getRemediationForProblem() did not return the actions of the remediation")
+	}
+	score := 90.0
+	expectedAction := remediationAction{
+		WaitTime:        "2m",
+		MaxAttempts:     2,
+		SuccessCriteria: &remediationSuccessCriteria{Result: "pass", Score: &score},
+	}
+	expectedAction.Name = "Scale up"
+	expectedAction.Action = "scaling"
+	expectedAction.Value = 1.0
+	if diff := deep.Equal(remediation.ActionsOnOpen[0], expectedAction); len(diff) > 0 {
+		t.Errorf("getRemediation() did not parse the escalation settings: %v", diff)
+	}
+	if remediation.Escalation == nil || remediation.Escalation.Action != "page" {
+		t.Errorf("getRemediation() did not parse the escalation action")
+	}
+
+	if remediation := getRemediationForProblem(config, keptnv2.ProblemDetails{ProblemTitle: "default"}); remediation == nil || remediation.ProblemType != "default" {
+		t.Errorf("getRemediationForProblem() did not return the default remediation")
+	}
+	if remediation := getRemediationForProblem(config, keptnv2.ProblemDetails{ProblemTitle: "Failure rate increase"}); remediation == nil || remediation.ProblemType != "default" {
+		t.Errorf("getRemediationForProblem() did not return the default remediation for an unknown problem type")
+	}
+
+	tests := []struct {
+		name          string
+		content       string
+		expectedError string
+	}{
+		{
+			name: "invalid wait time",
+			content: `apiVersion: spec.keptn.sh/0.1.4
+spec:
+  remediations:
+  - problemType: default
+    actionsOnOpen:
+    - action: scaling
+      waitTime: two minutes`,
+			expectedError: "invalid remediation.yaml: invalid waitTime 'two minutes' of action scaling: must be a duration, e.g. 5m",
+		},
+		{
+			name: "invalid mode",
+			content: `apiVersion: spec.keptn.sh/0.1.4
+spec:
+  mode: manual
+  remediations:
+  - problemType: default
+    actionsOnOpen:
+    - action: scaling`,
+			expectedError: "invalid remediation.yaml: invalid mode 'manual': must be automatic or recommend",
+		},
+		{
+			name: "invalid cooldown",
+			content: `apiVersion: spec.keptn.sh/0.1.4
+spec:
+  limits:
+    cooldown: 1 hour
+  remediations:
+  - problemType: default
+    actionsOnOpen:
+    - action: scaling`,
+			expectedError: "invalid remediation.yaml: invalid limits.cooldown '1 hour': must be a duration, e.g. 30m",
+		},
+		{
+			name: "invalid problem title regex",
+			content: `apiVersion: spec.keptn.sh/0.1.4
+spec:
+  remediations:
+  - problemTitleRegex: "Response time (degradation"
+    actionsOnOpen:
+    - action: scaling`,
+			expectedError: "invalid remediation.yaml: invalid problemTitleRegex 'Response time (degradation': error parsing regexp: missing closing ): `Response time (degradation`",
+		},
+		{
+			name: "invalid success criteria of escalation action",
+			content: `apiVersion: spec.keptn.sh/0.1.4
+spec:
+  remediations:
+  - problemType: default
+    actionsOnOpen:
+    - action: scaling
+    escalation:
+      action: page
+      successCriteria:
+        result: fail`,
+			expectedError: "invalid remediation.yaml: invalid successCriteria.result 'fail' of action page: must be pass or warning",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := r.getRemediation(&configmodels.Resource{ResourceContent: tt.content})
+			if err == nil || err.Error() != tt.expectedError {
+				t.Errorf("getRemediation() error = %v, expected %s", err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestRemediationMap_getNextStep(t *testing.T) {
+	config, err := (&RemediationHandler{}).getRemediation(&configmodels.Resource{ResourceContent: remediationYamlWithEscalationChain})
+	if err != nil {
+		t.Fatalf("getRemediation() error = %v", err)
+	}
+	remediation := getRemediationForProblem(config, keptnv2.ProblemDetails{ProblemTitle: "Response time degradation"})
+
+	steps := []string{}
+	for step := remediation.getFirstStep(); step != nil; step = remediation.getNextStep(step.actionIndex, step.attempt, step.escalation) {
+		steps = append(steps, step.action.Action)
+		if remediation.getAction(step.actionIndex, step.escalation) != step.action {
+			t.Errorf("getAction() did not return the action of step %d", len(steps))
+		}
+	}
+	if diff := deep.Equal(steps, []string{"scaling", "scaling", "togglefeature", "page"}); len(diff) > 0 {
+		t.Errorf("unexpected escalation chain: %v", diff)
+	}
+
+	// remediations without escalation action end after the last action
+	if step := getRemediationForProblem(config, keptnv2.ProblemDetails{ProblemTitle: "default"}).getNextStep(0, 1, false); step != nil {
+		t.Errorf("getNextStep() returned action %s after the last action", step.action.Action)
+	}
+}
+
+func TestRemediationSuccessCriteria_isMet(t *testing.T) {
+	score := 90.0
+	tests := []struct {
+		name            string
+		successCriteria *remediationSuccessCriteria
+		result          keptnv2.ResultType
+		score           float64
+		want            bool
+	}{
+		{name: "default - pass", result: keptnv2.ResultPass, want: true},
+		{name: "default - warning", result: keptnv2.ResultWarning, want: true},
+		{name: "default - fail", result: keptnv2.ResultFailed, want: false},
+		{name: "pass required - warning", successCriteria: &remediationSuccessCriteria{Result: "pass"}, result: keptnv2.ResultWarning, want: false},
+		{name: "pass required - pass", successCriteria: &remediationSuccessCriteria{Result: "pass"}, result: keptnv2.ResultPass, want: true},
+		{name: "minimum score reached", successCriteria: &remediationSuccessCriteria{Score: &score}, result: keptnv2.ResultWarning, score: 90, want: true},
+		{name: "minimum score not reached", successCriteria: &remediationSuccessCriteria{Score: &score}, result: keptnv2.ResultPass, score: 85, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluation := &keptnv2.EvaluationFinishedEventData{
+				EventData:  keptnv2.EventData{Result: tt.result},
+				Evaluation: keptnv2.EvaluationDetails{Score: tt.score},
+			}
+			if got := tt.successCriteria.isMet(evaluation); got != tt.want {
+				t.Errorf("isMet() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+const remediationYamlWithMatchingRules = `apiVersion: spec.keptn.sh/0.1.4
+kind: Remediation
+metadata:
+  name: remediation-configuration
+spec:
+  remediations:
+  - problemType: default
+    actionsOnOpen:
+    - action: escalate
+  - problemType: "Response time degradation"
+    actionsOnOpen:
+    - action: scaling
+  - problemTitleRegex: "^Response time degradation on /api/(cart|checkout)"
+    priority: 10
+    actionsOnOpen:
+    - action: togglefeature
+  - problemType: "Response time degradation on /api/cart*(p95)"
+    tags:
+    - "service:carts"
+    - "env:production"
+    priority: 20
+    actionsOnOpen:
+    - action: page
+  - problemType: "Failure rate increase"
+    impactedEntity: "carts-*"
+    actionsOnOpen:
+    - action: featuretoggle
+  - problemType: "Failure rate increase"
+    priority: 100
+    actionsOnOpen: []`
+
+func TestGetRemediationForProblem(t *testing.T) {
+	r := &RemediationHandler{}
+	config, err := r.getRemediation(&configmodels.Resource{ResourceContent: remediationYamlWithMatchingRules})
+	if err != nil {
+		t.Fatalf("getRemediation() error = %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		problem        keptnv2.ProblemDetails
+		expectedAction string
+	}{
+		{
+			name:           "problem type is a prefix of the title",
+			problem:        keptnv2.ProblemDetails{ProblemTitle: "Response time degradation on /api/orders (p95)"},
+			expectedAction: "scaling",
+		},
+		{
+			name:           "regex with higher priority matches the title",
+			problem:        keptnv2.ProblemDetails{ProblemTitle: "Response time degradation on /api/checkout (p95)"},
+			expectedAction: "togglefeature",
+		},
+		{
+			name:           "wildcard problem type without all tags",
+			problem:        keptnv2.ProblemDetails{ProblemTitle: "Response time degradation on /api/cart (p95)", Tags: "service:carts"},
+			expectedAction: "togglefeature",
+		},
+		{
+			name:           "wildcard problem type with all tags and the highest priority",
+			problem:        keptnv2.ProblemDetails{ProblemTitle: "Response time degradation on /api/cart (p95)", Tags: "service:carts, env:production, team:sockshop"},
+			expectedAction: "page",
+		},
+		{
+			name:           "wildcard problem type has to match the whole title",
+			problem:        keptnv2.ProblemDetails{ProblemTitle: "Response time degradation on /api/cart (p90)", Tags: "service:carts,env:production"},
+			expectedAction: "togglefeature",
+		},
+		{
+			name:           "impacted entity matches",
+			problem:        keptnv2.ProblemDetails{ProblemTitle: "Failure rate increase", ImpactedEntity: "carts-primary"},
+			expectedAction: "featuretoggle",
+		},
+		{
+			name:           "impacted entity does not match and remediations without actions are ignored",
+			problem:        keptnv2.ProblemDetails{ProblemTitle: "Failure rate increase", ImpactedEntity: "orders-primary"},
+			expectedAction: "escalate",
+		},
+		{
+			name:           "default remediation for unknown problems",
+			problem:        keptnv2.ProblemDetails{ProblemTitle: "Memory saturation"},
+			expectedAction: "escalate",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remediation := getRemediationForProblem(config, tt.problem)
+			if remediation == nil {
+				t.Fatalf("getRemediationForProblem() returned no remediation")
+			}
+			if action := remediation.getFirstStep().action.Action; action != tt.expectedAction {
+				t.Errorf("getRemediationForProblem() returned remediation with action %s, expected %s", action, tt.expectedAction)
+			}
+		})
+	}
+
+	config.Spec.Remediations = config.Spec.Remediations[1:]
+	if remediation := getRemediationForProblem(config, keptnv2.ProblemDetails{ProblemTitle: "Memory saturation"}); remediation != nil {
+		t.Errorf("getRemediationForProblem() returned a remediation for an unknown problem without default remediation")
+	}
+}
+
+func TestRemediationHandler_getSuppressionReason(t *testing.T) {
+	event := createTestCloudEvent(keptn.ProblemOpenEventType, responseTimeProblemEventPayload)
+	testKeptnHandler, _ := keptnv2.NewKeptn(&event, keptncommon.KeptnOpts{})
+
+	executedAction := func(keptnContext string, executedBefore time.Duration, escalated bool) *models.Remediation {
+		return &models.Remediation{
+			Action:       "scaling",
+			KeptnContext: keptnContext,
+			Type:         keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName),
+			Escalated:    escalated,
+			Stage:        "production",
+			Service:      "service",
+			Time:         time.Now().Add(-executedBefore).UTC().Format(time.RFC3339),
+		}
+	}
+	step := &remediationStep{action: &remediationAction{}, actionIndex: 0, attempt: 1}
+	step.action.Action = "scaling"
+	escalationStep := &remediationStep{action: &remediationAction{}, actionIndex: 1, attempt: 1, escalation: true}
+	escalationStep.action.Action = "page"
+
+	tests := []struct {
+		name            string
+		limits          *remediationLimits
+		step            *remediationStep
+		executedActions []*models.Remediation
+		wantSuppressed  bool
+	}{
+		{
+			name:            "no limits",
+			step:            step,
+			executedActions: []*models.Remediation{executedAction("previous-context", time.Minute, false)},
+			wantSuppressed:  false,
+		},
+		{
+			name:            "action of another remediation within the cooldown",
+			limits:          &remediationLimits{Cooldown: "30m"},
+			step:            step,
+			executedActions: []*models.Remediation{executedAction("previous-context", 10*time.Minute, false)},
+			wantSuppressed:  true,
+		},
+		{
+			name:            "action of the same remediation within the cooldown",
+			limits:          &remediationLimits{Cooldown: "30m"},
+			step:            step,
+			executedActions: []*models.Remediation{executedAction(testKeptnContext, 10*time.Minute, false)},
+			wantSuppressed:  false,
+		},
+		{
+			name:            "action of another remediation before the cooldown",
+			limits:          &remediationLimits{Cooldown: "30m"},
+			step:            step,
+			executedActions: []*models.Remediation{executedAction("previous-context", 40*time.Minute, false)},
+			wantSuppressed:  false,
+		},
+		{
+			name:   "maximum number of actions per hour reached",
+			limits: &remediationLimits{MaxActionsPerHour: 2},
+			step:   step,
+			executedActions: []*models.Remediation{
+				executedAction(testKeptnContext, 50*time.Minute, false),
+				executedAction(testKeptnContext, 20*time.Minute, false),
+			},
+			wantSuppressed: true,
+		},
+		{
+			name:   "escalation actions and actions before the last hour are not counted",
+			limits: &remediationLimits{MaxActionsPerHour: 2},
+			step:   step,
+			executedActions: []*models.Remediation{
+				executedAction(testKeptnContext, 90*time.Minute, false),
+				executedAction(testKeptnContext, 20*time.Minute, true),
+				executedAction(testKeptnContext, 10*time.Minute, false),
+			},
+			wantSuppressed: false,
+		},
+		{
+			name:            "escalation action is never suppressed",
+			limits:          &remediationLimits{Cooldown: "30m", MaxActionsPerHour: 1},
+			step:            escalationStep,
+			executedActions: []*models.Remediation{executedAction("previous-context", 10*time.Minute, false)},
+			wantSuppressed:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &RemediationHandler{
+				Keptn:           testKeptnHandler,
+				RemediationRepo: &fake.RemediationRepo{ExecutedActions: tt.executedActions},
+			}
+			reason, err := r.getSuppressionReason(tt.limits, tt.step)
+			if err != nil {
+				t.Fatalf("getSuppressionReason() error = %v", err)
+			}
+			if (reason != "") != tt.wantSuppressed {
+				t.Errorf("getSuppressionReason() = '%s', wantSuppressed %v", reason, tt.wantSuppressed)
+			}
+		})
+	}
+}
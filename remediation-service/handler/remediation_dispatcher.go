@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/remediation-service/db"
+	"github.com/keptn/keptn/remediation-service/models"
+)
+
+// RemediationDispatcher triggers the evaluations of executed actions whose wait time is over and expires the remediations that have not been
+// finished within the maximum age. Since the time at which an evaluation is due is stored with the remediation, evaluations that became due
+// while the remediation-service was not running are triggered on startup
+type RemediationDispatcher struct {
+	RemediationRepo db.IRemediationRepo
+	KeptnOpts       keptncommon.KeptnOpts
+	Logger          *keptncommon.Logger
+}
+
+// Run dispatches the due events immediately and then in the given interval until the context is done
+func (d *RemediationDispatcher) Run(ctx context.Context, interval time.Duration) {
+	d.dispatch(time.Now())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.dispatch(time.Now())
+		}
+	}
+}
+
+func (d *RemediationDispatcher) dispatch(now time.Time) {
+	projects, err := d.RemediationRepo.GetProjects()
+	if err != nil {
+		d.Logger.Error("Could not retrieve projects with running remediations: " + err.Error())
+		return
+	}
+	for _, project := range projects {
+		d.dispatchDueEvaluations(project, now)
+		d.expireRemediations(project, now)
+	}
+}
+
+func (d *RemediationDispatcher) dispatchDueEvaluations(project string, now time.Time) {
+	executedActions, err := d.RemediationRepo.GetDueEvaluations(project, now)
+	if err != nil {
+		d.Logger.Error(fmt.Sprintf("Could not retrieve due evaluations of project %s: %s", project, err.Error()))
+		return
+	}
+	for _, executedAction := range executedActions {
+		if err := d.dispatchEvaluation(project, executedAction); err != nil {
+			d.Logger.Error(fmt.Sprintf("Could not trigger evaluation of action %s for keptnContext %s: %s", executedAction.Action, executedAction.KeptnContext, err.Error()))
+		}
+	}
+}
+
+func (d *RemediationDispatcher) expireRemediations(project string, now time.Time) {
+	remediations, err := d.RemediationRepo.GetOpenRemediations(project)
+	if err != nil {
+		d.Logger.Error(fmt.Sprintf("Could not retrieve running remediations of project %s: %s", project, err.Error()))
+		return
+	}
+	openRemediations := map[string][]*models.Remediation{}
+	for _, remediation := range remediations {
+		openRemediations[remediation.KeptnContext] = append(openRemediations[remediation.KeptnContext], remediation)
+	}
+
+	maxAge := getMaxRemediationAge()
+	for _, keptnContext := range getExpiredRemediations(openRemediations, maxAge, now) {
+		remediationTriggered := getRemediationTriggeredEntry(openRemediations[keptnContext])
+		remediationHandler, err := d.newRemediationHandler(project, keptnContext, remediationTriggered)
+		if err == nil {
+			err = remediationHandler.expireRemediation(keptnContext, openRemediations[keptnContext], maxAge)
+		}
+		if err != nil {
+			d.Logger.Error("Could not expire remediation of keptnContext " + keptnContext + ": " + err.Error())
+		}
+	}
+}
+
+// dispatchEvaluation sends the evaluation.triggered event for the executed action and removes its due time. If the event cannot be sent,
+// the due time is kept, so that the evaluation is triggered in the next run
+func (d *RemediationDispatcher) dispatchEvaluation(project string, executedAction *models.Remediation) error {
+	remediations, err := d.RemediationRepo.GetRemediations(executedAction.KeptnContext, project)
+	if err != nil {
+		return err
+	}
+	remediationTriggered := getRemediationTriggeredEntry(remediations)
+	if remediationTriggered == nil {
+		d.RemediationRepo.SetEvaluationDue(executedAction.KeptnContext, project, executedAction.EventID, "")
+		return errors.New("no remediation.triggered entry found")
+	}
+
+	evaluationEnd, err := time.Parse(time.RFC3339, executedAction.EvaluationDue)
+	if err != nil {
+		d.RemediationRepo.SetEvaluationDue(executedAction.KeptnContext, project, executedAction.EventID, "")
+		return fmt.Errorf("invalid evaluation due time %s: %s", executedAction.EvaluationDue, err.Error())
+	}
+
+	remediationHandler, err := d.newRemediationHandler(project, executedAction.KeptnContext, remediationTriggered)
+	if err != nil {
+		return err
+	}
+	remediationHandler.Keptn.Logger.Info("Wait time is over. Sending start-evaluation event.")
+	if err := remediationHandler.sendEvaluationTriggeredEvent(evaluationEnd, remediationHandler.getActionWaitTime(executedAction)); err != nil {
+		return err
+	}
+	return d.RemediationRepo.SetEvaluationDue(executedAction.KeptnContext, project, executedAction.EventID, "")
+}
+
+// newRemediationHandler returns a RemediationHandler for the remediation of the given keptn context, based on its remediation.triggered entry
+func (d *RemediationDispatcher) newRemediationHandler(project, keptnContext string, remediationTriggered *models.Remediation) (*RemediationHandler, error) {
+	event := cloudevents.NewEvent()
+	event.SetID(remediationTriggered.EventID)
+	event.SetType(keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName))
+	event.SetSource("remediation-service")
+	event.SetDataContentType(cloudevents.ApplicationJSON)
+	event.SetExtension("shkeptncontext", keptnContext)
+	event.SetData(cloudevents.ApplicationJSON, keptnv2.EventData{
+		Project: project,
+		Stage:   remediationTriggered.Stage,
+		Service: remediationTriggered.Service,
+		Labels:  remediationTriggered.Labels,
+	})
+
+	keptnHandler, err := keptnv2.NewKeptn(&event, d.KeptnOpts)
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize Keptn handler: %s", err.Error())
+	}
+	return &RemediationHandler{
+		Keptn:           keptnHandler,
+		RemediationRepo: d.RemediationRepo,
+	}, nil
+}
+
+// getRemediationTriggeredEntry returns the remediation.triggered entry of a remediation, or nil if there is none
+func getRemediationTriggeredEntry(remediations []*models.Remediation) *models.Remediation {
+	for _, remediation := range remediations {
+		if remediation.Type == keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName) {
+			return remediation
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-test/deep"
+	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/remediation-service/handler/fake"
+	"github.com/keptn/keptn/remediation-service/models"
+)
+
+func TestRemediationDispatcher_dispatch(t *testing.T) {
+	now := time.Date(2021, 2, 1, 12, 0, 0, 0, time.UTC)
+	dueAction := &models.Remediation{
+		Action:        "togglefeature",
+		EventID:       "test-id-2",
+		KeptnContext:  testKeptnContext,
+		Type:          keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName),
+		WaitTime:      "5m",
+		EvaluationDue: now.Add(-time.Minute).Format(time.RFC3339),
+	}
+	pendingAction := &models.Remediation{
+		Action:        "scale",
+		EventID:       "other-id-2",
+		KeptnContext:  "other-context",
+		Type:          keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName),
+		EvaluationDue: now.Add(time.Minute).Format(time.RFC3339),
+	}
+	fakeRemediationRepo := &fake.RemediationRepo{
+		Project: "sockshop",
+		Remediations: []*models.Remediation{
+			{
+				EventID:      "test-id-1",
+				KeptnContext: testKeptnContext,
+				Type:         keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName),
+				Stage:        "production",
+				Service:      "carts",
+				Labels:       map[string]string{"owner": "team-carts"},
+			},
+			dueAction,
+			{
+				EventID:      "other-id-1",
+				KeptnContext: "other-context",
+				Type:         keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName),
+				Stage:        "production",
+				Service:      "orders",
+			},
+			pendingAction,
+			{
+				EventID:      "expired-id-1",
+				KeptnContext: "expired-context",
+				Type:         keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName),
+				Stage:        "production",
+				Service:      "carts",
+				Time:         now.Add(-25 * time.Hour).Format(time.RFC3339),
+			},
+		},
+	}
+
+	mockEV := NewMockEventbroker(nil)
+	defer mockEV.Server.Close()
+
+	d := &RemediationDispatcher{
+		RemediationRepo: fakeRemediationRepo,
+		KeptnOpts:       keptncommon.KeptnOpts{EventBrokerURL: mockEV.Server.URL},
+		Logger:          keptncommon.NewLogger("", "", "remediation-service"),
+	}
+	d.dispatch(now)
+
+	if len(mockEV.ReceivedEvents) != 2 {
+		t.Fatalf("Received %d events, expected 2", len(mockEV.ReceivedEvents))
+	}
+	receivedEvent := mockEV.ReceivedEvents[0]
+	if *receivedEvent.Type != keptnv2.GetTriggeredEventType(keptnv2.EvaluationTaskName) || receivedEvent.Shkeptncontext != testKeptnContext {
+		t.Errorf("Received %s event for keptnContext %s, expected evaluation.triggered event for %s", *receivedEvent.Type, receivedEvent.Shkeptncontext, testKeptnContext)
+	}
+
+	data, _ := json.Marshal(receivedEvent.Data)
+	evaluationTriggeredEventData := &keptnv2.EvaluationTriggeredEventData{}
+	if err := json.Unmarshal(data, evaluationTriggeredEventData); err != nil {
+		t.Fatalf("Could not parse evaluation.triggered event: %v", err)
+	}
+	expectedData := keptnv2.EventData{Project: "sockshop", Stage: "production", Service: "carts", Labels: map[string]string{"owner": "team-carts"}}
+	if diff := deep.Equal(evaluationTriggeredEventData.EventData, expectedData); len(diff) > 0 {
+		t.Errorf("Unexpected event data: %v", diff)
+	}
+	if evaluationTriggeredEventData.Evaluation.Start != now.Add(-6*time.Minute).Format(time.RFC3339) ||
+		evaluationTriggeredEventData.Evaluation.End != now.Add(-time.Minute).Format(time.RFC3339) {
+		t.Errorf("Evaluation from %s to %s, expected the wait time before the due time", evaluationTriggeredEventData.Evaluation.Start, evaluationTriggeredEventData.Evaluation.End)
+	}
+
+	if dueAction.EvaluationDue != "" {
+		t.Errorf("Evaluation due time has not been removed after triggering the evaluation")
+	}
+	if pendingAction.EvaluationDue == "" {
+		t.Errorf("Evaluation due time of pending evaluation has been removed")
+	}
+
+	expiredEvent := mockEV.ReceivedEvents[1]
+	if *expiredEvent.Type != keptnv2.GetFinishedEventType(keptnv2.RemediationTaskName) || expiredEvent.Shkeptncontext != "expired-context" {
+		t.Errorf("Received %s event for keptnContext %s, expected remediation.finished event for expired-context", *expiredEvent.Type, expiredEvent.Shkeptncontext)
+	}
+	if remediations, _ := fakeRemediationRepo.GetRemediations("expired-context", "sockshop"); len(remediations) > 0 {
+		t.Errorf("Expired remediation has not been removed")
+	}
+}
+
+type failingEventSender struct{}
+
+func (s *failingEventSender) SendEvent(event cloudevents.Event) error {
+	return errors.New("event broker not available")
+}
+
+func TestRemediationDispatcher_dispatch_eventCannotBeSent(t *testing.T) {
+	now := time.Date(2021, 2, 1, 12, 0, 0, 0, time.UTC)
+	dueAction := &models.Remediation{
+		Action:        "togglefeature",
+		EventID:       "test-id-2",
+		KeptnContext:  testKeptnContext,
+		Type:          keptnv2.GetStatusChangedEventType(keptnv2.RemediationTaskName),
+		EvaluationDue: now.Add(-time.Minute).Format(time.RFC3339),
+	}
+	fakeRemediationRepo := &fake.RemediationRepo{
+		Project: "sockshop",
+		Remediations: []*models.Remediation{
+			{
+				EventID:      "test-id-1",
+				KeptnContext: testKeptnContext,
+				Type:         keptnv2.GetTriggeredEventType(keptnv2.RemediationTaskName),
+				Stage:        "production",
+				Service:      "carts",
+				Time:         now.Add(-25 * time.Hour).Format(time.RFC3339),
+			},
+			dueAction,
+		},
+	}
+
+	d := &RemediationDispatcher{
+		RemediationRepo: fakeRemediationRepo,
+		KeptnOpts:       keptncommon.KeptnOpts{EventSender: &failingEventSender{}},
+		Logger:          keptncommon.NewLogger("", "", "remediation-service"),
+	}
+	d.dispatch(now)
+
+	if dueAction.EvaluationDue == "" {
+		t.Errorf("Evaluation due time has been removed although the evaluation could not be triggered")
+	}
+	if remediations, _ := fakeRemediationRepo.GetRemediations(testKeptnContext, "sockshop"); len(remediations) != 2 {
+		t.Errorf("Expired remediation has been removed although the remediation.finished event could not be sent")
+	}
+}
@@ -6,12 +6,16 @@ import (
 	"github.com/kelseyhightower/envconfig"
 	keptnapi "github.com/keptn/go-utils/pkg/api/utils"
 	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
+	"github.com/keptn/keptn/remediation-service/db"
 	"github.com/keptn/keptn/remediation-service/handler"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"log"
 	"os"
+	"time"
 )
 
+const evaluationDispatchInterval = 10 * time.Second
+
 type envConfig struct {
 	Port int    `envconfig:"RCV_PORT" default:"8080"`
 	Path string `envconfig:"RCV_PATH" default:"/"`
@@ -40,6 +44,19 @@ func _main(args []string, env envConfig) int {
 	if err != nil {
 		log.Fatalf("failed to create client, %v", err)
 	}
+
+	serviceName := "remediation-service"
+	dispatcher := &handler.RemediationDispatcher{
+		RemediationRepo: &db.RemediationMongoDBRepo{},
+		KeptnOpts: keptncommon.KeptnOpts{
+			LoggingOptions: &keptncommon.LoggingOpts{
+				ServiceName: &serviceName,
+			},
+		},
+		Logger: keptncommon.NewLogger("", "", serviceName),
+	}
+	go dispatcher.Run(ctx, evaluationDispatchInterval)
+
 	log.Fatal(c.StartReceiver(ctx, gotEvent))
 
 	return 0
@@ -12,4 +12,38 @@ type Remediation struct {
 
 	// Type of the event
 	Type string `json:"type,omitempty" bson:"type"`
+
+	// Index of the executed action within the actions of the remediation
+	ActionIndex int `json:"actionIndex,omitempty" bson:"actionIndex"`
+
+	// Number of the attempt to execute the action, starting at 1
+	Attempt int `json:"attempt,omitempty" bson:"attempt"`
+
+	// Time to wait after the action has finished before evaluating its effect, e.g. 5m. If empty, the default wait time is used
+	WaitTime string `json:"waitTime,omitempty" bson:"waitTime"`
+
+	// Escalated is set if the executed action is the escalation action of the remediation, which is triggered after all other actions failed
+	Escalated bool `json:"escalated,omitempty" bson:"escalated"`
+
+	// Time at which the effect of the executed action is evaluated, formatted as RFC3339. It is set when the action has finished and removed
+	// as soon as the evaluation has been triggered
+	EvaluationDue string `json:"evaluationDue,omitempty" bson:"evaluationDue" deep:"-"`
+
+	// ID of the problem the entry refers to. It is set for the remediation.triggered entry and for problems correlated with the remediation
+	ProblemID string `json:"problemId,omitempty" bson:"problemId"`
+
+	// PID of the problem the entry refers to
+	PID string `json:"pid,omitempty" bson:"pid"`
+
+	// Stage of the remediated service
+	Stage string `json:"stage,omitempty" bson:"stage"`
+
+	// Name of the remediated service
+	Service string `json:"service,omitempty" bson:"service"`
+
+	// Labels of the remediation.triggered event, which are passed on to the evaluations of the remediation
+	Labels map[string]string `json:"labels,omitempty" bson:"labels"`
+
+	// Time the entry has been created, formatted as RFC3339
+	Time string `json:"time,omitempty" bson:"time" deep:"-"`
 }
@@ -0,0 +1,22 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/keptn/keptn/shipyard-controller/handler"
+)
+
+type ScheduleController struct {
+	ScheduleHandler handler.IScheduleHandler
+}
+
+func NewScheduleController(scheduleHandler handler.IScheduleHandler) Controller {
+	return &ScheduleController{ScheduleHandler: scheduleHandler}
+}
+
+func (controller ScheduleController) Inject(apiGroup *gin.RouterGroup) {
+	apiGroup.GET("/schedule/:project", controller.ScheduleHandler.GetSchedules)
+	apiGroup.POST("/schedule/:project", controller.ScheduleHandler.CreateSchedule)
+	apiGroup.GET("/schedule/:project/:scheduleId", controller.ScheduleHandler.GetSchedule)
+	apiGroup.PUT("/schedule/:project/:scheduleId", controller.ScheduleHandler.UpdateSchedule)
+	apiGroup.DELETE("/schedule/:project/:scheduleId", controller.ScheduleHandler.DeleteSchedule)
+}
@@ -0,0 +1,19 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/keptn/keptn/shipyard-controller/handler"
+)
+
+type SequenceController struct {
+	SequenceHandler handler.ISequenceHandler
+}
+
+func NewSequenceController(sequenceHandler handler.ISequenceHandler) Controller {
+	return &SequenceController{SequenceHandler: sequenceHandler}
+}
+
+func (controller SequenceController) Inject(apiGroup *gin.RouterGroup) {
+	apiGroup.GET("/sequence/:project", controller.SequenceHandler.GetSequenceState)
+	apiGroup.POST("/sequence/:project/:keptnContext/control", controller.SequenceHandler.ControlSequence)
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"errors"
 	"github.com/keptn/keptn/shipyard-controller/models"
+	"time"
 )
 
 // EventStatus indicates the status type of an event, i.e. 'triggered', 'started', or 'finished'
@@ -26,6 +27,8 @@ type EventFilter struct {
 	TriggeredID  *string
 	Source       *string
 	KeptnContext *string
+	// DueAt excludes delayed events that must not be sent before a later time
+	DueAt *time.Time
 }
 
 // ErrNoEventFound indicates that no event could be found
@@ -37,6 +40,14 @@ type EventRepo interface {
 	GetEvents(project string, filter EventFilter, status EventStatus) ([]models.Event, error)
 	// InsertEvent inserts an event into the collection of the specified project
 	InsertEvent(project string, event models.Event, status EventStatus) error
+	// InsertDelayedEvent inserts an event that must not be sent before the given time into the collection of the specified project
+	InsertDelayedEvent(project string, event models.Event, status EventStatus, notBefore time.Time) error
+	// GetDueDelayedEvents gets all delayed events of a project that are due at the given time, but have not been sent yet
+	GetDueDelayedEvents(project string, status EventStatus, now time.Time) ([]models.Event, error)
+	// SetDelayedEventSent marks a delayed event as sent
+	SetDelayedEventSent(project string, eventID string, status EventStatus) error
+	// GetEventInsertionTime returns the time at which an event has been stored in the collection
+	GetEventInsertionTime(project string, eventID string, status EventStatus) (time.Time, error)
 	// DeleteEvent deletes an event from the collection
 	DeleteEvent(project string, eventID string, status EventStatus) error
 	// DeleteEventCollections godoc
@@ -9,6 +9,7 @@ import (
 	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
 	"github.com/keptn/keptn/shipyard-controller/models"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"time"
 )
@@ -18,6 +19,12 @@ const startedEventsCollectionNameSuffix = "-startedEvents"
 const finishedEventsCollectionNameSuffix = "-finishedEvents"
 const remediationCollectionNameSuffix = "-remediations"
 
+// notBeforeField is the name of the field that holds the time before which a delayed event must not be sent
+const notBeforeField = "notBefore"
+
+// pendingDeliveryField is the name of the field that marks delayed events that have not been sent yet
+const pendingDeliveryField = "pendingDelivery"
+
 // MongoDBEventsRepo retrieves and stores events in a mongodb collection
 type MongoDBEventsRepo struct {
 	DbConnection MongoDBConnection
@@ -41,6 +48,31 @@ func (mdbrepo *MongoDBEventsRepo) GetEvents(project string, filter EventFilter,
 
 	searchOptions := getSearchOptions(filter)
 
+	return findEvents(ctx, collection, searchOptions)
+}
+
+// GetDueDelayedEvents gets all delayed events of a project that are due at the given time, but have not been sent yet
+func (mdbrepo *MongoDBEventsRepo) GetDueDelayedEvents(project string, status EventStatus, now time.Time) ([]models.Event, error) {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := mdbrepo.getEventsCollection(project, status)
+
+	if collection == nil {
+		return nil, errors.New("invalid event type")
+	}
+
+	return findEvents(ctx, collection, bson.M{
+		pendingDeliveryField: true,
+		notBeforeField:       bson.M{"$lte": now},
+	})
+}
+
+func findEvents(ctx context.Context, collection *mongo.Collection, searchOptions bson.M) ([]models.Event, error) {
 	cur, err := collection.Find(ctx, searchOptions)
 	if err != nil && err == mongo.ErrNoDocuments {
 		return nil, ErrNoEventFound
@@ -79,6 +111,19 @@ func (mdbrepo *MongoDBEventsRepo) GetEvents(project string, filter EventFilter,
 
 // InsertEvent inserts an event into the collection of the specified project
 func (mdbrepo *MongoDBEventsRepo) InsertEvent(project string, event models.Event, status EventStatus) error {
+	return mdbrepo.insertEvent(project, event, status, nil)
+}
+
+// InsertDelayedEvent inserts an event that must not be sent before the given time into the collection of the specified project
+func (mdbrepo *MongoDBEventsRepo) InsertDelayedEvent(project string, event models.Event, status EventStatus, notBefore time.Time) error {
+	return mdbrepo.insertEvent(project, event, status, map[string]interface{}{
+		notBeforeField:       notBefore.UTC(),
+		pendingDeliveryField: true,
+	})
+}
+
+// insertEvent stores an event together with the given fields that are not part of the event itself
+func (mdbrepo *MongoDBEventsRepo) insertEvent(project string, event models.Event, status EventStatus, additionalFields map[string]interface{}) error {
 	err := mdbrepo.DbConnection.EnsureDBConnection()
 	if err != nil {
 		return err
@@ -93,8 +138,11 @@ func (mdbrepo *MongoDBEventsRepo) InsertEvent(project string, event models.Event
 	}
 
 	marshal, _ := json.Marshal(event)
-	var eventInterface interface{}
+	eventInterface := map[string]interface{}{}
 	_ = json.Unmarshal(marshal, &eventInterface)
+	for key, value := range additionalFields {
+		eventInterface[key] = value
+	}
 
 	existingEvent := collection.FindOne(ctx, bson.M{"id": event.ID})
 	if existingEvent.Err() == nil || existingEvent.Err() != mongo.ErrNoDocuments {
@@ -108,6 +156,56 @@ func (mdbrepo *MongoDBEventsRepo) InsertEvent(project string, event models.Event
 	return nil
 }
 
+// SetDelayedEventSent marks a delayed event as sent
+func (mdbrepo *MongoDBEventsRepo) SetDelayedEventSent(project string, eventID string, status EventStatus) error {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := mdbrepo.getEventsCollection(project, status)
+
+	if collection == nil {
+		return errors.New("invalid event type")
+	}
+
+	_, err = collection.UpdateMany(ctx, bson.M{"id": eventID}, bson.M{"$unset": bson.M{pendingDeliveryField: ""}})
+	return err
+}
+
+// GetEventInsertionTime returns the time at which an event has been stored in the collection, based on the ObjectID assigned by mongodb
+func (mdbrepo *MongoDBEventsRepo) GetEventInsertionTime(project string, eventID string, status EventStatus) (time.Time, error) {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return time.Time{}, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := mdbrepo.getEventsCollection(project, status)
+
+	if collection == nil {
+		return time.Time{}, errors.New("invalid event type")
+	}
+
+	result := collection.FindOne(ctx, bson.M{"id": eventID})
+	if result.Err() == mongo.ErrNoDocuments {
+		return time.Time{}, ErrNoEventFound
+	} else if result.Err() != nil {
+		return time.Time{}, result.Err()
+	}
+
+	storedEvent := struct {
+		ObjectID primitive.ObjectID `bson:"_id"`
+	}{}
+	if err := result.Decode(&storedEvent); err != nil {
+		return time.Time{}, err
+	}
+	return storedEvent.ObjectID.Timestamp(), nil
+}
+
 // DeleteEvent deletes an event from the collection
 func (mdbrepo *MongoDBEventsRepo) DeleteEvent(project, eventID string, status EventStatus) error {
 	err := mdbrepo.DbConnection.EnsureDBConnection()
@@ -212,6 +310,12 @@ func getSearchOptions(filter EventFilter) bson.M {
 	if filter.KeptnContext != nil && *filter.KeptnContext != "" {
 		searchOptions["shkeptncontext"] = *filter.KeptnContext
 	}
+	if filter.DueAt != nil {
+		searchOptions["$or"] = []bson.M{
+			{notBeforeField: bson.M{"$exists": false}},
+			{notBeforeField: bson.M{"$lte": *filter.DueAt}},
+		}
+	}
 	return searchOptions
 }
 
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
+	"github.com/keptn/keptn/shipyard-controller/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const pausedSequenceCollectionNameSuffix = "-pausedSequences"
+
+// PausedSequenceMongoDBRepo retrieves and stores paused task sequences in a mongodb collection
+type PausedSequenceMongoDBRepo struct {
+	DbConnection MongoDBConnection
+	Logger       keptncommon.LoggerInterface
+}
+
+// GetPausedSequences returns the paused task sequences of a project with the given keptnContext
+func (mdbrepo *PausedSequenceMongoDBRepo) GetPausedSequences(project, keptnContext string) ([]models.PausedSequence, error) {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := mdbrepo.getPausedSequenceCollection(project)
+	cur, err := collection.Find(ctx, bson.M{"keptnContext": keptnContext})
+	if err != nil {
+		mdbrepo.Logger.Error("Error retrieving paused sequences from mongoDB: " + err.Error())
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	result := []models.PausedSequence{}
+	for cur.Next(ctx) {
+		var outputSequence interface{}
+		if err := cur.Decode(&outputSequence); err != nil {
+			return nil, err
+		}
+		// convert the stored document back to plain maps and slices to preserve the payload of the pending tasks
+		outputSequence, err = flattenRecursively(outputSequence)
+		if err != nil {
+			return nil, err
+		}
+		data, _ := json.Marshal(outputSequence)
+
+		pausedSequence := &models.PausedSequence{}
+		if err := json.Unmarshal(data, pausedSequence); err != nil {
+			mdbrepo.Logger.Error("Could not cast to *models.PausedSequence: " + err.Error())
+			continue
+		}
+		result = append(result, *pausedSequence)
+	}
+	return result, nil
+}
+
+// UpsertPausedSequence creates or updates a paused task sequence
+func (mdbrepo *PausedSequenceMongoDBRepo) UpsertPausedSequence(project string, pausedSequence models.PausedSequence) error {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := mdbrepo.getPausedSequenceCollection(project)
+
+	marshal, _ := json.Marshal(pausedSequence)
+	var sequenceInterface interface{}
+	_ = json.Unmarshal(marshal, &sequenceInterface)
+
+	_, err = collection.ReplaceOne(
+		ctx,
+		bson.M{"keptnContext": pausedSequence.KeptnContext, "stage": pausedSequence.Stage},
+		sequenceInterface,
+		options.Replace().SetUpsert(true),
+	)
+	if err != nil {
+		mdbrepo.Logger.Error("Could not store paused sequence with context " + pausedSequence.KeptnContext + ": " + err.Error())
+		return err
+	}
+	return nil
+}
+
+// DeletePausedSequence deletes a paused task sequence
+func (mdbrepo *PausedSequenceMongoDBRepo) DeletePausedSequence(project, keptnContext, stage string) error {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := mdbrepo.getPausedSequenceCollection(project)
+
+	_, err = collection.DeleteMany(ctx, bson.M{"keptnContext": keptnContext, "stage": stage})
+	if err != nil {
+		mdbrepo.Logger.Error("Could not delete paused sequence with context " + keptnContext + " in stage " + stage + ": " + err.Error())
+		return err
+	}
+	return nil
+}
+
+// DeletePausedSequenceCollection godoc
+func (mdbrepo *PausedSequenceMongoDBRepo) DeletePausedSequenceCollection(project string) error {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return err
+	}
+	collection := mdbrepo.getPausedSequenceCollection(project)
+
+	mdbrepo.Logger.Debug(fmt.Sprintf("Delete collection: %s", collection.Name()))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := collection.Drop(ctx); err != nil {
+		err := fmt.Errorf("failed to drop collection %s: %v", collection.Name(), err)
+		mdbrepo.Logger.Error(err.Error())
+		return err
+	}
+	return nil
+}
+
+func (mdbrepo *PausedSequenceMongoDBRepo) getPausedSequenceCollection(project string) *mongo.Collection {
+	return mdbrepo.DbConnection.Client.Database(databaseName).Collection(project + pausedSequenceCollectionNameSuffix)
+}
@@ -0,0 +1,166 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
+	"github.com/keptn/keptn/shipyard-controller/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const scheduleCollectionNameSuffix = "-schedules"
+
+// ScheduleMongoDBRepo retrieves and stores schedules of task sequences in a mongodb collection
+type ScheduleMongoDBRepo struct {
+	DbConnection MongoDBConnection
+	Logger       keptncommon.LoggerInterface
+}
+
+// GetSchedules returns the schedules of a project, based on the provided filter
+func (mdbrepo *ScheduleMongoDBRepo) GetSchedules(project string, filter ScheduleFilter) ([]models.Schedule, error) {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := mdbrepo.getScheduleCollection(project)
+	cur, err := collection.Find(ctx, getScheduleSearchOptions(filter), options.Find().SetSort(bson.M{"createdAt": 1}))
+	if err != nil {
+		mdbrepo.Logger.Error("Error retrieving schedules from mongoDB: " + err.Error())
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	result := []models.Schedule{}
+	for cur.Next(ctx) {
+		schedule := &models.Schedule{}
+		if err := cur.Decode(schedule); err != nil {
+			mdbrepo.Logger.Error("Could not cast to *models.Schedule: " + err.Error())
+			continue
+		}
+		result = append(result, *schedule)
+	}
+	return result, nil
+}
+
+// GetSchedule returns the schedule with the given ID
+func (mdbrepo *ScheduleMongoDBRepo) GetSchedule(project, scheduleID string) (*models.Schedule, error) {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := mdbrepo.getScheduleCollection(project)
+	schedule := &models.Schedule{}
+	if err := collection.FindOne(ctx, bson.M{"_id": scheduleID}).Decode(schedule); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrScheduleNotFound
+		}
+		mdbrepo.Logger.Error("Error retrieving schedule " + scheduleID + " from mongoDB: " + err.Error())
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// CreateSchedule stores a new schedule
+func (mdbrepo *ScheduleMongoDBRepo) CreateSchedule(project string, schedule models.Schedule) error {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := mdbrepo.getScheduleCollection(project)
+	if _, err := collection.InsertOne(ctx, schedule); err != nil {
+		mdbrepo.Logger.Error("Could not store schedule for sequence " + schedule.Stage + "." + schedule.Sequence + ": " + err.Error())
+		return err
+	}
+	return nil
+}
+
+// UpdateSchedule updates an existing schedule
+func (mdbrepo *ScheduleMongoDBRepo) UpdateSchedule(project string, schedule models.Schedule) error {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := mdbrepo.getScheduleCollection(project)
+	res, err := collection.ReplaceOne(ctx, bson.M{"_id": schedule.ID}, schedule)
+	if err != nil {
+		mdbrepo.Logger.Error("Could not update schedule " + schedule.ID + ": " + err.Error())
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrScheduleNotFound
+	}
+	return nil
+}
+
+// DeleteSchedule deletes the schedule with the given ID
+func (mdbrepo *ScheduleMongoDBRepo) DeleteSchedule(project, scheduleID string) error {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := mdbrepo.getScheduleCollection(project)
+	res, err := collection.DeleteOne(ctx, bson.M{"_id": scheduleID})
+	if err != nil {
+		mdbrepo.Logger.Error("Could not delete schedule " + scheduleID + ": " + err.Error())
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrScheduleNotFound
+	}
+	return nil
+}
+
+// DeleteScheduleCollection godoc
+func (mdbrepo *ScheduleMongoDBRepo) DeleteScheduleCollection(project string) error {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return err
+	}
+	collection := mdbrepo.getScheduleCollection(project)
+
+	mdbrepo.Logger.Debug(fmt.Sprintf("Delete collection: %s", collection.Name()))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := collection.Drop(ctx); err != nil {
+		err := fmt.Errorf("failed to drop collection %s: %v", collection.Name(), err)
+		mdbrepo.Logger.Error(err.Error())
+		return err
+	}
+	return nil
+}
+
+func (mdbrepo *ScheduleMongoDBRepo) getScheduleCollection(project string) *mongo.Collection {
+	return mdbrepo.DbConnection.Client.Database(databaseName).Collection(project + scheduleCollectionNameSuffix)
+}
+
+func getScheduleSearchOptions(filter ScheduleFilter) bson.M {
+	searchOptions := bson.M{}
+	if filter.Stage != nil && *filter.Stage != "" {
+		searchOptions["stage"] = *filter.Stage
+	}
+	if filter.Service != nil && *filter.Service != "" {
+		searchOptions["service"] = *filter.Service
+	}
+	if filter.Sequence != nil && *filter.Sequence != "" {
+		searchOptions["sequence"] = *filter.Sequence
+	}
+	return searchOptions
+}
@@ -0,0 +1,140 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
+	"github.com/keptn/keptn/shipyard-controller/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const sequenceQueueCollectionNameSuffix = "-sequenceQueue"
+
+// SequenceQueueMongoDBRepo retrieves and stores queued task sequences in a mongodb collection
+type SequenceQueueMongoDBRepo struct {
+	DbConnection MongoDBConnection
+	Logger       keptncommon.LoggerInterface
+}
+
+// GetQueuedSequences returns the queued task sequences of a project, based on the provided filter. The sequences are sorted by the time they have been queued
+func (mdbrepo *SequenceQueueMongoDBRepo) GetQueuedSequences(project string, filter SequenceQueueFilter) ([]models.QueuedSequence, error) {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := mdbrepo.getSequenceQueueCollection(project)
+	cur, err := collection.Find(ctx, getSequenceQueueSearchOptions(filter), options.Find().SetSort(bson.M{"queuedAt": 1}))
+	if err != nil {
+		mdbrepo.Logger.Error("Error retrieving queued sequences from mongoDB: " + err.Error())
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	result := []models.QueuedSequence{}
+	for cur.Next(ctx) {
+		var outputSequence interface{}
+		if err := cur.Decode(&outputSequence); err != nil {
+			return nil, err
+		}
+		// convert the stored document back to plain maps and slices to preserve the payload of the previous tasks
+		outputSequence, err = flattenRecursively(outputSequence)
+		if err != nil {
+			return nil, err
+		}
+		data, _ := json.Marshal(outputSequence)
+
+		queuedSequence := &models.QueuedSequence{}
+		if err := json.Unmarshal(data, queuedSequence); err != nil {
+			mdbrepo.Logger.Error("Could not cast to *models.QueuedSequence: " + err.Error())
+			continue
+		}
+		result = append(result, *queuedSequence)
+	}
+	return result, nil
+}
+
+// QueueSequence adds a task sequence to the queue
+func (mdbrepo *SequenceQueueMongoDBRepo) QueueSequence(project string, queuedSequence models.QueuedSequence) error {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := mdbrepo.getSequenceQueueCollection(project)
+
+	marshal, _ := json.Marshal(queuedSequence)
+	var sequenceInterface interface{}
+	_ = json.Unmarshal(marshal, &sequenceInterface)
+
+	_, err = collection.InsertOne(ctx, sequenceInterface)
+	if err != nil {
+		mdbrepo.Logger.Error("Could not queue sequence " + queuedSequence.Stage + "." + queuedSequence.TaskSequenceName + " with context " + queuedSequence.KeptnContext + ": " + err.Error())
+		return err
+	}
+	return nil
+}
+
+// DeleteQueuedSequence removes a task sequence from the queue
+func (mdbrepo *SequenceQueueMongoDBRepo) DeleteQueuedSequence(project, keptnContext, stage, taskSequenceName string) error {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := mdbrepo.getSequenceQueueCollection(project)
+
+	_, err = collection.DeleteMany(ctx, bson.M{"keptnContext": keptnContext, "stage": stage, "taskSequenceName": taskSequenceName})
+	if err != nil {
+		mdbrepo.Logger.Error("Could not delete queued sequence " + stage + "." + taskSequenceName + " with context " + keptnContext + ": " + err.Error())
+		return err
+	}
+	return nil
+}
+
+// DeleteSequenceQueueCollection godoc
+func (mdbrepo *SequenceQueueMongoDBRepo) DeleteSequenceQueueCollection(project string) error {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return err
+	}
+	collection := mdbrepo.getSequenceQueueCollection(project)
+
+	mdbrepo.Logger.Debug(fmt.Sprintf("Delete collection: %s", collection.Name()))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := collection.Drop(ctx); err != nil {
+		err := fmt.Errorf("failed to drop collection %s: %v", collection.Name(), err)
+		mdbrepo.Logger.Error(err.Error())
+		return err
+	}
+	return nil
+}
+
+func (mdbrepo *SequenceQueueMongoDBRepo) getSequenceQueueCollection(project string) *mongo.Collection {
+	return mdbrepo.DbConnection.Client.Database(databaseName).Collection(project + sequenceQueueCollectionNameSuffix)
+}
+
+func getSequenceQueueSearchOptions(filter SequenceQueueFilter) bson.M {
+	searchOptions := bson.M{}
+	if filter.KeptnContext != nil && *filter.KeptnContext != "" {
+		searchOptions["keptnContext"] = *filter.KeptnContext
+	}
+	if filter.Stage != nil && *filter.Stage != "" {
+		searchOptions["stage"] = *filter.Stage
+	}
+	if filter.Service != nil && *filter.Service != "" {
+		searchOptions["service"] = *filter.Service
+	}
+	return searchOptions
+}
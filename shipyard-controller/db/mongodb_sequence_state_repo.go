@@ -0,0 +1,116 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
+	"github.com/keptn/keptn/shipyard-controller/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const sequenceStateCollectionNameSuffix = "-sequenceStates"
+
+// SequenceStateMongoDBRepo retrieves and stores the states of task sequences in a mongodb collection
+type SequenceStateMongoDBRepo struct {
+	DbConnection MongoDBConnection
+	Logger       keptncommon.LoggerInterface
+}
+
+// GetSequenceStates returns the states of the task sequences of a project, based on the provided filter
+func (mdbrepo *SequenceStateMongoDBRepo) GetSequenceStates(project string, filter SequenceStateFilter) ([]models.SequenceState, error) {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := mdbrepo.getSequenceStateCollection(project)
+	cur, err := collection.Find(ctx, getSequenceStateSearchOptions(filter), options.Find().SetSort(bson.M{"time": -1}))
+	if err != nil {
+		mdbrepo.Logger.Error("Error retrieving sequence states from mongoDB: " + err.Error())
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	result := []models.SequenceState{}
+	for cur.Next(ctx) {
+		state := &models.SequenceState{}
+		if err := cur.Decode(state); err != nil {
+			mdbrepo.Logger.Error("Could not cast to *models.SequenceState: " + err.Error())
+			continue
+		}
+		result = append(result, *state)
+	}
+	return result, nil
+}
+
+// UpsertSequenceState creates or updates the state of a task sequence
+func (mdbrepo *SequenceStateMongoDBRepo) UpsertSequenceState(project string, state models.SequenceState) error {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := mdbrepo.getSequenceStateCollection(project)
+
+	_, err = collection.ReplaceOne(
+		ctx,
+		bson.M{"shkeptncontext": state.KeptnContext, "stage": state.Stage, "name": state.Name},
+		state,
+		options.Replace().SetUpsert(true),
+	)
+	if err != nil {
+		mdbrepo.Logger.Error("Could not store state of sequence " + state.Stage + "." + state.Name + " with context " + state.KeptnContext + ": " + err.Error())
+		return err
+	}
+	return nil
+}
+
+// DeleteSequenceStateCollection godoc
+func (mdbrepo *SequenceStateMongoDBRepo) DeleteSequenceStateCollection(project string) error {
+	err := mdbrepo.DbConnection.EnsureDBConnection()
+	if err != nil {
+		return err
+	}
+	collection := mdbrepo.getSequenceStateCollection(project)
+
+	mdbrepo.Logger.Debug(fmt.Sprintf("Delete collection: %s", collection.Name()))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := collection.Drop(ctx); err != nil {
+		err := fmt.Errorf("failed to drop collection %s: %v", collection.Name(), err)
+		mdbrepo.Logger.Error(err.Error())
+		return err
+	}
+	return nil
+}
+
+func (mdbrepo *SequenceStateMongoDBRepo) getSequenceStateCollection(project string) *mongo.Collection {
+	return mdbrepo.DbConnection.Client.Database(databaseName).Collection(project + sequenceStateCollectionNameSuffix)
+}
+
+func getSequenceStateSearchOptions(filter SequenceStateFilter) bson.M {
+	searchOptions := bson.M{}
+	if filter.KeptnContext != nil && *filter.KeptnContext != "" {
+		searchOptions["shkeptncontext"] = *filter.KeptnContext
+	}
+	if filter.Stage != nil && *filter.Stage != "" {
+		searchOptions["stage"] = *filter.Stage
+	}
+	if filter.Service != nil && *filter.Service != "" {
+		searchOptions["service"] = *filter.Service
+	}
+	if filter.Name != nil && *filter.Name != "" {
+		searchOptions["name"] = *filter.Name
+	}
+	if filter.State != nil && *filter.State != "" {
+		searchOptions["state"] = *filter.State
+	}
+	return searchOptions
+}
@@ -0,0 +1,15 @@
+package db
+
+import "github.com/keptn/keptn/shipyard-controller/models"
+
+// PausedSequenceRepo is an interface for retrieving and storing paused task sequences
+type PausedSequenceRepo interface {
+	// GetPausedSequences returns the paused task sequences of a project with the given keptnContext
+	GetPausedSequences(project, keptnContext string) ([]models.PausedSequence, error)
+	// UpsertPausedSequence creates or updates a paused task sequence
+	UpsertPausedSequence(project string, pausedSequence models.PausedSequence) error
+	// DeletePausedSequence deletes a paused task sequence
+	DeletePausedSequence(project, keptnContext, stage string) error
+	// DeletePausedSequenceCollection godoc
+	DeletePausedSequenceCollection(project string) error
+}
@@ -0,0 +1,32 @@
+package db
+
+import (
+	"errors"
+	"github.com/keptn/keptn/shipyard-controller/models"
+)
+
+// ErrScheduleNotFound is returned if no schedule with the given ID exists
+var ErrScheduleNotFound = errors.New("schedule not found")
+
+// ScheduleFilter allows to pass filters
+type ScheduleFilter struct {
+	Stage    *string
+	Service  *string
+	Sequence *string
+}
+
+// ScheduleRepo is an interface for retrieving and storing schedules of task sequences
+type ScheduleRepo interface {
+	// GetSchedules returns the schedules of a project, based on the provided filter
+	GetSchedules(project string, filter ScheduleFilter) ([]models.Schedule, error)
+	// GetSchedule returns the schedule with the given ID
+	GetSchedule(project, scheduleID string) (*models.Schedule, error)
+	// CreateSchedule stores a new schedule
+	CreateSchedule(project string, schedule models.Schedule) error
+	// UpdateSchedule updates an existing schedule
+	UpdateSchedule(project string, schedule models.Schedule) error
+	// DeleteSchedule deletes the schedule with the given ID
+	DeleteSchedule(project, scheduleID string) error
+	// DeleteScheduleCollection godoc
+	DeleteScheduleCollection(project string) error
+}
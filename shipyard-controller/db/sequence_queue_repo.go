@@ -0,0 +1,22 @@
+package db
+
+import "github.com/keptn/keptn/shipyard-controller/models"
+
+// SequenceQueueFilter allows to pass filters
+type SequenceQueueFilter struct {
+	KeptnContext *string
+	Stage        *string
+	Service      *string
+}
+
+// SequenceQueueRepo is an interface for retrieving and storing queued task sequences
+type SequenceQueueRepo interface {
+	// GetQueuedSequences returns the queued task sequences of a project, based on the provided filter. The sequences are sorted by the time they have been queued
+	GetQueuedSequences(project string, filter SequenceQueueFilter) ([]models.QueuedSequence, error)
+	// QueueSequence adds a task sequence to the queue
+	QueueSequence(project string, queuedSequence models.QueuedSequence) error
+	// DeleteQueuedSequence removes a task sequence from the queue
+	DeleteQueuedSequence(project, keptnContext, stage, taskSequenceName string) error
+	// DeleteSequenceQueueCollection godoc
+	DeleteSequenceQueueCollection(project string) error
+}
@@ -0,0 +1,22 @@
+package db
+
+import "github.com/keptn/keptn/shipyard-controller/models"
+
+// SequenceStateFilter allows to pass filters
+type SequenceStateFilter struct {
+	KeptnContext *string
+	Stage        *string
+	Service      *string
+	Name         *string
+	State        *string
+}
+
+// SequenceStateRepo is an interface for retrieving and storing the states of task sequences
+type SequenceStateRepo interface {
+	// GetSequenceStates returns the states of the task sequences of a project, based on the provided filter
+	GetSequenceStates(project string, filter SequenceStateFilter) ([]models.SequenceState, error)
+	// UpsertSequenceState creates or updates the state of a task sequence
+	UpsertSequenceState(project string, state models.SequenceState) error
+	// DeleteSequenceStateCollection godoc
+	DeleteSequenceStateCollection(project string) error
+}
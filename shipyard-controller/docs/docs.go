@@ -414,6 +414,482 @@ var doc = `{
                     }
                 }
             }
+        },
+        "/schedule/{project}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the schedules of task sequences in a project",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedule"
+                ],
+                "summary": "Get the schedules of a project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stage",
+                        "name": "stage",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Service",
+                        "name": "service",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the task sequence",
+                        "name": "sequence",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The number of items to return",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pointer to the next set of items",
+                        "name": "nextPageKey",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/models.Schedules"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a schedule that periodically triggers a task sequence, based on a cron expression",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedule"
+                ],
+                "summary": "Create a schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schedule",
+                        "name": "schedule",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/operations.CreateScheduleParams"
+                        },
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/models.Schedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedule/{project}/{scheduleId}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the schedule with the given ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedule"
+                ],
+                "summary": "Get a schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "scheduleId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/models.Schedule"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update the cron expression and labels of a schedule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedule"
+                ],
+                "summary": "Update a schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "scheduleId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schedule",
+                        "name": "schedule",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/operations.UpdateScheduleParams"
+                        },
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/models.Schedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete the schedule with the given ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedule"
+                ],
+                "summary": "Delete a schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "scheduleId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/operations.DeleteScheduleResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/sequence/{project}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the state of the task sequences of a project, including the timeline of their tasks",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sequence"
+                ],
+                "summary": "Get the state of task sequences",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Keptn Context",
+                        "name": "keptnContext",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stage",
+                        "name": "stage",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Service",
+                        "name": "service",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the task sequence",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "State of the task sequence",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The number of items to return",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pointer to the next set of items",
+                        "name": "nextPageKey",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/models.SequenceStates"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/sequence/{project}/{keptnContext}/control": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Control the state of the task sequence(s) with the given keptnContext, i.e. abort, pause or resume them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sequence"
+                ],
+                "summary": "Control the state of a task sequence",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project",
+                        "name": "project",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Keptn Context",
+                        "name": "keptnContext",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Sequence control",
+                        "name": "control",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/operations.SequenceControlParams"
+                        },
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/operations.SequenceControlResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
         }
     },
     "definitions": {
@@ -499,6 +975,221 @@ var doc = `{
                 }
             }
         },
+        "models.Schedule": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "CreatedAt is the time the schedule has been created",
+                    "type": "string"
+                },
+                "cron": {
+                    "description": "Cron is the cron expression defining when the task sequence is triggered",
+                    "type": "string"
+                },
+                "evaluation": {
+                    "description": "Evaluation defines the timeframe of the evaluations of the task sequence",
+                    "$ref": "#/definitions/models.ScheduleEvaluation"
+                },
+                "id": {
+                    "description": "ID of the schedule",
+                    "type": "string"
+                },
+                "labels": {
+                    "description": "Labels that are added to the events triggering the task sequence",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "lastKeptnContext": {
+                    "description": "LastKeptnContext is the Keptn context of the task sequence that has been triggered the last time",
+                    "type": "string"
+                },
+                "lastTriggeredAt": {
+                    "description": "LastTriggeredAt is the time the task sequence has been triggered the last time",
+                    "type": "string"
+                },
+                "nextTriggerAt": {
+                    "description": "NextTriggerAt is the time the task sequence will be triggered next",
+                    "type": "string"
+                },
+                "project": {
+                    "description": "Project of the task sequence",
+                    "type": "string"
+                },
+                "sequence": {
+                    "description": "Sequence is the name of the task sequence",
+                    "type": "string"
+                },
+                "service": {
+                    "description": "Service of the task sequence",
+                    "type": "string"
+                },
+                "stage": {
+                    "description": "Stage of the task sequence",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ScheduleEvaluation": {
+            "type": "object",
+            "properties": {
+                "timeframe": {
+                    "description": "Timeframe is the duration before the trigger time that is evaluated, e.g. 15m",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Schedules": {
+            "type": "object",
+            "properties": {
+                "nextPageKey": {
+                    "description": "Pointer to next page, base64 encoded",
+                    "type": "string"
+                },
+                "pageSize": {
+                    "description": "Size of returned page",
+                    "type": "number"
+                },
+                "schedules": {
+                    "description": "schedules",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Schedule"
+                    }
+                },
+                "totalCount": {
+                    "description": "Total number of schedules",
+                    "type": "number"
+                }
+            }
+        },
+        "models.SequenceState": {
+            "type": "object",
+            "properties": {
+                "currentTask": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "project": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "string"
+                },
+                "service": {
+                    "type": "string"
+                },
+                "shkeptncontext": {
+                    "type": "string"
+                },
+                "stage": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SequenceStateTask"
+                    }
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SequenceStateExecutor": {
+            "type": "object",
+            "properties": {
+                "finishedAt": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SequenceStateTask": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "type": "integer"
+                },
+                "executors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SequenceStateExecutor"
+                    }
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "string"
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "triggeredAt": {
+                    "type": "string"
+                },
+                "triggeredID": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SequenceStates": {
+            "type": "object",
+            "properties": {
+                "nextPageKey": {
+                    "description": "Pointer to next page, base64 encoded",
+                    "type": "string"
+                },
+                "pageSize": {
+                    "description": "Size of returned page",
+                    "type": "number"
+                },
+                "states": {
+                    "description": "states",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SequenceState"
+                    }
+                },
+                "totalCount": {
+                    "description": "Total number of states",
+                    "type": "number"
+                }
+            }
+        },
         "operations.CreateProjectParams": {
             "type": "object",
             "properties": {
@@ -527,6 +1218,38 @@ var doc = `{
         "operations.CreateProjectResponse": {
             "type": "object"
         },
+        "operations.CreateScheduleParams": {
+            "type": "object",
+            "properties": {
+                "cron": {
+                    "description": "cron expression, e.g. '0 2 * * *' or '@daily'\nRequired: true",
+                    "type": "string"
+                },
+                "evaluation": {
+                    "description": "timeframe of the evaluations of the task sequence, required if the task sequence contains an evaluation",
+                    "$ref": "#/definitions/models.ScheduleEvaluation"
+                },
+                "labels": {
+                    "description": "labels that are added to the events triggering the task sequence",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "sequence": {
+                    "description": "name of the task sequence\nRequired: true",
+                    "type": "string"
+                },
+                "service": {
+                    "description": "service\nRequired: true",
+                    "type": "string"
+                },
+                "stage": {
+                    "description": "stage\nRequired: true",
+                    "type": "string"
+                }
+            }
+        },
         "operations.CreateServiceParams": {
             "type": "object",
             "properties": {
@@ -551,6 +1274,9 @@ var doc = `{
                 }
             }
         },
+        "operations.DeleteScheduleResponse": {
+            "type": "object"
+        },
         "operations.DeleteServiceResponse": {
             "type": "object",
             "properties": {
@@ -558,6 +1284,42 @@ var doc = `{
                     "type": "string"
                 }
             }
+        },
+        "operations.SequenceControlParams": {
+            "type": "object",
+            "properties": {
+                "stage": {
+                    "description": "stage, if not set, the task sequences of all stages are affected",
+                    "type": "string"
+                },
+                "state": {
+                    "description": "state\nRequired: true",
+                    "type": "string"
+                }
+            }
+        },
+        "operations.SequenceControlResponse": {
+            "type": "object"
+        },
+        "operations.UpdateScheduleParams": {
+            "type": "object",
+            "properties": {
+                "cron": {
+                    "description": "cron expression, e.g. '0 2 * * *' or '@daily'\nRequired: true",
+                    "type": "string"
+                },
+                "evaluation": {
+                    "description": "timeframe of the evaluations of the task sequence, required if the task sequence contains an evaluation",
+                    "$ref": "#/definitions/models.ScheduleEvaluation"
+                },
+                "labels": {
+                    "description": "labels that are added to the events triggering the task sequence",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
         }
     },
     "securityDefinitions": {
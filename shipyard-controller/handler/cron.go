@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronExpressionMacros maps the supported shorthand expressions to their standard five field representation
+var cronExpressionMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type cronField struct {
+	name string
+	min  int
+	max  int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 6},
+}
+
+// cronSchedule is a parsed cron expression consisting of the fields minute, hour, day of month, month and day of week
+type cronSchedule struct {
+	minutes     map[int]bool
+	hours       map[int]bool
+	daysOfMonth map[int]bool
+	months      map[int]bool
+	daysOfWeek  map[int]bool
+	// restrictedDays is true if both the day of month and the day of week are restricted, i.e. do not start with '*'. In this case,
+	// a day matches if either of them matches
+	restrictedDays bool
+}
+
+// parseCronExpression parses a standard cron expression with five fields, e.g. '0 2 * * 1-5', or one of the macros @yearly, @monthly, @weekly, @daily and @hourly
+func parseCronExpression(expression string) (*cronSchedule, error) {
+	expression = strings.TrimSpace(expression)
+	if macro, ok := cronExpressionMacros[expression]; ok {
+		expression = macro
+	}
+	fields := strings.Fields(expression)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("invalid cron expression '%s': expected %d fields but got %d", expression, len(cronFields), len(fields))
+	}
+
+	values := make([]map[int]bool, len(cronFields))
+	for index, field := range fields {
+		parsedValues, err := parseCronField(field, cronFields[index])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression '%s': %s", expression, err.Error())
+		}
+		values[index] = parsedValues
+	}
+
+	// 7 is an alias for sunday
+	if values[4][7] {
+		values[4][0] = true
+		delete(values[4], 7)
+	}
+
+	return &cronSchedule{
+		minutes:        values[0],
+		hours:          values[1],
+		daysOfMonth:    values[2],
+		months:         values[3],
+		daysOfWeek:     values[4],
+		restrictedDays: !strings.HasPrefix(fields[2], "*") && !strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+func parseCronField(field string, fieldSpec cronField) (map[int]bool, error) {
+	max := fieldSpec.max
+	if fieldSpec.name == "day of week" {
+		// allow 7 as an alias for sunday
+		max = 7
+	}
+	result := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		rangePart := part
+		step := 1
+		if split := strings.Split(part, "/"); len(split) == 2 {
+			parsedStep, err := strconv.Atoi(split[1])
+			if err != nil || parsedStep <= 0 {
+				return nil, fmt.Errorf("invalid step '%s' in %s field", split[1], fieldSpec.name)
+			}
+			rangePart = split[0]
+			step = parsedStep
+		} else if len(split) > 2 {
+			return nil, fmt.Errorf("invalid value '%s' in %s field", part, fieldSpec.name)
+		}
+
+		var start, end int
+		if rangePart == "*" {
+			start = fieldSpec.min
+			end = fieldSpec.max
+		} else if split := strings.Split(rangePart, "-"); len(split) == 2 {
+			var err error
+			if start, err = strconv.Atoi(split[0]); err != nil {
+				return nil, fmt.Errorf("invalid value '%s' in %s field", split[0], fieldSpec.name)
+			}
+			if end, err = strconv.Atoi(split[1]); err != nil {
+				return nil, fmt.Errorf("invalid value '%s' in %s field", split[1], fieldSpec.name)
+			}
+		} else {
+			value, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value '%s' in %s field", rangePart, fieldSpec.name)
+			}
+			start = value
+			end = value
+			if step > 1 {
+				// a value followed by a step, e.g. 5/15, is a range up to the maximum value
+				end = fieldSpec.max
+			}
+		}
+
+		if start < fieldSpec.min || end > max || start > end {
+			return nil, fmt.Errorf("value '%s' out of range [%d-%d] in %s field", rangePart, fieldSpec.min, fieldSpec.max, fieldSpec.name)
+		}
+		for value := start; value <= end; value += step {
+			result[value] = true
+		}
+	}
+	return result, nil
+}
+
+// next returns the first point in time after the given time that matches the schedule
+func (c *cronSchedule) next(after time.Time) (time.Time, error) {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	// a cron expression repeats at least every four years, i.e. no valid expression needs more iterations than that
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if !c.months[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.hours[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.minutes[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t, nil
+	}
+	return time.Time{}, errors.New("cron expression does not match any point in time")
+}
+
+func (c *cronSchedule) matchesDay(t time.Time) bool {
+	if c.restrictedDays {
+		return c.daysOfMonth[t.Day()] || c.daysOfWeek[int(t.Weekday())]
+	}
+	return c.daysOfMonth[t.Day()] && c.daysOfWeek[int(t.Weekday())]
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_parseCronExpression(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    bool
+	}{
+		{name: "every minute", expression: "* * * * *"},
+		{name: "lists, ranges and steps", expression: "0,30 8-18/2 1-15 */3 1-5"},
+		{name: "sunday as 7", expression: "0 0 * * 7"},
+		{name: "macro", expression: "@daily"},
+		{name: "too few fields", expression: "0 0 * *", wantErr: true},
+		{name: "value out of range", expression: "60 0 * * *", wantErr: true},
+		{name: "invalid step", expression: "*/0 0 * * *", wantErr: true},
+		{name: "invalid range", expression: "0 18-8 * * *", wantErr: true},
+		{name: "invalid value", expression: "0 noon * * *", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseCronExpression(tt.expression); (err != nil) != tt.wantErr {
+				t.Errorf("parseCronExpression() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_cronSchedule_next(t *testing.T) {
+	// 2021-02-10 is a wednesday
+	after := time.Date(2021, 2, 10, 10, 17, 30, 0, time.UTC)
+	tests := []struct {
+		name       string
+		expression string
+		want       time.Time
+	}{
+		{name: "every minute", expression: "* * * * *", want: time.Date(2021, 2, 10, 10, 18, 0, 0, time.UTC)},
+		{name: "every 15 minutes", expression: "*/15 * * * *", want: time.Date(2021, 2, 10, 10, 30, 0, 0, time.UTC)},
+		{name: "nightly", expression: "0 2 * * *", want: time.Date(2021, 2, 11, 2, 0, 0, 0, time.UTC)},
+		{name: "weekly on sunday", expression: "@weekly", want: time.Date(2021, 2, 14, 0, 0, 0, 0, time.UTC)},
+		{name: "weekdays", expression: "30 9 * * 1-5", want: time.Date(2021, 2, 11, 9, 30, 0, 0, time.UTC)},
+		{name: "first of month", expression: "0 0 1 * *", want: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "day of month or day of week", expression: "0 0 13 * 5", want: time.Date(2021, 2, 12, 0, 0, 0, 0, time.UTC)},
+		{name: "day of month with step starting with * and day of week", expression: "0 0 */2 * 1", want: time.Date(2021, 2, 15, 0, 0, 0, 0, time.UTC)},
+		{name: "day of month range with step or day of week", expression: "0 0 1-31/2 * 1", want: time.Date(2021, 2, 11, 0, 0, 0, 0, time.UTC)},
+		{name: "day of month and day of week with step starting with *", expression: "0 0 13 * */5", want: time.Date(2021, 6, 13, 0, 0, 0, 0, time.UTC)},
+		{name: "leap day", expression: "0 0 29 2 *", want: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cron, err := parseCronExpression(tt.expression)
+			if err != nil {
+				t.Fatalf("parseCronExpression() returned %v", err)
+			}
+			got, err := cron.next(after)
+			if err != nil {
+				t.Fatalf("next() returned %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	cron, _ := parseCronExpression("0 0 31 2 *")
+	if _, err := cron.next(after); err == nil {
+		t.Errorf("next() expected an error for an expression that never matches")
+	}
+}
@@ -149,3 +149,11 @@ func sendBadRequestResponse(err error, c *gin.Context) {
 		Message: &msg,
 	})
 }
+
+func sendNotFoundResponse(err error, c *gin.Context) {
+	msg := err.Error()
+	c.JSON(http.StatusNotFound, models.Error{
+		Code:    http.StatusNotFound,
+		Message: &msg,
+	})
+}
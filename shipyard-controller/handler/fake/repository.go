@@ -3,16 +3,25 @@ package fake
 import (
 	"github.com/keptn/keptn/shipyard-controller/db"
 	"github.com/keptn/keptn/shipyard-controller/models"
+	"time"
 )
 
 type getEventsMock func(project string, filter db.EventFilter, status db.EventStatus) ([]models.Event, error)
 type insertEventMock func(project string, event models.Event, status db.EventStatus) error
+type insertDelayedEventMock func(project string, event models.Event, status db.EventStatus, notBefore time.Time) error
+type getDueDelayedEventsMock func(project string, status db.EventStatus, now time.Time) ([]models.Event, error)
+type setDelayedEventSentMock func(project string, eventID string, status db.EventStatus) error
+type getEventInsertionTimeMock func(project string, eventID string, status db.EventStatus) (time.Time, error)
 type deleteEventMock func(project string, eventID string, status db.EventStatus) error
 type deleteEventCollectionsMock func(project string) error
 
 type EventRepository struct {
 	GetEventsFunc              getEventsMock
 	InsertEventFunc            insertEventMock
+	InsertDelayedEventFunc     insertDelayedEventMock
+	GetDueDelayedEventsFunc    getDueDelayedEventsMock
+	SetDelayedEventSentFunc    setDelayedEventSentMock
+	GetEventInsertionTimeFunc  getEventInsertionTimeMock
 	DeleteEventFunc            deleteEventMock
 	DeleteEventCollectionsFunc deleteEventCollectionsMock
 }
@@ -25,6 +34,22 @@ func (t EventRepository) InsertEvent(project string, event models.Event, status
 	return t.InsertEventFunc(project, event, status)
 }
 
+func (t EventRepository) InsertDelayedEvent(project string, event models.Event, status db.EventStatus, notBefore time.Time) error {
+	return t.InsertDelayedEventFunc(project, event, status, notBefore)
+}
+
+func (t EventRepository) GetDueDelayedEvents(project string, status db.EventStatus, now time.Time) ([]models.Event, error) {
+	return t.GetDueDelayedEventsFunc(project, status, now)
+}
+
+func (t EventRepository) SetDelayedEventSent(project string, eventID string, status db.EventStatus) error {
+	return t.SetDelayedEventSentFunc(project, eventID, status)
+}
+
+func (t EventRepository) GetEventInsertionTime(project string, eventID string, status db.EventStatus) (time.Time, error) {
+	return t.GetEventInsertionTimeFunc(project, eventID, status)
+}
+
 func (t EventRepository) DeleteEvent(project string, eventID string, status db.EventStatus) error {
 	return t.DeleteEventFunc(project, eventID, status)
 }
@@ -59,6 +84,70 @@ func (mts TaskSequenceRepository) DeleteTaskSequenceCollection(project string) e
 	return mts.DeleteTaskSequenceCollectionFunc(project)
 }
 
+type PausedSequenceRepository struct {
+	GetPausedSequencesFunc             func(project, keptnContext string) ([]models.PausedSequence, error)
+	UpsertPausedSequenceFunc           func(project string, pausedSequence models.PausedSequence) error
+	DeletePausedSequenceFunc           func(project, keptnContext, stage string) error
+	DeletePausedSequenceCollectionFunc func(project string) error
+}
+
+func (p PausedSequenceRepository) GetPausedSequences(project, keptnContext string) ([]models.PausedSequence, error) {
+	return p.GetPausedSequencesFunc(project, keptnContext)
+}
+
+func (p PausedSequenceRepository) UpsertPausedSequence(project string, pausedSequence models.PausedSequence) error {
+	return p.UpsertPausedSequenceFunc(project, pausedSequence)
+}
+
+func (p PausedSequenceRepository) DeletePausedSequence(project, keptnContext, stage string) error {
+	return p.DeletePausedSequenceFunc(project, keptnContext, stage)
+}
+
+func (p PausedSequenceRepository) DeletePausedSequenceCollection(project string) error {
+	return p.DeletePausedSequenceCollectionFunc(project)
+}
+
+type SequenceStateRepository struct {
+	GetSequenceStatesFunc             func(project string, filter db.SequenceStateFilter) ([]models.SequenceState, error)
+	UpsertSequenceStateFunc           func(project string, state models.SequenceState) error
+	DeleteSequenceStateCollectionFunc func(project string) error
+}
+
+func (s SequenceStateRepository) GetSequenceStates(project string, filter db.SequenceStateFilter) ([]models.SequenceState, error) {
+	return s.GetSequenceStatesFunc(project, filter)
+}
+
+func (s SequenceStateRepository) UpsertSequenceState(project string, state models.SequenceState) error {
+	return s.UpsertSequenceStateFunc(project, state)
+}
+
+func (s SequenceStateRepository) DeleteSequenceStateCollection(project string) error {
+	return s.DeleteSequenceStateCollectionFunc(project)
+}
+
+type SequenceQueueRepository struct {
+	GetQueuedSequencesFunc            func(project string, filter db.SequenceQueueFilter) ([]models.QueuedSequence, error)
+	QueueSequenceFunc                 func(project string, queuedSequence models.QueuedSequence) error
+	DeleteQueuedSequenceFunc          func(project, keptnContext, stage, taskSequenceName string) error
+	DeleteSequenceQueueCollectionFunc func(project string) error
+}
+
+func (s SequenceQueueRepository) GetQueuedSequences(project string, filter db.SequenceQueueFilter) ([]models.QueuedSequence, error) {
+	return s.GetQueuedSequencesFunc(project, filter)
+}
+
+func (s SequenceQueueRepository) QueueSequence(project string, queuedSequence models.QueuedSequence) error {
+	return s.QueueSequenceFunc(project, queuedSequence)
+}
+
+func (s SequenceQueueRepository) DeleteQueuedSequence(project, keptnContext, stage, taskSequenceName string) error {
+	return s.DeleteQueuedSequenceFunc(project, keptnContext, stage, taskSequenceName)
+}
+
+func (s SequenceQueueRepository) DeleteSequenceQueueCollection(project string) error {
+	return s.DeleteSequenceQueueCollectionFunc(project)
+}
+
 type getProjectsMock func() ([]string, error)
 
 type ProjectRepository struct {
@@ -68,3 +157,36 @@ type ProjectRepository struct {
 func (p ProjectRepository) GetProjects() ([]string, error) {
 	return p.GetProjectsFunc()
 }
+
+type ScheduleRepository struct {
+	GetSchedulesFunc             func(project string, filter db.ScheduleFilter) ([]models.Schedule, error)
+	GetScheduleFunc              func(project, scheduleID string) (*models.Schedule, error)
+	CreateScheduleFunc           func(project string, schedule models.Schedule) error
+	UpdateScheduleFunc           func(project string, schedule models.Schedule) error
+	DeleteScheduleFunc           func(project, scheduleID string) error
+	DeleteScheduleCollectionFunc func(project string) error
+}
+
+func (s ScheduleRepository) GetSchedules(project string, filter db.ScheduleFilter) ([]models.Schedule, error) {
+	return s.GetSchedulesFunc(project, filter)
+}
+
+func (s ScheduleRepository) GetSchedule(project, scheduleID string) (*models.Schedule, error) {
+	return s.GetScheduleFunc(project, scheduleID)
+}
+
+func (s ScheduleRepository) CreateSchedule(project string, schedule models.Schedule) error {
+	return s.CreateScheduleFunc(project, schedule)
+}
+
+func (s ScheduleRepository) UpdateSchedule(project string, schedule models.Schedule) error {
+	return s.UpdateScheduleFunc(project, schedule)
+}
+
+func (s ScheduleRepository) DeleteSchedule(project, scheduleID string) error {
+	return s.DeleteScheduleFunc(project, scheduleID)
+}
+
+func (s ScheduleRepository) DeleteScheduleCollection(project string) error {
+	return s.DeleteScheduleCollectionFunc(project)
+}
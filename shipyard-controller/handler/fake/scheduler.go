@@ -0,0 +1,35 @@
+package fake
+
+import (
+	"github.com/keptn/keptn/shipyard-controller/db"
+	"github.com/keptn/keptn/shipyard-controller/models"
+	"github.com/keptn/keptn/shipyard-controller/operations"
+)
+
+type Scheduler struct {
+	GetSchedulesFunc   func(project string, filter db.ScheduleFilter) ([]models.Schedule, error)
+	GetScheduleFunc    func(project, scheduleID string) (*models.Schedule, error)
+	CreateScheduleFunc func(project string, params operations.CreateScheduleParams) (*models.Schedule, error)
+	UpdateScheduleFunc func(project, scheduleID string, params operations.UpdateScheduleParams) (*models.Schedule, error)
+	DeleteScheduleFunc func(project, scheduleID string) error
+}
+
+func (s *Scheduler) GetSchedules(project string, filter db.ScheduleFilter) ([]models.Schedule, error) {
+	return s.GetSchedulesFunc(project, filter)
+}
+
+func (s *Scheduler) GetSchedule(project, scheduleID string) (*models.Schedule, error) {
+	return s.GetScheduleFunc(project, scheduleID)
+}
+
+func (s *Scheduler) CreateSchedule(project string, params operations.CreateScheduleParams) (*models.Schedule, error) {
+	return s.CreateScheduleFunc(project, params)
+}
+
+func (s *Scheduler) UpdateSchedule(project, scheduleID string, params operations.UpdateScheduleParams) (*models.Schedule, error) {
+	return s.UpdateScheduleFunc(project, scheduleID, params)
+}
+
+func (s *Scheduler) DeleteSchedule(project, scheduleID string) error {
+	return s.DeleteScheduleFunc(project, scheduleID)
+}
@@ -9,6 +9,10 @@ type ShipyardController struct {
 	GetAllTriggeredEventsFunc       func(filter db.EventFilter) ([]models.Event, error)
 	GetTriggeredEventsOfProjectFunc func(project string, filter db.EventFilter) ([]models.Event, error)
 	HandleIncomingEventFunc         func(event models.Event) error
+	AbortSequenceFunc               func(project, keptnContext, stage string) error
+	PauseSequenceFunc               func(project, keptnContext, stage string) error
+	ResumeSequenceFunc              func(project, keptnContext, stage string) error
+	GetSequenceStatesFunc           func(project string, filter db.SequenceStateFilter) ([]models.SequenceState, error)
 }
 
 func (s *ShipyardController) GetAllTriggeredEvents(filter db.EventFilter) ([]models.Event, error) {
@@ -22,3 +26,19 @@ func (s *ShipyardController) GetTriggeredEventsOfProject(project string, filter
 func (s *ShipyardController) HandleIncomingEvent(event models.Event) error {
 	return s.HandleIncomingEventFunc(event)
 }
+
+func (s *ShipyardController) AbortSequence(project, keptnContext, stage string) error {
+	return s.AbortSequenceFunc(project, keptnContext, stage)
+}
+
+func (s *ShipyardController) PauseSequence(project, keptnContext, stage string) error {
+	return s.PauseSequenceFunc(project, keptnContext, stage)
+}
+
+func (s *ShipyardController) ResumeSequence(project, keptnContext, stage string) error {
+	return s.ResumeSequenceFunc(project, keptnContext, stage)
+}
+
+func (s *ShipyardController) GetSequenceStates(project string, filter db.SequenceStateFilter) ([]models.SequenceState, error) {
+	return s.GetSequenceStatesFunc(project, filter)
+}
@@ -30,13 +30,29 @@ func newProjectManager() (*projectManager, error) {
 		taskSequenceRepo: &db.TaskSequenceMongoDBRepo{
 			Logger: base.logger,
 		},
+		pausedSequenceRepo: &db.PausedSequenceMongoDBRepo{
+			Logger: base.logger,
+		},
+		sequenceStateRepo: &db.SequenceStateMongoDBRepo{
+			Logger: base.logger,
+		},
+		sequenceQueueRepo: &db.SequenceQueueMongoDBRepo{
+			Logger: base.logger,
+		},
+		scheduleRepo: &db.ScheduleMongoDBRepo{
+			Logger: base.logger,
+		},
 	}, nil
 }
 
 type projectManager struct {
 	*apiBase
-	eventRepo        db.EventRepo
-	taskSequenceRepo db.TaskSequenceRepo
+	eventRepo          db.EventRepo
+	taskSequenceRepo   db.TaskSequenceRepo
+	pausedSequenceRepo db.PausedSequenceRepo
+	sequenceStateRepo  db.SequenceStateRepo
+	sequenceQueueRepo  db.SequenceQueueRepo
+	scheduleRepo       db.ScheduleRepo
 }
 
 type gitCredentials struct {
@@ -89,6 +105,22 @@ func (pm *projectManager) deleteProject(projectName string) (*operations.DeleteP
 		pm.logger.Error("could not delete task sequence collection: " + err.Error())
 	}
 
+	if err := pm.pausedSequenceRepo.DeletePausedSequenceCollection(projectName); err != nil {
+		pm.logger.Error("could not delete paused sequence collection: " + err.Error())
+	}
+
+	if err := pm.sequenceStateRepo.DeleteSequenceStateCollection(projectName); err != nil {
+		pm.logger.Error("could not delete sequence state collection: " + err.Error())
+	}
+
+	if err := pm.sequenceQueueRepo.DeleteSequenceQueueCollection(projectName); err != nil {
+		pm.logger.Error("could not delete sequence queue collection: " + err.Error())
+	}
+
+	if err := pm.scheduleRepo.DeleteScheduleCollection(projectName); err != nil {
+		pm.logger.Error("could not delete schedule collection: " + err.Error())
+	}
+
 	if err := pm.eventRepo.DeleteEventCollections(projectName); err != nil {
 		pm.logger.Error("could not delete event collections: " + err.Error())
 	}
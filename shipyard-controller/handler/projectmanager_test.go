@@ -398,6 +398,26 @@ func Test_projectManager_DeleteProject(t *testing.T) {
 				return nil
 			},
 		},
+		pausedSequenceRepo: &fake.PausedSequenceRepository{
+			DeletePausedSequenceCollectionFunc: func(project string) error {
+				return nil
+			},
+		},
+		sequenceStateRepo: &fake.SequenceStateRepository{
+			DeleteSequenceStateCollectionFunc: func(project string) error {
+				return nil
+			},
+		},
+		sequenceQueueRepo: &fake.SequenceQueueRepository{
+			DeleteSequenceQueueCollectionFunc: func(project string) error {
+				return nil
+			},
+		},
+		scheduleRepo: &fake.ScheduleRepository{
+			DeleteScheduleCollectionFunc: func(project string) error {
+				return nil
+			},
+		},
 	}
 
 	_, _ = pm.deleteProject("my-project")
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/keptn/keptn/shipyard-controller/common"
+	"github.com/keptn/keptn/shipyard-controller/db"
+	"github.com/keptn/keptn/shipyard-controller/models"
+	"github.com/keptn/keptn/shipyard-controller/operations"
+	"net/http"
+)
+
+type IScheduleHandler interface {
+	GetSchedules(context *gin.Context)
+	GetSchedule(context *gin.Context)
+	CreateSchedule(context *gin.Context)
+	UpdateSchedule(context *gin.Context)
+	DeleteSchedule(context *gin.Context)
+}
+
+type ScheduleHandler struct {
+	Scheduler IScheduler
+}
+
+// GetSchedules godoc
+// @Summary Get the schedules of a project
+// @Description Get the schedules of task sequences in a project
+// @Tags Schedule
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   project     path    string     true        "Project"
+// @Param   stage     query    string     false        "Stage"
+// @Param   service     query    string     false        "Service"
+// @Param   sequence     query    string     false        "Name of the task sequence"
+// @Param   pageSize     query    int     false        "The number of items to return"
+// @Param   nextPageKey     query    string     false        "Pointer to the next set of items"
+// @Success 200 {object} models.Schedules	"ok"
+// @Failure 400 {object} models.Error "Invalid payload"
+// @Failure 500 {object} models.Error "Internal error"
+// @Router /schedule/{project} [get]
+func (sh *ScheduleHandler) GetSchedules(c *gin.Context) {
+	project := c.Param("project")
+
+	params := &operations.GetSchedulesParams{}
+	if err := c.ShouldBindQuery(params); err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Code:    400,
+			Message: stringp("Invalid request format: " + err.Error()),
+		})
+		return
+	}
+
+	schedules, err := sh.Scheduler.GetSchedules(project, db.ScheduleFilter{
+		Stage:    params.Stage,
+		Service:  params.Service,
+		Sequence: params.Sequence,
+	})
+	if err != nil {
+		sendInternalServerErrorResponse(err, c)
+		return
+	}
+
+	var payload = &models.Schedules{
+		Schedules:   []models.Schedule{},
+		NextPageKey: "0",
+	}
+
+	paginationInfo := common.Paginate(len(schedules), params.PageSize, params.NextPageKey)
+	totalCount := len(schedules)
+	if paginationInfo.NextPageKey < int64(totalCount) {
+		payload.Schedules = append(payload.Schedules, schedules[paginationInfo.NextPageKey:paginationInfo.EndIndex]...)
+	}
+
+	payload.PageSize = float64(len(payload.Schedules))
+	payload.TotalCount = float64(totalCount)
+	payload.NextPageKey = paginationInfo.NewNextPageKey
+	c.JSON(http.StatusOK, payload)
+}
+
+// GetSchedule godoc
+// @Summary Get a schedule
+// @Description Get the schedule with the given ID
+// @Tags Schedule
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   project     path    string     true        "Project"
+// @Param   scheduleId     path    string     true        "Schedule ID"
+// @Success 200 {object} models.Schedule	"ok"
+// @Failure 404 {object} models.Error "Not found"
+// @Failure 500 {object} models.Error "Internal error"
+// @Router /schedule/{project}/{scheduleId} [get]
+func (sh *ScheduleHandler) GetSchedule(c *gin.Context) {
+	schedule, err := sh.Scheduler.GetSchedule(c.Param("project"), c.Param("scheduleId"))
+	if err != nil {
+		sendScheduleErrorResponse(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// CreateSchedule godoc
+// @Summary Create a schedule
+// @Description Create a schedule that periodically triggers a task sequence, based on a cron expression
+// @Tags Schedule
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   project     path    string     true        "Project"
+// @Param   schedule     body    operations.CreateScheduleParams     true        "Schedule"
+// @Success 200 {object} models.Schedule	"ok"
+// @Failure 400 {object} models.Error "Invalid payload"
+// @Failure 500 {object} models.Error "Internal error"
+// @Router /schedule/{project} [post]
+func (sh *ScheduleHandler) CreateSchedule(c *gin.Context) {
+	params := &operations.CreateScheduleParams{}
+	if err := c.ShouldBindJSON(params); err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Code:    400,
+			Message: stringp("Invalid request format: " + err.Error()),
+		})
+		return
+	}
+
+	schedule, err := sh.Scheduler.CreateSchedule(c.Param("project"), *params)
+	if err != nil {
+		sendScheduleErrorResponse(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// UpdateSchedule godoc
+// @Summary Update a schedule
+// @Description Update the cron expression and labels of a schedule
+// @Tags Schedule
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   project     path    string     true        "Project"
+// @Param   scheduleId     path    string     true        "Schedule ID"
+// @Param   schedule     body    operations.UpdateScheduleParams     true        "Schedule"
+// @Success 200 {object} models.Schedule	"ok"
+// @Failure 400 {object} models.Error "Invalid payload"
+// @Failure 404 {object} models.Error "Not found"
+// @Failure 500 {object} models.Error "Internal error"
+// @Router /schedule/{project}/{scheduleId} [put]
+func (sh *ScheduleHandler) UpdateSchedule(c *gin.Context) {
+	params := &operations.UpdateScheduleParams{}
+	if err := c.ShouldBindJSON(params); err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Code:    400,
+			Message: stringp("Invalid request format: " + err.Error()),
+		})
+		return
+	}
+
+	schedule, err := sh.Scheduler.UpdateSchedule(c.Param("project"), c.Param("scheduleId"), *params)
+	if err != nil {
+		sendScheduleErrorResponse(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteSchedule godoc
+// @Summary Delete a schedule
+// @Description Delete the schedule with the given ID
+// @Tags Schedule
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   project     path    string     true        "Project"
+// @Param   scheduleId     path    string     true        "Schedule ID"
+// @Success 200 {object} operations.DeleteScheduleResponse	"ok"
+// @Failure 404 {object} models.Error "Not found"
+// @Failure 500 {object} models.Error "Internal error"
+// @Router /schedule/{project}/{scheduleId} [delete]
+func (sh *ScheduleHandler) DeleteSchedule(c *gin.Context) {
+	if err := sh.Scheduler.DeleteSchedule(c.Param("project"), c.Param("scheduleId")); err != nil {
+		sendScheduleErrorResponse(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, operations.DeleteScheduleResponse{})
+}
+
+func sendScheduleErrorResponse(err error, c *gin.Context) {
+	if err == db.ErrScheduleNotFound {
+		sendNotFoundResponse(err, c)
+	} else if _, ok := err.(errInvalidSchedule); ok {
+		sendBadRequestResponse(err, c)
+	} else {
+		sendInternalServerErrorResponse(err, c)
+	}
+}
+
+func NewScheduleHandler() IScheduleHandler {
+	return &ScheduleHandler{
+		Scheduler: GetSchedulerInstance(),
+	}
+}
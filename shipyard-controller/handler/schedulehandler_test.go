@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/keptn/keptn/shipyard-controller/db"
+	"github.com/keptn/keptn/shipyard-controller/handler/fake"
+	"github.com/keptn/keptn/shipyard-controller/models"
+	"github.com/keptn/keptn/shipyard-controller/operations"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScheduleHandler_CreateSchedule(t *testing.T) {
+	tests := []struct {
+		name             string
+		scheduler        IScheduler
+		payload          string
+		expectStatusCode int
+	}{
+		{
+			name: "create schedule",
+			scheduler: &fake.Scheduler{
+				CreateScheduleFunc: func(project string, params operations.CreateScheduleParams) (*models.Schedule, error) {
+					return &models.Schedule{ID: "my-schedule", Project: project, Cron: params.Cron}, nil
+				},
+			},
+			payload:          `{"stage":"dev","service":"carts","sequence":"delivery","cron":"0 2 * * *"}`,
+			expectStatusCode: http.StatusOK,
+		},
+		{
+			name:             "return 400 on invalid payload",
+			scheduler:        &fake.Scheduler{},
+			payload:          `invalid`,
+			expectStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "return 400 on invalid schedule",
+			scheduler: &fake.Scheduler{
+				CreateScheduleFunc: func(project string, params operations.CreateScheduleParams) (*models.Schedule, error) {
+					return nil, errInvalidSchedule{reason: "invalid cron expression"}
+				},
+			},
+			payload:          `{"stage":"dev","service":"carts","sequence":"delivery","cron":"invalid"}`,
+			expectStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "return 500 on error",
+			scheduler: &fake.Scheduler{
+				CreateScheduleFunc: func(project string, params operations.CreateScheduleParams) (*models.Schedule, error) {
+					return nil, errors.New("")
+				},
+			},
+			payload:          `{"stage":"dev","service":"carts","sequence":"delivery","cron":"0 2 * * *"}`,
+			expectStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Request, _ = http.NewRequest(http.MethodPost, "", bytes.NewBufferString(tt.payload))
+			c.Params = gin.Params{
+				{Key: "project", Value: "test-project"},
+			}
+			handler := &ScheduleHandler{
+				Scheduler: tt.scheduler,
+			}
+
+			handler.CreateSchedule(c)
+			assert.Equal(t, tt.expectStatusCode, w.Code)
+		})
+	}
+}
+
+func TestScheduleHandler_GetSchedules(t *testing.T) {
+	tests := []struct {
+		name             string
+		scheduler        IScheduler
+		query            string
+		expectStatusCode int
+		expectSchedules  int
+	}{
+		{
+			name: "get schedules",
+			scheduler: &fake.Scheduler{
+				GetSchedulesFunc: func(project string, filter db.ScheduleFilter) ([]models.Schedule, error) {
+					return []models.Schedule{{ID: "nightly"}, {ID: "weekly"}}, nil
+				},
+			},
+			expectStatusCode: http.StatusOK,
+			expectSchedules:  2,
+		},
+		{
+			name: "get schedules with filter and page size",
+			scheduler: &fake.Scheduler{
+				GetSchedulesFunc: func(project string, filter db.ScheduleFilter) ([]models.Schedule, error) {
+					if filter.Stage == nil || *filter.Stage != "dev" || filter.Sequence == nil || *filter.Sequence != "delivery" {
+						return nil, errors.New("unexpected filter")
+					}
+					return []models.Schedule{{ID: "nightly"}, {ID: "weekly"}}, nil
+				},
+			},
+			query:            "?stage=dev&sequence=delivery&pageSize=1",
+			expectStatusCode: http.StatusOK,
+			expectSchedules:  1,
+		},
+		{
+			name: "return 500 on error",
+			scheduler: &fake.Scheduler{
+				GetSchedulesFunc: func(project string, filter db.ScheduleFilter) ([]models.Schedule, error) {
+					return nil, errors.New("")
+				},
+			},
+			expectStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Request, _ = http.NewRequest(http.MethodGet, "/schedule/test-project"+tt.query, nil)
+			c.Params = gin.Params{
+				{Key: "project", Value: "test-project"},
+			}
+			handler := &ScheduleHandler{
+				Scheduler: tt.scheduler,
+			}
+
+			handler.GetSchedules(c)
+			assert.Equal(t, tt.expectStatusCode, w.Code)
+
+			if tt.expectStatusCode == http.StatusOK {
+				schedules := &models.Schedules{}
+				err := json.Unmarshal(w.Body.Bytes(), schedules)
+				assert.Nil(t, err)
+				assert.Equal(t, tt.expectSchedules, len(schedules.Schedules))
+			}
+		})
+	}
+}
+
+func TestScheduleHandler_UpdateAndDeleteSchedule(t *testing.T) {
+	scheduler := &fake.Scheduler{
+		UpdateScheduleFunc: func(project, scheduleID string, params operations.UpdateScheduleParams) (*models.Schedule, error) {
+			if scheduleID != "nightly" {
+				return nil, db.ErrScheduleNotFound
+			}
+			return &models.Schedule{ID: scheduleID, Cron: params.Cron}, nil
+		},
+		DeleteScheduleFunc: func(project, scheduleID string) error {
+			if scheduleID != "nightly" {
+				return db.ErrScheduleNotFound
+			}
+			return nil
+		},
+	}
+	tests := []struct {
+		name             string
+		method           string
+		scheduleID       string
+		expectStatusCode int
+	}{
+		{name: "update schedule", method: http.MethodPut, scheduleID: "nightly", expectStatusCode: http.StatusOK},
+		{name: "update unknown schedule", method: http.MethodPut, scheduleID: "unknown", expectStatusCode: http.StatusNotFound},
+		{name: "delete schedule", method: http.MethodDelete, scheduleID: "nightly", expectStatusCode: http.StatusOK},
+		{name: "delete unknown schedule", method: http.MethodDelete, scheduleID: "unknown", expectStatusCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Request, _ = http.NewRequest(tt.method, "", bytes.NewBufferString(`{"cron":"0 3 * * *"}`))
+			c.Params = gin.Params{
+				{Key: "project", Value: "test-project"},
+				{Key: "scheduleId", Value: tt.scheduleID},
+			}
+			handler := &ScheduleHandler{
+				Scheduler: scheduler,
+			}
+
+			if tt.method == http.MethodPut {
+				handler.UpdateSchedule(c)
+			} else {
+				handler.DeleteSchedule(c)
+			}
+			assert.Equal(t, tt.expectStatusCode, w.Code)
+		})
+	}
+}
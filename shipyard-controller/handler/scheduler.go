@@ -0,0 +1,322 @@
+package handler
+
+import (
+	"fmt"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/shipyard-controller/common"
+	"github.com/keptn/keptn/shipyard-controller/db"
+	"github.com/keptn/keptn/shipyard-controller/models"
+	"github.com/keptn/keptn/shipyard-controller/operations"
+	"net/url"
+	"time"
+)
+
+const scheduleCheckInterval = 1 * time.Minute
+
+// schedulerEventSource is the source of the events that trigger scheduled task sequences
+const schedulerEventSource = "shipyard-controller-scheduler"
+
+// errInvalidSchedule is returned if a schedule contains an invalid cron expression or refers to a task sequence that does not exist
+type errInvalidSchedule struct {
+	reason string
+}
+
+func (e errInvalidSchedule) Error() string {
+	return "invalid schedule: " + e.reason
+}
+
+type IScheduler interface {
+	GetSchedules(project string, filter db.ScheduleFilter) ([]models.Schedule, error)
+	GetSchedule(project, scheduleID string) (*models.Schedule, error)
+	CreateSchedule(project string, params operations.CreateScheduleParams) (*models.Schedule, error)
+	UpdateSchedule(project, scheduleID string, params operations.UpdateScheduleParams) (*models.Schedule, error)
+	DeleteSchedule(project, scheduleID string) error
+}
+
+var schedulerInstance *scheduler
+
+type scheduler struct {
+	projectRepo  db.ProjectRepo
+	scheduleRepo db.ScheduleRepo
+	logger       *keptncommon.Logger
+}
+
+func GetSchedulerInstance() *scheduler {
+	if schedulerInstance == nil {
+		logger := keptncommon.NewLogger("", "", "shipyard-controller")
+		schedulerInstance = &scheduler{
+			projectRepo: &db.ProjectMongoDBRepo{
+				Logger: logger,
+			},
+			scheduleRepo: &db.ScheduleMongoDBRepo{
+				Logger: logger,
+			},
+			logger: logger,
+		}
+	}
+	return schedulerInstance
+}
+
+// GetSchedules returns the schedules of a project, including the time each of them will trigger its task sequence next
+func (s *scheduler) GetSchedules(project string, filter db.ScheduleFilter) ([]models.Schedule, error) {
+	schedules, err := s.scheduleRepo.GetSchedules(project, filter)
+	if err != nil {
+		return nil, err
+	}
+	for index := range schedules {
+		setNextTriggerTime(&schedules[index])
+	}
+	return schedules, nil
+}
+
+// GetSchedule returns the schedule with the given ID
+func (s *scheduler) GetSchedule(project, scheduleID string) (*models.Schedule, error) {
+	schedule, err := s.scheduleRepo.GetSchedule(project, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	setNextTriggerTime(schedule)
+	return schedule, nil
+}
+
+// CreateSchedule validates and stores a new schedule
+func (s *scheduler) CreateSchedule(project string, params operations.CreateScheduleParams) (*models.Schedule, error) {
+	schedule := models.Schedule{
+		ID:         uuid.New().String(),
+		Project:    project,
+		Stage:      params.Stage,
+		Service:    params.Service,
+		Sequence:   params.Sequence,
+		Cron:       params.Cron,
+		Labels:     params.Labels,
+		Evaluation: params.Evaluation,
+		CreatedAt:  time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := validateSchedule(schedule); err != nil {
+		return nil, err
+	}
+	s.logger.Info(fmt.Sprintf("Creating schedule for task sequence %s.%s of service %s in project %s: %s", schedule.Stage, schedule.Sequence, schedule.Service, project, schedule.Cron))
+	if err := s.scheduleRepo.CreateSchedule(project, schedule); err != nil {
+		return nil, err
+	}
+	setNextTriggerTime(&schedule)
+	return &schedule, nil
+}
+
+// UpdateSchedule changes the cron expression, labels and evaluation timeframe of an existing schedule
+func (s *scheduler) UpdateSchedule(project, scheduleID string, params operations.UpdateScheduleParams) (*models.Schedule, error) {
+	schedule, err := s.scheduleRepo.GetSchedule(project, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	schedule.Cron = params.Cron
+	schedule.Labels = params.Labels
+	schedule.Evaluation = params.Evaluation
+	if err := validateSchedule(*schedule); err != nil {
+		return nil, err
+	}
+	s.logger.Info(fmt.Sprintf("Updating schedule %s in project %s: %s", scheduleID, project, schedule.Cron))
+	if err := s.scheduleRepo.UpdateSchedule(project, *schedule); err != nil {
+		return nil, err
+	}
+	setNextTriggerTime(schedule)
+	return schedule, nil
+}
+
+// DeleteSchedule deletes the schedule with the given ID
+func (s *scheduler) DeleteSchedule(project, scheduleID string) error {
+	s.logger.Info(fmt.Sprintf("Deleting schedule %s in project %s", scheduleID, project))
+	return s.scheduleRepo.DeleteSchedule(project, scheduleID)
+}
+
+// StartScheduler periodically triggers the task sequences whose schedule is due
+func (s *scheduler) StartScheduler() {
+	s.logger.Info(fmt.Sprintf("Checking for scheduled task sequences every %s", scheduleCheckInterval.String()))
+	for {
+		<-time.After(scheduleCheckInterval)
+		if err := s.triggerDueSchedules(time.Now()); err != nil {
+			s.logger.Error("Could not trigger scheduled task sequences: " + err.Error())
+		}
+	}
+}
+
+func (s *scheduler) triggerDueSchedules(now time.Time) error {
+	projects, err := s.projectRepo.GetProjects()
+	if err != nil {
+		return err
+	}
+
+	for _, project := range projects {
+		schedules, err := s.scheduleRepo.GetSchedules(project, db.ScheduleFilter{})
+		if err != nil {
+			s.logger.Error("Could not retrieve schedules of project " + project + ": " + err.Error())
+			continue
+		}
+		for _, schedule := range schedules {
+			due, err := isScheduleDue(schedule, now)
+			if err != nil {
+				s.logger.Error("Could not determine if schedule " + schedule.ID + " is due: " + err.Error())
+				continue
+			} else if !due {
+				continue
+			}
+			if err := s.triggerSchedule(schedule, now); err != nil {
+				s.logger.Error("Could not trigger task sequence of schedule " + schedule.ID + ": " + err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// triggerSchedule sends the <stage>.<sequence>.triggered event of a schedule. The event is handled like any other event that triggers a task sequence
+func (s *scheduler) triggerSchedule(schedule models.Schedule, now time.Time) error {
+	keptnContext := uuid.New().String()
+	s.logger.Info(fmt.Sprintf("Triggering task sequence %s.%s of service %s in project %s with KeptnContext %s based on schedule %s", schedule.Stage, schedule.Sequence, schedule.Service, schedule.Project, keptnContext, schedule.ID))
+
+	source, _ := url.Parse(schedulerEventSource)
+
+	event := cloudevents.NewEvent()
+	event.SetID(uuid.New().String())
+	event.SetTime(now)
+	event.SetType(keptnv2.GetTriggeredEventType(schedule.Stage + "." + schedule.Sequence))
+	event.SetSource(source.String())
+	event.SetDataContentType(cloudevents.ApplicationJSON)
+	event.SetExtension("shkeptncontext", keptnContext)
+	eventData, err := getScheduledEventData(schedule, now)
+	if err != nil {
+		return err
+	}
+	event.SetData(cloudevents.ApplicationJSON, eventData)
+
+	if err := common.SendEvent(event); err != nil {
+		return err
+	}
+
+	schedule.LastTriggeredAt = now.UTC().Format(time.RFC3339)
+	schedule.LastKeptnContext = keptnContext
+	return s.scheduleRepo.UpdateSchedule(schedule.Project, schedule)
+}
+
+// scheduledEvaluationEventData is the payload of events that trigger a task sequence with an evaluation timeframe
+type scheduledEvaluationEventData struct {
+	keptnv2.EventData
+	Evaluation keptnv2.Evaluation `json:"evaluation"`
+}
+
+// getScheduledEventData returns the payload of the event that triggers the task sequence of a schedule. If the schedule
+// defines an evaluation timeframe, the evaluation covers the timeframe up to the given trigger time
+func getScheduledEventData(schedule models.Schedule, now time.Time) (interface{}, error) {
+	eventData := keptnv2.EventData{
+		Project: schedule.Project,
+		Stage:   schedule.Stage,
+		Service: schedule.Service,
+		Labels:  schedule.Labels,
+	}
+	if schedule.Evaluation == nil {
+		return eventData, nil
+	}
+	timeframe, err := parseEvaluationTimeframe(schedule.Evaluation.Timeframe)
+	if err != nil {
+		return nil, err
+	}
+	return scheduledEvaluationEventData{
+		EventData: eventData,
+		Evaluation: keptnv2.Evaluation{
+			Start: now.Add(-timeframe).UTC().Format(time.RFC3339),
+			End:   now.UTC().Format(time.RFC3339),
+		},
+	}, nil
+}
+
+func parseEvaluationTimeframe(timeframe string) (time.Duration, error) {
+	duration, err := time.ParseDuration(timeframe)
+	if err != nil {
+		return 0, fmt.Errorf("invalid evaluation timeframe %s: %s", timeframe, err.Error())
+	} else if duration <= 0 {
+		return 0, fmt.Errorf("invalid evaluation timeframe %s: must be positive", timeframe)
+	}
+	return duration, nil
+}
+
+// isScheduleDue checks if the next point in time of a schedule, after it has been triggered the last time, has been reached
+func isScheduleDue(schedule models.Schedule, now time.Time) (bool, error) {
+	next, err := getNextTriggerTime(schedule)
+	if err != nil {
+		return false, err
+	}
+	return !next.After(now), nil
+}
+
+func getNextTriggerTime(schedule models.Schedule) (time.Time, error) {
+	cron, err := parseCronExpression(schedule.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+	lastTriggeredAt := schedule.LastTriggeredAt
+	if lastTriggeredAt == "" {
+		lastTriggeredAt = schedule.CreatedAt
+	}
+	reference, err := time.Parse(time.RFC3339, lastTriggeredAt)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cron.next(reference.UTC())
+}
+
+func setNextTriggerTime(schedule *models.Schedule) {
+	if next, err := getNextTriggerTime(*schedule); err == nil {
+		schedule.NextTriggerAt = next.Format(time.RFC3339)
+	}
+}
+
+// validateSchedule checks if the cron expression of a schedule is valid and if the task sequence exists in the shipyard of the project
+func validateSchedule(schedule models.Schedule) error {
+	if schedule.Stage == "" || schedule.Service == "" || schedule.Sequence == "" {
+		return errInvalidSchedule{reason: "stage, service and sequence must be set"}
+	}
+	if _, err := parseCronExpression(schedule.Cron); err != nil {
+		return errInvalidSchedule{reason: err.Error()}
+	}
+	if schedule.Evaluation != nil {
+		if _, err := parseEvaluationTimeframe(schedule.Evaluation.Timeframe); err != nil {
+			return errInvalidSchedule{reason: err.Error()}
+		}
+	}
+
+	shipyard, err := common.GetShipyard(&keptnv2.EventData{Project: schedule.Project})
+	if err != nil {
+		return fmt.Errorf("could not retrieve shipyard of project %s: %s", schedule.Project, err.Error())
+	}
+	for _, stage := range shipyard.Spec.Stages {
+		if stage.Name != schedule.Stage {
+			continue
+		}
+		if schedule.Sequence == keptnv2.EvaluationTaskName {
+			return validateEvaluationTimeframe(schedule)
+		}
+		for _, sequence := range stage.Sequences {
+			if sequence.Name != schedule.Sequence {
+				continue
+			}
+			for _, task := range sequence.Tasks {
+				if task.Name == keptnv2.EvaluationTaskName {
+					return validateEvaluationTimeframe(schedule)
+				}
+			}
+			return nil
+		}
+		return errInvalidSchedule{reason: "no task sequence " + schedule.Sequence + " found in stage " + schedule.Stage}
+	}
+	return errInvalidSchedule{reason: "no stage " + schedule.Stage + " found in project " + schedule.Project}
+}
+
+// validateEvaluationTimeframe checks if a schedule whose task sequence contains an evaluation defines the evaluated timeframe
+func validateEvaluationTimeframe(schedule models.Schedule) error {
+	if schedule.Evaluation == nil {
+		return errInvalidSchedule{reason: "evaluation.timeframe must be set for task sequence " + schedule.Sequence + ", because it contains an evaluation"}
+	}
+	return nil
+}
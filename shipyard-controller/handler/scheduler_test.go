@@ -0,0 +1,207 @@
+package handler
+
+import (
+	keptncommon "github.com/keptn/go-utils/pkg/lib/keptn"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/shipyard-controller/db"
+	"github.com/keptn/keptn/shipyard-controller/handler/fake"
+	"github.com/keptn/keptn/shipyard-controller/models"
+	"github.com/keptn/keptn/shipyard-controller/operations"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+	"time"
+)
+
+func getTestScheduler(schedules map[string]models.Schedule) *scheduler {
+	return &scheduler{
+		projectRepo: &fake.ProjectRepository{
+			GetProjectsFunc: func() ([]string, error) {
+				return []string{"test-project"}, nil
+			},
+		},
+		scheduleRepo: &fake.ScheduleRepository{
+			GetSchedulesFunc: func(project string, filter db.ScheduleFilter) ([]models.Schedule, error) {
+				result := []models.Schedule{}
+				for _, schedule := range schedules {
+					result = append(result, schedule)
+				}
+				return result, nil
+			},
+			GetScheduleFunc: func(project, scheduleID string) (*models.Schedule, error) {
+				schedule, ok := schedules[scheduleID]
+				if !ok {
+					return nil, db.ErrScheduleNotFound
+				}
+				return &schedule, nil
+			},
+			CreateScheduleFunc: func(project string, schedule models.Schedule) error {
+				schedules[schedule.ID] = schedule
+				return nil
+			},
+			UpdateScheduleFunc: func(project string, schedule models.Schedule) error {
+				if _, ok := schedules[schedule.ID]; !ok {
+					return db.ErrScheduleNotFound
+				}
+				schedules[schedule.ID] = schedule
+				return nil
+			},
+			DeleteScheduleFunc: func(project, scheduleID string) error {
+				delete(schedules, scheduleID)
+				return nil
+			},
+		},
+		logger: keptncommon.NewLogger("", "", "shipyard-controller"),
+	}
+}
+
+func Test_scheduler_CreateSchedule(t *testing.T) {
+	mockCS := fake.NewConfigurationService(testShipyardResource)
+	defer mockCS.Close()
+	_ = os.Setenv("CONFIGURATION_SERVICE", mockCS.URL)
+
+	tests := []struct {
+		name        string
+		params      operations.CreateScheduleParams
+		wantErr     bool
+		wantInvalid bool
+	}{
+		{
+			name:   "valid schedule",
+			params: operations.CreateScheduleParams{Stage: "production", Service: "carts", Sequence: "artifact-delivery", Cron: "0 2 * * *"},
+		},
+		{
+			name:   "sequence with evaluation",
+			params: operations.CreateScheduleParams{Stage: "dev", Service: "carts", Sequence: "artifact-delivery", Cron: "0 2 * * *", Evaluation: &models.ScheduleEvaluation{Timeframe: "15m"}},
+		},
+		{
+			name:   "built-in evaluation sequence",
+			params: operations.CreateScheduleParams{Stage: "hardening", Service: "carts", Sequence: "evaluation", Cron: "@daily", Evaluation: &models.ScheduleEvaluation{Timeframe: "1h"}},
+		},
+		{
+			name:        "sequence with evaluation without timeframe",
+			params:      operations.CreateScheduleParams{Stage: "dev", Service: "carts", Sequence: "artifact-delivery", Cron: "0 2 * * *"},
+			wantErr:     true,
+			wantInvalid: true,
+		},
+		{
+			name:        "built-in evaluation sequence without timeframe",
+			params:      operations.CreateScheduleParams{Stage: "hardening", Service: "carts", Sequence: "evaluation", Cron: "@daily"},
+			wantErr:     true,
+			wantInvalid: true,
+		},
+		{
+			name:        "invalid evaluation timeframe",
+			params:      operations.CreateScheduleParams{Stage: "hardening", Service: "carts", Sequence: "evaluation", Cron: "@daily", Evaluation: &models.ScheduleEvaluation{Timeframe: "-15m"}},
+			wantErr:     true,
+			wantInvalid: true,
+		},
+		{
+			name:        "invalid cron expression",
+			params:      operations.CreateScheduleParams{Stage: "dev", Service: "carts", Sequence: "artifact-delivery", Cron: "0 25 * * *"},
+			wantErr:     true,
+			wantInvalid: true,
+		},
+		{
+			name:        "unknown stage",
+			params:      operations.CreateScheduleParams{Stage: "qa", Service: "carts", Sequence: "artifact-delivery", Cron: "0 2 * * *"},
+			wantErr:     true,
+			wantInvalid: true,
+		},
+		{
+			name:        "unknown sequence",
+			params:      operations.CreateScheduleParams{Stage: "dev", Service: "carts", Sequence: "nightly", Cron: "0 2 * * *"},
+			wantErr:     true,
+			wantInvalid: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedules := map[string]models.Schedule{}
+			s := getTestScheduler(schedules)
+			schedule, err := s.CreateSchedule("test-project", tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				_, invalid := err.(errInvalidSchedule)
+				assert.Equal(t, tt.wantInvalid, invalid)
+				assert.Empty(t, schedules)
+				return
+			}
+			assert.NotEmpty(t, schedule.ID)
+			assert.NotEmpty(t, schedule.NextTriggerAt)
+			assert.Equal(t, 1, len(schedules))
+		})
+	}
+}
+
+func Test_scheduler_triggerDueSchedules(t *testing.T) {
+	mockEV := fake.NewEventBroker(t,
+		func(meb *fake.EventBroker, event *models.Event) {
+			meb.ReceivedEvents = append(meb.ReceivedEvents, *event)
+		},
+		func(meb *fake.EventBroker) {
+
+		})
+	defer mockEV.Server.Close()
+	_ = os.Setenv("EVENTBROKER", mockEV.Server.URL)
+
+	schedules := map[string]models.Schedule{
+		"nightly": {
+			ID:         "nightly",
+			Project:    "test-project",
+			Stage:      "hardening",
+			Service:    "carts",
+			Sequence:   "evaluation",
+			Cron:       "0 2 * * *",
+			Labels:     map[string]string{"trigger": "nightly"},
+			Evaluation: &models.ScheduleEvaluation{Timeframe: "15m"},
+			CreatedAt:  "2021-02-10T10:00:00Z",
+		},
+		"weekly": {
+			ID:        "weekly",
+			Project:   "test-project",
+			Stage:     "hardening",
+			Service:   "carts",
+			Sequence:  "delivery",
+			Cron:      "@weekly",
+			CreatedAt: "2021-02-10T10:00:00Z",
+		},
+	}
+	s := getTestScheduler(schedules)
+
+	// no schedule is due yet
+	err := s.triggerDueSchedules(time.Date(2021, 2, 11, 1, 59, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.Empty(t, mockEV.ReceivedEvents)
+
+	// the nightly schedule is due
+	now := time.Date(2021, 2, 11, 2, 0, 30, 0, time.UTC)
+	err = s.triggerDueSchedules(now)
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(mockEV.ReceivedEvents)) {
+		event := mockEV.ReceivedEvents[0]
+		assert.Equal(t, keptnv2.GetTriggeredEventType("hardening.evaluation"), *event.Type)
+		assert.Equal(t, schedulerEventSource, *event.Source)
+		scope, err := getEventScope(event)
+		if assert.Nil(t, err) {
+			assert.Equal(t, "carts", scope.Service)
+			assert.Equal(t, "nightly", scope.Labels["trigger"])
+		}
+		// the evaluation covers the timeframe of the schedule up to the trigger time
+		eventData := keptnv2.EvaluationTriggeredEventData{}
+		if assert.Nil(t, keptnv2.Decode(event.Data, &eventData)) {
+			assert.Equal(t, "2021-02-11T01:45:30Z", eventData.Evaluation.Start)
+			assert.Equal(t, "2021-02-11T02:00:30Z", eventData.Evaluation.End)
+		}
+		assert.Equal(t, event.Shkeptncontext, schedules["nightly"].LastKeptnContext)
+	}
+	assert.Equal(t, "2021-02-11T02:00:30Z", schedules["nightly"].LastTriggeredAt)
+	assert.Empty(t, schedules["weekly"].LastTriggeredAt)
+
+	// the nightly schedule must not be triggered twice
+	err = s.triggerDueSchedules(now.Add(time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(mockEV.ReceivedEvents))
+}
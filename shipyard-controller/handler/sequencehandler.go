@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/keptn/keptn/shipyard-controller/common"
+	"github.com/keptn/keptn/shipyard-controller/db"
+	"github.com/keptn/keptn/shipyard-controller/models"
+	"github.com/keptn/keptn/shipyard-controller/operations"
+	"net/http"
+)
+
+type ISequenceHandler interface {
+	ControlSequence(context *gin.Context)
+	GetSequenceState(context *gin.Context)
+}
+
+type SequenceHandler struct {
+	ShipyardController IShipyardController
+}
+
+// ControlSequence godoc
+// @Summary Control the state of a task sequence
+// @Description Control the state of the task sequence(s) with the given keptnContext, i.e. abort, pause or resume them
+// @Tags Sequence
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   project     path    string     true        "Project"
+// @Param   keptnContext     path    string     true        "Keptn Context"
+// @Param   control     body    operations.SequenceControlParams     true        "Sequence control"
+// @Success 200 {object} operations.SequenceControlResponse	"ok"
+// @Failure 400 {object} models.Error "Invalid payload"
+// @Failure 404 {object} models.Error "Not found"
+// @Failure 500 {object} models.Error "Internal error"
+// @Router /sequence/{project}/{keptnContext}/control [post]
+func (service *SequenceHandler) ControlSequence(c *gin.Context) {
+	project := c.Param("project")
+	keptnContext := c.Param("keptnContext")
+
+	params := &operations.SequenceControlParams{}
+	if err := c.ShouldBindJSON(params); err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Code:    400,
+			Message: stringp("Invalid request format: " + err.Error()),
+		})
+		return
+	}
+
+	var err error
+	switch params.State {
+	case operations.AbortSequence:
+		err = service.ShipyardController.AbortSequence(project, keptnContext, params.Stage)
+	case operations.PauseSequence:
+		err = service.ShipyardController.PauseSequence(project, keptnContext, params.Stage)
+	case operations.ResumeSequence:
+		err = service.ShipyardController.ResumeSequence(project, keptnContext, params.Stage)
+	default:
+		c.JSON(http.StatusBadRequest, models.Error{
+			Code:    400,
+			Message: stringp("Invalid sequence state: " + string(params.State)),
+		})
+		return
+	}
+
+	if err != nil {
+		if err == errNoMatchingSequence {
+			sendNotFoundResponse(err, c)
+		} else if err == errSequencePausedInAllStages {
+			sendBadRequestResponse(err, c)
+		} else {
+			sendInternalServerErrorResponse(err, c)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, operations.SequenceControlResponse{})
+}
+
+// GetSequenceState godoc
+// @Summary Get the state of task sequences
+// @Description Get the state of the task sequences of a project, including the timeline of their tasks
+// @Tags Sequence
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   project     path    string     true        "Project"
+// @Param   keptnContext     query    string     false        "Keptn Context"
+// @Param   stage     query    string     false        "Stage"
+// @Param   service     query    string     false        "Service"
+// @Param   name     query    string     false        "Name of the task sequence"
+// @Param   state     query    string     false        "State of the task sequence"
+// @Param   pageSize     query    int     false        "The number of items to return"
+// @Param   nextPageKey     query    string     false        "Pointer to the next set of items"
+// @Success 200 {object} models.SequenceStates	"ok"
+// @Failure 400 {object} models.Error "Invalid payload"
+// @Failure 500 {object} models.Error "Internal error"
+// @Router /sequence/{project} [get]
+func (service *SequenceHandler) GetSequenceState(c *gin.Context) {
+	project := c.Param("project")
+
+	params := &operations.GetSequenceStateParams{}
+	if err := c.ShouldBindQuery(params); err != nil {
+		c.JSON(http.StatusBadRequest, models.Error{
+			Code:    400,
+			Message: stringp("Invalid request format: " + err.Error()),
+		})
+		return
+	}
+
+	states, err := service.ShipyardController.GetSequenceStates(project, db.SequenceStateFilter{
+		KeptnContext: params.KeptnContext,
+		Stage:        params.Stage,
+		Service:      params.Service,
+		Name:         params.Name,
+		State:        params.State,
+	})
+	if err != nil {
+		sendInternalServerErrorResponse(err, c)
+		return
+	}
+
+	var payload = &models.SequenceStates{
+		States:      []models.SequenceState{},
+		NextPageKey: "0",
+	}
+
+	paginationInfo := common.Paginate(len(states), params.PageSize, params.NextPageKey)
+	totalCount := len(states)
+	if paginationInfo.NextPageKey < int64(totalCount) {
+		payload.States = append(payload.States, states[paginationInfo.NextPageKey:paginationInfo.EndIndex]...)
+	}
+
+	payload.PageSize = float64(len(payload.States))
+	payload.TotalCount = float64(totalCount)
+	payload.NextPageKey = paginationInfo.NewNextPageKey
+	c.JSON(http.StatusOK, payload)
+}
+
+func NewSequenceHandler() ISequenceHandler {
+	return &SequenceHandler{
+		ShipyardController: GetShipyardControllerInstance(),
+	}
+}
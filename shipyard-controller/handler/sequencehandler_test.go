@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/keptn/keptn/shipyard-controller/db"
+	"github.com/keptn/keptn/shipyard-controller/handler/fake"
+	"github.com/keptn/keptn/shipyard-controller/models"
+	"github.com/keptn/keptn/shipyard-controller/operations"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSequenceHandler_ControlSequence(t *testing.T) {
+	type fields struct {
+		ShipyardController IShipyardController
+	}
+
+	tests := []struct {
+		name             string
+		fields           fields
+		params           operations.SequenceControlParams
+		expectStatusCode int
+	}{
+		{
+			name: "abort sequence",
+			fields: fields{
+				ShipyardController: &fake.ShipyardController{
+					AbortSequenceFunc: func(project, keptnContext, stage string) error {
+						return nil
+					},
+				},
+			},
+			params:           operations.SequenceControlParams{State: operations.AbortSequence},
+			expectStatusCode: http.StatusOK,
+		},
+		{
+			name: "pause sequence",
+			fields: fields{
+				ShipyardController: &fake.ShipyardController{
+					PauseSequenceFunc: func(project, keptnContext, stage string) error {
+						return nil
+					},
+				},
+			},
+			params:           operations.SequenceControlParams{State: operations.PauseSequence},
+			expectStatusCode: http.StatusOK,
+		},
+		{
+			name: "resume sequence",
+			fields: fields{
+				ShipyardController: &fake.ShipyardController{
+					ResumeSequenceFunc: func(project, keptnContext, stage string) error {
+						return nil
+					},
+				},
+			},
+			params:           operations.SequenceControlParams{State: operations.ResumeSequence},
+			expectStatusCode: http.StatusOK,
+		},
+		{
+			name: "return 400 on invalid state",
+			fields: fields{
+				ShipyardController: &fake.ShipyardController{},
+			},
+			params:           operations.SequenceControlParams{State: "invalid"},
+			expectStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "return 404 on errNoMatchingSequence",
+			fields: fields{
+				ShipyardController: &fake.ShipyardController{
+					AbortSequenceFunc: func(project, keptnContext, stage string) error {
+						return errNoMatchingSequence
+					},
+				},
+			},
+			params:           operations.SequenceControlParams{State: operations.AbortSequence},
+			expectStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "return 400 on resuming a single stage of a task sequence paused in all stages",
+			fields: fields{
+				ShipyardController: &fake.ShipyardController{
+					ResumeSequenceFunc: func(project, keptnContext, stage string) error {
+						return errSequencePausedInAllStages
+					},
+				},
+			},
+			params:           operations.SequenceControlParams{State: operations.ResumeSequence, Stage: "dev"},
+			expectStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "return 500 on error",
+			fields: fields{
+				ShipyardController: &fake.ShipyardController{
+					AbortSequenceFunc: func(project, keptnContext, stage string) error {
+						return errors.New("")
+					},
+				},
+			},
+			params:           operations.SequenceControlParams{State: operations.AbortSequence},
+			expectStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			marshal, _ := json.Marshal(tt.params)
+			c.Request, _ = http.NewRequest(http.MethodPost, "", bytes.NewBuffer(marshal))
+			c.Params = gin.Params{
+				{Key: "project", Value: "test-project"},
+				{Key: "keptnContext", Value: "test-context"},
+			}
+			service := &SequenceHandler{
+				ShipyardController: tt.fields.ShipyardController,
+			}
+
+			service.ControlSequence(c)
+			assert.Equal(t, tt.expectStatusCode, w.Code)
+		})
+	}
+}
+
+func TestSequenceHandler_GetSequenceState(t *testing.T) {
+	type fields struct {
+		ShipyardController IShipyardController
+	}
+
+	tests := []struct {
+		name             string
+		fields           fields
+		query            string
+		expectStatusCode int
+		expectStates     int
+	}{
+		{
+			name: "get sequence states",
+			fields: fields{
+				ShipyardController: &fake.ShipyardController{
+					GetSequenceStatesFunc: func(project string, filter db.SequenceStateFilter) ([]models.SequenceState, error) {
+						return []models.SequenceState{{Name: "delivery"}, {Name: "evaluation"}}, nil
+					},
+				},
+			},
+			expectStatusCode: http.StatusOK,
+			expectStates:     2,
+		},
+		{
+			name: "get sequence states with filter and page size",
+			fields: fields{
+				ShipyardController: &fake.ShipyardController{
+					GetSequenceStatesFunc: func(project string, filter db.SequenceStateFilter) ([]models.SequenceState, error) {
+						if filter.KeptnContext == nil || *filter.KeptnContext != "test-context" || filter.Stage == nil || *filter.Stage != "dev" {
+							return nil, errors.New("unexpected filter")
+						}
+						return []models.SequenceState{{Name: "delivery"}, {Name: "evaluation"}}, nil
+					},
+				},
+			},
+			query:            "?keptnContext=test-context&stage=dev&pageSize=1",
+			expectStatusCode: http.StatusOK,
+			expectStates:     1,
+		},
+		{
+			name: "return 500 on error",
+			fields: fields{
+				ShipyardController: &fake.ShipyardController{
+					GetSequenceStatesFunc: func(project string, filter db.SequenceStateFilter) ([]models.SequenceState, error) {
+						return nil, errors.New("")
+					},
+				},
+			},
+			expectStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Request, _ = http.NewRequest(http.MethodGet, "/sequence/test-project"+tt.query, nil)
+			c.Params = gin.Params{
+				{Key: "project", Value: "test-project"},
+			}
+			service := &SequenceHandler{
+				ShipyardController: tt.fields.ShipyardController,
+			}
+
+			service.GetSequenceState(c)
+			assert.Equal(t, tt.expectStatusCode, w.Code)
+
+			if tt.expectStatusCode == http.StatusOK {
+				states := &models.SequenceStates{}
+				err := json.Unmarshal(w.Body.Bytes(), states)
+				assert.Nil(t, err)
+				assert.Equal(t, tt.expectStates, len(states.States))
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"encoding/json"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/shipyard-controller/db"
+	"github.com/keptn/keptn/shipyard-controller/models"
+	"time"
+)
+
+// PauseSequence pauses the task sequences of a project with the given keptnContext. While a task sequence is paused, no further tasks are triggered.
+// If a stage is provided, only the task sequence running in that stage is paused. Pausing a stage while all stages are paused has no effect
+func (sc *shipyardController) PauseSequence(project, keptnContext, stage string) error {
+	sc.logger.Info("Pausing task sequences with KeptnContext " + keptnContext + " in project " + project)
+	filter := db.EventFilter{
+		KeptnContext: &keptnContext,
+	}
+	if stage != "" {
+		filter.Stage = &stage
+	}
+	triggeredEvents, err := sc.eventRepo.GetEvents(project, filter, db.TriggeredEvent)
+	if err != nil && err != db.ErrNoEventFound {
+		sc.logger.Error("could not retrieve open .triggered events: " + err.Error())
+		return err
+	} else if len(triggeredEvents) == 0 {
+		return errNoMatchingSequence
+	}
+
+	pausedSequences, err := sc.pausedSequenceRepo.GetPausedSequences(project, keptnContext)
+	if err != nil {
+		return err
+	}
+	for _, pausedSequence := range pausedSequences {
+		// a pause of all stages includes the stage to pause
+		if pausedSequence.Stage == stage || pausedSequence.Stage == "" {
+			sc.logger.Info("Task sequence with KeptnContext " + keptnContext + " is already paused")
+			return nil
+		}
+	}
+
+	return sc.pausedSequenceRepo.UpsertPausedSequence(project, models.PausedSequence{
+		KeptnContext: keptnContext,
+		Stage:        stage,
+		PausedAt:     time.Now().UTC().Format(time.RFC3339),
+		PendingTasks: []models.PendingTask{},
+	})
+}
+
+// ResumeSequence resumes paused task sequences of a project with the given keptnContext and triggers all tasks that have been held back.
+// If no stage is provided, the task sequences of all stages are resumed. If all stages have been paused, a single stage cannot be resumed
+func (sc *shipyardController) ResumeSequence(project, keptnContext, stage string) error {
+	sc.logger.Info("Resuming task sequences with KeptnContext " + keptnContext + " in project " + project)
+	pausedSequences, err := sc.pausedSequenceRepo.GetPausedSequences(project, keptnContext)
+	if err != nil {
+		return err
+	}
+
+	resumedSequences := []models.PausedSequence{}
+	for _, pausedSequence := range pausedSequences {
+		if stage == "" || pausedSequence.Stage == stage {
+			resumedSequences = append(resumedSequences, pausedSequence)
+		}
+	}
+	if len(resumedSequences) == 0 {
+		if getPausedSequenceOfStage(pausedSequences, stage) != nil {
+			// the pause of all stages is not split up into the stages, i.e. the task sequences stay paused until all stages are resumed
+			return errSequencePausedInAllStages
+		}
+		return errNoMatchingSequence
+	}
+
+	for _, resumedSequence := range resumedSequences {
+		if err := sc.pausedSequenceRepo.DeletePausedSequence(project, keptnContext, resumedSequence.Stage); err != nil {
+			return err
+		}
+	}
+
+	for _, resumedSequence := range resumedSequences {
+		for index := range resumedSequence.PendingTasks {
+			pendingTask := resumedSequence.PendingTasks[index]
+			paused, err := sc.holdTaskIfSequencePaused(keptnContext, &pendingTask.EventScope, pendingTask.TaskSequenceName, pendingTask.Task, pendingTask.PreviousFinishedEvents)
+			if err != nil {
+				return err
+			} else if paused {
+				continue
+			}
+			sc.logger.Info("Triggering task " + pendingTask.Task.Name + " of resumed task sequence " + pendingTask.EventScope.Stage + "." + pendingTask.TaskSequenceName)
+			if err := sc.triggerTask(keptnContext, &pendingTask.EventScope, pendingTask.TaskSequenceName, pendingTask.Task, pendingTask.PreviousFinishedEvents); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// holdTaskIfSequencePaused stores the given task as a pending task if the task sequence it belongs to is paused
+func (sc *shipyardController) holdTaskIfSequencePaused(keptnContext string, eventScope *keptnv2.EventData, taskSequenceName string, task keptnv2.Task, previousFinishedEvents []interface{}) (bool, error) {
+	pausedSequences, err := sc.pausedSequenceRepo.GetPausedSequences(eventScope.Project, keptnContext)
+	if err != nil {
+		sc.logger.Error("Could not retrieve paused task sequences: " + err.Error())
+		return false, err
+	}
+
+	pausedSequence := getPausedSequenceOfStage(pausedSequences, eventScope.Stage)
+	if pausedSequence == nil {
+		return false, nil
+	}
+
+	sc.logger.Info("Task sequence " + eventScope.Stage + "." + taskSequenceName + " with KeptnContext " + keptnContext + " is paused. Holding back task " + task.Name)
+	pausedSequence.PendingTasks = append(pausedSequence.PendingTasks, models.PendingTask{
+		TaskSequenceName:       taskSequenceName,
+		Task:                   task,
+		EventScope:             *eventScope,
+		PreviousFinishedEvents: previousFinishedEvents,
+	})
+	if err := sc.pausedSequenceRepo.UpsertPausedSequence(eventScope.Project, *pausedSequence); err != nil {
+		sc.logger.Error("Could not store pending task " + task.Name + ": " + err.Error())
+		return false, err
+	}
+	return true, nil
+}
+
+// filterEventsOfPausedSequences removes all .triggered events that belong to a paused task sequence
+func (sc *shipyardController) filterEventsOfPausedSequences(project string, events []models.Event) ([]models.Event, error) {
+	pausedSequencesByContext := map[string][]models.PausedSequence{}
+	result := []models.Event{}
+	for _, event := range events {
+		pausedSequences, ok := pausedSequencesByContext[event.Shkeptncontext]
+		if !ok {
+			var err error
+			pausedSequences, err = sc.pausedSequenceRepo.GetPausedSequences(project, event.Shkeptncontext)
+			if err != nil {
+				return nil, err
+			}
+			pausedSequencesByContext[event.Shkeptncontext] = pausedSequences
+		}
+
+		if len(pausedSequences) > 0 {
+			marshal, _ := json.Marshal(event.Data)
+			eventData := &keptnv2.EventData{}
+			if err := json.Unmarshal(marshal, eventData); err == nil && getPausedSequenceOfStage(pausedSequences, eventData.Stage) != nil {
+				continue
+			}
+		}
+		result = append(result, event)
+	}
+	return result, nil
+}
+
+func getPausedSequenceOfStage(pausedSequences []models.PausedSequence, stage string) *models.PausedSequence {
+	var result *models.PausedSequence
+	for index := range pausedSequences {
+		if pausedSequences[index].Stage == stage {
+			return &pausedSequences[index]
+		} else if pausedSequences[index].Stage == "" {
+			result = &pausedSequences[index]
+		}
+	}
+	return result
+}
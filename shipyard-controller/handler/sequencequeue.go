@@ -0,0 +1,176 @@
+package handler
+
+import (
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/shipyard-controller/common"
+	"github.com/keptn/keptn/shipyard-controller/db"
+	"github.com/keptn/keptn/shipyard-controller/models"
+	"os"
+	"time"
+)
+
+// sequenceConcurrencyPolicy defines how a task sequence is handled if another task sequence of the same service is still running in the same stage
+type sequenceConcurrencyPolicy string
+
+const (
+	// queueSequencePolicy queues the new task sequence until the running task sequences have been finished
+	queueSequencePolicy sequenceConcurrencyPolicy = "queue"
+	// rejectSequencePolicy rejects the new task sequence
+	rejectSequencePolicy sequenceConcurrencyPolicy = "reject"
+	// supersedeSequencePolicy aborts the running task sequences and starts the new task sequence
+	supersedeSequencePolicy sequenceConcurrencyPolicy = "supersede"
+)
+
+func getSequenceConcurrencyPolicy() sequenceConcurrencyPolicy {
+	switch policy := sequenceConcurrencyPolicy(os.Getenv("SEQUENCE_CONCURRENCY_POLICY")); policy {
+	case rejectSequencePolicy, supersedeSequencePolicy:
+		return policy
+	default:
+		return queueSequencePolicy
+	}
+}
+
+// checkSequenceConcurrency applies the sequence concurrency policy to a task sequence that is about to be started. It returns true if the task sequence can be started right away
+func (sc *shipyardController) checkSequenceConcurrency(keptnContext string, eventScope *keptnv2.EventData, taskSequenceName string, previousFinishedEvents []interface{}) (bool, error) {
+	runningSequences, err := sc.getRunningSequences(eventScope.Project, eventScope.Stage, eventScope.Service)
+	if err != nil {
+		return false, err
+	}
+	otherSequences := []models.SequenceState{}
+	for _, runningSequence := range runningSequences {
+		if runningSequence.KeptnContext != keptnContext {
+			otherSequences = append(otherSequences, runningSequence)
+		}
+	}
+	queuedSequences, err := sc.sequenceQueueRepo.GetQueuedSequences(eventScope.Project, db.SequenceQueueFilter{
+		Stage:   &eventScope.Stage,
+		Service: &eventScope.Service,
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(otherSequences) == 0 && len(queuedSequences) == 0 {
+		return true, nil
+	}
+
+	switch getSequenceConcurrencyPolicy() {
+	case rejectSequencePolicy:
+		sc.logger.Info("Rejecting task sequence " + eventScope.Stage + "." + taskSequenceName + " with KeptnContext " + keptnContext + " because another task sequence of service " + eventScope.Service + " is running")
+		return false, sc.completeTaskSequence(keptnContext, &keptnv2.EventData{
+			Project: eventScope.Project,
+			Stage:   eventScope.Stage,
+			Service: eventScope.Service,
+			Labels:  eventScope.Labels,
+			Status:  keptnv2.StatusErrored,
+			Result:  keptnv2.ResultFailed,
+			Message: "task sequence has been rejected because another task sequence of service " + eventScope.Service + " is running in stage " + eventScope.Stage,
+		}, taskSequenceName)
+	case supersedeSequencePolicy:
+		for _, otherSequence := range otherSequences {
+			sc.logger.Info("Task sequence " + eventScope.Stage + "." + taskSequenceName + " with KeptnContext " + keptnContext + " supersedes task sequence " + otherSequence.Name + " with KeptnContext " + otherSequence.KeptnContext)
+			if err := sc.AbortSequence(eventScope.Project, otherSequence.KeptnContext, eventScope.Stage); err != nil && err != errNoMatchingSequence {
+				return false, err
+			}
+		}
+		for _, queuedSequence := range queuedSequences {
+			if err := sc.AbortSequence(eventScope.Project, queuedSequence.KeptnContext, eventScope.Stage); err != nil && err != errNoMatchingSequence {
+				return false, err
+			}
+		}
+		return true, nil
+	default:
+		sc.logger.Info("Queueing task sequence " + eventScope.Stage + "." + taskSequenceName + " with KeptnContext " + keptnContext + " because another task sequence of service " + eventScope.Service + " is running")
+		if err := sc.sequenceQueueRepo.QueueSequence(eventScope.Project, models.QueuedSequence{
+			KeptnContext:           keptnContext,
+			Stage:                  eventScope.Stage,
+			Service:                eventScope.Service,
+			TaskSequenceName:       taskSequenceName,
+			QueuedAt:               time.Now().UTC().Format(time.RFC3339Nano),
+			EventScope:             *eventScope,
+			PreviousFinishedEvents: previousFinishedEvents,
+		}); err != nil {
+			return false, err
+		}
+		sc.setSequenceState(eventScope.Project, keptnContext, eventScope.Stage, taskSequenceName, models.SequenceQueuedState)
+		if len(otherSequences) == 0 {
+			// no task sequence is running anymore, but older task sequences are still waiting in the queue
+			return false, sc.startNextQueuedSequence(eventScope.Project, eventScope.Stage, eventScope.Service)
+		}
+		return false, nil
+	}
+}
+
+// startNextQueuedSequence starts the oldest queued task sequence of a service in a stage, if no other task sequence of the service is running in that stage
+func (sc *shipyardController) startNextQueuedSequence(project, stage, service string) error {
+	runningSequences, err := sc.getRunningSequences(project, stage, service)
+	if err != nil {
+		return err
+	} else if len(runningSequences) > 0 {
+		return nil
+	}
+
+	queuedSequences, err := sc.sequenceQueueRepo.GetQueuedSequences(project, db.SequenceQueueFilter{
+		Stage:   &stage,
+		Service: &service,
+	})
+	if err != nil {
+		return err
+	} else if len(queuedSequences) == 0 {
+		return nil
+	}
+
+	nextSequence := queuedSequences[0]
+	if err := sc.sequenceQueueRepo.DeleteQueuedSequence(project, nextSequence.KeptnContext, nextSequence.Stage, nextSequence.TaskSequenceName); err != nil {
+		return err
+	}
+	sc.logger.Info("Starting queued task sequence " + nextSequence.Stage + "." + nextSequence.TaskSequenceName + " with KeptnContext " + nextSequence.KeptnContext)
+
+	shipyard, err := common.GetShipyard(&nextSequence.EventScope)
+	if err != nil {
+		return err
+	}
+	taskSequence, err := sc.getTaskSequenceInStage(nextSequence.Stage, nextSequence.TaskSequenceName, shipyard)
+	if err != nil {
+		return err
+	}
+	sc.setSequenceState(project, nextSequence.KeptnContext, nextSequence.Stage, nextSequence.TaskSequenceName, models.SequenceTriggeredState)
+	return sc.proceedTaskSequence(&nextSequence.EventScope, taskSequence, models.Event{Shkeptncontext: nextSequence.KeptnContext}, shipyard, nextSequence.PreviousFinishedEvents, "")
+}
+
+// removeQueuedSequences removes the queued task sequences with the given keptnContext from the queue and returns them
+func (sc *shipyardController) removeQueuedSequences(project, keptnContext, stage string) ([]models.QueuedSequence, error) {
+	filter := db.SequenceQueueFilter{
+		KeptnContext: &keptnContext,
+	}
+	if stage != "" {
+		filter.Stage = &stage
+	}
+	queuedSequences, err := sc.sequenceQueueRepo.GetQueuedSequences(project, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, queuedSequence := range queuedSequences {
+		if err := sc.sequenceQueueRepo.DeleteQueuedSequence(project, keptnContext, queuedSequence.Stage, queuedSequence.TaskSequenceName); err != nil {
+			return nil, err
+		}
+	}
+	return queuedSequences, nil
+}
+
+// getRunningSequences returns the task sequences of a service that have been triggered or started, but not finished yet in a stage
+func (sc *shipyardController) getRunningSequences(project, stage, service string) ([]models.SequenceState, error) {
+	states, err := sc.sequenceStateRepo.GetSequenceStates(project, db.SequenceStateFilter{
+		Stage:   &stage,
+		Service: &service,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := []models.SequenceState{}
+	for _, state := range states {
+		if state.State == models.SequenceTriggeredState || state.State == models.SequenceStartedState {
+			result = append(result, state)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/shipyard-controller/db"
+	"github.com/keptn/keptn/shipyard-controller/models"
+	"time"
+)
+
+// GetSequenceStates returns the states of the task sequences of a project
+func (sc *shipyardController) GetSequenceStates(project string, filter db.SequenceStateFilter) ([]models.SequenceState, error) {
+	return sc.sequenceStateRepo.GetSequenceStates(project, filter)
+}
+
+func (sc *shipyardController) onSequenceTriggered(keptnContext string, eventScope *keptnv2.EventData, taskSequenceName string, eventTime string) {
+	sc.upsertSequenceState(models.SequenceState{
+		Name:         taskSequenceName,
+		Project:      eventScope.Project,
+		Stage:        eventScope.Stage,
+		Service:      eventScope.Service,
+		KeptnContext: keptnContext,
+		State:        models.SequenceTriggeredState,
+		Time:         getEventTime(eventTime),
+		Tasks:        []models.SequenceStateTask{},
+	})
+}
+
+func (sc *shipyardController) onTaskTriggered(keptnContext string, eventScope *keptnv2.EventData, taskSequenceName, taskName, taskGroup, triggeredID, eventTime string, attempt int) {
+	state := sc.getSequenceState(eventScope.Project, keptnContext, eventScope.Stage, taskSequenceName)
+	if state == nil {
+		return
+	}
+	state.CurrentTask = taskName
+	if taskGroup != "" {
+		state.CurrentTask = taskGroup
+	}
+	state.Tasks = append(state.Tasks, models.SequenceStateTask{
+		Name:        taskName,
+		TriggeredID: triggeredID,
+		State:       models.SequenceTriggeredState,
+		TriggeredAt: getEventTime(eventTime),
+		Attempt:     attempt,
+		Executors:   []models.SequenceStateExecutor{},
+	})
+	sc.upsertSequenceState(*state)
+}
+
+func (sc *shipyardController) onTaskStarted(event models.Event, eventScope *keptnv2.EventData) {
+	state, task := sc.getSequenceStateOfTask(eventScope.Project, event.Triggeredid)
+	if task == nil {
+		return
+	}
+	startedAt := getEventTime(event.Time)
+	if task.StartedAt == "" {
+		task.StartedAt = startedAt
+	}
+	task.State = models.SequenceStartedState
+	task.Executors = append(task.Executors, models.SequenceStateExecutor{
+		Source:    *event.Source,
+		StartedAt: startedAt,
+	})
+	state.State = models.SequenceStartedState
+	sc.upsertSequenceState(*state)
+}
+
+func (sc *shipyardController) onTaskFinished(event models.Event, eventScope *keptnv2.EventData) {
+	state, task := sc.getSequenceStateOfTask(eventScope.Project, event.Triggeredid)
+	if task == nil {
+		return
+	}
+	finishedAt := getEventTime(event.Time)
+	executorFound := false
+	for index := range task.Executors {
+		if task.Executors[index].Source == *event.Source && task.Executors[index].FinishedAt == "" {
+			task.Executors[index].FinishedAt = finishedAt
+			task.Executors[index].Result = string(eventScope.Result)
+			task.Executors[index].Status = string(eventScope.Status)
+			task.Executors[index].Message = eventScope.Message
+			executorFound = true
+			break
+		}
+	}
+	if !executorFound {
+		task.Executors = append(task.Executors, models.SequenceStateExecutor{
+			Source:     *event.Source,
+			FinishedAt: finishedAt,
+			Result:     string(eventScope.Result),
+			Status:     string(eventScope.Status),
+			Message:    eventScope.Message,
+		})
+	}
+	sc.upsertSequenceState(*state)
+}
+
+// onTaskCompleted records the aggregated result of a task once all of its executors have finished
+func (sc *shipyardController) onTaskCompleted(eventScope *keptnv2.EventData, triggeredID string, eventTime string) {
+	state, task := sc.getSequenceStateOfTask(eventScope.Project, triggeredID)
+	if task == nil {
+		return
+	}
+	task.State = models.SequenceFinishedState
+	task.FinishedAt = getEventTime(eventTime)
+	task.Result = string(eventScope.Result)
+	task.Status = string(eventScope.Status)
+	sc.upsertSequenceState(*state)
+}
+
+func (sc *shipyardController) onSequenceFinished(keptnContext string, eventScope *keptnv2.EventData, taskSequenceName string) {
+	state := sc.getSequenceState(eventScope.Project, keptnContext, eventScope.Stage, taskSequenceName)
+	if state == nil {
+		return
+	}
+	state.State = models.SequenceFinishedState
+	state.CurrentTask = ""
+	state.Result = string(eventScope.Result)
+	state.Status = string(eventScope.Status)
+	sc.upsertSequenceState(*state)
+}
+
+func (sc *shipyardController) setSequenceState(project, keptnContext, stage, taskSequenceName, sequenceState string) {
+	state := sc.getSequenceState(project, keptnContext, stage, taskSequenceName)
+	if state == nil {
+		return
+	}
+	state.State = sequenceState
+	sc.upsertSequenceState(*state)
+}
+
+func (sc *shipyardController) getSequenceState(project, keptnContext, stage, taskSequenceName string) *models.SequenceState {
+	states, err := sc.sequenceStateRepo.GetSequenceStates(project, db.SequenceStateFilter{
+		KeptnContext: &keptnContext,
+		Stage:        &stage,
+		Name:         &taskSequenceName,
+	})
+	if err != nil {
+		sc.logger.Error("Could not retrieve state of task sequence " + stage + "." + taskSequenceName + " with KeptnContext " + keptnContext + ": " + err.Error())
+		return nil
+	} else if len(states) == 0 {
+		sc.logger.Info("No state of task sequence " + stage + "." + taskSequenceName + " with KeptnContext " + keptnContext + " found")
+		return nil
+	}
+	return &states[0]
+}
+
+// getSequenceStateOfTask returns the state of the task sequence that triggered the task with the given triggeredID, as well as the state of the task itself
+func (sc *shipyardController) getSequenceStateOfTask(project, triggeredID string) (*models.SequenceState, *models.SequenceStateTask) {
+	taskSequence, err := sc.taskSequenceRepo.GetTaskSequence(project, triggeredID)
+	if err != nil {
+		sc.logger.Error("Could not retrieve task sequence associated to eventID " + triggeredID + ": " + err.Error())
+		return nil, nil
+	} else if taskSequence == nil {
+		return nil, nil
+	}
+	state := sc.getSequenceState(project, taskSequence.KeptnContext, taskSequence.Stage, taskSequence.TaskSequenceName)
+	if state == nil {
+		return nil, nil
+	}
+	for index := range state.Tasks {
+		if state.Tasks[index].TriggeredID == triggeredID {
+			return state, &state.Tasks[index]
+		}
+	}
+	return nil, nil
+}
+
+func (sc *shipyardController) upsertSequenceState(state models.SequenceState) {
+	if err := sc.sequenceStateRepo.UpsertSequenceState(state.Project, state); err != nil {
+		sc.logger.Error("Could not store state of task sequence " + state.Stage + "." + state.Name + " with KeptnContext " + state.KeptnContext + ": " + err.Error())
+	}
+}
+
+func getEventTime(eventTime string) string {
+	if eventTime != "" {
+		return eventTime
+	}
+	return time.Now().UTC().Format(time.RFC3339)
+}
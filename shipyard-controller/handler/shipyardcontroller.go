@@ -20,19 +20,34 @@ const maxRepoReadRetries = 30
 
 var errNoMatchingEvent = errors.New("no matching event found")
 
+var errNoMatchingSequence = errors.New("no matching task sequence found")
+
+var errSequencePausedInAllStages = errors.New("the task sequences are paused in all stages and can only be resumed in all stages, i.e. without providing a stage")
+
+// sequenceAbortedEventType is the type of the event that can be sent to abort a task sequence. The shipyard-controller sends it as well when task
+// sequences are aborted via its API
+const sequenceAbortedEventType = "sh.keptn.event.sequence.aborted"
+
 var shipyardControllerInstance *shipyardController
 
 type IShipyardController interface {
 	GetAllTriggeredEvents(filter db.EventFilter) ([]models.Event, error)
 	GetTriggeredEventsOfProject(project string, filter db.EventFilter) ([]models.Event, error)
 	HandleIncomingEvent(event models.Event) error
+	AbortSequence(project, keptnContext, stage string) error
+	PauseSequence(project, keptnContext, stage string) error
+	ResumeSequence(project, keptnContext, stage string) error
+	GetSequenceStates(project string, filter db.SequenceStateFilter) ([]models.SequenceState, error)
 }
 
 type shipyardController struct {
-	projectRepo      db.ProjectRepo
-	eventRepo        db.EventRepo
-	taskSequenceRepo db.TaskSequenceRepo
-	logger           *keptncommon.Logger
+	projectRepo        db.ProjectRepo
+	eventRepo          db.EventRepo
+	taskSequenceRepo   db.TaskSequenceRepo
+	pausedSequenceRepo db.PausedSequenceRepo
+	sequenceStateRepo  db.SequenceStateRepo
+	sequenceQueueRepo  db.SequenceQueueRepo
+	logger             *keptncommon.Logger
 }
 
 func GetShipyardControllerInstance() *shipyardController {
@@ -48,6 +63,15 @@ func GetShipyardControllerInstance() *shipyardController {
 			taskSequenceRepo: &db.TaskSequenceMongoDBRepo{
 				Logger: logger,
 			},
+			pausedSequenceRepo: &db.PausedSequenceMongoDBRepo{
+				Logger: logger,
+			},
+			sequenceStateRepo: &db.SequenceStateMongoDBRepo{
+				Logger: logger,
+			},
+			sequenceQueueRepo: &db.SequenceQueueMongoDBRepo{
+				Logger: logger,
+			},
 			logger: logger,
 		}
 	}
@@ -61,10 +85,18 @@ func (sc *shipyardController) GetAllTriggeredEvents(filter db.EventFilter) ([]mo
 		return nil, err
 	}
 
+	// delayed events, i.e. retries of a task, must not be picked up before their backoff has expired
+	now := time.Now()
+	filter.DueAt = &now
+
 	allEvents := []models.Event{}
 	for _, project := range projects {
 		sc.logger.Info(fmt.Sprintf("Retrieving all .triggered events of project %s with filter: %s", project, printObject(filter)))
 		events, err := sc.eventRepo.GetEvents(project, filter, db.TriggeredEvent)
+		if err != nil {
+			continue
+		}
+		events, err = sc.filterEventsOfPausedSequences(project, events)
 		if err == nil {
 			allEvents = append(allEvents, events...)
 		}
@@ -73,11 +105,24 @@ func (sc *shipyardController) GetAllTriggeredEvents(filter db.EventFilter) ([]mo
 }
 
 func (sc *shipyardController) GetTriggeredEventsOfProject(project string, filter db.EventFilter) ([]models.Event, error) {
+	// delayed events, i.e. retries of a task, must not be picked up before their backoff has expired
+	now := time.Now()
+	filter.DueAt = &now
+
 	sc.logger.Info(fmt.Sprintf("Retrieving all .triggered events with filter: %s", printObject(filter)))
-	return sc.eventRepo.GetEvents(project, filter, db.TriggeredEvent)
+	events, err := sc.eventRepo.GetEvents(project, filter, db.TriggeredEvent)
+	if err != nil {
+		return nil, err
+	}
+	// tasks of paused task sequences must not be picked up by any Keptn service until the task sequence is resumed
+	return sc.filterEventsOfPausedSequences(project, events)
 }
 
 func (sc *shipyardController) HandleIncomingEvent(event models.Event) error {
+	if *event.Type == sequenceAbortedEventType {
+		return sc.handleSequenceAbortedEvent(event)
+	}
+
 	// check if the status type is either 'triggered', 'started', or 'finished'
 	split := strings.Split(*event.Type, ".")
 
@@ -165,6 +210,8 @@ func (sc *shipyardController) handleFinishedEvent(event models.Event) error {
 			}
 		}
 	}
+	sc.onTaskFinished(event, eventScope)
+
 	// check if this was the last '.started' event
 	if len(startedEvents) == 1 {
 		triggeredEventFilter := db.EventFilter{
@@ -182,6 +229,31 @@ func (sc *shipyardController) handleFinishedEvent(event models.Event) error {
 			sc.logger.Error(msg)
 			return errNoMatchingEvent
 		}
+
+		// wait for the expected executors of the task that did not respond yet
+		missingExecutors, err := sc.getMissingExecutors(eventScope.Project, triggeredEvents[0])
+		if err != nil {
+			msg := "could not determine missing executors of task triggered by event " + event.Triggeredid + ": " + err.Error()
+			sc.logger.Error(msg)
+			return errors.New(msg)
+		} else if len(missingExecutors) > 0 {
+			sc.logger.Info("Waiting for executors " + strings.Join(missingExecutors, ", ") + " of task triggered by event " + event.Triggeredid)
+			return nil
+		}
+
+		// aggregate the results of all executors of the task - the worst result wins
+		finishedEventsOfTask, err := sc.eventRepo.GetEvents(eventScope.Project, db.EventFilter{
+			Type:        trimmedEventType + string(db.FinishedEvent),
+			TriggeredID: &event.Triggeredid,
+		}, db.FinishedEvent)
+		if err != nil && err != db.ErrNoEventFound {
+			msg := "could not retrieve '.finished' events for triggeredid " + event.Triggeredid + ": " + err.Error()
+			sc.logger.Error(msg)
+			return errors.New(msg)
+		}
+		mergeFinishedEventResults(eventScope, finishedEventsOfTask)
+		sc.onTaskCompleted(eventScope, event.Triggeredid, event.Time)
+
 		// if the previously deleted '.started' event was the last, the '.triggered' event can be removed
 		sc.logger.Info("triggered event will be deleted")
 		err = sc.eventRepo.DeleteEvent(eventScope.Project, triggeredEvents[0].ID, db.TriggeredEvent)
@@ -219,6 +291,30 @@ func (sc *shipyardController) handleFinishedEvent(event models.Event) error {
 			return errors.New(msg)
 		}
 
+		// tasks that finished with status 'errored' are triggered again if their retry policy allows it
+		retried, err := sc.retryTask(eventScope, sequence, *eventToSequence, triggeredEvents[0])
+		if err != nil {
+			msg := "Could not retry task triggered by event " + event.Triggeredid + ": " + err.Error()
+			sc.logger.Error(msg)
+			return errors.New(msg)
+		} else if retried {
+			return nil
+		}
+
+		var groupTasks []keptnv2.Task
+		if eventToSequence.TaskGroup != "" {
+			groupTasks = getTaskGroupOfSequence(sequence, eventToSequence.TaskGroup)
+			completed, err := sc.isTaskGroupCompleted(event.Shkeptncontext, eventScope, groupTasks)
+			if err != nil {
+				msg := "Could not determine state of task group " + eventToSequence.TaskGroup + ": " + err.Error()
+				sc.logger.Error(msg)
+				return errors.New(msg)
+			} else if !completed {
+				sc.logger.Info("Waiting for remaining tasks of task group " + eventToSequence.TaskGroup + " to be finished")
+				return nil
+			}
+		}
+
 		sc.logger.Info("retrieving all .finished events for task " + trimmedEventType + " triggered by " + event.Triggeredid + " to aggregate data")
 		allFinishedEventsForTask, err := sc.eventRepo.GetEvents(eventScope.Project, db.EventFilter{
 			Type:    "",
@@ -266,6 +362,12 @@ func (sc *shipyardController) handleFinishedEvent(event models.Event) error {
 			}, sequence.Name)
 		}
 
+		if eventToSequence.TaskGroup != "" {
+			// all tasks of the group have been finished -> the worst result of the group is used to proceed with the task sequence
+			mergeTaskGroupResults(eventScope, groupTasks, allFinishedEventsForTask)
+			return sc.proceedTaskSequence(eventScope, sequence, event, shipyard, finishedEventsData, eventToSequence.TaskGroup)
+		}
+
 		split := strings.Split(trimmedEventType, ".")
 
 		if len(split) < 2 {
@@ -358,7 +460,11 @@ func (sc *shipyardController) handleStartedEvent(event models.Event) error {
 		return errNoMatchingEvent
 	}
 
-	return sc.eventRepo.InsertEvent(eventScope.Project, event, db.StartedEvent)
+	if err := sc.eventRepo.InsertEvent(eventScope.Project, event, db.StartedEvent); err != nil {
+		return err
+	}
+	sc.onTaskStarted(event, eventScope)
+	return nil
 }
 
 func (sc *shipyardController) handleTriggeredEvent(event models.Event) error {
@@ -431,6 +537,7 @@ func (sc *shipyardController) handleTriggeredEvent(event models.Event) error {
 	}
 
 	eventScope.Stage = stageName
+	sc.onSequenceTriggered(event.Shkeptncontext, eventScope, taskSequenceName, event.Time)
 
 	eventMap := map[string]interface{}{}
 
@@ -444,11 +551,39 @@ func (sc *shipyardController) handleTriggeredEvent(event models.Event) error {
 		return err
 	}
 
+	start, err := sc.checkSequenceConcurrency(event.Shkeptncontext, eventScope, taskSequenceName, []interface{}{eventMap})
+	if err != nil {
+		sc.logger.Error("Could not check if task sequence " + stageName + "." + taskSequenceName + " can be started: " + err.Error())
+		return err
+	} else if !start {
+		return nil
+	}
+
 	return sc.proceedTaskSequence(eventScope, taskSequence, event, shipyard, []interface{}{eventMap}, "")
 }
 
 func (sc *shipyardController) proceedTaskSequence(eventScope *keptnv2.EventData, taskSequence *keptnv2.Sequence, event models.Event, shipyard *keptnv2.Shipyard, previousFinishedEvents []interface{}, previousTask string) error {
 	task, err := sc.getNextTaskOfSequence(taskSequence, previousTask)
+	for err == nil {
+		// skip all tasks whose condition is not met by the results of the previous tasks
+		conditionMet, conditionErr := isTaskConditionMet(getTaskCondition(*task), previousFinishedEvents)
+		if conditionErr != nil {
+			sc.logger.Error("Could not evaluate condition of task " + task.Name + ": " + conditionErr.Error())
+			return sc.completeTaskSequence(event.Shkeptncontext, &keptnv2.EventData{
+				Project: eventScope.Project,
+				Stage:   eventScope.Stage,
+				Service: eventScope.Service,
+				Labels:  eventScope.Labels,
+				Status:  keptnv2.StatusErrored,
+				Result:  keptnv2.ResultFailed,
+				Message: conditionErr.Error(),
+			}, taskSequence.Name)
+		} else if conditionMet {
+			break
+		}
+		sc.logger.Info("Condition '" + getTaskCondition(*task) + "' of task " + task.Name + " is not met. Skipping task")
+		task, err = sc.getNextTaskOfSequence(taskSequence, task.Name)
+	}
 	if err != nil && err == errNoFurtherTaskForSequence {
 		// get the input for te .triggered event that triggered the previous sequence and append it to the list of previous events to gather all required data for the next stage
 		events, err := sc.eventRepo.GetEvents(eventScope.Project, db.EventFilter{
@@ -489,7 +624,14 @@ func (sc *shipyardController) proceedTaskSequence(eventScope *keptnv2.EventData,
 		sc.logger.Error("Could not get next task of sequence: " + err.Error())
 		return err
 	}
-	return sc.sendTaskTriggeredEvent(event.Shkeptncontext, eventScope, taskSequence.Name, *task, previousFinishedEvents)
+
+	paused, err := sc.holdTaskIfSequencePaused(event.Shkeptncontext, eventScope, taskSequence.Name, *task, previousFinishedEvents)
+	if err != nil {
+		return err
+	} else if paused {
+		return nil
+	}
+	return sc.triggerTask(event.Shkeptncontext, eventScope, taskSequence.Name, *task, previousFinishedEvents)
 }
 
 func (sc *shipyardController) triggerNextTaskSequences(event models.Event, eventScope *keptnv2.EventData, completedSequence *keptnv2.Sequence, shipyard *keptnv2.Shipyard, previousFinishedEvents []interface{}, inputEvent *models.Event) error {
@@ -508,6 +650,14 @@ func (sc *shipyardController) triggerNextTaskSequences(event models.Event, event
 			continue
 		}
 
+		start, err := sc.checkSequenceConcurrency(event.Shkeptncontext, newScope, sequence.Sequence.Name, previousFinishedEvents)
+		if err != nil {
+			sc.logger.Error("could not check if task sequence " + newScope.Stage + "." + sequence.Sequence.Name + " can be started: " + err.Error())
+			continue
+		} else if !start {
+			continue
+		}
+
 		err = sc.proceedTaskSequence(newScope, &sequence.Sequence, event, shipyard, previousFinishedEvents, "")
 		if err != nil {
 			sc.logger.Error("could not proceed task sequence " + newScope.Stage + "." + sequence.Sequence.Name + ".triggered: " + err.Error())
@@ -531,7 +681,7 @@ func (sc *shipyardController) completeTaskSequence(keptnContext string, eventSco
 		KeptnContext: &keptnContext,
 	}, db.FinishedEvent)
 
-	if err != nil {
+	if err != nil && err != db.ErrNoEventFound {
 		sc.logger.Error("could not retrieve task.finished events: " + err.Error())
 		return err
 	}
@@ -544,7 +694,192 @@ func (sc *shipyardController) completeTaskSequence(keptnContext string, eventSco
 		}
 	}
 
-	return sc.sendTaskSequenceFinishedEvent(keptnContext, eventScope, taskSequenceName)
+	sc.onSequenceFinished(keptnContext, eventScope, taskSequenceName)
+	if err := sc.sendTaskSequenceFinishedEvent(keptnContext, eventScope, taskSequenceName); err != nil {
+		return err
+	}
+
+	// the completion of the task sequence might allow a queued task sequence of the same service to be started
+	if err := sc.startNextQueuedSequence(eventScope.Project, eventScope.Stage, eventScope.Service); err != nil {
+		sc.logger.Error("Could not start queued task sequence of service " + eventScope.Service + " in stage " + eventScope.Stage + ": " + err.Error())
+	}
+	return nil
+}
+
+func (sc *shipyardController) handleSequenceAbortedEvent(event models.Event) error {
+	if *event.Source == "shipyard-controller" {
+		sc.logger.Info("Received event from myself. Ignoring.")
+		return nil
+	}
+
+	marshal, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	data := &keptnv2.EventData{}
+	if err := json.Unmarshal(marshal, data); err != nil {
+		return err
+	}
+	if data.Project == "" {
+		return errors.New("event does not contain a project")
+	}
+	return sc.abortSequence(data.Project, event.Shkeptncontext, data.Stage)
+}
+
+// AbortSequence aborts all open task sequences of a project with the given keptnContext and sends a sequence.aborted event.
+// If a stage is provided, only the task sequence running in that stage is aborted
+func (sc *shipyardController) AbortSequence(project, keptnContext, stage string) error {
+	if err := sc.abortSequence(project, keptnContext, stage); err != nil {
+		return err
+	}
+	return sc.sendSequenceAbortedEvent(project, keptnContext, stage)
+}
+
+// abortSequence aborts the task sequences without sending a sequence.aborted event, e.g. because they are aborted due to such an event.
+// The <stage>.<sequence>.finished events of aborted task sequences have the status errored and the result fail
+func (sc *shipyardController) abortSequence(project, keptnContext, stage string) error {
+	sc.logger.Info("Aborting task sequences with KeptnContext " + keptnContext + " in project " + project)
+	filter := db.EventFilter{
+		KeptnContext: &keptnContext,
+	}
+	if stage != "" {
+		filter.Stage = &stage
+	}
+	triggeredEvents, err := sc.eventRepo.GetEvents(project, filter, db.TriggeredEvent)
+	if err != nil && err != db.ErrNoEventFound {
+		sc.logger.Error("could not retrieve open .triggered events: " + err.Error())
+		return err
+	}
+
+	// only task sequences that still have open tasks are aborted - the mapping of a task sequence is removed once it is completed
+	abortedSequences := map[string]*keptnv2.EventData{}
+	for _, triggeredEvent := range triggeredEvents {
+		taskSequence, err := sc.taskSequenceRepo.GetTaskSequence(project, triggeredEvent.ID)
+		if err != nil {
+			sc.logger.Error("Could not retrieve task sequence associated to eventID " + triggeredEvent.ID + ": " + err.Error())
+			return err
+		}
+		if taskSequence == nil {
+			continue
+		}
+		eventScope, err := getEventScope(triggeredEvent)
+		if err != nil {
+			sc.logger.Error("Could not determine scope of .triggered event with ID " + triggeredEvent.ID + ": " + err.Error())
+			continue
+		}
+		abortedSequences[taskSequence.Stage+"."+taskSequence.TaskSequenceName] = &keptnv2.EventData{
+			Project: project,
+			Stage:   taskSequence.Stage,
+			Service: eventScope.Service,
+			Labels:  eventScope.Labels,
+			Status:  keptnv2.StatusErrored,
+			Result:  keptnv2.ResultFailed,
+			Message: "task sequence " + taskSequence.TaskSequenceName + " has been aborted",
+		}
+		if err := sc.deleteOpenEventsOfTask(project, triggeredEvent.ID); err != nil {
+			return err
+		}
+	}
+
+	// task sequences that are paused may not have any open tasks, but still need to be aborted
+	pausedSequences, err := sc.pausedSequenceRepo.GetPausedSequences(project, keptnContext)
+	if err != nil {
+		return err
+	}
+	for _, pausedSequence := range pausedSequences {
+		if stage != "" && pausedSequence.Stage != stage {
+			continue
+		}
+		for _, pendingTask := range pausedSequence.PendingTasks {
+			eventScope := pendingTask.EventScope
+			eventScope.Status = keptnv2.StatusErrored
+			eventScope.Result = keptnv2.ResultFailed
+			eventScope.Message = "task sequence " + pendingTask.TaskSequenceName + " has been aborted"
+			abortedSequences[eventScope.Stage+"."+pendingTask.TaskSequenceName] = &eventScope
+		}
+		if err := sc.pausedSequenceRepo.DeletePausedSequence(project, keptnContext, pausedSequence.Stage); err != nil {
+			return err
+		}
+	}
+
+	// task sequences that are queued do not have any open tasks yet
+	queuedSequences, err := sc.removeQueuedSequences(project, keptnContext, stage)
+	if err != nil {
+		return err
+	}
+	for _, queuedSequence := range queuedSequences {
+		eventScope := queuedSequence.EventScope
+		eventScope.Status = keptnv2.StatusErrored
+		eventScope.Result = keptnv2.ResultFailed
+		eventScope.Message = "task sequence " + queuedSequence.TaskSequenceName + " has been aborted"
+		abortedSequences[queuedSequence.Stage+"."+queuedSequence.TaskSequenceName] = &eventScope
+	}
+
+	if len(abortedSequences) == 0 {
+		return errNoMatchingSequence
+	}
+
+	for _, triggeredEvent := range triggeredEvents {
+		// also remove the events of type sh.keptn.event.<stage>.<sequence>.triggered that started the aborted task sequences
+		sequenceEventType := strings.TrimSuffix(strings.TrimPrefix(*triggeredEvent.Type, "sh.keptn.event."), "."+string(db.TriggeredEvent))
+		if abortedSequences[sequenceEventType] != nil {
+			if err := sc.deleteOpenEventsOfTask(project, triggeredEvent.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	for sequenceEventType, eventScope := range abortedSequences {
+		sequenceName := strings.TrimPrefix(sequenceEventType, eventScope.Stage+".")
+		sc.logger.Info("Aborting task sequence " + sequenceEventType + " with KeptnContext " + keptnContext)
+		if err := sc.completeTaskSequence(keptnContext, eventScope, sequenceName); err != nil {
+			sc.logger.Error("Could not abort task sequence " + sequenceEventType + " with KeptnContext " + keptnContext + ": " + err.Error())
+			return err
+		}
+		sc.setSequenceState(project, keptnContext, eventScope.Stage, sequenceName, models.SequenceAbortedState)
+	}
+	return nil
+}
+
+func (sc *shipyardController) sendSequenceAbortedEvent(project, keptnContext, stage string) error {
+	source, _ := url.Parse("shipyard-controller")
+
+	event := cloudevents.NewEvent()
+	event.SetType(sequenceAbortedEventType)
+	event.SetSource(source.String())
+	event.SetDataContentType(cloudevents.ApplicationJSON)
+	event.SetExtension("shkeptncontext", keptnContext)
+	event.SetData(cloudevents.ApplicationJSON, keptnv2.EventData{
+		Project: project,
+		Stage:   stage,
+		Status:  keptnv2.StatusErrored,
+		Result:  keptnv2.ResultFailed,
+		Message: "task sequences have been aborted",
+	})
+
+	return common.SendEvent(event)
+}
+
+// deleteOpenEventsOfTask deletes a .triggered event and all .started events related to it
+func (sc *shipyardController) deleteOpenEventsOfTask(project, triggeredID string) error {
+	startedEvents, err := sc.eventRepo.GetEvents(project, db.EventFilter{
+		TriggeredID: &triggeredID,
+	}, db.StartedEvent)
+	if err != nil && err != db.ErrNoEventFound {
+		sc.logger.Error("could not retrieve .started events for triggeredid " + triggeredID + ": " + err.Error())
+		return err
+	}
+	for _, startedEvent := range startedEvents {
+		if err := sc.eventRepo.DeleteEvent(project, startedEvent.ID, db.StartedEvent); err != nil {
+			sc.logger.Error("could not delete .started event with ID " + startedEvent.ID + ": " + err.Error())
+			return err
+		}
+	}
+	if err := sc.eventRepo.DeleteEvent(project, triggeredID, db.TriggeredEvent); err != nil {
+		sc.logger.Error("could not delete .triggered event with ID " + triggeredID + ": " + err.Error())
+		return err
+	}
+	return nil
 }
 
 var errNoFurtherTaskForSequence = errors.New("no further task for sequence")
@@ -662,6 +997,7 @@ func (sc *shipyardController) sendTaskSequenceTriggeredEvent(keptnContext string
 	if err := sc.eventRepo.InsertEvent(eventScope.Project, *toEvent, db.TriggeredEvent); err != nil {
 		return fmt.Errorf("could not store event that triggered task sequence: " + err.Error())
 	}
+	sc.onSequenceTriggered(keptnContext, eventScope, taskSequenceName, toEvent.Time)
 
 	return common.SendEvent(event)
 }
@@ -680,7 +1016,7 @@ func (sc *shipyardController) sendTaskSequenceFinishedEvent(keptnContext string,
 	return common.SendEvent(event)
 }
 
-func (sc *shipyardController) sendTaskTriggeredEvent(keptnContext string, eventScope *keptnv2.EventData, taskSequenceName string, task keptnv2.Task, previousFinishedEvents []interface{}) error {
+func (sc *shipyardController) sendTaskTriggeredEvent(keptnContext string, eventScope *keptnv2.EventData, taskSequenceName string, task keptnv2.Task, previousFinishedEvents []interface{}, taskGroup string, attempt int) error {
 
 	eventPayload := map[string]interface{}{}
 
@@ -706,10 +1042,19 @@ func (sc *shipyardController) sendTaskTriggeredEvent(keptnContext string, eventS
 	eventPayload["result"] = eventScope.Result
 	eventPayload["status"] = eventScope.Status
 
+	return sc.sendTriggeredEventOfTask(keptnContext, eventScope, taskSequenceName, task, eventPayload, taskGroup, attempt)
+}
+
+// sendTriggeredEventOfTask stores the .triggered event of a task with the given payload and sends it
+func (sc *shipyardController) sendTriggeredEventOfTask(keptnContext string, eventScope *keptnv2.EventData, taskSequenceName string, task keptnv2.Task, eventPayload interface{}, taskGroup string, attempt int) error {
+	// retries of a task are sent after the backoff defined in its retry policy
+	backoff := getTaskRetryPolicy(task).getBackoffOfAttempt(attempt)
+
 	source, _ := url.Parse("shipyard-controller")
 
 	event := cloudevents.NewEvent()
 	event.SetID(uuid.New().String())
+	event.SetTime(time.Now())
 	event.SetType(keptnv2.GetTriggeredEventType(task.Name))
 	event.SetSource(source.String())
 	event.SetDataContentType(cloudevents.ApplicationJSON)
@@ -725,7 +1070,12 @@ func (sc *shipyardController) sendTaskTriggeredEvent(keptnContext string, eventS
 		return err
 	}
 
-	err = sc.eventRepo.InsertEvent(eventScope.Project, *storeEvent, db.TriggeredEvent)
+	if backoff > 0 {
+		// the event is sent by the delayed event dispatcher once the backoff has expired
+		err = sc.eventRepo.InsertDelayedEvent(eventScope.Project, *storeEvent, db.TriggeredEvent, time.Now().Add(backoff))
+	} else {
+		err = sc.eventRepo.InsertEvent(eventScope.Project, *storeEvent, db.TriggeredEvent)
+	}
 	if err != nil {
 		sc.logger.Error("Could not store event: " + err.Error())
 		return err
@@ -736,11 +1086,18 @@ func (sc *shipyardController) sendTaskTriggeredEvent(keptnContext string, eventS
 		TriggeredEventID: event.ID(),
 		Stage:            eventScope.Stage,
 		KeptnContext:     keptnContext,
+		TaskGroup:        taskGroup,
+		Attempt:          attempt,
 	})
 	if err != nil {
 		sc.logger.Error("Could not store mapping between eventID and task: " + err.Error())
 		return err
 	}
+	sc.onTaskTriggered(keptnContext, eventScope, taskSequenceName, task.Name, taskGroup, event.ID(), storeEvent.Time, attempt)
 
+	if backoff > 0 {
+		sc.logger.Info(fmt.Sprintf("Sending event %s with ID %s in %s", event.Type(), event.ID(), backoff.String()))
+		return nil
+	}
 	return common.SendEvent(event)
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/keptn/keptn/shipyard-controller/db"
 	"github.com/keptn/keptn/shipyard-controller/handler/fake"
 	"github.com/keptn/keptn/shipyard-controller/models"
+	"github.com/stretchr/testify/assert"
 	"os"
 	"reflect"
 	"testing"
@@ -57,6 +58,11 @@ func Test_eventManager_GetAllTriggeredEvents(t *testing.T) {
 			em := &shipyardController{
 				projectRepo: tt.fields.projectRepo,
 				eventRepo:   tt.fields.triggeredEventRepo,
+				pausedSequenceRepo: &fake.PausedSequenceRepository{
+					GetPausedSequencesFunc: func(project, keptnContext string) ([]models.PausedSequence, error) {
+						return []models.PausedSequence{}, nil
+					},
+				},
 			}
 			got, err := em.GetAllTriggeredEvents(tt.args.filter)
 			if (err != nil) != tt.wantErr {
@@ -110,6 +116,11 @@ func Test_eventManager_GetTriggeredEventsOfProject(t *testing.T) {
 			em := &shipyardController{
 				projectRepo: tt.fields.projectRepo,
 				eventRepo:   tt.fields.triggeredEventRepo,
+				pausedSequenceRepo: &fake.PausedSequenceRepository{
+					GetPausedSequencesFunc: func(project, keptnContext string) ([]models.PausedSequence, error) {
+						return []models.PausedSequence{}, nil
+					},
+				},
 			}
 			got, err := em.GetTriggeredEventsOfProject(tt.args.project, tt.args.filter)
 			if (err != nil) != tt.wantErr {
@@ -302,7 +313,12 @@ func Test_eventManager_handleStartedEvent(t *testing.T) {
 			em := &shipyardController{
 				projectRepo: tt.fields.projectRepo,
 				eventRepo:   tt.fields.eventRepo,
-				logger:      tt.fields.logger,
+				taskSequenceRepo: &fake.TaskSequenceRepository{
+					GetTaskSequenceFund: func(project, triggeredID string) (*models.TaskSequenceEvent, error) {
+						return nil, nil
+					},
+				},
+				logger: tt.fields.logger,
 			}
 			err := em.handleStartedEvent(tt.args.event)
 			if (err != nil) != tt.wantErr {
@@ -363,7 +379,12 @@ func Test_eventManager_handleFinishedEvent(t *testing.T) {
 			em := &shipyardController{
 				projectRepo: tt.fields.projectRepo,
 				eventRepo:   tt.fields.eventRepo,
-				logger:      tt.fields.logger,
+				taskSequenceRepo: &fake.TaskSequenceRepository{
+					GetTaskSequenceFund: func(project, triggeredID string) (*models.TaskSequenceEvent, error) {
+						return nil, nil
+					},
+				},
+				logger: tt.fields.logger,
 			}
 			if err := em.handleFinishedEvent(tt.args.event); (err != nil) != tt.wantErr {
 				t.Errorf("handleFinishedEvent() error = %v, wantErr %v", err, tt.wantErr)
@@ -1320,199 +1341,1180 @@ func Test_shipyardController_Scenario5(t *testing.T) {
 	fake.ShouldContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetFinishedEventType("dev.artifact-delivery"), "", nil)
 }
 
-func sendAndVerifyFinishedEvent(t *testing.T, sc *shipyardController, finishedEvent models.Event, eventType, nextEventType string, mockEV *fake.EventBroker, nextStage string, verifyTriggeredEvent func(t *testing.T, e models.Event) bool) (string, bool) {
-	err := sc.HandleIncomingEvent(finishedEvent)
+// Scenario 6: Task sequence is aborted while a task is running
+func Test_shipyardController_Scenario6(t *testing.T) {
+
+	t.Logf("Executing Shipyard Controller Scenario 6 with shipyard file %s", testShipyardFile)
+	sc := getTestShipyardController()
+
+	mockCS := fake.NewConfigurationService(testShipyardResource)
+	defer mockCS.Close()
+
+	_ = os.Setenv("CONFIGURATION_SERVICE", mockCS.URL)
+
+	mockEV := fake.NewEventBroker(t,
+		func(meb *fake.EventBroker, event *models.Event) {
+			meb.ReceivedEvents = append(meb.ReceivedEvents, *event)
+		},
+		func(meb *fake.EventBroker) {
+
+		})
+	defer mockEV.Server.Close()
+	_ = os.Setenv("EVENTBROKER", mockEV.Server.URL)
+
+	// STEP 1
+	// send dev.artifact-delivery.triggered event
+	err := sc.HandleIncomingEvent(getArtifactDeliveryTriggeredEvent())
 	if err != nil {
-		t.Errorf("STEP failed: HandleIncomingEvent(%s) returned %v", *finishedEvent.Type, err)
-		return "", true
+		t.Errorf("STEP 1 failed: HandleIncomingEvent(dev.artifact-delivery.triggered) returned %v", err)
+		return
 	}
 
-	scope, _ := getEventScope(finishedEvent)
-	if nextStage == "" {
-		nextStage = scope.Stage
-	}
-	// check triggeredEvent collection -> should not contain <eventType>.triggered event anymore
 	triggeredEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
-		Type:    keptnv2.GetTriggeredEventType(eventType),
-		Stage:   &scope.Stage,
-		Service: stringp("carts"),
-		Source:  stringp("shipyard-controller"),
+		Type:  keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName),
+		Stage: stringp("dev"),
 	}, db.TriggeredEvent)
-	done := fake.ShouldNotContainEvent(t, triggeredEvents, keptnv2.GetTriggeredEventType(eventType), scope.Stage)
-	if done {
-		return "", true
+	if fake.ShouldContainEvent(t, triggeredEvents, keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName), "", nil) {
+		return
 	}
-
-	// check triggeredEvent collection -> should contain <nextEventType>.triggered event
-	triggeredEvents, _ = sc.eventRepo.GetEvents("test-project", db.EventFilter{
-		Type:    keptnv2.GetTriggeredEventType(nextEventType),
-		Stage:   &nextStage,
-		Service: stringp("carts"),
-		Source:  stringp("shipyard-controller"),
-	}, db.TriggeredEvent)
-
 	triggeredID := triggeredEvents[0].ID
-	done = fake.ShouldContainEvent(t, triggeredEvents, keptnv2.GetTriggeredEventType(nextEventType), nextStage, nil)
-	if done {
-		return "", true
-	}
 
-	// check startedEvent collection -> should not contain <eventType>.started event anymore
-	startedEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
-		Type:        keptnv2.GetStartedEventType(eventType),
-		Stage:       &scope.Stage,
-		Service:     stringp("carts"),
-		TriggeredID: stringp(finishedEvent.Triggeredid),
-	}, db.StartedEvent)
-	done = fake.ShouldNotContainEvent(t, startedEvents, keptnv2.GetStartedEventType(eventType), scope.Stage)
-	if done {
-		return "", true
-	}
-
-	// check event broker -> should contain <nextEventType>.triggered event with properties
-	done = fake.ShouldContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetTriggeredEventType(nextEventType), nextStage, verifyTriggeredEvent)
-	if done {
-		return "", true
+	// STEP 2
+	// send deployment.started event
+	if sendAndVerifyStartedEvent(t, sc, keptnv2.DeploymentTaskName, triggeredID, "dev", "test-source") {
+		return
 	}
-	return triggeredID, false
-}
 
-func sendFinishedEventAndVerifyTaskSequenceCompletion(t *testing.T, sc *shipyardController, finishedEvent models.Event, eventType, taskSequence string, mockEV *fake.EventBroker, nextStage string, verifyTriggeredEvent func(t *testing.T, e models.Event) bool) bool {
-	err := sc.HandleIncomingEvent(finishedEvent)
+	// STEP 3
+	// send sequence.aborted event
+	err = sc.HandleIncomingEvent(models.Event{
+		Data:           keptnv2.EventData{Project: "test-project", Stage: "dev"},
+		ID:             "sequence-aborted-id",
+		Shkeptncontext: "test-context",
+		Source:         stringp("test-source"),
+		Specversion:    "0.2",
+		Type:           stringp(sequenceAbortedEventType),
+	})
 	if err != nil {
-		t.Errorf("STEP failed: HandleIncomingEvent(%s) returned %v", *finishedEvent.Type, err)
-		return true
+		t.Errorf("STEP 3 failed: HandleIncomingEvent(sequence.aborted) returned %v", err)
+		return
 	}
 
-	scope, _ := getEventScope(finishedEvent)
-	if nextStage == "" {
-		nextStage = scope.Stage
-	}
-	// check triggeredEvent collection -> should not contain <eventType>.triggered event anymore
-	triggeredEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
-		Type:    keptnv2.GetTriggeredEventType(eventType),
-		Stage:   &scope.Stage,
-		Service: stringp("carts"),
-		Source:  stringp("shipyard-controller"),
-	}, db.TriggeredEvent)
-	done := fake.ShouldNotContainEvent(t, triggeredEvents, keptnv2.GetTriggeredEventType(eventType), scope.Stage)
+	done := fake.ShouldContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetFinishedEventType("dev.artifact-delivery"), "dev", func(t *testing.T, event models.Event) bool {
+		scope, _ := getEventScope(event)
+		if scope.Status != keptnv2.StatusErrored {
+			t.Errorf("expected status %s but got %s", keptnv2.StatusErrored, scope.Status)
+			return true
+		}
+		return false
+	})
 	if done {
-		return true
+		return
+	}
+	// the sequence.aborted event is not sent again
+	if fake.ShouldNotContainEvent(t, mockEV.ReceivedEvents, sequenceAbortedEventType, "") {
+		return
 	}
 
-	// check startedEvent collection -> should not contain <eventType>.started event anymore
-	startedEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
-		Type:        keptnv2.GetStartedEventType(eventType),
-		Stage:       &scope.Stage,
-		Service:     stringp("carts"),
-		TriggeredID: stringp(finishedEvent.Triggeredid),
-	}, db.StartedEvent)
-	done = fake.ShouldNotContainEvent(t, startedEvents, keptnv2.GetStartedEventType(eventType), scope.Stage)
-	if done {
-		return true
+	triggeredEvents, _ = sc.eventRepo.GetEvents("test-project", db.EventFilter{KeptnContext: stringp("test-context")}, db.TriggeredEvent)
+	if len(triggeredEvents) != 0 {
+		t.Errorf("expected no open .triggered events but got %d", len(triggeredEvents))
+	}
+	startedEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{KeptnContext: stringp("test-context")}, db.StartedEvent)
+	if len(startedEvents) != 0 {
+		t.Errorf("expected no open .started events but got %d", len(startedEvents))
 	}
 
-	// check event broker -> should contain <nextEventType>.triggered event with properties
-	done = fake.ShouldContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetFinishedEventType(taskSequence), nextStage, verifyTriggeredEvent)
-	if done {
-		return true
+	// STEP 4
+	// aborting the sequence again should not be possible
+	if err := sc.AbortSequence("test-project", "test-context", "dev"); err != errNoMatchingSequence {
+		t.Errorf("STEP 4 failed: expected errNoMatchingSequence but got %v", err)
 	}
-	return false
 }
 
-func sendAndVerifyPartialFinishedEvent(t *testing.T, sc *shipyardController, finishedEvent models.Event, eventType, nextEventType string, mockEV *fake.EventBroker, nextStage string) bool {
-	err := sc.HandleIncomingEvent(finishedEvent)
+// Scenario 7: Task sequence is paused and resumed
+func Test_shipyardController_Scenario7(t *testing.T) {
+
+	t.Logf("Executing Shipyard Controller Scenario 7 with shipyard file %s", testShipyardFile)
+	sc := getTestShipyardController()
+
+	mockCS := fake.NewConfigurationService(testShipyardResource)
+	defer mockCS.Close()
+
+	_ = os.Setenv("CONFIGURATION_SERVICE", mockCS.URL)
+
+	mockEV := fake.NewEventBroker(t,
+		func(meb *fake.EventBroker, event *models.Event) {
+			meb.ReceivedEvents = append(meb.ReceivedEvents, *event)
+		},
+		func(meb *fake.EventBroker) {
+
+		})
+	defer mockEV.Server.Close()
+	_ = os.Setenv("EVENTBROKER", mockEV.Server.URL)
+
+	// STEP 1
+	// send dev.artifact-delivery.triggered event
+	err := sc.HandleIncomingEvent(getArtifactDeliveryTriggeredEvent())
 	if err != nil {
-		t.Errorf("STEP failed: HandleIncomingEvent(%s) returned %v", *finishedEvent.Type, err)
-		return true
+		t.Errorf("STEP 1 failed: HandleIncomingEvent(dev.artifact-delivery.triggered) returned %v", err)
+		return
 	}
 
-	scope, _ := getEventScope(finishedEvent)
-	if nextStage == "" {
-		nextStage = scope.Stage
-	}
-	// check triggeredEvent collection -> should still contain <eventType>.triggered event
 	triggeredEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
-		Type:    keptnv2.GetTriggeredEventType(eventType),
-		Stage:   &scope.Stage,
-		Service: stringp("carts"),
-		Source:  stringp("shipyard-controller"),
+		Type:  keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName),
+		Stage: stringp("dev"),
 	}, db.TriggeredEvent)
-	done := fake.ShouldContainEvent(t, triggeredEvents, keptnv2.GetTriggeredEventType(eventType), scope.Stage, nil)
-	if done {
-		return true
+	if fake.ShouldContainEvent(t, triggeredEvents, keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName), "", nil) {
+		return
 	}
+	triggeredID := triggeredEvents[0].ID
 
-	// check triggeredEvent collection -> should not contain <nextEventType>.triggered event
-	triggeredEvents, _ = sc.eventRepo.GetEvents("test-project", db.EventFilter{
-		Type:    keptnv2.GetTriggeredEventType(nextEventType),
-		Stage:   &nextStage,
-		Service: stringp("carts"),
-		Source:  stringp("shipyard-controller"),
-	}, db.TriggeredEvent)
-
-	done = fake.ShouldNotContainEvent(t, triggeredEvents, keptnv2.GetTriggeredEventType(nextEventType), nextStage)
-	if done {
-		return true
+	// STEP 2
+	// pause the sequence -> open tasks should not be visible to Keptn services anymore
+	if err := sc.PauseSequence("test-project", "test-context", "dev"); err != nil {
+		t.Errorf("STEP 2 failed: PauseSequence() returned %v", err)
+		return
+	}
+	visibleEvents, _ := sc.GetTriggeredEventsOfProject("test-project", db.EventFilter{
+		Type: keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName),
+	})
+	if len(visibleEvents) != 0 {
+		t.Errorf("STEP 2 failed: expected no visible .triggered events but got %d", len(visibleEvents))
+		return
 	}
 
-	// check startedEvent collection -> should still contain one <eventType>.started event
-	startedEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
-		Type:        keptnv2.GetStartedEventType(eventType),
-		Stage:       &scope.Stage,
-		Service:     stringp("carts"),
-		TriggeredID: stringp(finishedEvent.Triggeredid),
-	}, db.StartedEvent)
-	if len(startedEvents) != 1 {
-		t.Errorf("List of started events does not hold proper number of events. Expected 1 but got %d", len(startedEvents))
-		return true
+	// STEP 3
+	// finish the running deployment task -> test.triggered should be held back
+	if sendAndVerifyStartedEvent(t, sc, keptnv2.DeploymentTaskName, triggeredID, "dev", "test-source") {
+		return
 	}
-	done = fake.ShouldContainEvent(t, startedEvents, keptnv2.GetStartedEventType(eventType), scope.Stage, nil)
-	if done {
-		return true
+	err = sc.HandleIncomingEvent(getDeploymentFinishedEvent("dev", triggeredID, "test-source"))
+	if err != nil {
+		t.Errorf("STEP 3 failed: HandleIncomingEvent(deployment.finished) returned %v", err)
+		return
+	}
+	if fake.ShouldNotContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetTriggeredEventType(keptnv2.TestTaskName), "") {
+		return
 	}
 
-	// check event broker -> should not contain <nextEventType>.triggered event with properties
-	done = fake.ShouldNotContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetTriggeredEventType(nextEventType), nextStage)
+	// STEP 4
+	// resume the sequence -> test.triggered should be sent
+	if err := sc.ResumeSequence("test-project", "test-context", "dev"); err != nil {
+		t.Errorf("STEP 4 failed: ResumeSequence() returned %v", err)
+		return
+	}
+	done := fake.ShouldContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetTriggeredEventType(keptnv2.TestTaskName), "dev", func(t *testing.T, e models.Event) bool {
+		testData := &keptnv2.TestTriggeredEventData{}
+		marshal, _ := json.Marshal(e.Data)
+		if err := json.Unmarshal(marshal, testData); err != nil {
+			t.Errorf("Expected test.triggered data but could not convert: %v: %s", e.Data, err.Error())
+			return true
+		}
+		if len(testData.Deployment.DeploymentURIsLocal) != 2 {
+			t.Errorf("DeploymentURIsLocal property was not transmitted correctly")
+			return true
+		}
+		return false
+	})
 	if done {
-		return true
+		return
 	}
-	return false
-}
 
-func sendAndVerifyStartedEvent(t *testing.T, sc *shipyardController, taskName string, triggeredID string, stage string, fromSource string) bool {
-	err := sc.HandleIncomingEvent(getStartedEvent(stage, triggeredID, taskName, fromSource))
-	if err != nil {
-		t.Errorf("STEP failed: HandleIncomingEvent(%s.started) returned %v", taskName, err)
-		return true
+	// STEP 5
+	// resuming a sequence that is not paused should not be possible
+	if err := sc.ResumeSequence("test-project", "test-context", "dev"); err != errNoMatchingSequence {
+		t.Errorf("STEP 5 failed: expected errNoMatchingSequence but got %v", err)
 	}
-	// check startedEvent collection -> should contain <taskName>.started event
-	startedEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
-		Type:        keptnv2.GetStartedEventType(taskName),
-		Stage:       stringp(stage),
-		Service:     stringp("carts"),
-		TriggeredID: stringp(triggeredID),
-	}, db.StartedEvent)
-	return fake.ShouldContainEvent(t, startedEvents, keptnv2.GetStartedEventType(taskName), stage, nil)
 }
 
-const testShipyardResourceWithInvalidVersion = `{
-      "resourceContent": "YXBpVmVyc2lvbjogMApraW5kOiBTaGlweWFyZAptZXRhZGF0YToKICBuYW1lOiB0ZXN0LXNoaXB5YXJk",
-      "resourceURI": "shipyard.yaml"
-    }`
+// Scenario 7a: Task sequence is paused in all stages and in a single stage
+func Test_shipyardController_Scenario7a(t *testing.T) {
 
-const testShipyardFileWithInvalidVersion = `apiVersion: 0
-kind: Shipyard
-metadata:
-  name: test-shipyard`
+	t.Logf("Executing Shipyard Controller Scenario 7a with shipyard file %s", testShipyardFile)
+	sc := getTestShipyardController()
 
-const testShipyardResource = `{
-      "resourceContent": "YXBpVmVyc2lvbjogc3BlYy5rZXB0bi5zaC8wLjIuMApraW5kOiBTaGlweWFyZAptZXRhZGF0YToKICBuYW1lOiB0ZXN0LXNoaXB5YXJkCnNwZWM6CiAgc3RhZ2VzOgogIC0gbmFtZTogZGV2CiAgICBzZXF1ZW5jZXM6CiAgICAtIG5hbWU6IGFydGlmYWN0LWRlbGl2ZXJ5CiAgICAgIHRhc2tzOgogICAgICAtIG5hbWU6IGRlcGxveW1lbnQKICAgICAgICBwcm9wZXJ0aWVzOiAgCiAgICAgICAgICBzdHJhdGVneTogZGlyZWN0CiAgICAgIC0gbmFtZTogdGVzdAogICAgICAgIHByb3BlcnRpZXM6CiAgICAgICAgICBraW5kOiBmdW5jdGlvbmFsCiAgICAgIC0gbmFtZTogZXZhbHVhdGlvbiAKICAgICAgLSBuYW1lOiByZWxlYXNlIAoKICAtIG5hbWU6IGhhcmRlbmluZwogICAgc2VxdWVuY2VzOgogICAgLSBuYW1lOiBhcnRpZmFjdC1kZWxpdmVyeQogICAgICB0cmlnZ2VyczoKICAgICAgLSBkZXYuYXJ0aWZhY3QtZGVsaXZlcnkuZmluaXNoZWQKICAgICAgdGFza3M6CiAgICAgIC0gbmFtZTogZGVwbG95bWVudAogICAgICAgIHByb3BlcnRpZXM6IAogICAgICAgICAgc3RyYXRlZ3k6IGJsdWVfZ3JlZW5fc2VydmljZQogICAgICAtIG5hbWU6IHRlc3QKICAgICAgICBwcm9wZXJ0aWVzOiAgCiAgICAgICAgICBraW5kOiBwZXJmb3JtYW5jZQogICAgICAtIG5hbWU6IGV2YWx1YXRpb24KICAgICAgLSBuYW1lOiByZWxlYXNlCiAgICAgICAgCiAgLSBuYW1lOiBwcm9kdWN0aW9uCiAgICBzZXF1ZW5jZXM6CiAgICAtIG5hbWU6IGFydGlmYWN0LWRlbGl2ZXJ5IAogICAgICB0cmlnZ2VyczoKICAgICAgLSBoYXJkZW5pbmcuYXJ0aWZhY3QtZGVsaXZlcnkuZmluaXNoZWQKICAgICAgdGFza3M6CiAgICAgIC0gbmFtZTogZGVwbG95bWVudAogICAgICAgIHByb3BlcnRpZXM6CiAgICAgICAgICBzdHJhdGVneTogYmx1ZV9ncmVlbgogICAgICAtIG5hbWU6IHJlbGVhc2UKICAgICAgCiAgICAtIG5hbWU6IHJlbWVkaWF0aW9uCiAgICAgIHRhc2tzOgogICAgICAtIG5hbWU6IHJlbWVkaWF0aW9uCiAgICAgIC0gbmFtZTogZXZhbHVhdGlvbg==",
-      "resourceURI": "shipyard.yaml"
-    }`
+	mockCS := fake.NewConfigurationService(testShipyardResource)
+	defer mockCS.Close()
 
-const testShipyardFile = `apiVersion: spec.keptn.sh/0.2.0
-kind: Shipyard
+	_ = os.Setenv("CONFIGURATION_SERVICE", mockCS.URL)
+
+	mockEV := fake.NewEventBroker(t,
+		func(meb *fake.EventBroker, event *models.Event) {
+			meb.ReceivedEvents = append(meb.ReceivedEvents, *event)
+		},
+		func(meb *fake.EventBroker) {
+
+		})
+	defer mockEV.Server.Close()
+	_ = os.Setenv("EVENTBROKER", mockEV.Server.URL)
+
+	getVisibleEvents := func() []models.Event {
+		visibleEvents, _ := sc.GetTriggeredEventsOfProject("test-project", db.EventFilter{
+			Type: keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName),
+		})
+		return visibleEvents
+	}
+
+	// STEP 1
+	// send dev.artifact-delivery.triggered event
+	if err := sc.HandleIncomingEvent(getArtifactDeliveryTriggeredEvent()); err != nil {
+		t.Errorf("STEP 1 failed: HandleIncomingEvent(dev.artifact-delivery.triggered) returned %v", err)
+		return
+	}
+
+	// STEP 2
+	// pause all stages, then the dev stage -> the pause of all stages already includes the dev stage
+	if err := sc.PauseSequence("test-project", "test-context", ""); err != nil {
+		t.Errorf("STEP 2 failed: PauseSequence() returned %v", err)
+		return
+	}
+	if err := sc.PauseSequence("test-project", "test-context", "dev"); err != nil {
+		t.Errorf("STEP 2 failed: PauseSequence(dev) returned %v", err)
+		return
+	}
+	pausedSequences, _ := sc.pausedSequenceRepo.GetPausedSequences("test-project", "test-context")
+	if len(pausedSequences) != 1 || pausedSequences[0].Stage != "" {
+		t.Errorf("STEP 2 failed: expected only the pause of all stages but got %v", pausedSequences)
+		return
+	}
+
+	// STEP 3
+	// resuming the dev stage of a task sequence paused in all stages should not be possible
+	if err := sc.ResumeSequence("test-project", "test-context", "dev"); err != errSequencePausedInAllStages {
+		t.Errorf("STEP 3 failed: expected errSequencePausedInAllStages but got %v", err)
+		return
+	}
+	if len(getVisibleEvents()) != 0 {
+		t.Errorf("STEP 3 failed: expected the task sequence to be still paused")
+		return
+	}
+
+	// STEP 4
+	// resume all stages
+	if err := sc.ResumeSequence("test-project", "test-context", ""); err != nil {
+		t.Errorf("STEP 4 failed: ResumeSequence() returned %v", err)
+		return
+	}
+	if len(getVisibleEvents()) != 1 {
+		t.Errorf("STEP 4 failed: expected the task sequence to be resumed")
+		return
+	}
+
+	// STEP 5
+	// pause the dev stage, then all stages -> resuming the dev stage keeps the task sequence paused in all stages
+	if err := sc.PauseSequence("test-project", "test-context", "dev"); err != nil {
+		t.Errorf("STEP 5 failed: PauseSequence(dev) returned %v", err)
+		return
+	}
+	if err := sc.PauseSequence("test-project", "test-context", ""); err != nil {
+		t.Errorf("STEP 5 failed: PauseSequence() returned %v", err)
+		return
+	}
+	if err := sc.ResumeSequence("test-project", "test-context", "dev"); err != nil {
+		t.Errorf("STEP 5 failed: ResumeSequence(dev) returned %v", err)
+		return
+	}
+	if len(getVisibleEvents()) != 0 {
+		t.Errorf("STEP 5 failed: expected the task sequence to be still paused in all stages")
+	}
+}
+
+// Scenario 8: the state of a task sequence and its tasks is tracked while the sequence is executed
+func Test_shipyardController_Scenario8(t *testing.T) {
+
+	t.Logf("Executing Shipyard Controller Scenario 8 with shipyard file %s", testShipyardFile)
+	sc := getTestShipyardController()
+
+	mockCS := fake.NewConfigurationService(testShipyardResource)
+	defer mockCS.Close()
+
+	_ = os.Setenv("CONFIGURATION_SERVICE", mockCS.URL)
+
+	mockEV := fake.NewEventBroker(t,
+		func(meb *fake.EventBroker, event *models.Event) {
+			meb.ReceivedEvents = append(meb.ReceivedEvents, *event)
+		},
+		func(meb *fake.EventBroker) {
+
+		})
+	defer mockEV.Server.Close()
+	_ = os.Setenv("EVENTBROKER", mockEV.Server.URL)
+
+	getState := func() *models.SequenceState {
+		states, err := sc.GetSequenceStates("test-project", db.SequenceStateFilter{
+			KeptnContext: stringp("test-context"),
+			Stage:        stringp("dev"),
+		})
+		if err != nil || len(states) != 1 {
+			t.Errorf("expected exactly one sequence state but got %d (%v)", len(states), err)
+			return nil
+		}
+		return &states[0]
+	}
+
+	// STEP 1
+	// send dev.artifact-delivery.triggered event -> sequence and deployment task should be in state 'triggered'
+	err := sc.HandleIncomingEvent(getArtifactDeliveryTriggeredEvent())
+	if err != nil {
+		t.Errorf("STEP 1 failed: HandleIncomingEvent(dev.artifact-delivery.triggered) returned %v", err)
+		return
+	}
+	triggeredEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
+		Type:  keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName),
+		Stage: stringp("dev"),
+	}, db.TriggeredEvent)
+	if fake.ShouldContainEvent(t, triggeredEvents, keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName), "", nil) {
+		return
+	}
+	triggeredID := triggeredEvents[0].ID
+
+	state := getState()
+	if state == nil {
+		return
+	}
+	assert.Equal(t, "artifact-delivery", state.Name)
+	assert.Equal(t, models.SequenceTriggeredState, state.State)
+	assert.Equal(t, keptnv2.DeploymentTaskName, state.CurrentTask)
+	assert.Equal(t, 1, len(state.Tasks))
+	assert.Equal(t, triggeredID, state.Tasks[0].TriggeredID)
+	assert.Equal(t, models.SequenceTriggeredState, state.Tasks[0].State)
+
+	// STEP 2
+	// send deployment.started event -> sequence and task should be in state 'started'
+	if sendAndVerifyStartedEvent(t, sc, keptnv2.DeploymentTaskName, triggeredID, "dev", "test-source") {
+		return
+	}
+	state = getState()
+	if state == nil {
+		return
+	}
+	assert.Equal(t, models.SequenceStartedState, state.State)
+	assert.Equal(t, models.SequenceStartedState, state.Tasks[0].State)
+	assert.Equal(t, 1, len(state.Tasks[0].Executors))
+	assert.Equal(t, "test-source", state.Tasks[0].Executors[0].Source)
+	assert.NotEmpty(t, state.Tasks[0].Executors[0].StartedAt)
+
+	// STEP 3
+	// send deployment.finished event -> deployment task should be finished and test task should be triggered
+	err = sc.HandleIncomingEvent(getDeploymentFinishedEvent("dev", triggeredID, "test-source"))
+	if err != nil {
+		t.Errorf("STEP 3 failed: HandleIncomingEvent(deployment.finished) returned %v", err)
+		return
+	}
+	state = getState()
+	if state == nil {
+		return
+	}
+	assert.Equal(t, keptnv2.TestTaskName, state.CurrentTask)
+	assert.Equal(t, 2, len(state.Tasks))
+	assert.Equal(t, models.SequenceFinishedState, state.Tasks[0].State)
+	assert.Equal(t, string(keptnv2.ResultPass), state.Tasks[0].Result)
+	assert.NotEmpty(t, state.Tasks[0].Executors[0].FinishedAt)
+	assert.Equal(t, keptnv2.TestTaskName, state.Tasks[1].Name)
+	assert.Equal(t, models.SequenceTriggeredState, state.Tasks[1].State)
+
+	// STEP 4
+	// abort the sequence -> sequence should be in state 'aborted'
+	if err := sc.AbortSequence("test-project", "test-context", "dev"); err != nil {
+		t.Errorf("STEP 4 failed: AbortSequence() returned %v", err)
+		return
+	}
+	state = getState()
+	if state == nil {
+		return
+	}
+	assert.Equal(t, models.SequenceAbortedState, state.State)
+	assert.Equal(t, "", state.CurrentTask)
+	assert.Equal(t, string(keptnv2.ResultFailed), state.Result)
+
+	// aborting the sequence via the API sends a sequence.aborted event
+	fake.ShouldContainEvent(t, mockEV.ReceivedEvents, sequenceAbortedEventType, "", func(t *testing.T, event models.Event) bool {
+		data := &keptnv2.EventData{}
+		marshal, _ := json.Marshal(event.Data)
+		if err := json.Unmarshal(marshal, data); err != nil {
+			t.Errorf("could not decode sequence.aborted event: %v", err)
+			return true
+		}
+		if data.Project != "test-project" || data.Stage != "dev" || data.Status != keptnv2.StatusErrored {
+			t.Errorf("expected sequence.aborted event of stage dev with status %s but got %v", keptnv2.StatusErrored, data)
+			return true
+		}
+		return false
+	})
+}
+
+// Scenario 9: The tasks of a task group are triggered at once. The task sequence proceeds when all tasks of the group have been finished
+func Test_shipyardController_Scenario9(t *testing.T) {
+
+	t.Logf("Executing Shipyard Controller Scenario 9 with shipyard file %s", testShipyardFileWithTaskGroup)
+	sc := getTestShipyardController()
+
+	mockCS := fake.NewConfigurationService(testShipyardResourceWithTaskGroup)
+	defer mockCS.Close()
+
+	_ = os.Setenv("CONFIGURATION_SERVICE", mockCS.URL)
+
+	mockEV := fake.NewEventBroker(t,
+		func(meb *fake.EventBroker, event *models.Event) {
+			meb.ReceivedEvents = append(meb.ReceivedEvents, *event)
+		},
+		func(meb *fake.EventBroker) {
+
+		})
+	defer mockEV.Server.Close()
+	_ = os.Setenv("EVENTBROKER", mockEV.Server.URL)
+
+	getTriggeredID := func(taskName string) string {
+		triggeredEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
+			Type:  keptnv2.GetTriggeredEventType(taskName),
+			Stage: stringp("dev"),
+		}, db.TriggeredEvent)
+		if fake.ShouldContainEvent(t, triggeredEvents, keptnv2.GetTriggeredEventType(taskName), "", nil) {
+			return ""
+		}
+		return triggeredEvents[0].ID
+	}
+
+	// STEP 1
+	// send dev.artifact-delivery.triggered event
+	err := sc.HandleIncomingEvent(getArtifactDeliveryTriggeredEvent())
+	if err != nil {
+		t.Errorf("STEP 1 failed: HandleIncomingEvent(dev.artifact-delivery.triggered) returned %v", err)
+		return
+	}
+	triggeredID := getTriggeredID(keptnv2.DeploymentTaskName)
+	if triggeredID == "" {
+		return
+	}
+
+	// STEP 2
+	// finish the deployment -> test.triggered and security-scan.triggered should be sent at once
+	if sendAndVerifyStartedEvent(t, sc, keptnv2.DeploymentTaskName, triggeredID, "dev", "test-source") {
+		return
+	}
+	err = sc.HandleIncomingEvent(getDeploymentFinishedEvent("dev", triggeredID, "test-source"))
+	if err != nil {
+		t.Errorf("STEP 2 failed: HandleIncomingEvent(deployment.finished) returned %v", err)
+		return
+	}
+	if fake.ShouldContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetTriggeredEventType(keptnv2.TestTaskName), "dev", nil) {
+		return
+	}
+	if fake.ShouldContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetTriggeredEventType("security-scan"), "dev", nil) {
+		return
+	}
+	testTriggeredID := getTriggeredID(keptnv2.TestTaskName)
+	securityScanTriggeredID := getTriggeredID("security-scan")
+	if testTriggeredID == "" || securityScanTriggeredID == "" {
+		return
+	}
+
+	// STEP 3
+	// finish the test task -> release.triggered should not be sent until security-scan has been finished as well
+	if sendAndVerifyStartedEvent(t, sc, keptnv2.TestTaskName, testTriggeredID, "dev", "test-source") {
+		return
+	}
+	err = sc.HandleIncomingEvent(getTestTaskFinishedEvent("dev", testTriggeredID))
+	if err != nil {
+		t.Errorf("STEP 3 failed: HandleIncomingEvent(test.finished) returned %v", err)
+		return
+	}
+	if fake.ShouldNotContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetTriggeredEventType(keptnv2.ReleaseTaskName), "dev") {
+		return
+	}
+
+	// STEP 4
+	// finish the security-scan task with result 'warning' -> release.triggered should be sent with the worst result of the group
+	if sendAndVerifyStartedEvent(t, sc, "security-scan", securityScanTriggeredID, "dev", "security-service") {
+		return
+	}
+	err = sc.HandleIncomingEvent(getSecurityScanFinishedEvent("dev", securityScanTriggeredID, keptnv2.ResultWarning))
+	if err != nil {
+		t.Errorf("STEP 4 failed: HandleIncomingEvent(security-scan.finished) returned %v", err)
+		return
+	}
+	fake.ShouldContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetTriggeredEventType(keptnv2.ReleaseTaskName), "dev", func(t *testing.T, e models.Event) bool {
+		eventData := &keptnv2.EventData{}
+		marshal, _ := json.Marshal(e.Data)
+		_ = json.Unmarshal(marshal, eventData)
+		if eventData.Result != keptnv2.ResultWarning {
+			t.Errorf("Expected result %s but got %s", keptnv2.ResultWarning, eventData.Result)
+			return true
+		}
+		return false
+	})
+}
+
+func getSecurityScanFinishedEvent(stage string, triggeredID string, result keptnv2.ResultType) models.Event {
+	return models.Event{
+		Contenttype: "application/json",
+		Data: keptnv2.EventData{
+			Project: "test-project",
+			Stage:   stage,
+			Service: "carts",
+			Status:  keptnv2.StatusSucceeded,
+			Result:  result,
+		},
+		Extensions:     nil,
+		ID:             "security-scan-finished-id",
+		Shkeptncontext: "test-context",
+		Source:         stringp("security-service"),
+		Specversion:    "0.2",
+		Time:           "",
+		Triggeredid:    triggeredID,
+		Type:           stringp("sh.keptn.event.security-scan.finished"),
+	}
+}
+
+// Scenario 10: Tasks whose condition is not met by the results of the previous tasks are skipped
+func Test_shipyardController_Scenario10(t *testing.T) {
+
+	t.Logf("Executing Shipyard Controller Scenario 10 with shipyard file %s", testShipyardFileWithTaskConditions)
+	sc := getTestShipyardController()
+
+	mockCS := fake.NewConfigurationService(testShipyardResourceWithTaskConditions)
+	defer mockCS.Close()
+
+	_ = os.Setenv("CONFIGURATION_SERVICE", mockCS.URL)
+
+	mockEV := fake.NewEventBroker(t,
+		func(meb *fake.EventBroker, event *models.Event) {
+			meb.ReceivedEvents = append(meb.ReceivedEvents, *event)
+		},
+		func(meb *fake.EventBroker) {
+
+		})
+	defer mockEV.Server.Close()
+	_ = os.Setenv("EVENTBROKER", mockEV.Server.URL)
+
+	// STEP 1
+	// send dev.artifact-delivery.triggered event -> evaluation.triggered should be sent
+	err := sc.HandleIncomingEvent(getArtifactDeliveryTriggeredEvent())
+	if err != nil {
+		t.Errorf("STEP 1 failed: HandleIncomingEvent(dev.artifact-delivery.triggered) returned %v", err)
+		return
+	}
+	triggeredEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
+		Type:  keptnv2.GetTriggeredEventType(keptnv2.EvaluationTaskName),
+		Stage: stringp("dev"),
+	}, db.TriggeredEvent)
+	if fake.ShouldContainEvent(t, triggeredEvents, keptnv2.GetTriggeredEventType(keptnv2.EvaluationTaskName), "", nil) {
+		return
+	}
+	triggeredID := triggeredEvents[0].ID
+
+	// STEP 2
+	// evaluation passes -> rollback should be skipped and release.triggered should be sent
+	if sendAndVerifyStartedEvent(t, sc, keptnv2.EvaluationTaskName, triggeredID, "dev", "test-source") {
+		return
+	}
+	err = sc.HandleIncomingEvent(getEvaluationTaskFinishedEvent("dev", triggeredID, keptnv2.ResultPass))
+	if err != nil {
+		t.Errorf("STEP 2 failed: HandleIncomingEvent(evaluation.finished) returned %v", err)
+		return
+	}
+	if fake.ShouldNotContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetTriggeredEventType("rollback"), "dev") {
+		return
+	}
+	if fake.ShouldContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetTriggeredEventType(keptnv2.ReleaseTaskName), "dev", nil) {
+		return
+	}
+}
+
+// Scenario 11: A task sequence is triggered while another task sequence of the same service is running in the same stage
+func Test_shipyardController_Scenario11(t *testing.T) {
+
+	t.Logf("Executing Shipyard Controller Scenario 11 with shipyard file %s", testShipyardFile)
+
+	mockCS := fake.NewConfigurationService(testShipyardResource)
+	defer mockCS.Close()
+
+	_ = os.Setenv("CONFIGURATION_SERVICE", mockCS.URL)
+	defer os.Unsetenv("SEQUENCE_CONCURRENCY_POLICY")
+
+	getOpenDeploymentEvents := func(sc *shipyardController, keptnContext string) []models.Event {
+		triggeredEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
+			Type:         keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName),
+			Stage:        stringp("dev"),
+			KeptnContext: stringp(keptnContext),
+		}, db.TriggeredEvent)
+		return triggeredEvents
+	}
+	getSequenceState := func(sc *shipyardController, keptnContext string) string {
+		states, _ := sc.GetSequenceStates("test-project", db.SequenceStateFilter{
+			KeptnContext: stringp(keptnContext),
+			Stage:        stringp("dev"),
+		})
+		if len(states) == 0 {
+			return ""
+		}
+		return states[0].State
+	}
+
+	tests := []struct {
+		name                    string
+		policy                  string
+		wantSecondSequenceState string
+		wantFirstSequenceState  string
+	}{
+		{
+			name:                    "queue",
+			policy:                  "queue",
+			wantSecondSequenceState: models.SequenceQueuedState,
+			wantFirstSequenceState:  models.SequenceTriggeredState,
+		},
+		{
+			name:                    "reject",
+			policy:                  "reject",
+			wantSecondSequenceState: models.SequenceFinishedState,
+			wantFirstSequenceState:  models.SequenceTriggeredState,
+		},
+		{
+			name:                    "supersede",
+			policy:                  "supersede",
+			wantSecondSequenceState: models.SequenceTriggeredState,
+			wantFirstSequenceState:  models.SequenceAbortedState,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = os.Setenv("SEQUENCE_CONCURRENCY_POLICY", tt.policy)
+			sc := getTestShipyardController()
+
+			mockEV := fake.NewEventBroker(t,
+				func(meb *fake.EventBroker, event *models.Event) {
+					meb.ReceivedEvents = append(meb.ReceivedEvents, *event)
+				},
+				func(meb *fake.EventBroker) {
+
+				})
+			defer mockEV.Server.Close()
+			_ = os.Setenv("EVENTBROKER", mockEV.Server.URL)
+
+			// STEP 1
+			// send dev.artifact-delivery.triggered event for the first task sequence
+			if err := sc.HandleIncomingEvent(getArtifactDeliveryTriggeredEvent()); err != nil {
+				t.Errorf("STEP 1 failed: HandleIncomingEvent(dev.artifact-delivery.triggered) returned %v", err)
+				return
+			}
+			assert.Equal(t, 1, len(getOpenDeploymentEvents(sc, "test-context")))
+
+			// STEP 2
+			// send dev.artifact-delivery.triggered event for the second task sequence while the first one is still running
+			secondEvent := getArtifactDeliveryTriggeredEvent()
+			secondEvent.ID = "second-artifact-delivery-triggered-id"
+			secondEvent.Shkeptncontext = "second-test-context"
+			if err := sc.HandleIncomingEvent(secondEvent); err != nil {
+				t.Errorf("STEP 2 failed: HandleIncomingEvent(dev.artifact-delivery.triggered) returned %v", err)
+				return
+			}
+			assert.Equal(t, tt.wantSecondSequenceState, getSequenceState(sc, "second-test-context"))
+			assert.Equal(t, tt.wantFirstSequenceState, getSequenceState(sc, "test-context"))
+
+			if tt.policy != "queue" {
+				return
+			}
+			assert.Equal(t, 0, len(getOpenDeploymentEvents(sc, "second-test-context")))
+
+			// STEP 3
+			// abort the first task sequence -> the queued task sequence should be started
+			if err := sc.AbortSequence("test-project", "test-context", "dev"); err != nil {
+				t.Errorf("STEP 3 failed: AbortSequence() returned %v", err)
+				return
+			}
+			assert.Equal(t, models.SequenceTriggeredState, getSequenceState(sc, "second-test-context"))
+			assert.Equal(t, 1, len(getOpenDeploymentEvents(sc, "second-test-context")))
+		})
+	}
+}
+
+// Scenario 12: A task is executed by multiple executors. The task sequence proceeds when all expected executors have finished the task, using the worst result
+func Test_shipyardController_Scenario12(t *testing.T) {
+
+	t.Logf("Executing Shipyard Controller Scenario 12 with shipyard file %s", testShipyardFileWithExpectedExecutors)
+	sc := getTestShipyardController()
+
+	mockCS := fake.NewConfigurationService(testShipyardResourceWithExpectedExecutors)
+	defer mockCS.Close()
+
+	_ = os.Setenv("CONFIGURATION_SERVICE", mockCS.URL)
+
+	mockEV := fake.NewEventBroker(t,
+		func(meb *fake.EventBroker, event *models.Event) {
+			meb.ReceivedEvents = append(meb.ReceivedEvents, *event)
+		},
+		func(meb *fake.EventBroker) {
+
+		})
+	defer mockEV.Server.Close()
+	_ = os.Setenv("EVENTBROKER", mockEV.Server.URL)
+
+	// STEP 1
+	// send dev.artifact-delivery.triggered event
+	err := sc.HandleIncomingEvent(getArtifactDeliveryTriggeredEvent())
+	if err != nil {
+		t.Errorf("STEP 1 failed: HandleIncomingEvent(dev.artifact-delivery.triggered) returned %v", err)
+		return
+	}
+	triggeredEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
+		Type:  keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName),
+		Stage: stringp("dev"),
+	}, db.TriggeredEvent)
+	if fake.ShouldContainEvent(t, triggeredEvents, keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName), "", nil) {
+		return
+	}
+	triggeredID := triggeredEvents[0].ID
+
+	// STEP 2
+	// the first executor finishes the deployment with result 'warning' -> the shipyard-controller should wait for the second executor
+	if sendAndVerifyStartedEvent(t, sc, keptnv2.DeploymentTaskName, triggeredID, "dev", "helm-service") {
+		return
+	}
+	firstFinishedEvent := getDeploymentFinishedEvent("dev", triggeredID, "helm-service")
+	firstFinishedEvent.Data = keptnv2.EventData{
+		Project: "test-project",
+		Stage:   "dev",
+		Service: "carts",
+		Status:  keptnv2.StatusSucceeded,
+		Result:  keptnv2.ResultWarning,
+		Message: "deployment took longer than expected",
+	}
+	if err := sc.HandleIncomingEvent(firstFinishedEvent); err != nil {
+		t.Errorf("STEP 2 failed: HandleIncomingEvent(deployment.finished) returned %v", err)
+		return
+	}
+	if fake.ShouldNotContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetTriggeredEventType(keptnv2.TestTaskName), "dev") {
+		return
+	}
+
+	// STEP 3
+	// the second executor finishes the deployment with result 'pass' -> test.triggered should be sent with the worst result
+	if sendAndVerifyStartedEvent(t, sc, keptnv2.DeploymentTaskName, triggeredID, "dev", "test-source") {
+		return
+	}
+	if err := sc.HandleIncomingEvent(getDeploymentFinishedEvent("dev", triggeredID, "test-source")); err != nil {
+		t.Errorf("STEP 3 failed: HandleIncomingEvent(deployment.finished) returned %v", err)
+		return
+	}
+	fake.ShouldContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetTriggeredEventType(keptnv2.TestTaskName), "dev", func(t *testing.T, e models.Event) bool {
+		eventData := &keptnv2.EventData{}
+		marshal, _ := json.Marshal(e.Data)
+		_ = json.Unmarshal(marshal, eventData)
+		if eventData.Result != keptnv2.ResultWarning {
+			t.Errorf("Expected result %s but got %s", keptnv2.ResultWarning, eventData.Result)
+			return true
+		}
+		return false
+	})
+}
+
+// Scenario 13: A task with a retry policy finishes with status 'errored'. The task is triggered again until the maximum number of attempts has been reached
+func Test_shipyardController_Scenario13(t *testing.T) {
+
+	t.Logf("Executing Shipyard Controller Scenario 13 with shipyard file %s", testShipyardFileWithTaskRetry)
+
+	mockCS := fake.NewConfigurationService(testShipyardResourceWithTaskRetry)
+	defer mockCS.Close()
+
+	_ = os.Setenv("CONFIGURATION_SERVICE", mockCS.URL)
+
+	getOpenDeploymentEvents := func(sc *shipyardController) []models.Event {
+		triggeredEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
+			Type:  keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName),
+			Stage: stringp("dev"),
+		}, db.TriggeredEvent)
+		return triggeredEvents
+	}
+
+	tests := []struct {
+		name                   string
+		wantEventType          string
+		wantSequenceStatus     keptnv2.StatusType
+		wantTasksInSequenceLog int
+	}{
+		{
+			name:                   "second attempt succeeds",
+			wantEventType:          keptnv2.GetTriggeredEventType(keptnv2.TestTaskName),
+			wantTasksInSequenceLog: 3,
+		},
+		{
+			name:                   "second attempt fails",
+			wantEventType:          keptnv2.GetFinishedEventType("dev.artifact-delivery"),
+			wantSequenceStatus:     keptnv2.StatusErrored,
+			wantTasksInSequenceLog: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := getTestShipyardController()
+
+			mockEV := fake.NewEventBroker(t,
+				func(meb *fake.EventBroker, event *models.Event) {
+					meb.ReceivedEvents = append(meb.ReceivedEvents, *event)
+				},
+				func(meb *fake.EventBroker) {
+
+				})
+			defer mockEV.Server.Close()
+			_ = os.Setenv("EVENTBROKER", mockEV.Server.URL)
+
+			// STEP 1
+			// send dev.artifact-delivery.triggered event
+			if err := sc.HandleIncomingEvent(getArtifactDeliveryTriggeredEvent()); err != nil {
+				t.Errorf("STEP 1 failed: HandleIncomingEvent(dev.artifact-delivery.triggered) returned %v", err)
+				return
+			}
+			openEvents := getOpenDeploymentEvents(sc)
+			assert.Equal(t, 1, len(openEvents))
+			firstAttemptID := openEvents[0].ID
+			firstAttemptData := openEvents[0].Data
+
+			// STEP 2
+			// the first attempt fails -> deployment.triggered should be sent again
+			if sendAndVerifyStartedEvent(t, sc, keptnv2.DeploymentTaskName, firstAttemptID, "dev", "test-source") {
+				return
+			}
+			if err := sc.HandleIncomingEvent(getErroredDeploymentFinishedEvent("dev", firstAttemptID, "test-source")); err != nil {
+				t.Errorf("STEP 2 failed: HandleIncomingEvent(deployment.finished) returned %v", err)
+				return
+			}
+			openEvents = getOpenDeploymentEvents(sc)
+			assert.Equal(t, 1, len(openEvents))
+			secondAttemptID := openEvents[0].ID
+			assert.NotEqual(t, firstAttemptID, secondAttemptID)
+			// the second attempt is triggered with the same payload as the first one
+			assert.Equal(t, firstAttemptData, openEvents[0].Data)
+			if fake.ShouldNotContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetFinishedEventType("dev.artifact-delivery"), "dev") {
+				return
+			}
+			mapping, _ := sc.taskSequenceRepo.GetTaskSequence("test-project", secondAttemptID)
+			if assert.NotNil(t, mapping) {
+				assert.Equal(t, 2, mapping.Attempt)
+			}
+
+			// STEP 3
+			// the second attempt finishes
+			if sendAndVerifyStartedEvent(t, sc, keptnv2.DeploymentTaskName, secondAttemptID, "dev", "test-source") {
+				return
+			}
+			finishedEvent := getDeploymentFinishedEvent("dev", secondAttemptID, "test-source")
+			if tt.wantSequenceStatus == keptnv2.StatusErrored {
+				finishedEvent = getErroredDeploymentFinishedEvent("dev", secondAttemptID, "test-source")
+			}
+			if err := sc.HandleIncomingEvent(finishedEvent); err != nil {
+				t.Errorf("STEP 3 failed: HandleIncomingEvent(deployment.finished) returned %v", err)
+				return
+			}
+			assert.Equal(t, 0, len(getOpenDeploymentEvents(sc)))
+			fake.ShouldContainEvent(t, mockEV.ReceivedEvents, tt.wantEventType, "dev", func(t *testing.T, e models.Event) bool {
+				if tt.wantSequenceStatus == "" {
+					return false
+				}
+				scope, _ := getEventScope(e)
+				if scope.Status != tt.wantSequenceStatus {
+					t.Errorf("expected status %s but got %s", tt.wantSequenceStatus, scope.Status)
+					return true
+				}
+				return false
+			})
+
+			states, _ := sc.GetSequenceStates("test-project", db.SequenceStateFilter{KeptnContext: stringp("test-context")})
+			if assert.Equal(t, 1, len(states)) {
+				assert.Equal(t, tt.wantTasksInSequenceLog, len(states[0].Tasks))
+				assert.Equal(t, 2, states[0].Tasks[1].Attempt)
+			}
+		})
+	}
+}
+
+func sendAndVerifyFinishedEvent(t *testing.T, sc *shipyardController, finishedEvent models.Event, eventType, nextEventType string, mockEV *fake.EventBroker, nextStage string, verifyTriggeredEvent func(t *testing.T, e models.Event) bool) (string, bool) {
+	err := sc.HandleIncomingEvent(finishedEvent)
+	if err != nil {
+		t.Errorf("STEP failed: HandleIncomingEvent(%s) returned %v", *finishedEvent.Type, err)
+		return "", true
+	}
+
+	scope, _ := getEventScope(finishedEvent)
+	if nextStage == "" {
+		nextStage = scope.Stage
+	}
+	// check triggeredEvent collection -> should not contain <eventType>.triggered event anymore
+	triggeredEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
+		Type:    keptnv2.GetTriggeredEventType(eventType),
+		Stage:   &scope.Stage,
+		Service: stringp("carts"),
+		Source:  stringp("shipyard-controller"),
+	}, db.TriggeredEvent)
+	done := fake.ShouldNotContainEvent(t, triggeredEvents, keptnv2.GetTriggeredEventType(eventType), scope.Stage)
+	if done {
+		return "", true
+	}
+
+	// check triggeredEvent collection -> should contain <nextEventType>.triggered event
+	triggeredEvents, _ = sc.eventRepo.GetEvents("test-project", db.EventFilter{
+		Type:    keptnv2.GetTriggeredEventType(nextEventType),
+		Stage:   &nextStage,
+		Service: stringp("carts"),
+		Source:  stringp("shipyard-controller"),
+	}, db.TriggeredEvent)
+
+	triggeredID := triggeredEvents[0].ID
+	done = fake.ShouldContainEvent(t, triggeredEvents, keptnv2.GetTriggeredEventType(nextEventType), nextStage, nil)
+	if done {
+		return "", true
+	}
+
+	// check startedEvent collection -> should not contain <eventType>.started event anymore
+	startedEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
+		Type:        keptnv2.GetStartedEventType(eventType),
+		Stage:       &scope.Stage,
+		Service:     stringp("carts"),
+		TriggeredID: stringp(finishedEvent.Triggeredid),
+	}, db.StartedEvent)
+	done = fake.ShouldNotContainEvent(t, startedEvents, keptnv2.GetStartedEventType(eventType), scope.Stage)
+	if done {
+		return "", true
+	}
+
+	// check event broker -> should contain <nextEventType>.triggered event with properties
+	done = fake.ShouldContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetTriggeredEventType(nextEventType), nextStage, verifyTriggeredEvent)
+	if done {
+		return "", true
+	}
+	return triggeredID, false
+}
+
+func sendFinishedEventAndVerifyTaskSequenceCompletion(t *testing.T, sc *shipyardController, finishedEvent models.Event, eventType, taskSequence string, mockEV *fake.EventBroker, nextStage string, verifyTriggeredEvent func(t *testing.T, e models.Event) bool) bool {
+	err := sc.HandleIncomingEvent(finishedEvent)
+	if err != nil {
+		t.Errorf("STEP failed: HandleIncomingEvent(%s) returned %v", *finishedEvent.Type, err)
+		return true
+	}
+
+	scope, _ := getEventScope(finishedEvent)
+	if nextStage == "" {
+		nextStage = scope.Stage
+	}
+	// check triggeredEvent collection -> should not contain <eventType>.triggered event anymore
+	triggeredEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
+		Type:    keptnv2.GetTriggeredEventType(eventType),
+		Stage:   &scope.Stage,
+		Service: stringp("carts"),
+		Source:  stringp("shipyard-controller"),
+	}, db.TriggeredEvent)
+	done := fake.ShouldNotContainEvent(t, triggeredEvents, keptnv2.GetTriggeredEventType(eventType), scope.Stage)
+	if done {
+		return true
+	}
+
+	// check startedEvent collection -> should not contain <eventType>.started event anymore
+	startedEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
+		Type:        keptnv2.GetStartedEventType(eventType),
+		Stage:       &scope.Stage,
+		Service:     stringp("carts"),
+		TriggeredID: stringp(finishedEvent.Triggeredid),
+	}, db.StartedEvent)
+	done = fake.ShouldNotContainEvent(t, startedEvents, keptnv2.GetStartedEventType(eventType), scope.Stage)
+	if done {
+		return true
+	}
+
+	// check event broker -> should contain <nextEventType>.triggered event with properties
+	done = fake.ShouldContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetFinishedEventType(taskSequence), nextStage, verifyTriggeredEvent)
+	if done {
+		return true
+	}
+	return false
+}
+
+func sendAndVerifyPartialFinishedEvent(t *testing.T, sc *shipyardController, finishedEvent models.Event, eventType, nextEventType string, mockEV *fake.EventBroker, nextStage string) bool {
+	err := sc.HandleIncomingEvent(finishedEvent)
+	if err != nil {
+		t.Errorf("STEP failed: HandleIncomingEvent(%s) returned %v", *finishedEvent.Type, err)
+		return true
+	}
+
+	scope, _ := getEventScope(finishedEvent)
+	if nextStage == "" {
+		nextStage = scope.Stage
+	}
+	// check triggeredEvent collection -> should still contain <eventType>.triggered event
+	triggeredEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
+		Type:    keptnv2.GetTriggeredEventType(eventType),
+		Stage:   &scope.Stage,
+		Service: stringp("carts"),
+		Source:  stringp("shipyard-controller"),
+	}, db.TriggeredEvent)
+	done := fake.ShouldContainEvent(t, triggeredEvents, keptnv2.GetTriggeredEventType(eventType), scope.Stage, nil)
+	if done {
+		return true
+	}
+
+	// check triggeredEvent collection -> should not contain <nextEventType>.triggered event
+	triggeredEvents, _ = sc.eventRepo.GetEvents("test-project", db.EventFilter{
+		Type:    keptnv2.GetTriggeredEventType(nextEventType),
+		Stage:   &nextStage,
+		Service: stringp("carts"),
+		Source:  stringp("shipyard-controller"),
+	}, db.TriggeredEvent)
+
+	done = fake.ShouldNotContainEvent(t, triggeredEvents, keptnv2.GetTriggeredEventType(nextEventType), nextStage)
+	if done {
+		return true
+	}
+
+	// check startedEvent collection -> should still contain one <eventType>.started event
+	startedEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
+		Type:        keptnv2.GetStartedEventType(eventType),
+		Stage:       &scope.Stage,
+		Service:     stringp("carts"),
+		TriggeredID: stringp(finishedEvent.Triggeredid),
+	}, db.StartedEvent)
+	if len(startedEvents) != 1 {
+		t.Errorf("List of started events does not hold proper number of events. Expected 1 but got %d", len(startedEvents))
+		return true
+	}
+	done = fake.ShouldContainEvent(t, startedEvents, keptnv2.GetStartedEventType(eventType), scope.Stage, nil)
+	if done {
+		return true
+	}
+
+	// check event broker -> should not contain <nextEventType>.triggered event with properties
+	done = fake.ShouldNotContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetTriggeredEventType(nextEventType), nextStage)
+	if done {
+		return true
+	}
+	return false
+}
+
+func sendAndVerifyStartedEvent(t *testing.T, sc *shipyardController, taskName string, triggeredID string, stage string, fromSource string) bool {
+	err := sc.HandleIncomingEvent(getStartedEvent(stage, triggeredID, taskName, fromSource))
+	if err != nil {
+		t.Errorf("STEP failed: HandleIncomingEvent(%s.started) returned %v", taskName, err)
+		return true
+	}
+	// check startedEvent collection -> should contain <taskName>.started event
+	startedEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
+		Type:        keptnv2.GetStartedEventType(taskName),
+		Stage:       stringp(stage),
+		Service:     stringp("carts"),
+		TriggeredID: stringp(triggeredID),
+	}, db.StartedEvent)
+	return fake.ShouldContainEvent(t, startedEvents, keptnv2.GetStartedEventType(taskName), stage, nil)
+}
+
+const testShipyardResourceWithTaskGroup = `{
+      "resourceContent": "YXBpVmVyc2lvbjogc3BlYy5rZXB0bi5zaC8wLjIuMApraW5kOiBTaGlweWFyZAptZXRhZGF0YToKICBuYW1lOiB0ZXN0LXNoaXB5YXJkCnNwZWM6CiAgc3RhZ2VzOgogIC0gbmFtZTogZGV2CiAgICBzZXF1ZW5jZXM6CiAgICAtIG5hbWU6IGFydGlmYWN0LWRlbGl2ZXJ5CiAgICAgIHRhc2tzOgogICAgICAtIG5hbWU6IGRlcGxveW1lbnQKICAgICAgICBwcm9wZXJ0aWVzOgogICAgICAgICAgc3RyYXRlZ3k6IGRpcmVjdAogICAgICAtIG5hbWU6IHF1YWxpdHktY2hlY2tzCiAgICAgICAgcHJvcGVydGllczoKICAgICAgICAgIHBhcmFsbGVsOgogICAgICAgICAgLSBuYW1lOiB0ZXN0CiAgICAgICAgICAgIHByb3BlcnRpZXM6CiAgICAgICAgICAgICAga2luZDogcGVyZm9ybWFuY2UKICAgICAgICAgIC0gbmFtZTogc2VjdXJpdHktc2NhbgogICAgICAtIG5hbWU6IHJlbGVhc2UK",
+      "resourceURI": "shipyard.yaml"
+    }`
+
+const testShipyardFileWithTaskGroup = `apiVersion: spec.keptn.sh/0.2.0
+kind: Shipyard
+metadata:
+  name: test-shipyard
+spec:
+  stages:
+  - name: dev
+    sequences:
+    - name: artifact-delivery
+      tasks:
+      - name: deployment
+        properties:
+          strategy: direct
+      - name: quality-checks
+        properties:
+          parallel:
+          - name: test
+            properties:
+              kind: performance
+          - name: security-scan
+      - name: release`
+
+const testShipyardResourceWithTaskConditions = `{
+      "resourceContent": "YXBpVmVyc2lvbjogc3BlYy5rZXB0bi5zaC8wLjIuMApraW5kOiBTaGlweWFyZAptZXRhZGF0YToKICBuYW1lOiB0ZXN0LXNoaXB5YXJkCnNwZWM6CiAgc3RhZ2VzOgogIC0gbmFtZTogZGV2CiAgICBzZXF1ZW5jZXM6CiAgICAtIG5hbWU6IGFydGlmYWN0LWRlbGl2ZXJ5CiAgICAgIHRhc2tzOgogICAgICAtIG5hbWU6IGV2YWx1YXRpb24KICAgICAgLSBuYW1lOiByb2xsYmFjawogICAgICAgIHByb3BlcnRpZXM6CiAgICAgICAgICB3aGVuOiAiZXZhbHVhdGlvbi5yZXN1bHQgPT0gZmFpbCIKICAgICAgLSBuYW1lOiByZWxlYXNlCiAgICAgICAgcHJvcGVydGllczoKICAgICAgICAgIHdoZW46ICJldmFsdWF0aW9uLnJlc3VsdCA9PSBwYXNzIgo=",
+      "resourceURI": "shipyard.yaml"
+    }`
+
+const testShipyardFileWithTaskConditions = `apiVersion: spec.keptn.sh/0.2.0
+kind: Shipyard
+metadata:
+  name: test-shipyard
+spec:
+  stages:
+  - name: dev
+    sequences:
+    - name: artifact-delivery
+      tasks:
+      - name: evaluation
+      - name: rollback
+        properties:
+          when: "evaluation.result == fail"
+      - name: release
+        properties:
+          when: "evaluation.result == pass"`
+
+const testShipyardResourceWithExpectedExecutors = `{
+      "resourceContent": "YXBpVmVyc2lvbjogc3BlYy5rZXB0bi5zaC8wLjIuMApraW5kOiBTaGlweWFyZAptZXRhZGF0YToKICBuYW1lOiB0ZXN0LXNoaXB5YXJkCnNwZWM6CiAgc3RhZ2VzOgogIC0gbmFtZTogZGV2CiAgICBzZXF1ZW5jZXM6CiAgICAtIG5hbWU6IGFydGlmYWN0LWRlbGl2ZXJ5CiAgICAgIHRhc2tzOgogICAgICAtIG5hbWU6IGRlcGxveW1lbnQKICAgICAgICBwcm9wZXJ0aWVzOgogICAgICAgICAgZXhlY3V0b3JzOgogICAgICAgICAgLSBoZWxtLXNlcnZpY2UKICAgICAgICAgIC0gdGVzdC1zb3VyY2UKICAgICAgICAgIGV4ZWN1dG9yR3JhY2VQZXJpb2Q6IDEwbQogICAgICAtIG5hbWU6IHRlc3QK",
+      "resourceURI": "shipyard.yaml"
+    }`
+
+const testShipyardFileWithExpectedExecutors = `apiVersion: spec.keptn.sh/0.2.0
+kind: Shipyard
+metadata:
+  name: test-shipyard
+spec:
+  stages:
+  - name: dev
+    sequences:
+    - name: artifact-delivery
+      tasks:
+      - name: deployment
+        properties:
+          executors:
+          - helm-service
+          - test-source
+          executorGracePeriod: 10m
+      - name: test`
+
+const testShipyardResourceWithTaskRetry = `{
+      "resourceContent": "YXBpVmVyc2lvbjogc3BlYy5rZXB0bi5zaC8wLjIuMApraW5kOiBTaGlweWFyZAptZXRhZGF0YToKICBuYW1lOiB0ZXN0LXNoaXB5YXJkCnNwZWM6CiAgc3RhZ2VzOgogIC0gbmFtZTogZGV2CiAgICBzZXF1ZW5jZXM6CiAgICAtIG5hbWU6IGFydGlmYWN0LWRlbGl2ZXJ5CiAgICAgIHRhc2tzOgogICAgICAtIG5hbWU6IGRlcGxveW1lbnQKICAgICAgICBwcm9wZXJ0aWVzOgogICAgICAgICAgcmV0cnk6CiAgICAgICAgICAgIG1heEF0dGVtcHRzOiAyCiAgICAgIC0gbmFtZTogdGVzdAo=",
+      "resourceURI": "shipyard.yaml"
+    }`
+
+const testShipyardFileWithTaskRetry = `apiVersion: spec.keptn.sh/0.2.0
+kind: Shipyard
+metadata:
+  name: test-shipyard
+spec:
+  stages:
+  - name: dev
+    sequences:
+    - name: artifact-delivery
+      tasks:
+      - name: deployment
+        properties:
+          retry:
+            maxAttempts: 2
+      - name: test`
+
+const testShipyardResourceWithInvalidVersion = `{
+      "resourceContent": "YXBpVmVyc2lvbjogMApraW5kOiBTaGlweWFyZAptZXRhZGF0YToKICBuYW1lOiB0ZXN0LXNoaXB5YXJk",
+      "resourceURI": "shipyard.yaml"
+    }`
+
+const testShipyardFileWithInvalidVersion = `apiVersion: 0
+kind: Shipyard
+metadata:
+  name: test-shipyard`
+
+const testShipyardResource = `{
+      "resourceContent": "YXBpVmVyc2lvbjogc3BlYy5rZXB0bi5zaC8wLjIuMApraW5kOiBTaGlweWFyZAptZXRhZGF0YToKICBuYW1lOiB0ZXN0LXNoaXB5YXJkCnNwZWM6CiAgc3RhZ2VzOgogIC0gbmFtZTogZGV2CiAgICBzZXF1ZW5jZXM6CiAgICAtIG5hbWU6IGFydGlmYWN0LWRlbGl2ZXJ5CiAgICAgIHRhc2tzOgogICAgICAtIG5hbWU6IGRlcGxveW1lbnQKICAgICAgICBwcm9wZXJ0aWVzOiAgCiAgICAgICAgICBzdHJhdGVneTogZGlyZWN0CiAgICAgIC0gbmFtZTogdGVzdAogICAgICAgIHByb3BlcnRpZXM6CiAgICAgICAgICBraW5kOiBmdW5jdGlvbmFsCiAgICAgIC0gbmFtZTogZXZhbHVhdGlvbiAKICAgICAgLSBuYW1lOiByZWxlYXNlIAoKICAtIG5hbWU6IGhhcmRlbmluZwogICAgc2VxdWVuY2VzOgogICAgLSBuYW1lOiBhcnRpZmFjdC1kZWxpdmVyeQogICAgICB0cmlnZ2VyczoKICAgICAgLSBkZXYuYXJ0aWZhY3QtZGVsaXZlcnkuZmluaXNoZWQKICAgICAgdGFza3M6CiAgICAgIC0gbmFtZTogZGVwbG95bWVudAogICAgICAgIHByb3BlcnRpZXM6IAogICAgICAgICAgc3RyYXRlZ3k6IGJsdWVfZ3JlZW5fc2VydmljZQogICAgICAtIG5hbWU6IHRlc3QKICAgICAgICBwcm9wZXJ0aWVzOiAgCiAgICAgICAgICBraW5kOiBwZXJmb3JtYW5jZQogICAgICAtIG5hbWU6IGV2YWx1YXRpb24KICAgICAgLSBuYW1lOiByZWxlYXNlCiAgICAgICAgCiAgLSBuYW1lOiBwcm9kdWN0aW9uCiAgICBzZXF1ZW5jZXM6CiAgICAtIG5hbWU6IGFydGlmYWN0LWRlbGl2ZXJ5IAogICAgICB0cmlnZ2VyczoKICAgICAgLSBoYXJkZW5pbmcuYXJ0aWZhY3QtZGVsaXZlcnkuZmluaXNoZWQKICAgICAgdGFza3M6CiAgICAgIC0gbmFtZTogZGVwbG95bWVudAogICAgICAgIHByb3BlcnRpZXM6CiAgICAgICAgICBzdHJhdGVneTogYmx1ZV9ncmVlbgogICAgICAtIG5hbWU6IHJlbGVhc2UKICAgICAgCiAgICAtIG5hbWU6IHJlbWVkaWF0aW9uCiAgICAgIHRhc2tzOgogICAgICAtIG5hbWU6IHJlbWVkaWF0aW9uCiAgICAgIC0gbmFtZTogZXZhbHVhdGlvbg==",
+      "resourceURI": "shipyard.yaml"
+    }`
+
+const testShipyardFile = `apiVersion: spec.keptn.sh/0.2.0
+kind: Shipyard
 metadata:
   name: test-shipyard
 spec:
@@ -1602,6 +2604,12 @@ func getTestShipyardController() *shipyardController {
 	startedEventsCollection := []models.Event{}
 	finishedEventsCollection := []models.Event{}
 	taskSequenceCollection := []models.TaskSequenceEvent{}
+	pausedSequenceCollection := []models.PausedSequence{}
+	sequenceStateCollection := []models.SequenceState{}
+	sequenceQueueCollection := []models.QueuedSequence{}
+	insertionTimes := map[string]time.Time{}
+	notBeforeTimes := map[string]time.Time{}
+	pendingDelivery := map[string]bool{}
 
 	em := &shipyardController{
 		projectRepo: nil,
@@ -1611,7 +2619,17 @@ func getTestShipyardController() *shipyardController {
 					if triggeredEventsCollection == nil || len(triggeredEventsCollection) == 0 {
 						return nil, db.ErrNoEventFound
 					}
-					return filterEvents(triggeredEventsCollection, filter)
+					events, err := filterEvents(triggeredEventsCollection, filter)
+					if err != nil || filter.DueAt == nil {
+						return events, err
+					}
+					dueEvents := []models.Event{}
+					for _, event := range events {
+						if notBefore, ok := notBeforeTimes[event.ID]; !ok || !notBefore.After(*filter.DueAt) {
+							dueEvents = append(dueEvents, event)
+						}
+					}
+					return dueEvents, nil
 				} else if status == db.StartedEvent {
 					if startedEventsCollection == nil || len(startedEventsCollection) == 0 {
 						return nil, db.ErrNoEventFound
@@ -1626,6 +2644,7 @@ func getTestShipyardController() *shipyardController {
 				return nil, nil
 			},
 			InsertEventFunc: func(project string, event models.Event, status db.EventStatus) error {
+				insertionTimes[string(status)+"/"+event.ID] = time.Now()
 				if status == db.TriggeredEvent {
 					triggeredEventsCollection = append(triggeredEventsCollection, event)
 				} else if status == db.StartedEvent {
@@ -1635,6 +2654,33 @@ func getTestShipyardController() *shipyardController {
 				}
 				return nil
 			},
+			InsertDelayedEventFunc: func(project string, event models.Event, status db.EventStatus, notBefore time.Time) error {
+				insertionTimes[string(status)+"/"+event.ID] = time.Now()
+				notBeforeTimes[event.ID] = notBefore
+				pendingDelivery[event.ID] = true
+				triggeredEventsCollection = append(triggeredEventsCollection, event)
+				return nil
+			},
+			GetDueDelayedEventsFunc: func(project string, status db.EventStatus, now time.Time) ([]models.Event, error) {
+				dueEvents := []models.Event{}
+				for _, event := range triggeredEventsCollection {
+					if pendingDelivery[event.ID] && !notBeforeTimes[event.ID].After(now) {
+						dueEvents = append(dueEvents, event)
+					}
+				}
+				return dueEvents, nil
+			},
+			SetDelayedEventSentFunc: func(project string, eventID string, status db.EventStatus) error {
+				delete(pendingDelivery, eventID)
+				return nil
+			},
+			GetEventInsertionTimeFunc: func(project string, eventID string, status db.EventStatus) (time.Time, error) {
+				insertedAt, ok := insertionTimes[string(status)+"/"+eventID]
+				if !ok {
+					return time.Time{}, db.ErrNoEventFound
+				}
+				return insertedAt, nil
+			},
 			DeleteEventFunc: func(project string, eventID string, status db.EventStatus) error {
 				if status == db.TriggeredEvent {
 					for index, event := range triggeredEventsCollection {
@@ -1687,6 +2733,105 @@ func getTestShipyardController() *shipyardController {
 				return nil
 			},
 		},
+		pausedSequenceRepo: &fake.PausedSequenceRepository{
+			GetPausedSequencesFunc: func(project, keptnContext string) ([]models.PausedSequence, error) {
+				result := []models.PausedSequence{}
+				for _, ps := range pausedSequenceCollection {
+					if ps.KeptnContext == keptnContext {
+						result = append(result, ps)
+					}
+				}
+				return result, nil
+			},
+			UpsertPausedSequenceFunc: func(project string, pausedSequence models.PausedSequence) error {
+				for index, ps := range pausedSequenceCollection {
+					if ps.KeptnContext == pausedSequence.KeptnContext && ps.Stage == pausedSequence.Stage {
+						pausedSequenceCollection[index] = pausedSequence
+						return nil
+					}
+				}
+				pausedSequenceCollection = append(pausedSequenceCollection, pausedSequence)
+				return nil
+			},
+			DeletePausedSequenceFunc: func(project, keptnContext, stage string) error {
+				newPausedSequenceCollection := []models.PausedSequence{}
+				for index, ps := range pausedSequenceCollection {
+					if ps.KeptnContext == keptnContext && ps.Stage == stage {
+						continue
+					}
+					newPausedSequenceCollection = append(newPausedSequenceCollection, pausedSequenceCollection[index])
+				}
+				pausedSequenceCollection = newPausedSequenceCollection
+				return nil
+			},
+		},
+		sequenceStateRepo: &fake.SequenceStateRepository{
+			GetSequenceStatesFunc: func(project string, filter db.SequenceStateFilter) ([]models.SequenceState, error) {
+				result := []models.SequenceState{}
+				for _, state := range sequenceStateCollection {
+					if filter.KeptnContext != nil && *filter.KeptnContext != state.KeptnContext {
+						continue
+					}
+					if filter.Stage != nil && *filter.Stage != state.Stage {
+						continue
+					}
+					if filter.Service != nil && *filter.Service != state.Service {
+						continue
+					}
+					if filter.Name != nil && *filter.Name != state.Name {
+						continue
+					}
+					if filter.State != nil && *filter.State != state.State {
+						continue
+					}
+					result = append(result, state)
+				}
+				return result, nil
+			},
+			UpsertSequenceStateFunc: func(project string, state models.SequenceState) error {
+				for index, s := range sequenceStateCollection {
+					if s.KeptnContext == state.KeptnContext && s.Stage == state.Stage && s.Name == state.Name {
+						sequenceStateCollection[index] = state
+						return nil
+					}
+				}
+				sequenceStateCollection = append(sequenceStateCollection, state)
+				return nil
+			},
+		},
+		sequenceQueueRepo: &fake.SequenceQueueRepository{
+			GetQueuedSequencesFunc: func(project string, filter db.SequenceQueueFilter) ([]models.QueuedSequence, error) {
+				result := []models.QueuedSequence{}
+				for _, queuedSequence := range sequenceQueueCollection {
+					if filter.KeptnContext != nil && *filter.KeptnContext != queuedSequence.KeptnContext {
+						continue
+					}
+					if filter.Stage != nil && *filter.Stage != queuedSequence.Stage {
+						continue
+					}
+					if filter.Service != nil && *filter.Service != queuedSequence.Service {
+						continue
+					}
+					result = append(result, queuedSequence)
+				}
+				return result, nil
+			},
+			QueueSequenceFunc: func(project string, queuedSequence models.QueuedSequence) error {
+				sequenceQueueCollection = append(sequenceQueueCollection, queuedSequence)
+				return nil
+			},
+			DeleteQueuedSequenceFunc: func(project, keptnContext, stage, taskSequenceName string) error {
+				newSequenceQueueCollection := []models.QueuedSequence{}
+				for index, queuedSequence := range sequenceQueueCollection {
+					if queuedSequence.KeptnContext == keptnContext && queuedSequence.Stage == stage && queuedSequence.TaskSequenceName == taskSequenceName {
+						continue
+					}
+					newSequenceQueueCollection = append(newSequenceQueueCollection, sequenceQueueCollection[index])
+				}
+				sequenceQueueCollection = newSequenceQueueCollection
+				return nil
+			},
+		},
 		logger: keptncommon.NewLogger("", "", ""),
 	}
 	return em
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"fmt"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"strings"
+)
+
+// taskConditionProperty is the name of the task property that contains the condition under which a task is triggered, e.g. 'evaluation.result == fail'
+const taskConditionProperty = "when"
+
+// getTaskCondition returns the condition of a task, or an empty string if the task should always be triggered
+func getTaskCondition(task keptnv2.Task) string {
+	properties, ok := task.Properties.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	condition, ok := properties[taskConditionProperty].(string)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(condition)
+}
+
+// isTaskConditionMet evaluates the condition of a task against the payloads of the previously finished tasks.
+// A condition consists of comparisons ('<property> == <value>' or '<property> != <value>') that can be combined using '&&' and '||', where '&&' takes precedence.
+// The property is a path within the payloads, e.g. 'evaluation.result' or 'result'. If multiple payloads contain the property, the most recent value is used
+func isTaskConditionMet(condition string, previousFinishedEvents []interface{}) (bool, error) {
+	if condition == "" {
+		return true, nil
+	}
+	for _, alternative := range strings.Split(condition, "||") {
+		allMet := true
+		for _, comparison := range strings.Split(alternative, "&&") {
+			met, err := isComparisonMet(strings.TrimSpace(comparison), previousFinishedEvents)
+			if err != nil {
+				return false, err
+			}
+			if !met {
+				allMet = false
+				break
+			}
+		}
+		if allMet {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func isComparisonMet(comparison string, previousFinishedEvents []interface{}) (bool, error) {
+	operator := "=="
+	if strings.Contains(comparison, "!=") {
+		operator = "!="
+	}
+	split := strings.SplitN(comparison, operator, 2)
+	if len(split) != 2 {
+		return false, fmt.Errorf("invalid task condition '%s': must have the format '<property> == <value>' or '<property> != <value>'", comparison)
+	}
+	property := strings.TrimSpace(split[0])
+	expected := strings.Trim(strings.TrimSpace(split[1]), `"'`)
+	if property == "" {
+		return false, fmt.Errorf("invalid task condition '%s': no property specified", comparison)
+	}
+
+	actual := ""
+	if value, found := getPropertyOfPreviousEvents(property, previousFinishedEvents); found {
+		actual = fmt.Sprintf("%v", value)
+	}
+	if operator == "==" {
+		return actual == expected, nil
+	}
+	return actual != expected, nil
+}
+
+// getPropertyOfPreviousEvents returns the most recent value of the property with the given path, e.g. 'evaluation.result'
+func getPropertyOfPreviousEvents(property string, previousFinishedEvents []interface{}) (interface{}, bool) {
+	path := strings.Split(property, ".")
+	for i := len(previousFinishedEvents) - 1; i >= 0; i-- {
+		var current interface{} = previousFinishedEvents[i]
+		found := true
+		for _, key := range path {
+			currentMap, ok := current.(map[string]interface{})
+			if !ok {
+				found = false
+				break
+			}
+			current, ok = currentMap[key]
+			if !ok {
+				found = false
+				break
+			}
+		}
+		if found {
+			return current, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"testing"
+)
+
+func Test_isTaskConditionMet(t *testing.T) {
+	previousFinishedEvents := []interface{}{
+		map[string]interface{}{
+			"result": "pass",
+			"deployment": map[string]interface{}{
+				"deploymentstrategy": "blue_green_service",
+			},
+		},
+		map[string]interface{}{
+			"result": "fail",
+			"evaluation": map[string]interface{}{
+				"result": "fail",
+				"score":  float64(40),
+			},
+		},
+	}
+	tests := []struct {
+		name      string
+		condition string
+		want      bool
+		wantErr   bool
+	}{
+		{
+			name:      "no condition",
+			condition: "",
+			want:      true,
+		},
+		{
+			name:      "nested property matches",
+			condition: "evaluation.result == fail",
+			want:      true,
+		},
+		{
+			name:      "nested property does not match",
+			condition: "evaluation.result == pass",
+			want:      false,
+		},
+		{
+			name:      "not equal",
+			condition: "evaluation.result != pass",
+			want:      true,
+		},
+		{
+			name:      "most recent value is used",
+			condition: "result == fail",
+			want:      true,
+		},
+		{
+			name:      "quoted value",
+			condition: "deployment.deploymentstrategy == 'blue_green_service'",
+			want:      true,
+		},
+		{
+			name:      "numeric value",
+			condition: "evaluation.score == 40",
+			want:      true,
+		},
+		{
+			name:      "missing property",
+			condition: "release.result == pass",
+			want:      false,
+		},
+		{
+			name:      "and",
+			condition: "evaluation.result == fail && deployment.deploymentstrategy == direct",
+			want:      false,
+		},
+		{
+			name:      "or",
+			condition: "evaluation.result == pass || deployment.deploymentstrategy == blue_green_service",
+			want:      true,
+		},
+		{
+			name:      "invalid condition",
+			condition: "evaluation.result",
+			wantErr:   true,
+		},
+		{
+			name:      "no property",
+			condition: "== fail",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := isTaskConditionMet(tt.condition, previousFinishedEvents)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("isTaskConditionMet() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("isTaskConditionMet() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
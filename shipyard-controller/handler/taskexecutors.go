@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/shipyard-controller/db"
+	"github.com/keptn/keptn/shipyard-controller/models"
+	"strings"
+	"time"
+)
+
+// expectedExecutorsProperty is the name of the task property that contains the Keptn services that are expected to execute a task
+const expectedExecutorsProperty = "executors"
+
+// executorGracePeriodProperty is the name of the task property that defines how long the shipyard-controller waits for the expected executors to send a .started event
+const executorGracePeriodProperty = "executorGracePeriod"
+
+const defaultExecutorGracePeriod = 5 * time.Minute
+
+// getExpectedExecutors returns the expected executors and their grace period as defined in the task properties of a .triggered event
+func getExpectedExecutors(triggeredEvent models.Event) ([]string, time.Duration, error) {
+	taskName, err := getTaskNameOfEvent(triggeredEvent, db.TriggeredEvent)
+	if err != nil {
+		return nil, 0, err
+	}
+	marshal, err := json.Marshal(triggeredEvent.Data)
+	if err != nil {
+		return nil, 0, err
+	}
+	eventData := map[string]interface{}{}
+	if err := json.Unmarshal(marshal, &eventData); err != nil {
+		return nil, 0, err
+	}
+	properties, ok := eventData[taskName].(map[string]interface{})
+	if !ok {
+		return nil, 0, nil
+	}
+
+	executors := []string{}
+	if executorList, ok := properties[expectedExecutorsProperty].([]interface{}); ok {
+		for _, executor := range executorList {
+			if executorName, ok := executor.(string); ok && executorName != "" {
+				executors = append(executors, executorName)
+			}
+		}
+	}
+
+	gracePeriod := defaultExecutorGracePeriod
+	if gracePeriodString, ok := properties[executorGracePeriodProperty].(string); ok {
+		if parsedGracePeriod, err := time.ParseDuration(gracePeriodString); err == nil && parsedGracePeriod > 0 {
+			gracePeriod = parsedGracePeriod
+		}
+	}
+	return executors, gracePeriod, nil
+}
+
+// getMissingExecutors returns the expected executors of a task that have not sent a .finished event yet
+func (sc *shipyardController) getMissingExecutors(project string, triggeredEvent models.Event) ([]string, error) {
+	expectedExecutors, _, err := getExpectedExecutors(triggeredEvent)
+	if err != nil || len(expectedExecutors) == 0 {
+		return nil, err
+	}
+	finishedEvents, err := sc.eventRepo.GetEvents(project, db.EventFilter{
+		Type:        strings.TrimSuffix(*triggeredEvent.Type, string(db.TriggeredEvent)) + string(db.FinishedEvent),
+		TriggeredID: &triggeredEvent.ID,
+	}, db.FinishedEvent)
+	if err != nil && err != db.ErrNoEventFound {
+		return nil, err
+	}
+
+	missingExecutors := []string{}
+	for _, expectedExecutor := range expectedExecutors {
+		responded := false
+		for _, finishedEvent := range finishedEvents {
+			if finishedEvent.Source != nil && *finishedEvent.Source == expectedExecutor {
+				responded = true
+				break
+			}
+		}
+		if !responded {
+			missingExecutors = append(missingExecutors, expectedExecutor)
+		}
+	}
+	return missingExecutors, nil
+}
+
+// closeTasksWithMissingExecutors closes all tasks whose expected executors did not send a .started event within their grace period,
+// except for the tasks of paused task sequences
+func (sc *shipyardController) closeTasksWithMissingExecutors(project string, now time.Time) error {
+	triggeredEvents, err := sc.eventRepo.GetEvents(project, db.EventFilter{DueAt: &now}, db.TriggeredEvent)
+	if err != nil && err != db.ErrNoEventFound {
+		return err
+	}
+	// the tasks of paused task sequences are not closed until the task sequences are resumed
+	triggeredEvents, err = sc.filterEventsOfPausedSequences(project, triggeredEvents)
+	if err != nil {
+		return err
+	}
+	for _, triggeredEvent := range triggeredEvents {
+		expectedExecutors, gracePeriod, err := getExpectedExecutors(triggeredEvent)
+		if err != nil || len(expectedExecutors) == 0 {
+			continue
+		}
+		triggeredAt, err := sc.getSendTimeOfTriggeredEvent(project, triggeredEvent)
+		if err != nil || now.Sub(triggeredAt) <= gracePeriod {
+			continue
+		}
+		// tasks that are still being executed are closed by the task timeout
+		startedEvents, err := sc.eventRepo.GetEvents(project, db.EventFilter{
+			TriggeredID: &triggeredEvent.ID,
+		}, db.StartedEvent)
+		if err != nil && err != db.ErrNoEventFound {
+			return err
+		} else if len(startedEvents) > 0 {
+			continue
+		}
+
+		missingExecutors, err := sc.getMissingExecutors(project, triggeredEvent)
+		if err != nil {
+			return err
+		}
+		for _, missingExecutor := range missingExecutors {
+			sc.logger.Info(fmt.Sprintf("Task %s with triggeredid %s has not been started by %s within %s", *triggeredEvent.Type, triggeredEvent.ID, missingExecutor, gracePeriod.String()))
+			if err := sc.closeTaskOfMissingExecutor(triggeredEvent, missingExecutor, gracePeriod); err != nil {
+				sc.logger.Error("Could not close task with triggeredid " + triggeredEvent.ID + ": " + err.Error())
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// closeTaskOfMissingExecutor closes a task on behalf of an expected executor that did not respond
+func (sc *shipyardController) closeTaskOfMissingExecutor(triggeredEvent models.Event, executor string, gracePeriod time.Duration) error {
+	return sc.closeUnstartedTask(triggeredEvent, executor, fmt.Sprintf("%s did not send a .started event within the grace period of %s", executor, gracePeriod.String()))
+}
+
+// closeUnstartedTask closes a task on behalf of the given executor. For this purpose, a .started event of the executor is recorded first
+func (sc *shipyardController) closeUnstartedTask(triggeredEvent models.Event, executor string, message string) error {
+	eventScope, err := getEventScope(triggeredEvent)
+	if err != nil {
+		return err
+	}
+	startedEvent := models.Event{
+		Contenttype:    "application/json",
+		Data:           keptnv2.EventData{Project: eventScope.Project, Stage: eventScope.Stage, Service: eventScope.Service, Labels: eventScope.Labels},
+		ID:             triggeredEvent.ID + "-" + executor,
+		Shkeptncontext: triggeredEvent.Shkeptncontext,
+		Source:         &executor,
+		Specversion:    triggeredEvent.Specversion,
+		Time:           time.Now().UTC().Format(time.RFC3339),
+		Triggeredid:    triggeredEvent.ID,
+		Type:           stringp(strings.TrimSuffix(*triggeredEvent.Type, string(db.TriggeredEvent)) + string(db.StartedEvent)),
+	}
+	if err := sc.eventRepo.InsertEvent(eventScope.Project, startedEvent, db.StartedEvent); err != nil {
+		return err
+	}
+	return sc.closeStartedTask(startedEvent, message)
+}
+
+// mergeFinishedEventResults sets the result and status of the given event scope to the worst result and status of the .finished events of a task.
+// The messages of all .finished events are kept
+func mergeFinishedEventResults(eventScope *keptnv2.EventData, finishedEvents []models.Event) {
+	if len(finishedEvents) == 0 {
+		return
+	}
+	messages := []string{}
+	for _, finishedEvent := range finishedEvents {
+		finishedEventScope, err := getEventScope(finishedEvent)
+		if err != nil {
+			continue
+		}
+		if resultSeverity[finishedEventScope.Result] > resultSeverity[eventScope.Result] {
+			eventScope.Result = finishedEventScope.Result
+		}
+		if statusSeverity[finishedEventScope.Status] > statusSeverity[eventScope.Status] {
+			eventScope.Status = finishedEventScope.Status
+		}
+		if finishedEventScope.Message != "" {
+			source := ""
+			if finishedEvent.Source != nil {
+				source = *finishedEvent.Source
+			}
+			messages = append(messages, source+": "+finishedEventScope.Message)
+		}
+	}
+	if len(finishedEvents) > 1 {
+		eventScope.Message = strings.Join(messages, "; ")
+	}
+}
+
+func getTaskNameOfEvent(event models.Event, status db.EventStatus) (string, error) {
+	split := strings.Split(strings.TrimSuffix(*event.Type, "."+string(status)), ".")
+	if len(split) < 2 {
+		return "", errors.New("could not determine task name of event type " + *event.Type)
+	}
+	return split[len(split)-1], nil
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/shipyard-controller/db"
+	"github.com/keptn/keptn/shipyard-controller/handler/fake"
+	"github.com/keptn/keptn/shipyard-controller/models"
+	"os"
+	"testing"
+	"time"
+)
+
+func Test_mergeFinishedEventResults(t *testing.T) {
+	getFinishedEvent := func(source string, result keptnv2.ResultType, status keptnv2.StatusType, message string) models.Event {
+		return models.Event{
+			Data:   keptnv2.EventData{Project: "test-project", Stage: "dev", Service: "carts", Result: result, Status: status, Message: message},
+			Source: stringp(source),
+			Type:   stringp(keptnv2.GetFinishedEventType(keptnv2.TestTaskName)),
+		}
+	}
+	tests := []struct {
+		name           string
+		eventScope     keptnv2.EventData
+		finishedEvents []models.Event
+		want           keptnv2.EventData
+	}{
+		{
+			name:       "single executor",
+			eventScope: keptnv2.EventData{Result: keptnv2.ResultPass, Status: keptnv2.StatusSucceeded, Message: "ok"},
+			finishedEvents: []models.Event{
+				getFinishedEvent("jmeter-service", keptnv2.ResultPass, keptnv2.StatusSucceeded, "ok"),
+			},
+			want: keptnv2.EventData{Result: keptnv2.ResultPass, Status: keptnv2.StatusSucceeded, Message: "ok"},
+		},
+		{
+			name:       "worst result wins and all messages are kept",
+			eventScope: keptnv2.EventData{Result: keptnv2.ResultPass, Status: keptnv2.StatusSucceeded, Message: "ok"},
+			finishedEvents: []models.Event{
+				getFinishedEvent("jmeter-service", keptnv2.ResultFailed, keptnv2.StatusSucceeded, "response time too high"),
+				getFinishedEvent("security-service", keptnv2.ResultWarning, keptnv2.StatusSucceeded, ""),
+				getFinishedEvent("my-service", keptnv2.ResultPass, keptnv2.StatusSucceeded, "ok"),
+			},
+			want: keptnv2.EventData{Result: keptnv2.ResultFailed, Status: keptnv2.StatusSucceeded, Message: "jmeter-service: response time too high; my-service: ok"},
+		},
+		{
+			name:       "worst status wins",
+			eventScope: keptnv2.EventData{Result: keptnv2.ResultPass, Status: keptnv2.StatusSucceeded},
+			finishedEvents: []models.Event{
+				getFinishedEvent("jmeter-service", keptnv2.ResultPass, keptnv2.StatusErrored, "could not execute tests"),
+				getFinishedEvent("my-service", keptnv2.ResultPass, keptnv2.StatusSucceeded, ""),
+			},
+			want: keptnv2.EventData{Result: keptnv2.ResultPass, Status: keptnv2.StatusErrored, Message: "jmeter-service: could not execute tests"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventScope := tt.eventScope
+			mergeFinishedEventResults(&eventScope, tt.finishedEvents)
+			if eventScope.Result != tt.want.Result || eventScope.Status != tt.want.Status || eventScope.Message != tt.want.Message {
+				t.Errorf("mergeFinishedEventResults() got = %v, want %v", eventScope, tt.want)
+			}
+		})
+	}
+}
+
+func Test_shipyardController_closeTasksWithMissingExecutors(t *testing.T) {
+	sc := getTestShipyardController()
+
+	mockCS := fake.NewConfigurationService(testShipyardResourceWithExpectedExecutors)
+	defer mockCS.Close()
+
+	_ = os.Setenv("CONFIGURATION_SERVICE", mockCS.URL)
+
+	mockEV := fake.NewEventBroker(t,
+		func(meb *fake.EventBroker, event *models.Event) {
+			meb.ReceivedEvents = append(meb.ReceivedEvents, *event)
+		},
+		func(meb *fake.EventBroker) {
+
+		})
+	defer mockEV.Server.Close()
+	_ = os.Setenv("EVENTBROKER", mockEV.Server.URL)
+
+	// send dev.artifact-delivery.triggered event
+	if err := sc.HandleIncomingEvent(getArtifactDeliveryTriggeredEvent()); err != nil {
+		t.Errorf("HandleIncomingEvent(dev.artifact-delivery.triggered) returned %v", err)
+		return
+	}
+	triggeredEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
+		Type: keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName),
+	}, db.TriggeredEvent)
+	if fake.ShouldContainEvent(t, triggeredEvents, keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName), "", nil) {
+		return
+	}
+	triggeredID := triggeredEvents[0].ID
+
+	// only one of the expected executors executes the task
+	if sendAndVerifyStartedEvent(t, sc, keptnv2.DeploymentTaskName, triggeredID, "dev", "helm-service") {
+		return
+	}
+	if err := sc.HandleIncomingEvent(getDeploymentFinishedEvent("dev", triggeredID, "helm-service")); err != nil {
+		t.Errorf("HandleIncomingEvent(deployment.finished) returned %v", err)
+		return
+	}
+
+	// the grace period has not expired yet
+	if err := sc.closeTasksWithMissingExecutors("test-project", time.Now()); err != nil {
+		t.Errorf("closeTasksWithMissingExecutors() returned %v", err)
+		return
+	}
+	if fake.ShouldNotContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetTriggeredEventType(keptnv2.TestTaskName), "") {
+		return
+	}
+
+	// the stage of the task sequence is paused -> the task is not closed although the grace period of the second executor expires
+	if err := sc.PauseSequence("test-project", "test-context", "dev"); err != nil {
+		t.Errorf("PauseSequence() returned %v", err)
+		return
+	}
+	if err := sc.closeTasksWithMissingExecutors("test-project", time.Now().Add(15*time.Minute)); err != nil {
+		t.Errorf("closeTasksWithMissingExecutors() returned %v", err)
+		return
+	}
+	if fake.ShouldNotContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetFinishedEventType(keptnv2.DeploymentTaskName), "") {
+		return
+	}
+	if err := sc.ResumeSequence("test-project", "test-context", "dev"); err != nil {
+		t.Errorf("ResumeSequence() returned %v", err)
+		return
+	}
+
+	// the grace period of the second executor expires -> the task is closed with status errored
+	if err := sc.closeTasksWithMissingExecutors("test-project", time.Now().Add(15*time.Minute)); err != nil {
+		t.Errorf("closeTasksWithMissingExecutors() returned %v", err)
+		return
+	}
+	done := fake.ShouldContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetFinishedEventType(keptnv2.DeploymentTaskName), "dev", func(t *testing.T, event models.Event) bool {
+		scope, _ := getEventScope(event)
+		if scope.Status != keptnv2.StatusErrored {
+			t.Errorf("expected status %s but got %s", keptnv2.StatusErrored, scope.Status)
+			return true
+		}
+		return false
+	})
+	if done {
+		return
+	}
+	fake.ShouldContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetFinishedEventType("dev.artifact-delivery"), "dev", func(t *testing.T, event models.Event) bool {
+		scope, _ := getEventScope(event)
+		if scope.Status != keptnv2.StatusErrored {
+			t.Errorf("expected status %s but got %s", keptnv2.StatusErrored, scope.Status)
+			return true
+		}
+		return false
+	})
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"encoding/json"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/shipyard-controller/db"
+	"github.com/keptn/keptn/shipyard-controller/models"
+)
+
+// taskGroupProperty is the name of the task property that contains the tasks of a task group. All tasks of a group are triggered at once
+const taskGroupProperty = "parallel"
+
+var resultSeverity = map[keptnv2.ResultType]int{
+	keptnv2.ResultPass:    0,
+	keptnv2.ResultWarning: 1,
+	keptnv2.ResultFailed:  2,
+}
+
+var statusSeverity = map[keptnv2.StatusType]int{
+	keptnv2.StatusSucceeded: 0,
+	keptnv2.StatusUnknown:   1,
+	keptnv2.StatusErrored:   2,
+}
+
+// getTasksOfGroup returns the tasks of a task group, or nil if the given task is not a task group
+func getTasksOfGroup(task keptnv2.Task) []keptnv2.Task {
+	properties, ok := task.Properties.(map[string]interface{})
+	if !ok || properties[taskGroupProperty] == nil {
+		return nil
+	}
+	marshal, err := json.Marshal(properties[taskGroupProperty])
+	if err != nil {
+		return nil
+	}
+	tasks := []keptnv2.Task{}
+	if err := json.Unmarshal(marshal, &tasks); err != nil {
+		return nil
+	}
+	return tasks
+}
+
+// triggerTask sends the .triggered event for the given task. If the task is a task group, the .triggered events for all tasks of the group are sent at once
+func (sc *shipyardController) triggerTask(keptnContext string, eventScope *keptnv2.EventData, taskSequenceName string, task keptnv2.Task, previousFinishedEvents []interface{}) error {
+	groupTasks := getTasksOfGroup(task)
+	if groupTasks == nil {
+		return sc.sendTaskTriggeredEvent(keptnContext, eventScope, taskSequenceName, task, previousFinishedEvents, "", 1)
+	}
+	sc.logger.Info("Triggering all tasks of task group " + task.Name + " of task sequence " + eventScope.Stage + "." + taskSequenceName)
+	for _, groupTask := range groupTasks {
+		if err := sc.sendTaskTriggeredEvent(keptnContext, eventScope, taskSequenceName, groupTask, previousFinishedEvents, task.Name, 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getTaskGroupOfSequence returns the task group with the given name
+func getTaskGroupOfSequence(taskSequence *keptnv2.Sequence, taskGroupName string) []keptnv2.Task {
+	for _, task := range taskSequence.Tasks {
+		if task.Name == taskGroupName {
+			return getTasksOfGroup(task)
+		}
+	}
+	return nil
+}
+
+// isTaskGroupCompleted checks if all tasks of a task group have been finished, i.e. there is no open .triggered event for any of the tasks left
+func (sc *shipyardController) isTaskGroupCompleted(keptnContext string, eventScope *keptnv2.EventData, groupTasks []keptnv2.Task) (bool, error) {
+	for _, groupTask := range groupTasks {
+		openEvents, err := sc.eventRepo.GetEvents(eventScope.Project, db.EventFilter{
+			Type:         keptnv2.GetTriggeredEventType(groupTask.Name),
+			Stage:        &eventScope.Stage,
+			KeptnContext: &keptnContext,
+		}, db.TriggeredEvent)
+		if err != nil && err != db.ErrNoEventFound {
+			return false, err
+		}
+		if len(openEvents) > 0 {
+			sc.logger.Info("Task " + groupTask.Name + " of task group has not been finished yet")
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// mergeTaskGroupResults sets the result and status of the given event scope to the worst result and status reported by the tasks of a task group
+func mergeTaskGroupResults(eventScope *keptnv2.EventData, groupTasks []keptnv2.Task, finishedEvents []models.Event) {
+	for _, finishedEvent := range finishedEvents {
+		if !isFinishedEventOfGroup(finishedEvent, groupTasks) {
+			continue
+		}
+		finishedEventScope, err := getEventScope(finishedEvent)
+		if err != nil {
+			continue
+		}
+		if resultSeverity[finishedEventScope.Result] > resultSeverity[eventScope.Result] {
+			eventScope.Result = finishedEventScope.Result
+		}
+		if statusSeverity[finishedEventScope.Status] > statusSeverity[eventScope.Status] {
+			eventScope.Status = finishedEventScope.Status
+		}
+	}
+}
+
+func isFinishedEventOfGroup(event models.Event, groupTasks []keptnv2.Task) bool {
+	if event.Type == nil {
+		return false
+	}
+	for _, groupTask := range groupTasks {
+		if *event.Type == keptnv2.GetFinishedEventType(groupTask.Name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"fmt"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/shipyard-controller/common"
+	"github.com/keptn/keptn/shipyard-controller/db"
+	"github.com/keptn/keptn/shipyard-controller/models"
+	"time"
+)
+
+// taskRetryProperty is the name of the task property that defines how often a task that finished with status 'errored' is triggered again
+const taskRetryProperty = "retry"
+
+const taskRetryMaxAttemptsProperty = "maxAttempts"
+
+const taskRetryBackoffProperty = "backoff"
+
+const maxTaskRetryBackoff = 1 * time.Hour
+
+const delayedEventCheckInterval = 10 * time.Second
+
+// taskRetryPolicy defines how often a task is attempted and how long the shipyard-controller waits before the first retry. The backoff is doubled with each further retry, up to maxTaskRetryBackoff
+type taskRetryPolicy struct {
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// getTaskRetryPolicy returns the retry policy set in the properties of a task. Tasks without a valid retry policy are attempted once
+func getTaskRetryPolicy(task keptnv2.Task) taskRetryPolicy {
+	policy := taskRetryPolicy{maxAttempts: 1}
+	properties, ok := task.Properties.(map[string]interface{})
+	if !ok {
+		return policy
+	}
+	retryProperties, ok := properties[taskRetryProperty].(map[string]interface{})
+	if !ok {
+		return policy
+	}
+	// numbers in the shipyard are unmarshalled as float64
+	if maxAttempts, ok := retryProperties[taskRetryMaxAttemptsProperty].(float64); ok && maxAttempts > 1 {
+		policy.maxAttempts = int(maxAttempts)
+	}
+	if backoffString, ok := retryProperties[taskRetryBackoffProperty].(string); ok {
+		if backoff, err := time.ParseDuration(backoffString); err == nil && backoff > 0 {
+			policy.backoff = backoff
+		}
+	}
+	return policy
+}
+
+// getBackoffOfAttempt returns how long the shipyard-controller waits before sending the .triggered event of the given attempt.
+// The doubled backoff does not exceed maxTaskRetryBackoff, unless the backoff of the retry policy already does
+func (p taskRetryPolicy) getBackoffOfAttempt(attempt int) time.Duration {
+	if attempt <= 1 || p.backoff == 0 {
+		return 0
+	}
+	backoff := p.backoff
+	for i := 2; i < attempt && backoff < maxTaskRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxTaskRetryBackoff && p.backoff < maxTaskRetryBackoff {
+		return maxTaskRetryBackoff
+	}
+	return backoff
+}
+
+// getSendTimeOfTriggeredEvent returns the time at which a .triggered event is sent, i.e. the time of the event plus the backoff of the attempt it triggers
+func (sc *shipyardController) getSendTimeOfTriggeredEvent(project string, triggeredEvent models.Event) (time.Time, error) {
+	triggeredAt, err := time.Parse(time.RFC3339, triggeredEvent.Time)
+	if err != nil {
+		return time.Time{}, err
+	}
+	eventToSequence, err := sc.taskSequenceRepo.GetTaskSequence(project, triggeredEvent.ID)
+	if err != nil {
+		return time.Time{}, err
+	} else if eventToSequence == nil || eventToSequence.Attempt <= 1 {
+		return triggeredAt, nil
+	}
+
+	taskProperties, err := getTaskPropertiesOfEvent(triggeredEvent)
+	if err != nil {
+		return time.Time{}, err
+	}
+	policy := getTaskRetryPolicy(keptnv2.Task{Properties: taskProperties})
+	return triggeredAt.Add(policy.getBackoffOfAttempt(eventToSequence.Attempt)), nil
+}
+
+// getTaskOfSequence returns the task with the given name. If a task group is set, the task is looked up in the tasks of the group
+func getTaskOfSequence(taskSequence *keptnv2.Sequence, taskName, taskGroup string) *keptnv2.Task {
+	tasks := taskSequence.Tasks
+	if taskGroup != "" {
+		tasks = getTaskGroupOfSequence(taskSequence, taskGroup)
+	}
+	for index := range tasks {
+		if tasks[index].Name == taskName {
+			return &tasks[index]
+		}
+	}
+	return nil
+}
+
+// retryTask triggers a task that has finished with status 'errored' again, if its retry policy allows another attempt.
+// It returns true if the task has been triggered again
+func (sc *shipyardController) retryTask(eventScope *keptnv2.EventData, taskSequence *keptnv2.Sequence, eventToSequence models.TaskSequenceEvent, triggeredEvent models.Event) (bool, error) {
+	if eventScope.Status != keptnv2.StatusErrored {
+		return false, nil
+	}
+	taskName, err := getTaskNameOfEvent(triggeredEvent, db.TriggeredEvent)
+	if err != nil {
+		return false, err
+	}
+	task := getTaskOfSequence(taskSequence, taskName, eventToSequence.TaskGroup)
+	if task == nil {
+		return false, nil
+	}
+
+	attempt := eventToSequence.Attempt
+	if attempt < 1 {
+		attempt = 1
+	}
+	if attempt >= getTaskRetryPolicy(*task).maxAttempts {
+		return false, nil
+	}
+
+	// the .finished events of the failed attempt must not be taken into account for the remaining task sequence
+	finishedEvents, err := sc.eventRepo.GetEvents(eventScope.Project, db.EventFilter{
+		Type:        keptnv2.GetFinishedEventType(taskName),
+		TriggeredID: &triggeredEvent.ID,
+	}, db.FinishedEvent)
+	if err != nil && err != db.ErrNoEventFound {
+		return false, err
+	}
+	for _, finishedEvent := range finishedEvents {
+		if err := sc.eventRepo.DeleteEvent(eventScope.Project, finishedEvent.ID, db.FinishedEvent); err != nil {
+			return false, err
+		}
+	}
+
+	// the task is triggered with the same payload as the failed attempt
+	retryScope, err := getEventScope(triggeredEvent)
+	if err != nil {
+		return false, err
+	}
+	sc.logger.Info(fmt.Sprintf("Task %s of task sequence %s.%s with KeptnContext %s finished with status %s. Starting attempt %d", taskName, eventToSequence.Stage, eventToSequence.TaskSequenceName, eventToSequence.KeptnContext, eventScope.Status, attempt+1))
+	if err := sc.sendTriggeredEventOfTask(eventToSequence.KeptnContext, retryScope, eventToSequence.TaskSequenceName, *task, triggeredEvent.Data, eventToSequence.TaskGroup, attempt+1); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// StartDelayedEventDispatcher periodically sends the .triggered events of retried tasks whose backoff has expired
+func (sc *shipyardController) StartDelayedEventDispatcher() {
+	sc.logger.Info(fmt.Sprintf("Checking for delayed events every %s", delayedEventCheckInterval.String()))
+	for {
+		<-time.After(delayedEventCheckInterval)
+		if err := sc.sendDueDelayedEvents(time.Now()); err != nil {
+			sc.logger.Error("Could not send delayed events: " + err.Error())
+		}
+	}
+}
+
+// sendDueDelayedEvents sends all delayed .triggered events that are due at the given time. Events that have been removed in the meantime,
+// e.g. because the task sequence has been aborted, are not sent
+func (sc *shipyardController) sendDueDelayedEvents(now time.Time) error {
+	projects, err := sc.projectRepo.GetProjects()
+	if err != nil {
+		return err
+	}
+
+	for _, project := range projects {
+		dueEvents, err := sc.eventRepo.GetDueDelayedEvents(project, db.TriggeredEvent, now)
+		if err != nil && err != db.ErrNoEventFound {
+			sc.logger.Error("Could not retrieve delayed events of project " + project + ": " + err.Error())
+			continue
+		}
+		for _, dueEvent := range dueEvents {
+			if err := sc.sendDelayedEvent(project, dueEvent); err != nil {
+				sc.logger.Error("Could not send event " + *dueEvent.Type + " with ID " + dueEvent.ID + ": " + err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+func (sc *shipyardController) sendDelayedEvent(project string, storedEvent models.Event) error {
+	event := cloudevents.NewEvent()
+	event.SetID(storedEvent.ID)
+	if eventTime, err := time.Parse(time.RFC3339, storedEvent.Time); err == nil {
+		event.SetTime(eventTime)
+	}
+	event.SetType(*storedEvent.Type)
+	event.SetSource(*storedEvent.Source)
+	event.SetDataContentType(cloudevents.ApplicationJSON)
+	event.SetExtension("shkeptncontext", storedEvent.Shkeptncontext)
+	if err := event.SetData(cloudevents.ApplicationJSON, storedEvent.Data); err != nil {
+		return err
+	}
+
+	if err := common.SendEvent(event); err != nil {
+		return err
+	}
+	return sc.eventRepo.SetDelayedEventSent(project, storedEvent.ID, db.TriggeredEvent)
+}
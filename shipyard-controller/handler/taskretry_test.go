@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"encoding/base64"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/shipyard-controller/db"
+	"github.com/keptn/keptn/shipyard-controller/handler/fake"
+	"github.com/keptn/keptn/shipyard-controller/models"
+	"os"
+	"testing"
+	"time"
+)
+
+func Test_getTaskRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name string
+		task keptnv2.Task
+		want taskRetryPolicy
+	}{
+		{
+			name: "no properties",
+			task: keptnv2.Task{Name: "test"},
+			want: taskRetryPolicy{maxAttempts: 1},
+		},
+		{
+			name: "no retry policy",
+			task: keptnv2.Task{Name: "test", Properties: map[string]interface{}{"teststrategy": "performance"}},
+			want: taskRetryPolicy{maxAttempts: 1},
+		},
+		{
+			name: "max attempts and backoff",
+			task: keptnv2.Task{Name: "test", Properties: map[string]interface{}{
+				"retry": map[string]interface{}{"maxAttempts": float64(3), "backoff": "30s"},
+			}},
+			want: taskRetryPolicy{maxAttempts: 3, backoff: 30 * time.Second},
+		},
+		{
+			name: "invalid values",
+			task: keptnv2.Task{Name: "test", Properties: map[string]interface{}{
+				"retry": map[string]interface{}{"maxAttempts": "three", "backoff": "soon"},
+			}},
+			want: taskRetryPolicy{maxAttempts: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getTaskRetryPolicy(tt.task); got != tt.want {
+				t.Errorf("getTaskRetryPolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_taskRetryPolicy_getBackoffOfAttempt(t *testing.T) {
+	policy := taskRetryPolicy{maxAttempts: 4, backoff: 10 * time.Second}
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 0},
+		{attempt: 2, want: 10 * time.Second},
+		{attempt: 3, want: 20 * time.Second},
+		{attempt: 4, want: 40 * time.Second},
+		{attempt: 10, want: 2560 * time.Second},
+		{attempt: 11, want: maxTaskRetryBackoff},
+		{attempt: 100, want: maxTaskRetryBackoff},
+	}
+	for _, tt := range tests {
+		if got := policy.getBackoffOfAttempt(tt.attempt); got != tt.want {
+			t.Errorf("getBackoffOfAttempt(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+
+	// a backoff that already exceeds the maximum is not doubled
+	policy = taskRetryPolicy{maxAttempts: 4, backoff: 2 * time.Hour}
+	if got := policy.getBackoffOfAttempt(4); got != 2*time.Hour {
+		t.Errorf("getBackoffOfAttempt(4) = %v, want %v", got, 2*time.Hour)
+	}
+}
+
+func Test_shipyardController_sendDueDelayedEvents(t *testing.T) {
+	shipyardResource := `{
+      "resourceContent": "` + base64.StdEncoding.EncodeToString([]byte(testShipyardFileWithTaskRetryBackoff)) + `",
+      "resourceURI": "shipyard.yaml"
+    }`
+	mockCS := fake.NewConfigurationService(shipyardResource)
+	defer mockCS.Close()
+	_ = os.Setenv("CONFIGURATION_SERVICE", mockCS.URL)
+
+	mockEV := fake.NewEventBroker(t,
+		func(meb *fake.EventBroker, event *models.Event) {
+			meb.ReceivedEvents = append(meb.ReceivedEvents, *event)
+		},
+		func(meb *fake.EventBroker) {
+
+		})
+	defer mockEV.Server.Close()
+	_ = os.Setenv("EVENTBROKER", mockEV.Server.URL)
+
+	sc := getTestShipyardController()
+	sc.projectRepo = &fake.ProjectRepository{GetProjectsFunc: func() ([]string, error) {
+		return []string{"test-project"}, nil
+	}}
+	deploymentFilter := db.EventFilter{Type: keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName)}
+
+	if err := sc.HandleIncomingEvent(getArtifactDeliveryTriggeredEvent()); err != nil {
+		t.Errorf("HandleIncomingEvent(dev.artifact-delivery.triggered) returned %v", err)
+		return
+	}
+	openEvents, _ := sc.GetTriggeredEventsOfProject("test-project", deploymentFilter)
+	if len(openEvents) != 1 {
+		t.Errorf("expected 1 open deployment.triggered event but got %d", len(openEvents))
+		return
+	}
+	firstAttemptID := openEvents[0].ID
+
+	// the first attempt fails -> the second attempt is stored, but not sent before the backoff has expired
+	if sendAndVerifyStartedEvent(t, sc, keptnv2.DeploymentTaskName, firstAttemptID, "dev", "test-source") {
+		return
+	}
+	retriedAt := time.Now()
+	if err := sc.HandleIncomingEvent(getErroredDeploymentFinishedEvent("dev", firstAttemptID, "test-source")); err != nil {
+		t.Errorf("HandleIncomingEvent(deployment.finished) returned %v", err)
+		return
+	}
+	mockEV.ReceivedEvents = []models.Event{}
+	if openEvents, _ := sc.GetTriggeredEventsOfProject("test-project", deploymentFilter); len(openEvents) != 0 {
+		t.Errorf("expected no open deployment.triggered events before the backoff has expired but got %d", len(openEvents))
+	}
+	if err := sc.sendDueDelayedEvents(retriedAt.Add(30 * time.Second)); err != nil {
+		t.Errorf("sendDueDelayedEvents() returned %v", err)
+		return
+	}
+	fake.ShouldNotContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName), "")
+
+	// the backoff has expired -> the second attempt is sent once
+	if err := sc.sendDueDelayedEvents(retriedAt.Add(2 * time.Minute)); err != nil {
+		t.Errorf("sendDueDelayedEvents() returned %v", err)
+		return
+	}
+	if err := sc.sendDueDelayedEvents(retriedAt.Add(3 * time.Minute)); err != nil {
+		t.Errorf("sendDueDelayedEvents() returned %v", err)
+		return
+	}
+	if len(mockEV.ReceivedEvents) != 1 {
+		t.Errorf("expected 1 sent event but got %d", len(mockEV.ReceivedEvents))
+		return
+	}
+	sentEvent := mockEV.ReceivedEvents[0]
+	if *sentEvent.Type != keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName) || sentEvent.ID == firstAttemptID {
+		t.Errorf("expected the second attempt of the deployment to be sent but got %s with ID %s", *sentEvent.Type, sentEvent.ID)
+	}
+	// the time of the event is the time it has been created, not the time it is due
+	if eventTime, err := time.Parse(time.RFC3339, sentEvent.Time); err != nil || eventTime.After(time.Now()) {
+		t.Errorf("unexpected time of the delayed event: %s", sentEvent.Time)
+	}
+}
+
+const testShipyardFileWithTaskRetryBackoff = `apiVersion: spec.keptn.sh/0.2.0
+kind: Shipyard
+metadata:
+  name: test-shipyard
+spec:
+  stages:
+  - name: dev
+    sequences:
+    - name: artifact-delivery
+      tasks:
+      - name: deployment
+        properties:
+          retry:
+            maxAttempts: 2
+            backoff: 1m
+      - name: test`
@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/shipyard-controller/common"
+	"github.com/keptn/keptn/shipyard-controller/db"
+	"github.com/keptn/keptn/shipyard-controller/models"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// taskTimeoutProperty is the name of the task property that defines how long the shipyard-controller waits for a .finished event
+const taskTimeoutProperty = "timeout"
+
+const defaultTaskTimeout = 24 * time.Hour
+
+const taskTimeoutCheckInterval = 1 * time.Minute
+
+// unstartedTaskSource is the source on whose behalf tasks that have not been started by any Keptn service are closed
+const unstartedTaskSource = "shipyard-controller-task-timeout"
+
+// StartTaskTimeoutReaper periodically closes all tasks that have not been started or finished within their timeout, as well as tasks whose expected executors did not respond
+func (sc *shipyardController) StartTaskTimeoutReaper() {
+	sc.logger.Info(fmt.Sprintf("Checking for timed out tasks every %s", taskTimeoutCheckInterval.String()))
+	for {
+		<-time.After(taskTimeoutCheckInterval)
+		if err := sc.closeTimedOutTasks(time.Now()); err != nil {
+			sc.logger.Error("Could not close timed out tasks: " + err.Error())
+		}
+	}
+}
+
+func (sc *shipyardController) closeTimedOutTasks(now time.Time) error {
+	projects, err := sc.projectRepo.GetProjects()
+	if err != nil {
+		return err
+	}
+
+	defaultTimeout := getDefaultTaskTimeout()
+	for _, project := range projects {
+		startedEvents, err := sc.eventRepo.GetEvents(project, db.EventFilter{}, db.StartedEvent)
+		if err != nil && err != db.ErrNoEventFound {
+			sc.logger.Error("Could not retrieve .started events of project " + project + ": " + err.Error())
+			continue
+		}
+		for _, startedEvent := range startedEvents {
+			startedAt, err := sc.getStartTimeOfTask(project, startedEvent)
+			if err != nil {
+				sc.logger.Error("Could not determine start time of task started with event " + startedEvent.ID + ": " + err.Error())
+				continue
+			}
+
+			timeout, err := sc.getTimeoutOfStartedTask(project, startedEvent, defaultTimeout)
+			if err != nil {
+				sc.logger.Error("Could not determine timeout of task started with event " + startedEvent.ID + ": " + err.Error())
+				continue
+			}
+			if now.Sub(startedAt) <= timeout {
+				continue
+			}
+
+			sc.logger.Info(fmt.Sprintf("Task %s with triggeredid %s has not been finished by %s within %s", *startedEvent.Type, startedEvent.Triggeredid, *startedEvent.Source, timeout.String()))
+			if err := sc.closeTimedOutTask(startedEvent, timeout); err != nil {
+				sc.logger.Error("Could not close timed out task with triggeredid " + startedEvent.Triggeredid + ": " + err.Error())
+			}
+		}
+
+		if err := sc.closeTasksWithMissingExecutors(project, now); err != nil {
+			sc.logger.Error("Could not close tasks with missing executors in project " + project + ": " + err.Error())
+		}
+		if err := sc.closeUnstartedTasks(project, now, defaultTimeout); err != nil {
+			sc.logger.Error("Could not close unstarted tasks in project " + project + ": " + err.Error())
+		}
+	}
+	return nil
+}
+
+// getStartTimeOfTask returns the time of the .started event, or the time at which it has been stored if its time cannot be parsed
+func (sc *shipyardController) getStartTimeOfTask(project string, startedEvent models.Event) (time.Time, error) {
+	startedAt, err := time.Parse(time.RFC3339, startedEvent.Time)
+	if err == nil {
+		return startedAt, nil
+	}
+	sc.logger.Error("Could not parse time of .started event with ID " + startedEvent.ID + ", using the time at which it has been stored instead: " + err.Error())
+	return sc.eventRepo.GetEventInsertionTime(project, startedEvent.ID, db.StartedEvent)
+}
+
+// getTimeoutOfStartedTask returns the timeout defined in the task properties of the .triggered event that belongs to the .started event
+func (sc *shipyardController) getTimeoutOfStartedTask(project string, startedEvent models.Event, defaultTimeout time.Duration) (time.Duration, error) {
+	trimmedEventType := strings.TrimSuffix(*startedEvent.Type, string(db.StartedEvent))
+	triggeredEvents, err := sc.eventRepo.GetEvents(project, db.EventFilter{
+		Type: trimmedEventType + string(db.TriggeredEvent),
+		ID:   &startedEvent.Triggeredid,
+	}, db.TriggeredEvent)
+	if err != nil && err != db.ErrNoEventFound {
+		return 0, err
+	} else if len(triggeredEvents) == 0 {
+		return defaultTimeout, nil
+	}
+
+	taskProperties, err := getTaskPropertiesOfEvent(triggeredEvents[0])
+	if err != nil {
+		return 0, err
+	}
+	return getTaskTimeout(taskProperties, defaultTimeout), nil
+}
+
+// closeUnstartedTasks closes all tasks that have not been started by any Keptn service within their timeout. Otherwise, their task sequence
+// would never be finished and block the task sequences queued for the same service and stage. Tasks with expected executors are closed
+// after the grace period of the executors instead, tasks of paused task sequences are skipped
+func (sc *shipyardController) closeUnstartedTasks(project string, now time.Time, defaultTimeout time.Duration) error {
+	triggeredEvents, err := sc.eventRepo.GetEvents(project, db.EventFilter{DueAt: &now}, db.TriggeredEvent)
+	if err != nil && err != db.ErrNoEventFound {
+		return err
+	}
+	// the tasks of paused task sequences are not closed until the task sequences are resumed
+	triggeredEvents, err = sc.filterEventsOfPausedSequences(project, triggeredEvents)
+	if err != nil {
+		return err
+	}
+	for _, triggeredEvent := range triggeredEvents {
+		// events that triggered a task sequence are not mapped to a task sequence
+		eventToSequence, err := sc.taskSequenceRepo.GetTaskSequence(project, triggeredEvent.ID)
+		if err != nil {
+			return err
+		} else if eventToSequence == nil {
+			continue
+		}
+		if expectedExecutors, _, err := getExpectedExecutors(triggeredEvent); err != nil || len(expectedExecutors) > 0 {
+			continue
+		}
+		startedEvents, err := sc.eventRepo.GetEvents(project, db.EventFilter{
+			TriggeredID: &triggeredEvent.ID,
+		}, db.StartedEvent)
+		if err != nil && err != db.ErrNoEventFound {
+			return err
+		} else if len(startedEvents) > 0 {
+			continue
+		}
+
+		taskProperties, err := getTaskPropertiesOfEvent(triggeredEvent)
+		if err != nil {
+			sc.logger.Error("Could not determine timeout of task triggered with event " + triggeredEvent.ID + ": " + err.Error())
+			continue
+		}
+		timeout := getTaskTimeout(taskProperties, defaultTimeout)
+		triggeredAt, err := sc.getSendTimeOfTriggeredEvent(project, triggeredEvent)
+		if err != nil {
+			sc.logger.Error("Could not determine time of .triggered event with ID " + triggeredEvent.ID + ": " + err.Error())
+			continue
+		} else if now.Sub(triggeredAt) <= timeout {
+			continue
+		}
+
+		sc.logger.Info(fmt.Sprintf("Task %s with triggeredid %s has not been started by any Keptn service within %s", *triggeredEvent.Type, triggeredEvent.ID, timeout.String()))
+		message := fmt.Sprintf("no Keptn service started the task within the task timeout of %s", timeout.String())
+		if err := sc.closeUnstartedTask(triggeredEvent, unstartedTaskSource, message); err != nil {
+			sc.logger.Error("Could not close timed out task with triggeredid " + triggeredEvent.ID + ": " + err.Error())
+		}
+	}
+	return nil
+}
+
+// getTaskPropertiesOfEvent returns the properties of the task a .triggered event has been sent for
+func getTaskPropertiesOfEvent(triggeredEvent models.Event) (interface{}, error) {
+	taskName, err := getTaskNameOfEvent(triggeredEvent, db.TriggeredEvent)
+	if err != nil {
+		return nil, err
+	}
+	marshal, err := json.Marshal(triggeredEvent.Data)
+	if err != nil {
+		return nil, err
+	}
+	eventData := map[string]interface{}{}
+	if err := json.Unmarshal(marshal, &eventData); err != nil {
+		return nil, err
+	}
+	return eventData[taskName], nil
+}
+
+func (sc *shipyardController) closeTimedOutTask(startedEvent models.Event, timeout time.Duration) error {
+	return sc.closeStartedTask(startedEvent, fmt.Sprintf("%s did not send a .finished event within the task timeout of %s", *startedEvent.Source, timeout.String()))
+}
+
+// closeStartedTask sends a .finished event with status errored for a task that has been started by a Keptn service
+func (sc *shipyardController) closeStartedTask(startedEvent models.Event, message string) error {
+	eventScope, err := getEventScope(startedEvent)
+	if err != nil {
+		return err
+	}
+
+	source, _ := url.Parse("shipyard-controller")
+	trimmedEventType := strings.TrimSuffix(*startedEvent.Type, string(db.StartedEvent))
+
+	event := cloudevents.NewEvent()
+	event.SetID(uuid.New().String())
+	event.SetTime(time.Now())
+	event.SetType(trimmedEventType + string(db.FinishedEvent))
+	event.SetSource(source.String())
+	event.SetDataContentType(cloudevents.ApplicationJSON)
+	event.SetExtension("shkeptncontext", startedEvent.Shkeptncontext)
+	event.SetExtension("triggeredid", startedEvent.Triggeredid)
+	event.SetData(cloudevents.ApplicationJSON, keptnv2.EventData{
+		Project: eventScope.Project,
+		Stage:   eventScope.Stage,
+		Service: eventScope.Service,
+		Labels:  eventScope.Labels,
+		Status:  keptnv2.StatusErrored,
+		Result:  keptnv2.ResultFailed,
+		Message: message,
+	})
+
+	if err := common.SendEvent(event); err != nil {
+		return err
+	}
+
+	// the .finished event is processed on behalf of the service that did not respond in time - events sent by the shipyard-controller are ignored otherwise
+	finishedEvent, err := models.ConvertToEvent(event)
+	if err != nil {
+		return err
+	}
+	finishedEvent.Source = startedEvent.Source
+	return sc.handleFinishedEvent(*finishedEvent)
+}
+
+// getTaskTimeout returns the timeout set in the properties of a task, or the default timeout if no valid timeout is set
+func getTaskTimeout(taskProperties interface{}, defaultTimeout time.Duration) time.Duration {
+	properties, ok := taskProperties.(map[string]interface{})
+	if !ok {
+		return defaultTimeout
+	}
+	timeoutString, ok := properties[taskTimeoutProperty].(string)
+	if !ok {
+		return defaultTimeout
+	}
+	timeout, err := time.ParseDuration(timeoutString)
+	if err != nil || timeout <= 0 {
+		return defaultTimeout
+	}
+	return timeout
+}
+
+func getDefaultTaskTimeout() time.Duration {
+	if timeout, err := time.ParseDuration(os.Getenv("TASK_TIMEOUT")); err == nil && timeout > 0 {
+		return timeout
+	}
+	return defaultTaskTimeout
+}
@@ -0,0 +1,286 @@
+package handler
+
+import (
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/keptn/shipyard-controller/db"
+	"github.com/keptn/keptn/shipyard-controller/handler/fake"
+	"github.com/keptn/keptn/shipyard-controller/models"
+	"os"
+	"testing"
+	"time"
+)
+
+func Test_getTaskTimeout(t *testing.T) {
+	type args struct {
+		taskProperties interface{}
+		defaultTimeout time.Duration
+	}
+	tests := []struct {
+		name string
+		args args
+		want time.Duration
+	}{
+		{
+			name: "timeout set in task properties",
+			args: args{
+				taskProperties: map[string]interface{}{"timeout": "10m"},
+				defaultTimeout: time.Hour,
+			},
+			want: 10 * time.Minute,
+		},
+		{
+			name: "no task properties",
+			args: args{
+				taskProperties: nil,
+				defaultTimeout: time.Hour,
+			},
+			want: time.Hour,
+		},
+		{
+			name: "no timeout in task properties",
+			args: args{
+				taskProperties: map[string]interface{}{"strategy": "direct"},
+				defaultTimeout: time.Hour,
+			},
+			want: time.Hour,
+		},
+		{
+			name: "invalid timeout in task properties",
+			args: args{
+				taskProperties: map[string]interface{}{"timeout": "ten minutes"},
+				defaultTimeout: time.Hour,
+			},
+			want: time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getTaskTimeout(tt.args.taskProperties, tt.args.defaultTimeout); got != tt.want {
+				t.Errorf("getTaskTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_shipyardController_closeTimedOutTasks(t *testing.T) {
+	sc := getTestShipyardController()
+	sc.projectRepo = &fake.ProjectRepository{GetProjectsFunc: func() ([]string, error) {
+		return []string{"test-project"}, nil
+	}}
+
+	mockCS := fake.NewConfigurationService(testShipyardResource)
+	defer mockCS.Close()
+
+	_ = os.Setenv("CONFIGURATION_SERVICE", mockCS.URL)
+
+	mockEV := fake.NewEventBroker(t,
+		func(meb *fake.EventBroker, event *models.Event) {
+			meb.ReceivedEvents = append(meb.ReceivedEvents, *event)
+		},
+		func(meb *fake.EventBroker) {
+
+		})
+	defer mockEV.Server.Close()
+	_ = os.Setenv("EVENTBROKER", mockEV.Server.URL)
+
+	// send dev.artifact-delivery.triggered event
+	if err := sc.HandleIncomingEvent(getArtifactDeliveryTriggeredEvent()); err != nil {
+		t.Errorf("HandleIncomingEvent(dev.artifact-delivery.triggered) returned %v", err)
+		return
+	}
+	triggeredEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
+		Type: keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName),
+	}, db.TriggeredEvent)
+	if fake.ShouldContainEvent(t, triggeredEvents, keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName), "", nil) {
+		return
+	}
+
+	startedAt := time.Now().Add(-2 * time.Hour)
+	startedEvent := getStartedEvent("dev", triggeredEvents[0].ID, keptnv2.DeploymentTaskName, "test-source")
+	startedEvent.Time = startedAt.Format(time.RFC3339)
+	if err := sc.HandleIncomingEvent(startedEvent); err != nil {
+		t.Errorf("HandleIncomingEvent(deployment.started) returned %v", err)
+		return
+	}
+
+	// the task has not timed out yet
+	if err := sc.closeTimedOutTasks(startedAt.Add(time.Hour)); err != nil {
+		t.Errorf("closeTimedOutTasks() returned %v", err)
+		return
+	}
+	fake.ShouldNotContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetFinishedEventType(keptnv2.DeploymentTaskName), "")
+
+	// the task exceeds the default timeout
+	if err := sc.closeTimedOutTasks(startedAt.Add(defaultTaskTimeout + time.Minute)); err != nil {
+		t.Errorf("closeTimedOutTasks() returned %v", err)
+		return
+	}
+	done := fake.ShouldContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetFinishedEventType(keptnv2.DeploymentTaskName), "dev", func(t *testing.T, event models.Event) bool {
+		scope, _ := getEventScope(event)
+		if scope.Status != keptnv2.StatusErrored {
+			t.Errorf("expected status %s but got %s", keptnv2.StatusErrored, scope.Status)
+			return true
+		}
+		return false
+	})
+	if done {
+		return
+	}
+	fake.ShouldContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetFinishedEventType("dev.artifact-delivery"), "dev", nil)
+
+	startedEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{}, db.StartedEvent)
+	if len(startedEvents) != 0 {
+		t.Errorf("expected no open .started events but got %d", len(startedEvents))
+	}
+}
+
+func Test_shipyardController_closeTimedOutTasksWithUnparseableStartTime(t *testing.T) {
+	sc := getTestShipyardController()
+	sc.projectRepo = &fake.ProjectRepository{GetProjectsFunc: func() ([]string, error) {
+		return []string{"test-project"}, nil
+	}}
+
+	mockCS := fake.NewConfigurationService(testShipyardResource)
+	defer mockCS.Close()
+
+	_ = os.Setenv("CONFIGURATION_SERVICE", mockCS.URL)
+
+	mockEV := fake.NewEventBroker(t,
+		func(meb *fake.EventBroker, event *models.Event) {
+			meb.ReceivedEvents = append(meb.ReceivedEvents, *event)
+		},
+		func(meb *fake.EventBroker) {
+
+		})
+	defer mockEV.Server.Close()
+	_ = os.Setenv("EVENTBROKER", mockEV.Server.URL)
+
+	if err := sc.HandleIncomingEvent(getArtifactDeliveryTriggeredEvent()); err != nil {
+		t.Errorf("HandleIncomingEvent(dev.artifact-delivery.triggered) returned %v", err)
+		return
+	}
+	triggeredEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
+		Type: keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName),
+	}, db.TriggeredEvent)
+	if fake.ShouldContainEvent(t, triggeredEvents, keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName), "", nil) {
+		return
+	}
+
+	storedAt := time.Now()
+	startedEvent := getStartedEvent("dev", triggeredEvents[0].ID, keptnv2.DeploymentTaskName, "test-source")
+	startedEvent.Time = "invalid"
+	if err := sc.HandleIncomingEvent(startedEvent); err != nil {
+		t.Errorf("HandleIncomingEvent(deployment.started) returned %v", err)
+		return
+	}
+
+	// the time at which the .started event has been stored is used as start time of the task
+	if err := sc.closeTimedOutTasks(storedAt.Add(time.Hour)); err != nil {
+		t.Errorf("closeTimedOutTasks() returned %v", err)
+		return
+	}
+	fake.ShouldNotContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetFinishedEventType(keptnv2.DeploymentTaskName), "")
+
+	if err := sc.closeTimedOutTasks(storedAt.Add(defaultTaskTimeout + time.Minute)); err != nil {
+		t.Errorf("closeTimedOutTasks() returned %v", err)
+		return
+	}
+	fake.ShouldContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetFinishedEventType(keptnv2.DeploymentTaskName), "dev", nil)
+}
+
+func Test_shipyardController_closeUnstartedTasks(t *testing.T) {
+	sc := getTestShipyardController()
+	sc.projectRepo = &fake.ProjectRepository{GetProjectsFunc: func() ([]string, error) {
+		return []string{"test-project"}, nil
+	}}
+
+	mockCS := fake.NewConfigurationService(testShipyardResource)
+	defer mockCS.Close()
+
+	_ = os.Setenv("CONFIGURATION_SERVICE", mockCS.URL)
+	_ = os.Setenv("SEQUENCE_CONCURRENCY_POLICY", "queue")
+	defer os.Unsetenv("SEQUENCE_CONCURRENCY_POLICY")
+
+	mockEV := fake.NewEventBroker(t,
+		func(meb *fake.EventBroker, event *models.Event) {
+			meb.ReceivedEvents = append(meb.ReceivedEvents, *event)
+		},
+		func(meb *fake.EventBroker) {
+
+		})
+	defer mockEV.Server.Close()
+	_ = os.Setenv("EVENTBROKER", mockEV.Server.URL)
+
+	getOpenDeploymentEvents := func(keptnContext string) []models.Event {
+		triggeredEvents, _ := sc.eventRepo.GetEvents("test-project", db.EventFilter{
+			Type:         keptnv2.GetTriggeredEventType(keptnv2.DeploymentTaskName),
+			KeptnContext: &keptnContext,
+		}, db.TriggeredEvent)
+		return triggeredEvents
+	}
+
+	// the deployment of the first task sequence is never started, the second task sequence is queued
+	triggeredAt := time.Now()
+	if err := sc.HandleIncomingEvent(getArtifactDeliveryTriggeredEvent()); err != nil {
+		t.Errorf("HandleIncomingEvent(dev.artifact-delivery.triggered) returned %v", err)
+		return
+	}
+	secondEvent := getArtifactDeliveryTriggeredEvent()
+	secondEvent.ID = "second-artifact-delivery-triggered-id"
+	secondEvent.Shkeptncontext = "second-test-context"
+	if err := sc.HandleIncomingEvent(secondEvent); err != nil {
+		t.Errorf("HandleIncomingEvent(dev.artifact-delivery.triggered) returned %v", err)
+		return
+	}
+	if len(getOpenDeploymentEvents("test-context")) != 1 || len(getOpenDeploymentEvents("second-test-context")) != 0 {
+		t.Errorf("expected the deployment of the first task sequence to be triggered and the second task sequence to be queued")
+		return
+	}
+
+	// the task has not timed out yet
+	if err := sc.closeTimedOutTasks(triggeredAt.Add(time.Hour)); err != nil {
+		t.Errorf("closeTimedOutTasks() returned %v", err)
+		return
+	}
+	fake.ShouldNotContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetFinishedEventType(keptnv2.DeploymentTaskName), "")
+
+	// the task sequence is paused -> the unstarted task is not closed although it exceeds the default timeout
+	if err := sc.PauseSequence("test-project", "test-context", ""); err != nil {
+		t.Errorf("PauseSequence() returned %v", err)
+		return
+	}
+	if err := sc.closeTimedOutTasks(triggeredAt.Add(defaultTaskTimeout + time.Minute)); err != nil {
+		t.Errorf("closeTimedOutTasks() returned %v", err)
+		return
+	}
+	if fake.ShouldNotContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetFinishedEventType(keptnv2.DeploymentTaskName), "") {
+		return
+	}
+	if err := sc.ResumeSequence("test-project", "test-context", ""); err != nil {
+		t.Errorf("ResumeSequence() returned %v", err)
+		return
+	}
+
+	// the unstarted task exceeds the default timeout -> it is closed and the queued task sequence is started
+	if err := sc.closeTimedOutTasks(triggeredAt.Add(defaultTaskTimeout + time.Minute)); err != nil {
+		t.Errorf("closeTimedOutTasks() returned %v", err)
+		return
+	}
+	done := fake.ShouldContainEvent(t, mockEV.ReceivedEvents, keptnv2.GetFinishedEventType(keptnv2.DeploymentTaskName), "dev", func(t *testing.T, event models.Event) bool {
+		scope, _ := getEventScope(event)
+		if scope.Status != keptnv2.StatusErrored {
+			t.Errorf("expected status %s but got %s", keptnv2.StatusErrored, scope.Status)
+			return true
+		}
+		return false
+	})
+	if done {
+		return
+	}
+	if len(getOpenDeploymentEvents("test-context")) != 0 {
+		t.Errorf("expected the deployment of the first task sequence to be closed")
+	}
+	if len(getOpenDeploymentEvents("second-test-context")) != 1 {
+		t.Errorf("expected the deployment of the queued task sequence to be triggered")
+	}
+}
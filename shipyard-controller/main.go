@@ -45,6 +45,18 @@ func main() {
 	eventController := controller.NewEventController(eventHandler)
 	eventController.Inject(apiV1)
 
+	sequenceHandler := handler.NewSequenceHandler()
+	sequenceController := controller.NewSequenceController(sequenceHandler)
+	sequenceController.Inject(apiV1)
+
+	scheduleHandler := handler.NewScheduleHandler()
+	scheduleController := controller.NewScheduleController(scheduleHandler)
+	scheduleController.Inject(apiV1)
+
+	go handler.GetShipyardControllerInstance().StartTaskTimeoutReaper()
+	go handler.GetShipyardControllerInstance().StartDelayedEventDispatcher()
+	go handler.GetSchedulerInstance().StartScheduler()
+
 	engine.Static("/swagger-ui", "./swagger-ui")
 	engine.Run()
 }
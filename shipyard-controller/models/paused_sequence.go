@@ -0,0 +1,19 @@
+package models
+
+import keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+
+// PausedSequence describes a paused task sequence. If no stage is set, the task sequences of all stages are paused
+type PausedSequence struct {
+	KeptnContext string        `json:"keptnContext" bson:"keptnContext"`
+	Stage        string        `json:"stage" bson:"stage"`
+	PausedAt     string        `json:"pausedAt" bson:"pausedAt"`
+	PendingTasks []PendingTask `json:"pendingTasks" bson:"pendingTasks"`
+}
+
+// PendingTask contains the information needed to trigger a task that has been held back while its task sequence was paused
+type PendingTask struct {
+	TaskSequenceName       string            `json:"taskSequenceName" bson:"taskSequenceName"`
+	Task                   keptnv2.Task      `json:"task" bson:"task"`
+	EventScope             keptnv2.EventData `json:"eventScope" bson:"eventScope"`
+	PreviousFinishedEvents []interface{}     `json:"previousFinishedEvents" bson:"previousFinishedEvents"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+
+// QueuedSequence contains the information needed to start a task sequence that has been queued because another task sequence of the same service was running in the same stage
+type QueuedSequence struct {
+	KeptnContext           string            `json:"keptnContext" bson:"keptnContext"`
+	Stage                  string            `json:"stage" bson:"stage"`
+	Service                string            `json:"service" bson:"service"`
+	TaskSequenceName       string            `json:"taskSequenceName" bson:"taskSequenceName"`
+	QueuedAt               string            `json:"queuedAt" bson:"queuedAt"`
+	EventScope             keptnv2.EventData `json:"eventScope" bson:"eventScope"`
+	PreviousFinishedEvents []interface{}     `json:"previousFinishedEvents" bson:"previousFinishedEvents"`
+}
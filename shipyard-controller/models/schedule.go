@@ -0,0 +1,50 @@
+package models
+
+// Schedule describes a task sequence that is triggered periodically, based on a cron expression
+type Schedule struct {
+	// ID of the schedule
+	ID string `json:"id" bson:"_id"`
+	// Project of the task sequence
+	Project string `json:"project" bson:"project"`
+	// Stage of the task sequence
+	Stage string `json:"stage" bson:"stage"`
+	// Service of the task sequence
+	Service string `json:"service" bson:"service"`
+	// Sequence is the name of the task sequence
+	Sequence string `json:"sequence" bson:"sequence"`
+	// Cron is the cron expression defining when the task sequence is triggered
+	Cron string `json:"cron" bson:"cron"`
+	// Labels that are added to the events triggering the task sequence
+	Labels map[string]string `json:"labels,omitempty" bson:"labels,omitempty"`
+	// Evaluation defines the timeframe of the evaluations of the task sequence
+	Evaluation *ScheduleEvaluation `json:"evaluation,omitempty" bson:"evaluation,omitempty"`
+	// CreatedAt is the time the schedule has been created
+	CreatedAt string `json:"createdAt" bson:"createdAt"`
+	// LastTriggeredAt is the time the task sequence has been triggered the last time
+	LastTriggeredAt string `json:"lastTriggeredAt,omitempty" bson:"lastTriggeredAt,omitempty"`
+	// LastKeptnContext is the Keptn context of the task sequence that has been triggered the last time
+	LastKeptnContext string `json:"lastKeptnContext,omitempty" bson:"lastKeptnContext,omitempty"`
+	// NextTriggerAt is the time the task sequence will be triggered next
+	NextTriggerAt string `json:"nextTriggerAt,omitempty" bson:"-"`
+}
+
+// ScheduleEvaluation defines the timeframe that is evaluated when a scheduled task sequence contains an evaluation
+type ScheduleEvaluation struct {
+	// Timeframe is the duration before the trigger time that is evaluated, e.g. 15m
+	Timeframe string `json:"timeframe" bson:"timeframe"`
+}
+
+// Schedules contains a paginated list of schedules
+type Schedules struct {
+	// schedules
+	Schedules []Schedule `json:"schedules"`
+
+	// Pointer to next page, base64 encoded
+	NextPageKey string `json:"nextPageKey,omitempty"`
+
+	// Size of returned page
+	PageSize float64 `json:"pageSize,omitempty"`
+
+	// Total number of schedules
+	TotalCount float64 `json:"totalCount,omitempty"`
+}
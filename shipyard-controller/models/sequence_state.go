@@ -0,0 +1,68 @@
+package models
+
+const (
+	// SequenceQueuedState indicates that a task sequence is waiting for another task sequence of the same service in the same stage to be finished
+	SequenceQueuedState = "queued"
+	// SequenceTriggeredState indicates that a task sequence or task has been triggered
+	SequenceTriggeredState = "triggered"
+	// SequenceStartedState indicates that at least one task of a task sequence, or at least one executor of a task, has been started
+	SequenceStartedState = "started"
+	// SequenceFinishedState indicates that a task sequence or task has been finished
+	SequenceFinishedState = "finished"
+	// SequenceAbortedState indicates that a task sequence has been aborted
+	SequenceAbortedState = "aborted"
+)
+
+// SequenceState describes the state of a task sequence of a keptnContext within a stage
+type SequenceState struct {
+	Name         string              `json:"name" bson:"name"`
+	Project      string              `json:"project" bson:"project"`
+	Stage        string              `json:"stage" bson:"stage"`
+	Service      string              `json:"service" bson:"service"`
+	KeptnContext string              `json:"shkeptncontext" bson:"shkeptncontext"`
+	State        string              `json:"state" bson:"state"`
+	CurrentTask  string              `json:"currentTask" bson:"currentTask"`
+	Result       string              `json:"result,omitempty" bson:"result,omitempty"`
+	Status       string              `json:"status,omitempty" bson:"status,omitempty"`
+	Time         string              `json:"time" bson:"time"`
+	Tasks        []SequenceStateTask `json:"tasks" bson:"tasks"`
+}
+
+// SequenceStateTask describes the state of a single task within a task sequence
+type SequenceStateTask struct {
+	Name        string                  `json:"name" bson:"name"`
+	TriggeredID string                  `json:"triggeredID" bson:"triggeredID"`
+	State       string                  `json:"state" bson:"state"`
+	TriggeredAt string                  `json:"triggeredAt" bson:"triggeredAt"`
+	StartedAt   string                  `json:"startedAt,omitempty" bson:"startedAt,omitempty"`
+	FinishedAt  string                  `json:"finishedAt,omitempty" bson:"finishedAt,omitempty"`
+	Result      string                  `json:"result,omitempty" bson:"result,omitempty"`
+	Status      string                  `json:"status,omitempty" bson:"status,omitempty"`
+	Attempt     int                     `json:"attempt,omitempty" bson:"attempt,omitempty"`
+	Executors   []SequenceStateExecutor `json:"executors" bson:"executors"`
+}
+
+// SequenceStateExecutor describes the state of a task as reported by one of the Keptn services executing it
+type SequenceStateExecutor struct {
+	Source     string `json:"source" bson:"source"`
+	StartedAt  string `json:"startedAt,omitempty" bson:"startedAt,omitempty"`
+	FinishedAt string `json:"finishedAt,omitempty" bson:"finishedAt,omitempty"`
+	Result     string `json:"result,omitempty" bson:"result,omitempty"`
+	Status     string `json:"status,omitempty" bson:"status,omitempty"`
+	Message    string `json:"message,omitempty" bson:"message,omitempty"`
+}
+
+// SequenceStates contains a paginated list of task sequence states
+type SequenceStates struct {
+	// states
+	States []SequenceState `json:"states"`
+
+	// Pointer to next page, base64 encoded
+	NextPageKey string `json:"nextPageKey,omitempty"`
+
+	// Size of returned page
+	PageSize float64 `json:"pageSize,omitempty"`
+
+	// Total number of states
+	TotalCount float64 `json:"totalCount,omitempty"`
+}
@@ -6,4 +6,6 @@ type TaskSequenceEvent struct {
 	TriggeredEventID string `json:"triggeredEventID" bson:"triggeredEventID"`
 	Stage            string `json:"stage" bson:"stage"`
 	KeptnContext     string `json:"keptnContext" bson:"keptnContext"`
+	TaskGroup        string `json:"taskGroup,omitempty" bson:"taskGroup,omitempty"`
+	Attempt          int    `json:"attempt,omitempty" bson:"attempt,omitempty"`
 }
@@ -0,0 +1,81 @@
+package operations
+
+import "github.com/keptn/keptn/shipyard-controller/models"
+
+// CreateScheduleParams contains all the bound params for the CreateSchedule operation
+// typically these are obtained from a http.Request
+//
+// swagger:parameters create schedule
+type CreateScheduleParams struct {
+	// stage
+	// Required: true
+	Stage string `json:"stage"`
+
+	// service
+	// Required: true
+	Service string `json:"service"`
+
+	// name of the task sequence
+	// Required: true
+	Sequence string `json:"sequence"`
+
+	// cron expression, e.g. '0 2 * * *' or '@daily'
+	// Required: true
+	Cron string `json:"cron"`
+
+	// labels that are added to the events triggering the task sequence
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// timeframe of the evaluations of the task sequence, required if the task sequence contains an evaluation
+	Evaluation *models.ScheduleEvaluation `json:"evaluation,omitempty"`
+}
+
+// UpdateScheduleParams contains all the bound params for the UpdateSchedule operation
+// typically these are obtained from a http.Request
+//
+// swagger:parameters update schedule
+type UpdateScheduleParams struct {
+	// cron expression, e.g. '0 2 * * *' or '@daily'
+	// Required: true
+	Cron string `json:"cron"`
+
+	// labels that are added to the events triggering the task sequence
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// timeframe of the evaluations of the task sequence, required if the task sequence contains an evaluation
+	Evaluation *models.ScheduleEvaluation `json:"evaluation,omitempty"`
+}
+
+// DeleteScheduleResponse contains information about the result of the DeleteSchedule operation
+type DeleteScheduleResponse struct {
+}
+
+// GetSchedulesParams contains all the bound params for the GetSchedules operation
+// typically these are obtained from a http.Request
+//
+// swagger:parameters get schedules
+type GetSchedulesParams struct {
+	/*Stage name
+	  In: query
+	*/
+	Stage *string `form:"stage" json:"stage"`
+	/*Service name
+	  In: query
+	*/
+	Service *string `form:"service" json:"service"`
+	/*Name of the task sequence
+	  In: query
+	*/
+	Sequence *string `form:"sequence" json:"sequence"`
+	/*Pointer to the next set of items
+	  In: query
+	*/
+	NextPageKey *string `form:"nextPageKey" json:"nextPageKey"`
+	/*The number of items to return
+	  Maximum: 50
+	  Minimum: 1
+	  In: query
+	  Default: 20
+	*/
+	PageSize *int64 `form:"pageSize" json:"pageSize"`
+}
@@ -0,0 +1,68 @@
+package operations
+
+// SequenceControlState describes the state a task sequence should be set to
+type SequenceControlState string
+
+const (
+	// AbortSequence aborts a task sequence
+	AbortSequence SequenceControlState = "abort"
+	// PauseSequence pauses a task sequence, i.e. no further tasks are triggered until the sequence is resumed
+	PauseSequence SequenceControlState = "pause"
+	// ResumeSequence resumes a paused task sequence
+	ResumeSequence SequenceControlState = "resume"
+)
+
+// SequenceControlParams contains all the bound params for the ControlSequence operation
+// typically these are obtained from a http.Request
+//
+// swagger:parameters control sequence
+type SequenceControlParams struct {
+	// state
+	// Required: true
+	State SequenceControlState `json:"state"`
+
+	// stage, if not set, the task sequences of all stages are affected
+	Stage string `json:"stage,omitempty"`
+}
+
+// SequenceControlResponse contains information about the result of the ControlSequence operation
+type SequenceControlResponse struct {
+}
+
+// GetSequenceStateParams contains all the bound params for the GetSequenceState operation
+// typically these are obtained from a http.Request
+//
+// swagger:parameters get sequence state
+type GetSequenceStateParams struct {
+	/*Keptn context of the task sequence
+	  In: query
+	*/
+	KeptnContext *string `form:"keptnContext" json:"keptnContext"`
+	/*Stage name
+	  In: query
+	*/
+	Stage *string `form:"stage" json:"stage"`
+	/*Service name
+	  In: query
+	*/
+	Service *string `form:"service" json:"service"`
+	/*Name of the task sequence
+	  In: query
+	*/
+	Name *string `form:"name" json:"name"`
+	/*State of the task sequence (queued, triggered, started, finished, aborted)
+	  In: query
+	*/
+	State *string `form:"state" json:"state"`
+	/*Pointer to the next set of items
+	  In: query
+	*/
+	NextPageKey *string `form:"nextPageKey" json:"nextPageKey"`
+	/*The number of items to return
+	  Maximum: 50
+	  Minimum: 1
+	  In: query
+	  Default: 20
+	*/
+	PageSize *int64 `form:"pageSize" json:"pageSize"`
+}